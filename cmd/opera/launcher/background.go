@@ -0,0 +1,94 @@
+// This file configures the throttling of the background tasks (historical sync,
+// index building, DB compaction), so they yield to the live block processing.
+// The limits come from the selected preset and may be overridden per task by the flags.
+
+package launcher
+
+import (
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/rony4d/go-opera-asset/integration"
+	"github.com/rony4d/go-opera-asset/utils/clock"
+	"github.com/rony4d/go-opera-asset/utils/throttle"
+)
+
+// BackgroundConfig limits the background tasks of the node. Zero values mean no limit.
+type BackgroundConfig struct {
+	Sync       throttle.Config // historical sync, rate in blocks per second
+	Index      throttle.Config // index building, rate in blocks per second
+	Compaction throttle.Config // DB compaction, rate in MB per second
+}
+
+// Throttlers are the throttlers of the background tasks, sharing the gate of the live processing.
+type Throttlers struct {
+	Gate       *throttle.Gate
+	Sync       *throttle.Throttler
+	Index      *throttle.Throttler
+	Compaction *throttle.Throttler
+}
+
+// NewThrottlers creates the throttlers of the background tasks.
+// The live processing must hold the returned Gate while it handles a block or an event.
+func NewThrottlers(cfg BackgroundConfig) Throttlers {
+	gate := throttle.NewGate()
+	return Throttlers{
+		Gate:       gate,
+		Sync:       throttle.New(cfg.Sync, gate, clock.Real{}),
+		Index:      throttle.New(cfg.Index, gate, clock.Real{}),
+		Compaction: throttle.New(cfg.Compaction, gate, clock.Real{}),
+	}
+}
+
+func backgroundFromPreset(p integration.PresetConfig) BackgroundConfig {
+	return BackgroundConfig{
+		Sync:       p.SyncThrottle,
+		Index:      p.IndexThrottle,
+		Compaction: p.CompactionThrottle,
+	}
+}
+
+// applyBackgroundOverrides takes the limits of the selected preset, then the per-task flags.
+// An unknown preset is left to `opera config check` to report.
+func applyBackgroundOverrides(ctx *cli.Context, cfg *Config) {
+	if cfg.OperaStore.Preset != "" {
+		if p, err := integration.GetPresetByName(cfg.OperaStore.Preset); err == nil {
+			cfg.Background = backgroundFromPreset(p)
+		}
+	}
+	for name, c := range map[string]*throttle.Config{
+		"sync":       &cfg.Background.Sync,
+		"index":      &cfg.Background.Index,
+		"compaction": &cfg.Background.Compaction,
+	} {
+		if ctx.IsSet("background." + name + ".concurrency") {
+			c.MaxConcurrency = ctx.Int("background." + name + ".concurrency")
+		}
+		if ctx.IsSet("background." + name + ".rate") {
+			c.Rate = ctx.Float64("background." + name + ".rate")
+		}
+	}
+}
+
+func checkBackground(cfg Config, report *ConfigReport) {
+	tasks := []struct {
+		name string
+		cfg  throttle.Config
+	}{
+		{"sync", cfg.Background.Sync},
+		{"index", cfg.Background.Index},
+		{"compaction", cfg.Background.Compaction},
+	}
+	for _, task := range tasks {
+		c := task.cfg
+		if c.MaxConcurrency < 0 || c.Rate < 0 || c.Burst < 0 {
+			report.add("background", CheckFail, "negative %s throttle limits %+v", task.name, c)
+			return
+		}
+	}
+	if cfg.Emitter.Enabled && cfg.Background.Sync.MaxConcurrency == 0 && cfg.Background.Sync.Rate == 0 {
+		report.add("background", CheckWarn, "validator syncs history without limits, it may miss emission deadlines")
+		return
+	}
+	report.add("background", CheckPass, "sync %+v, index %+v, compaction %+v",
+		cfg.Background.Sync, cfg.Background.Index, cfg.Background.Compaction)
+}
@@ -0,0 +1,29 @@
+// This file configures the pipelined block processing (see iblockproc.Pipeline): the stages of
+// consecutive blocks overlap, unless the serial mode is switched on by --blockproc.serial.
+
+package launcher
+
+import (
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+)
+
+// NewBlockPipeline creates the block processing pipeline of the stages.
+func NewBlockPipeline(cfg Config, stages ...iblockproc.Stage) *iblockproc.Pipeline {
+	return iblockproc.NewPipeline(cfg.BlockProc, stages...)
+}
+
+func checkBlockProc(cfg Config, report *ConfigReport) {
+	if cfg.BlockProc.Serial {
+		report.add("blockproc", CheckPass, "serial")
+		return
+	}
+	if cfg.BlockProc.QueueSize < 0 {
+		report.add("blockproc", CheckFail, "negative queue size %d", cfg.BlockProc.QueueSize)
+		return
+	}
+	if cfg.BlockProc.QueueSize == 0 {
+		report.add("blockproc", CheckWarn, "no blocks are queued between the stages, they'll barely overlap")
+		return
+	}
+	report.add("blockproc", CheckPass, "pipelined, %d blocks queued per stage", cfg.BlockProc.QueueSize)
+}
@@ -0,0 +1,19 @@
+package launcher
+
+import "testing"
+
+// TestCheckBlockProc verifies the config check of the block processing pipeline.
+func TestCheckBlockProc(t *testing.T) {
+	runCheckCases(t, []checkCase{
+		{
+			name: "negative block processing queue",
+			args: []string{"--blockproc.queue", "-1"},
+			want: map[string]CheckStatus{"blockproc": CheckFail},
+		},
+		{
+			name: "serial block processing",
+			args: []string{"--blockproc.serial", "--blockproc.queue", "-1"},
+			want: map[string]CheckStatus{"blockproc": CheckPass},
+		},
+	})
+}
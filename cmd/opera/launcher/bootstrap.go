@@ -0,0 +1,309 @@
+// This file implements --bootstrap-url: a fresh node downloads a published snapshot
+// archive (tar.gz of the network datadir databases) over HTTPS, verifies its hash and unpacks
+// it into the datadir before starting, so it only has to sync the recent epochs.
+// The archive is unpacked into a temporary dir first, so a node interrupted while unpacking
+// never starts on a partial database.
+
+package launcher
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// BootstrapConfig is the config of the snapshot bootstrap of a fresh node.
+type BootstrapConfig struct {
+	URL  string // snapshot archive URL, empty means no bootstrap
+	Hash string // hex-encoded SHA-256 of the archive
+}
+
+var (
+	errBootstrapScheme = errors.New("bootstrap snapshot must be downloaded over https")
+	errBootstrapNoHash = errors.New("bootstrap snapshot hash isn't specified")
+	// ErrSnapshotHashMismatch is returned if the downloaded archive doesn't match the expected hash.
+	ErrSnapshotHashMismatch = errors.New("snapshot archive hash mismatch")
+)
+
+const (
+	// snapshotTmpDir is the dir of the datadir the snapshot is unpacked into.
+	snapshotTmpDir = "bootstrap.tmp"
+	// snapshotUnpackedMarker is created in snapshotTmpDir once the snapshot is unpacked entirely.
+	snapshotUnpackedMarker = ".unpacked"
+)
+
+// bootstrapNode downloads and unpacks the snapshot if it's configured and the node has no database yet.
+func bootstrapNode(ctx context.Context, cfg Config) error {
+	if cfg.Bootstrap.URL == "" {
+		return nil
+	}
+	datadir := cfg.NetworkDataDir()
+	if err := FinishSnapshot(datadir, cfg.OperaStore.Path); err != nil {
+		return err
+	}
+	chaindata := filepath.Join(datadir, cfg.OperaStore.Path)
+	if files, err := ioutil.ReadDir(chaindata); err == nil && len(files) != 0 {
+		log.Info("Database already exists, skipping snapshot bootstrap", "path", chaindata)
+		return nil
+	}
+	if err := ensureDir(datadir); err != nil {
+		return err
+	}
+
+	archive := filepath.Join(datadir, "bootstrap.tar.gz")
+	log.Info("Downloading snapshot", "url", cfg.Bootstrap.URL)
+	if err := DownloadSnapshot(ctx, http.DefaultClient, cfg.Bootstrap.URL, archive, cfg.Bootstrap.Hash); err != nil {
+		return err
+	}
+	log.Info("Unpacking snapshot", "datadir", datadir)
+	if err := ExtractSnapshot(archive, datadir, cfg.OperaStore.Path); err != nil {
+		return err
+	}
+	return os.Remove(archive)
+}
+
+// DownloadSnapshot downloads the archive into dst and verifies its SHA-256 hash.
+// The data is written into dst+".part" first, so an interrupted download is resumed
+// from where it stopped (if the server supports range requests).
+func DownloadSnapshot(ctx context.Context, client *http.Client, rawurl, dst, hash string) error {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" {
+		return errBootstrapScheme
+	}
+	if hash == "" {
+		return errBootstrapNoHash
+	}
+	if !strings.HasPrefix(hash, "0x") {
+		hash = "0x" + hash
+	}
+	expected, err := hexutil.Decode(hash)
+	if err != nil || len(expected) != sha256.Size {
+		return fmt.Errorf("invalid bootstrap snapshot hash %s", hash)
+	}
+
+	part := dst + ".part"
+	if err := download(ctx, client, u.String(), part); err != nil {
+		return err
+	}
+
+	got, err := fileSHA256(part)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(got, expected) {
+		// the partial data is corrupted, don't resume from it
+		_ = os.Remove(part)
+		return fmt.Errorf("%w: expected %x, got %x", ErrSnapshotHashMismatch, expected, got)
+	}
+	return os.Rename(part, dst)
+}
+
+// download appends the missing tail of the resource to the file.
+func download(ctx context.Context, client *http.Client, rawurl, path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawurl, nil)
+	if err != nil {
+		return err
+	}
+	if offset != 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		if !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			return fmt.Errorf("unexpected content range %q", resp.Header.Get("Content-Range"))
+		}
+		log.Info("Resuming snapshot download", "offset", offset)
+	case http.StatusOK:
+		// range requests aren't supported, start over
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// already downloaded entirely
+		return nil
+	default:
+		return fmt.Errorf("failed to download snapshot: %s", resp.Status)
+	}
+
+	_, err = io.Copy(f, resp.Body)
+	return err
+}
+
+func fileSHA256(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// ExtractSnapshot unpacks the tar.gz archive into the dir.
+// The archive is unpacked into snapshotTmpDir first, and its entries replace the ones
+// of the dir only once it's unpacked entirely, see FinishSnapshot.
+// The archive may contain only the database dir db (relative to the dir), so a snapshot
+// can never overwrite the keystore or the node keys. Other entries, and entries other
+// than directories and regular files, are rejected.
+func ExtractSnapshot(archive, dir, db string) error {
+	if err := checkSnapshotDB(db); err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, snapshotTmpDir)
+	// drop the leftovers of an interrupted unpacking
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	if err := extractArchive(archive, tmp, db); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(tmp, snapshotUnpackedMarker), nil, 0o644); err != nil {
+		return err
+	}
+	return FinishSnapshot(dir, db)
+}
+
+// FinishSnapshot moves the entries of the entirely unpacked snapshot into the dir, replacing
+// the existing ones. If the node was interrupted while moving them, the next call completes
+// the move. It does nothing if there is no unpacked snapshot.
+// Nothing is replaced if the snapshot has entries other than the database dir db.
+func FinishSnapshot(dir, db string) error {
+	if err := checkSnapshotDB(db); err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, snapshotTmpDir)
+	if _, err := os.Stat(filepath.Join(tmp, snapshotUnpackedMarker)); os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	entries, err := ioutil.ReadDir(tmp)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.Name() != snapshotUnpackedMarker && (e.Name() != db || !e.IsDir()) {
+			return fmt.Errorf("snapshot entry %q isn't the database dir %q", e.Name(), db)
+		}
+	}
+	for _, e := range entries {
+		if e.Name() == snapshotUnpackedMarker {
+			continue
+		}
+		dst := filepath.Join(dir, e.Name())
+		if err := os.RemoveAll(dst); err != nil {
+			return err
+		}
+		if err := os.Rename(filepath.Join(tmp, e.Name()), dst); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(tmp)
+}
+
+// checkSnapshotDB checks that the database dir is a single dir of the datadir.
+func checkSnapshotDB(db string) error {
+	if db == "" || db != filepath.Base(db) || db == "." || db == ".." || db == snapshotTmpDir {
+		return fmt.Errorf("snapshot bootstrap requires the database dir %q to be a dir of the datadir", db)
+	}
+	return nil
+}
+
+func extractArchive(archive, dir, db string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("snapshot entry %q is outside of the datadir", hdr.Name)
+		}
+		if top := strings.SplitN(name, string(filepath.Separator), 2)[0]; top != db {
+			return fmt.Errorf("snapshot entry %q is outside of the database dir %q", hdr.Name, db)
+		}
+		path := filepath.Join(dir, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return err
+			}
+			if err := extractFile(tr, path); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("snapshot entry %q has unsupported type %d", hdr.Name, hdr.Typeflag)
+		}
+	}
+}
+
+func extractFile(r io.Reader, path string) error {
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,89 @@
+package launcher
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+func checkCommand() cli.Command {
+	return cli.Command{
+		Name:     "check",
+		Usage:    "Offline data consistency checks",
+		Category: "MISCELLANEOUS COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:      "dag",
+				Usage:     "Check DAG invariants of an events file before importing it",
+				ArgsUsage: "<filename>",
+				Action:    checkDagAction,
+				Description: `
+    opera check dag events.rlp[.gz]
+
+Reads the RLP-encoded events from the file (gzipped if the name ends with .gz)
+and checks that lamports grow along the parents, frames don't decrease along the
+parents and seqs grow by one along the self-parents.
+Exits with a non-zero code if any invariant is violated.`,
+			},
+		},
+	}
+}
+
+func checkDagAction(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("expected exactly one events file")
+	}
+	num, err := CheckEventsFile(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("DAG invariants hold for %d events\n", num)
+	return nil
+}
+
+// CheckEventsFile reads the RLP stream of events from the file and checks their DAG invariants.
+// The events are checked as they're read, so the file doesn't have to fit into memory.
+// Returns the number of events read.
+func CheckEventsFile(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var reader io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return 0, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	checker := inter.NewDagChecker()
+	stream := rlp.NewStream(reader, 0)
+	num := 0
+	for {
+		e := new(inter.EventPayload)
+		err := stream.Decode(e)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return num, fmt.Errorf("failed to decode event #%d: %w", num, err)
+		}
+		if err := checker.Add(e); err != nil {
+			return num, err
+		}
+		num++
+	}
+	return num, nil
+}
@@ -6,11 +6,20 @@ package launcher
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/log"
 	"gopkg.in/urfave/cli.v1"
+
+	"github.com/rony4d/go-opera-asset/gossip"
+	"github.com/rony4d/go-opera-asset/integration"
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+	"github.com/rony4d/go-opera-asset/opera"
 )
 
 // Config aggregates every subsystem’s configuration the launcher needs.
@@ -25,23 +34,40 @@ type Config struct {
 	VectorClock   VectorClockConfig
 	DBs           DBsConfig
 	Genesis       GenesisConfig
+	Bootstrap     BootstrapConfig
+	Registry      RegistryConfig
+	Indexer       IndexerConfig
+	Background    BackgroundConfig
+	BlockProc     iblockproc.PipelineConfig
+	Faucet        FaucetConfig
+	GRPC          GRPCConfig
+	Explorer      ExplorerConfig
+	Watchdog      WatchdogConfig
+	Telemetry     TelemetryConfig
 }
 
 // MakeConfig merges defaults, optional config file, then CLI flag overrides.
 
 type NodeConfig struct {
-	DataDir string
-	Name    string
-	P2P     P2PConfig
-	RPC     RPCConfig
-	Logging LoggingConfig
+	DataDir      string
+	KeyStoreDir  string // empty means <datadir>/keystore
+	Name         string
+	ReadOnly     bool // open the datadir read-only, see readonly.go
+	LegacyLayout bool // the data of the network is right in the datadir, see NetworkDataDir; set by makeConfig
+	P2P          P2PConfig
+	RPC          RPCConfig
+	Logging      LoggingConfig
 }
 
 type P2PConfig struct {
-	ListenAddr string
-	ListenPort int
-	MaxPeers   int
-	Bootnodes  []string
+	ListenAddr  string
+	ListenAddr6 string // IPv6 address bound in addition to ListenAddr, empty if none
+	ListenPort  int
+	ExternalIP  string // advertised IPv4 address, empty if unknown
+	ExternalIP6 string // advertised IPv6 address, empty if unknown
+	MaxPeers    int
+	Bootnodes   []string
+	Serve       gossip.ServeLimitsConfig // limits of the heavy requests served to the peers
 }
 
 type RPCConfig struct {
@@ -57,6 +83,10 @@ type RPCConfig struct {
 
 	EnableIPC bool
 	IPCPath   string
+
+	LogsBlockRange uint64        // max number of blocks of an eth_getLogs query, 0 means unlimited
+	LogsMaxResults int           // max number of logs returned by an eth_getLogs query, 0 means unlimited
+	LogsTimeout    time.Duration // max duration of an eth_getLogs query, 0 means unlimited
 }
 
 type LoggingConfig struct {
@@ -70,15 +100,21 @@ type OperaConfig struct {
 	NetworkID   uint64
 	FakeNet     bool
 	FakeSlots   int
+	// RulesTemplate selects the rules of an app-chain (see opera.TemplateRules),
+	// empty for the fakenet rules. It's ignored on mainnet and testnet.
+	RulesTemplate string
 }
 
 type EmitterConfig struct {
 	Enabled        bool
 	ValidatorID    uint32
 	ValidatorKey   string // hex public key for now
-	Password       string // TODO: replace with secure keystore handling
+	Password       string // password of the validator keystore, PasswordFile is preferred
 	PasswordFile   string
 	UnlockAccounts []string
+	// Shadow computes the events of the validator without signing or broadcasting them,
+	// see NewShadowEmitter. It's exclusive with Enabled.
+	Shadow bool
 }
 
 type TxPoolConfig struct {
@@ -95,6 +131,9 @@ type TxPoolConfig struct {
 type StoreConfig struct {
 	Path    string
 	CacheMB int
+	Handles int    // number of open files the DBs may use
+	Preset  string // name of the integration preset, empty if none is selected
+	WarmUp  int    // number of hot accounts and slots preloaded into the state cache on startup, 0 disables
 }
 
 type LachesisConfig struct {
@@ -138,6 +177,7 @@ func defaultConfig() Config {
 				ListenPort: DefaultConfig().Node.ListenPort,
 				MaxPeers:   DefaultConfig().Node.MaxPeers,
 				Bootnodes:  DefaultConfig().Network.Bootnodes,
+				Serve:      gossip.DefaultServeLimitsConfig(),
 			},
 			RPC: RPCConfig{
 				HTTPEnabled: true,
@@ -150,6 +190,10 @@ func defaultConfig() Config {
 				WSAPI:       DefaultConfig().RPC.WSAPI,
 				EnableIPC:   DefaultConfig().RPC.EnableIPC,
 				IPCPath:     DefaultConfig().RPC.IPCPath,
+
+				LogsBlockRange: 10000,
+				LogsMaxResults: 10000,
+				LogsTimeout:    10 * time.Second,
 			},
 			Logging: LoggingConfig{
 				Verbosity: DefaultConfig().Logging.Verbosity,
@@ -174,7 +218,7 @@ func defaultConfig() Config {
 			GlobalQueue:   DefaultConfig().TxPool.GlobalQueue,
 			TxLifetimeSec: DefaultConfig().TxPool.TxLifetimeSec,
 		},
-		OperaStore:    StoreConfig{Path: "chaindata", CacheMB: 1024},
+		OperaStore:    StoreConfig{Path: "chaindata", CacheMB: 1024, Handles: DefaultConfig().Storage.Handles, WarmUp: 10000},
 		Lachesis:      LachesisConfig{MaxEpochBlocks: 1000, MaxEpochTime: "24h"},
 		LachesisStore: LachesisStoreConfig{CacheMB: 512},
 		VectorClock:   VectorClockConfig{CacheSize: 64 * 1024},
@@ -182,6 +226,36 @@ func defaultConfig() Config {
 		Genesis: GenesisConfig{
 			Path: DefaultConfig().Genesis.Path,
 		},
+		Indexer: IndexerConfig{
+			HTTPAddr: DefaultConfig().RPC.HTTPAddr,
+			HTTPPort: 18547,
+		},
+		Background: backgroundFromPreset(integration.DefaultPreset()),
+		Faucet: FaucetConfig{
+			HTTPAddr: DefaultConfig().RPC.HTTPAddr,
+			HTTPPort: 18548,
+			Amount:   10,
+			Interval: time.Hour,
+		},
+		GRPC: GRPCConfig{
+			Addr: DefaultConfig().RPC.HTTPAddr,
+			Port: 18549,
+		},
+		Explorer: ExplorerConfig{
+			HTTPAddr: DefaultConfig().Metrics.HTTPAddr,
+			HTTPPort: DefaultConfig().Metrics.HTTPPort,
+		},
+		BlockProc: iblockproc.DefaultPipelineConfig(),
+		Watchdog: WatchdogConfig{
+			Interval:  time.Minute,
+			MinUptime: time.Hour,
+		},
+		Telemetry: TelemetryConfig{
+			Interval: 24 * time.Hour,
+		},
+		Registry: RegistryConfig{
+			Interval: time.Hour,
+		},
 	}
 }
 
@@ -189,21 +263,140 @@ func defaultConfig() Config {
 // config-file values, and CLI overrides into a single config struct.
 
 func MakeAllConfigs(ctx *cli.Context) Config {
+	cfg, err := makeConfig(ctx)
+	if err != nil {
+		// In this placeholder we simply panic; in the real launcher return the error.
+		panic(err)
+	}
+
+	if cfg.Node.ReadOnly {
+		return cfg
+	}
+	if err := ensureDir(cfg.Node.DataDir); err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+// makeConfig merges defaults, config file and CLI overrides without touching the filesystem.
+func makeConfig(ctx *cli.Context) (Config, error) {
 	cfg := defaultConfig()
 
 	if file := ctx.String("config"); file != "" {
 		if err := loadConfigFile(file, &cfg); err != nil {
-			// In this placeholder we simply panic; in the real launcher return the error.
-			panic(fmt.Errorf("failed to load config file %s: %w", file, err))
+			return cfg, fmt.Errorf("failed to load config file %s: %w", file, err)
 		}
 	}
 
+	if ctx.IsSet("network") {
+		if err := setNetwork(ctx.String("network"), &cfg.Opera); err != nil {
+			return cfg, err
+		}
+	}
+	if ctx.IsSet("telemetry") {
+		enabled, err := parseTelemetryFlag(ctx.String("telemetry"))
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Telemetry.Enabled = enabled
+	}
 	applyCLIOverrides(ctx, &cfg)
+	cfg.Node.LegacyLayout = cfg.Opera.NetworkName != "" && isLegacyDataDir(cfg)
+	applyActiveValidatorKey(&cfg)
+	applyReadOnly(&cfg)
+	return cfg, nil
+}
 
-	if err := ensureDir(cfg.Node.DataDir); err != nil {
-		panic(err)
+// setNetwork selects one of the known networks by name.
+func setNetwork(name string, cfg *OperaConfig) error {
+	switch name {
+	case "mainnet":
+		cfg.NetworkID = opera.MainNetworkID
+	case "testnet":
+		cfg.NetworkID = opera.TestNetworkID
+	case "fakenet":
+		cfg.NetworkID = opera.FakeNetworkID
+	default:
+		return fmt.Errorf("unknown network %q, expected mainnet, testnet or fakenet", name)
+	}
+	cfg.NetworkName = name
+	cfg.FakeNet = name == "fakenet"
+	return nil
+}
+
+// NetworkIDFile records the ID of the network whose data the network datadir holds.
+const NetworkIDFile = "networkid"
+
+// NetworkDataDir returns the directory which holds the data of the selected network:
+// <datadir>/<network-name>, so nodes of different networks may share one datadir
+// without mixing up their databases and keys.
+// Datadirs created before the per-network layout keep the data right in the datadir,
+// if they record the ID of the selected network, see isLegacyDataDir.
+func (c Config) NetworkDataDir() string {
+	if c.Opera.NetworkName == "" || c.Node.LegacyLayout {
+		return c.Node.DataDir
 	}
-	return cfg
+	return filepath.Join(c.Node.DataDir, c.Opera.NetworkName)
+}
+
+// isLegacyDataDir returns true if the database of the selected network is stored right in the datadir,
+// i.e. the datadir holds a database and records the ID of the selected network. A database of an
+// unrecorded or another network is left alone, and the network gets its own subdirectory.
+func isLegacyDataDir(c Config) bool {
+	if _, err := os.Stat(filepath.Join(c.Node.DataDir, c.OperaStore.Path)); err != nil {
+		return false
+	}
+	id, ok, err := readNetworkID(c.Node.DataDir)
+	if err != nil {
+		log.Warn("Failed to read the network of the datadir", "datadir", c.Node.DataDir, "err", err)
+		return false
+	}
+	if !ok {
+		log.Warn("Datadir holds a database of an unknown network, not using it", "datadir", c.Node.DataDir,
+			"hint", fmt.Sprintf("write the network ID into %s to keep using it", filepath.Join(c.Node.DataDir, NetworkIDFile)))
+		return false
+	}
+	if id != c.Opera.NetworkID {
+		log.Info("Datadir holds a database of another network", "datadir", c.Node.DataDir, "network", id)
+		return false
+	}
+	return true
+}
+
+// readNetworkID reads the network ID recorded in the dir. ok is false if there is no record.
+func readNetworkID(dir string) (id uint64, ok bool, err error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, NetworkIDFile))
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	id, err = strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid %s: %w", NetworkIDFile, err)
+	}
+	return id, true, nil
+}
+
+// RecordNetworkID records the network ID in the network datadir, or checks the recorded one,
+// so the data of one network is never opened as the data of another.
+func RecordNetworkID(c Config) error {
+	dir := c.NetworkDataDir()
+	id, ok, err := readNetworkID(dir)
+	if err != nil {
+		return err
+	}
+	if ok {
+		if id != c.Opera.NetworkID {
+			return fmt.Errorf("datadir %s holds the data of network %d, but the node is configured for network %d", dir, id, c.Opera.NetworkID)
+		}
+		return nil
+	}
+	if err := ensureDir(dir); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, NetworkIDFile), []byte(strconv.FormatUint(c.Opera.NetworkID, 10)+"\n"), 0o644)
 }
 
 // -----------------------------------------------------------------------------
@@ -219,19 +412,46 @@ func applyCLIOverrides(ctx *cli.Context, cfg *Config) {
 	if ctx.IsSet("datadir") {
 		cfg.Node.DataDir = resolvePath(ctx.String("datadir"))
 	}
+	if ctx.IsSet("keystore") {
+		cfg.Node.KeyStoreDir = resolvePath(ctx.String("keystore"))
+	}
 	if ctx.IsSet("identity") {
 		cfg.Node.Name = ctx.String("identity")
 	}
+	if ctx.Bool("readonly") {
+		cfg.Node.ReadOnly = true
+	}
 
 	if ctx.IsSet("port") {
 		cfg.Node.P2P.ListenPort = ctx.Int("port")
 	}
+	if ctx.IsSet("p2p.addr") {
+		cfg.Node.P2P.ListenAddr = ctx.String("p2p.addr")
+	}
+	if ctx.IsSet("p2p.addr6") {
+		cfg.Node.P2P.ListenAddr6 = ctx.String("p2p.addr6")
+	}
+	if ctx.IsSet("p2p.extip") {
+		cfg.Node.P2P.ExternalIP = ctx.String("p2p.extip")
+	}
+	if ctx.IsSet("p2p.extip6") {
+		cfg.Node.P2P.ExternalIP6 = ctx.String("p2p.extip6")
+	}
 	if ctx.IsSet("maxpeers") {
 		cfg.Node.P2P.MaxPeers = ctx.Int("maxpeers")
 	}
 	if ctx.IsSet("bootnodes") {
 		cfg.Node.P2P.Bootnodes = splitCSV(ctx.String("bootnodes"))
 	}
+	if ctx.IsSet("p2p.serve.inflight") {
+		cfg.Node.P2P.Serve.MaxInflight = ctx.Int("p2p.serve.inflight")
+	}
+	if ctx.IsSet("p2p.serve.peerinflight") {
+		cfg.Node.P2P.Serve.MaxInflightPerPeer = ctx.Int("p2p.serve.peerinflight")
+	}
+	if ctx.IsSet("p2p.serve.rate") {
+		cfg.Node.P2P.Serve.BytesPerSec = ctx.Float64("p2p.serve.rate") * 1024 * 1024
+	}
 
 	if ctx.Bool("http") {
 		cfg.Node.RPC.HTTPEnabled = true
@@ -263,6 +483,15 @@ func applyCLIOverrides(ctx *cli.Context, cfg *Config) {
 	if ctx.IsSet("ipc.path") {
 		cfg.Node.RPC.IPCPath = ctx.String("ipc.path")
 	}
+	if ctx.IsSet("rpc.logs.blockrange") {
+		cfg.Node.RPC.LogsBlockRange = ctx.Uint64("rpc.logs.blockrange")
+	}
+	if ctx.IsSet("rpc.logs.maxresults") {
+		cfg.Node.RPC.LogsMaxResults = ctx.Int("rpc.logs.maxresults")
+	}
+	if ctx.IsSet("rpc.logs.timeout") {
+		cfg.Node.RPC.LogsTimeout = ctx.Duration("rpc.logs.timeout")
+	}
 
 	if ctx.IsSet("log.format") {
 		cfg.Node.Logging.Format = ctx.String("log.format")
@@ -299,6 +528,9 @@ func applyCLIOverrides(ctx *cli.Context, cfg *Config) {
 		cfg.TxPool.TxLifetimeSec = ctx.Uint64("txpool.lifetime")
 	}
 
+	if ctx.IsSet("rules.template") {
+		cfg.Opera.RulesTemplate = ctx.String("rules.template")
+	}
 	if ctx.IsSet("genesis") {
 		cfg.Genesis.Path = ctx.String("genesis")
 	}
@@ -311,6 +543,100 @@ func applyCLIOverrides(ctx *cli.Context, cfg *Config) {
 		cfg.OperaStore.CacheMB = ctx.Int("cache")
 		cfg.DBs.RuntimeCache = ctx.Int("cache")
 	}
+	if ctx.IsSet("cache.warmup") {
+		cfg.OperaStore.WarmUp = ctx.Int("cache.warmup")
+	}
+	if ctx.IsSet("preset") {
+		cfg.OperaStore.Preset = ctx.String("preset")
+	}
+	if ctx.IsSet("bootstrap-url") {
+		cfg.Bootstrap.URL = ctx.String("bootstrap-url")
+	}
+	if ctx.IsSet("bootstrap-hash") {
+		cfg.Bootstrap.Hash = ctx.String("bootstrap-hash")
+	}
+	if ctx.IsSet("registry.url") {
+		cfg.Registry.URL = ctx.String("registry.url")
+	}
+	if ctx.IsSet("registry.key") {
+		cfg.Registry.Key = ctx.String("registry.key")
+	}
+	if ctx.IsSet("registry.interval") {
+		cfg.Registry.Interval = ctx.Duration("registry.interval")
+	}
+	if ctx.IsSet("indexer.dir") {
+		cfg.Indexer.Dir = resolvePath(ctx.String("indexer.dir"))
+	}
+	if ctx.IsSet("indexer.http.addr") {
+		cfg.Indexer.HTTPAddr = ctx.String("indexer.http.addr")
+	}
+	if ctx.IsSet("indexer.http.port") {
+		cfg.Indexer.HTTPPort = ctx.Int("indexer.http.port")
+	}
+	applyBackgroundOverrides(ctx, cfg)
+	if ctx.IsSet("blockproc.serial") {
+		cfg.BlockProc.Serial = ctx.Bool("blockproc.serial")
+	}
+	if ctx.IsSet("blockproc.queue") {
+		cfg.BlockProc.QueueSize = ctx.Int("blockproc.queue")
+	}
+	if ctx.IsSet("faucet") {
+		cfg.Faucet.Enabled = ctx.Bool("faucet")
+	}
+	if ctx.IsSet("validator.password") {
+		cfg.Emitter.PasswordFile = ctx.String("validator.password")
+	}
+	if ctx.IsSet("validator.shadow") {
+		cfg.Emitter.Shadow = ctx.Bool("validator.shadow")
+	}
+	if ctx.IsSet("explorer") {
+		cfg.Explorer.Enabled = ctx.Bool("explorer")
+	}
+	if ctx.IsSet("metrics.addr") {
+		cfg.Explorer.HTTPAddr = ctx.String("metrics.addr")
+	}
+	if ctx.IsSet("metrics.port") {
+		cfg.Explorer.HTTPPort = ctx.Int("metrics.port")
+	}
+	if ctx.IsSet("grpc") {
+		cfg.GRPC.Enabled = ctx.Bool("grpc")
+	}
+	if ctx.IsSet("grpc.addr") {
+		cfg.GRPC.Addr = ctx.String("grpc.addr")
+	}
+	if ctx.IsSet("grpc.port") {
+		cfg.GRPC.Port = ctx.Int("grpc.port")
+	}
+	if ctx.IsSet("faucet.http.addr") {
+		cfg.Faucet.HTTPAddr = ctx.String("faucet.http.addr")
+	}
+	if ctx.IsSet("faucet.http.port") {
+		cfg.Faucet.HTTPPort = ctx.Int("faucet.http.port")
+	}
+	if ctx.IsSet("faucet.amount") {
+		cfg.Faucet.Amount = ctx.Float64("faucet.amount")
+	}
+	if ctx.IsSet("faucet.interval") {
+		cfg.Faucet.Interval = ctx.Duration("faucet.interval")
+	}
+	if ctx.IsSet("faucet.key") {
+		cfg.Faucet.KeyFile = resolvePath(ctx.String("faucet.key"))
+	}
+	if ctx.IsSet("watchdog.rss") {
+		cfg.Watchdog.MaxRSSMB = ctx.Uint64("watchdog.rss")
+	}
+	if ctx.IsSet("watchdog.interval") {
+		cfg.Watchdog.Interval = ctx.Duration("watchdog.interval")
+	}
+	if ctx.IsSet("watchdog.minuptime") {
+		cfg.Watchdog.MinUptime = ctx.Duration("watchdog.minuptime")
+	}
+	if ctx.IsSet("telemetry.endpoint") {
+		cfg.Telemetry.Endpoint = ctx.String("telemetry.endpoint")
+	}
+	if ctx.IsSet("telemetry.interval") {
+		cfg.Telemetry.Interval = ctx.Duration("telemetry.interval")
+	}
 	if ctx.IsSet("gcmode") {
 		cfg.OperaStore.Path = ctx.String("gcmode") // placeholder; replace with real GC mode handling
 	}
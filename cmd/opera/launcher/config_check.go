@@ -0,0 +1,368 @@
+// This file implements `opera config check`: static validation of the merged config
+// (defaults + config file + flags) without starting the node. It's meant for CI of
+// infrastructure repos, so it prints a plain pass/fail report and exits with a
+// non-zero code if any check fails.
+
+package launcher
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/integration"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// CheckStatus is the outcome of a single config check.
+type CheckStatus int
+
+const (
+	CheckPass CheckStatus = iota // the check passed
+	CheckWarn                    // suspicious, but the node may still start
+	CheckFail                    // the node can't run with this config
+)
+
+func (s CheckStatus) String() string {
+	switch s {
+	case CheckPass:
+		return "PASS"
+	case CheckWarn:
+		return "WARN"
+	default:
+		return "FAIL"
+	}
+}
+
+// CheckResult is a single line of the config check report.
+type CheckResult struct {
+	Name   string
+	Status CheckStatus
+	Detail string
+}
+
+// ConfigReport is the list of all the config check results.
+type ConfigReport []CheckResult
+
+// Failed returns true if at least one check failed.
+func (r ConfigReport) Failed() bool {
+	for _, res := range r {
+		if res.Status == CheckFail {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *ConfigReport) add(name string, status CheckStatus, format string, args ...interface{}) {
+	*r = append(*r, CheckResult{
+		Name:   name,
+		Status: status,
+		Detail: fmt.Sprintf(format, args...),
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Command
+// -----------------------------------------------------------------------------
+
+func configCommand() cli.Command {
+	return cli.Command{
+		Name:     "config",
+		Usage:    "Configuration helpers",
+		Category: "MISCELLANEOUS COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:   "check",
+				Usage:  "Validate the config file and flags without starting the node",
+				Action: checkConfigAction,
+				Flags:  configFlags(),
+				Description: `
+    opera config check [--config file.toml] [flags]
+
+Loads the config file and the flags, runs all the static validators and prints
+a pass/fail report. Exits with a non-zero code if any check fails.
+
+The validators check:
+  - the network rules and the storage preset;
+  - the background throttles and the block processing pipeline;
+  - the faucet, the gRPC endpoint, the explorer and the memory watchdog;
+  - the p2p listeners and the heavy requests limits;
+  - the telemetry, the network registry and the eth_getLogs limits;
+  - the read-only mode, the port collisions and the paths writability;
+  - the validator keystore, the shadow validator mode and the validator key rotation.`,
+			},
+		},
+	}
+}
+
+func checkConfigAction(ctx *cli.Context) error {
+	cfg, err := makeConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	report := CheckConfig(cfg)
+	for _, res := range report {
+		fmt.Printf("[%s] %-10s %s\n", res.Status, res.Name, res.Detail)
+	}
+	if report.Failed() {
+		return fmt.Errorf("config check failed")
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Validators
+// -----------------------------------------------------------------------------
+
+// CheckConfig runs all the static validators against the config.
+// It never modifies the filesystem (except for a temporary probe file when checking writability).
+func CheckConfig(cfg Config) ConfigReport {
+	var report ConfigReport
+	checkRules(cfg, &report)
+	checkPreset(cfg, &report)
+	checkBackground(cfg, &report)
+	checkBlockProc(cfg, &report)
+	checkFaucet(cfg, &report)
+	checkGRPC(cfg, &report)
+	checkExplorer(cfg, &report)
+	checkWatchdog(cfg, &report)
+	checkP2P(cfg, &report)
+	checkServeLimits(cfg, &report)
+	checkTelemetry(cfg, &report)
+	checkRegistry(cfg, &report)
+	checkRPCLogs(cfg, &report)
+	checkReadOnly(cfg, &report)
+	checkPorts(cfg, &report)
+	checkPaths(cfg, &report)
+	checkKeystore(cfg, &report)
+	checkShadow(cfg, &report)
+	checkKeyRotation(cfg, &report)
+	return report
+}
+
+// NetworkRules returns the built-in rules of the configured network, or the rules of the
+// configured template for other networks. An invalid template falls back to the fakenet
+// rules, checkRules reports it.
+func NetworkRules(cfg OperaConfig) opera.Rules {
+	switch cfg.NetworkID {
+	case opera.MainNetworkID:
+		return opera.MainNetRules()
+	case opera.TestNetworkID:
+		return opera.TestNetRules()
+	default:
+		if cfg.RulesTemplate != "" {
+			if rules, err := templateRules(cfg); err == nil {
+				return rules
+			}
+		}
+		rules := opera.FakeNetRules()
+		rules.NetworkID = cfg.NetworkID
+		return rules
+	}
+}
+
+// templateRules returns the rules of the configured template for the network.
+func templateRules(cfg OperaConfig) (opera.Rules, error) {
+	return opera.TemplateRules(cfg.RulesTemplate, opera.TemplateKnobs{NetworkID: cfg.NetworkID})
+}
+
+func checkRules(cfg Config, report *ConfigReport) {
+	if cfg.Opera.RulesTemplate != "" {
+		switch cfg.Opera.NetworkID {
+		case opera.MainNetworkID, opera.TestNetworkID:
+			report.add("rules", CheckWarn, "rules template %q is ignored on %s", cfg.Opera.RulesTemplate, cfg.Opera.NetworkName)
+		default:
+			if _, err := templateRules(cfg.Opera); err != nil {
+				report.add("rules", CheckFail, "%v", err)
+				return
+			}
+		}
+	}
+	rules := NetworkRules(cfg.Opera)
+	if err := rules.Validate(); err != nil {
+		report.add("rules", CheckFail, "%s rules are invalid: %v", rules.Name, err)
+		return
+	}
+	report.add("rules", CheckPass, "%s rules, network ID %d", rules.Name, rules.NetworkID)
+}
+
+func checkPreset(cfg Config, report *ConfigReport) {
+	if cfg.OperaStore.Preset == "" {
+		report.add("preset", CheckPass, "no preset selected")
+		return
+	}
+	preset, err := integration.GetPresetByName(cfg.OperaStore.Preset)
+	if err != nil {
+		report.add("preset", CheckFail, "%v", err)
+		return
+	}
+	if cfg.OperaStore.CacheMB < preset.CacheMB {
+		report.add("preset", CheckWarn, "cache %d MB is below %d MB of the %q preset", cfg.OperaStore.CacheMB, preset.CacheMB, preset.Name)
+		return
+	}
+	report.add("preset", CheckPass, "%q preset", preset.Name)
+}
+
+type endpoint struct {
+	name string
+	addr string
+	port int
+}
+
+func isWildcardAddr(addr string) bool {
+	return addr == "" || addr == "0.0.0.0" || addr == "::" || addr == "[::]"
+}
+
+func (e endpoint) collides(o endpoint) bool {
+	if e.port == 0 || e.port != o.port {
+		return false
+	}
+	return e.addr == o.addr || isWildcardAddr(e.addr) || isWildcardAddr(o.addr)
+}
+
+func checkPorts(cfg Config, report *ConfigReport) {
+	endpoints := []endpoint{{"p2p", cfg.Node.P2P.ListenAddr, cfg.Node.P2P.ListenPort}}
+	if cfg.Node.P2P.ListenAddr6 != "" {
+		endpoints = append(endpoints, endpoint{"p2p6", cfg.Node.P2P.ListenAddr6, cfg.Node.P2P.ListenPort})
+	}
+	if cfg.Node.RPC.HTTPEnabled {
+		endpoints = append(endpoints, endpoint{"http", cfg.Node.RPC.HTTPAddr, cfg.Node.RPC.HTTPPort})
+	}
+	if cfg.Node.RPC.EnableWS {
+		endpoints = append(endpoints, endpoint{"ws", cfg.Node.RPC.WSAddr, cfg.Node.RPC.WSPort})
+	}
+	if cfg.Faucet.Enabled {
+		endpoints = append(endpoints, endpoint{"faucet", cfg.Faucet.HTTPAddr, cfg.Faucet.HTTPPort})
+	}
+	if cfg.GRPC.Enabled {
+		endpoints = append(endpoints, endpoint{"grpc", cfg.GRPC.Addr, cfg.GRPC.Port})
+	}
+	if cfg.Explorer.Enabled {
+		endpoints = append(endpoints, endpoint{"explorer", cfg.Explorer.HTTPAddr, cfg.Explorer.HTTPPort})
+	}
+
+	ok := true
+	for i, a := range endpoints {
+		if a.port < 0 || a.port > 65535 {
+			report.add("ports", CheckFail, "%s port %d is out of range", a.name, a.port)
+			ok = false
+			continue
+		}
+		for _, b := range endpoints[i+1:] {
+			// HTTP and WS may share the same listener
+			if a.name == "http" && b.name == "ws" && a.addr == b.addr {
+				continue
+			}
+			// the IPv4 and IPv6 p2p sockets are bound separately and don't collide
+			if a.name == "p2p" && b.name == "p2p6" {
+				continue
+			}
+			if a.collides(b) {
+				report.add("ports", CheckFail, "%s and %s both listen on port %d", a.name, b.name, a.port)
+				ok = false
+			}
+		}
+	}
+	if ok {
+		names := make([]string, 0, len(endpoints))
+		for _, e := range endpoints {
+			names = append(names, fmt.Sprintf("%s=%d", e.name, e.port))
+		}
+		report.add("ports", CheckPass, "%s", strings.Join(names, " "))
+	}
+}
+
+// checkWritable reports whether files may be created in dir. If dir doesn't exist yet,
+// the nearest existing parent is probed, as the node creates missing directories itself.
+func checkWritable(dir string) error {
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s isn't a directory", dir)
+			}
+			break
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return fmt.Errorf("no existing parent directory")
+		}
+		dir = parent
+	}
+	f, err := ioutil.TempFile(dir, ".opera-check-")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+func checkPaths(cfg Config, report *ConfigReport) {
+	if cfg.Node.ReadOnly {
+		if _, err := os.Stat(cfg.Node.DataDir); err != nil {
+			report.add("datadir", CheckFail, "%v", err)
+		} else {
+			report.add("datadir", CheckPass, "%s (read-only)", cfg.Node.DataDir)
+		}
+	} else if err := checkWritable(cfg.Node.DataDir); err != nil {
+		report.add("datadir", CheckFail, "%s isn't writable: %v", cfg.Node.DataDir, err)
+	} else {
+		report.add("datadir", CheckPass, "%s", cfg.Node.DataDir)
+	}
+
+	// fakenet generates its genesis in place
+	if !cfg.Opera.FakeNet && cfg.Genesis.Path != "" {
+		if alloc, err := evmcore.LoadGenesisAlloc(cfg.Genesis.Path); err != nil {
+			report.add("genesis", CheckFail, "%s: %v", cfg.Genesis.Path, err)
+		} else {
+			report.add("genesis", CheckPass, "%s, %d accounts", cfg.Genesis.Path, len(alloc))
+		}
+	}
+}
+
+// keyStoreDir returns the configured keystore directory.
+// By default, every network has its own keystore, so keys aren't reused across networks.
+func keyStoreDir(cfg Config) string {
+	if cfg.Node.KeyStoreDir != "" {
+		return cfg.Node.KeyStoreDir
+	}
+	return filepath.Join(cfg.NetworkDataDir(), "keystore")
+}
+
+func checkKeystore(cfg Config, report *ConfigReport) {
+	if !cfg.Emitter.Enabled {
+		report.add("keystore", CheckPass, "validator mode is off")
+		return
+	}
+	if cfg.Emitter.ValidatorID == 0 {
+		report.add("keystore", CheckFail, "validator mode is on, but the validator ID isn't set")
+		return
+	}
+	dir := keyStoreDir(cfg)
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		report.add("keystore", CheckFail, "validator mode is on, but keystore is unavailable: %v", err)
+		return
+	}
+	keys := 0
+	for _, f := range files {
+		if !f.IsDir() && !strings.HasPrefix(f.Name(), ".") {
+			keys++
+		}
+	}
+	if keys == 0 {
+		report.add("keystore", CheckFail, "validator mode is on, but %s contains no keys", dir)
+		return
+	}
+	report.add("keystore", CheckPass, "%d key(s) in %s", keys, dir)
+}
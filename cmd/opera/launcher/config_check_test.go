@@ -0,0 +1,139 @@
+package launcher
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+// checkCase is a scenario of `opera config check`: the config is built from the command line
+// arguments (the datadir is added), tweaked by setup, and the named checks must have the statuses.
+type checkCase struct {
+	name  string
+	args  []string
+	setup func(cfg *Config) // optional tweaks which have no flags yet
+	want  map[string]CheckStatus
+}
+
+// configFromArgs builds the config from the command line arguments, as the opera command does.
+func configFromArgs(t *testing.T, args []string) Config {
+	t.Helper()
+	app := cli.NewApp()
+	app.HideHelp = true
+	app.HideVersion = true
+	app.Flags = configFlags()
+	var cfg Config
+	app.Action = func(ctx *cli.Context) error {
+		cfg = MakeAllConfigs(ctx)
+		return nil
+	}
+	if err := app.Run(append([]string{"opera"}, args...)); err != nil {
+		t.Fatalf("app.Run failed: %v", err)
+	}
+	return cfg
+}
+
+// statusOf returns the status of the named check, failing the test if it's missing.
+func statusOf(t *testing.T, report ConfigReport, name string) CheckStatus {
+	t.Helper()
+	for _, res := range report {
+		if res.Name == name {
+			return res.Status
+		}
+	}
+	t.Fatalf("check %q is missing from the report", name)
+	return CheckFail
+}
+
+// runCheckCases runs the config check scenarios.
+func runCheckCases(t *testing.T, cases []checkCase) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "opera-config-check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := configFromArgs(t, append([]string{"--datadir", filepath.Join(dir, "node")}, tt.args...))
+			if tt.setup != nil {
+				tt.setup(&cfg)
+			}
+			report := CheckConfig(cfg)
+			for name, want := range tt.want {
+				if got := statusOf(t, report, name); got != want {
+					t.Errorf("check %q is %s, want %s: %+v", name, got, want, report)
+				}
+			}
+		})
+	}
+}
+
+// TestCheckConfig verifies that `opera config check` accepts the default config,
+// and that the common validators catch broken configs.
+func TestCheckConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opera-config-check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	genesis := filepath.Join(dir, "genesis.json")
+	if err := ioutil.WriteFile(genesis, []byte(`{"alloc": {"0x0000000000000000000000000000000000001000": {"balance": "1000"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	badGenesis := filepath.Join(dir, "bad-genesis.json")
+	if err := ioutil.WriteFile(badGenesis, []byte(`{"alloc": {"0x1000": {"nonce": "1"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := configFromArgs(t, []string{"--datadir", filepath.Join(dir, "node")})
+	if report := CheckConfig(cfg); report.Failed() {
+		t.Fatalf("default config failed: %+v", report)
+	}
+
+	runCheckCases(t, []checkCase{
+		{
+			name: "p2p and http port collision",
+			args: []string{"--port", "18545"},
+			want: map[string]CheckStatus{"ports": CheckFail},
+		},
+		{
+			name: "unknown preset",
+			args: []string{"--preset", "turbo"},
+			want: map[string]CheckStatus{"preset": CheckFail},
+		},
+		{
+			name: "validator without keystore",
+			args: []string{"--keystore", filepath.Join(dir, "missing")},
+			setup: func(cfg *Config) {
+				cfg.Emitter.Enabled = true
+				cfg.Emitter.ValidatorID = 1
+			},
+			want: map[string]CheckStatus{"keystore": CheckFail},
+		},
+		{
+			name: "unknown rules template",
+			args: []string{"--network", "fakenet", "--rules.template", "turbo"},
+			want: map[string]CheckStatus{"rules": CheckFail},
+		},
+		{
+			name: "rules template on mainnet",
+			args: []string{"--network", "mainnet", "--rules.template", "low-latency"},
+			want: map[string]CheckStatus{"rules": CheckWarn},
+		},
+		{
+			name: "genesis allocation",
+			args: []string{"--network", "mainnet", "--genesis", genesis},
+			want: map[string]CheckStatus{"genesis": CheckPass},
+		},
+		{
+			name: "invalid genesis allocation",
+			args: []string{"--network", "mainnet", "--genesis", badGenesis},
+			want: map[string]CheckStatus{"genesis": CheckFail},
+		},
+	})
+}
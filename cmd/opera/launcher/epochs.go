@@ -0,0 +1,164 @@
+package launcher
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/rony4d/go-opera-asset/ethapi"
+)
+
+var (
+	epochsRPCFlag = cli.StringFlag{
+		Name:  "rpc",
+		Usage: "RPC endpoint (HTTP, WS or IPC) of the node to fetch epoch summaries from",
+		Value: "http://localhost:18545",
+	}
+	epochsFromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First epoch to export (defaults to 1)",
+		Value: 1,
+	}
+	epochsToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last epoch to export (defaults to the last sealed epoch)",
+	}
+	epochsFormatFlag = cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format (csv|json)",
+		Value: "csv",
+	}
+	epochsOutputFlag = cli.StringFlag{
+		Name:  "output",
+		Usage: "Output file (defaults to stdout)",
+	}
+)
+
+func epochsCommand() cli.Command {
+	return cli.Command{
+		Name:     "epochs",
+		Usage:    "Sealed epochs statistics",
+		Category: "MISCELLANEOUS COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:   "export",
+				Usage:  "Export summaries of sealed epochs as CSV or JSON",
+				Action: exportEpochsAction,
+				Flags: []cli.Flag{
+					epochsRPCFlag,
+					epochsFromFlag,
+					epochsToFlag,
+					epochsFormatFlag,
+					epochsOutputFlag,
+				},
+				Description: `
+    opera epochs export [--rpc url] [--from N] [--to M] [--format csv|json] [--output file]
+
+Fetches the summaries of sealed epochs from a running node via abft_getEpochSummary
+and writes them as CSV (one row per epoch) or JSON (an array of summaries).`,
+			},
+		},
+	}
+}
+
+func exportEpochsAction(ctx *cli.Context) error {
+	format := ctx.String(epochsFormatFlag.Name)
+	if format != "csv" && format != "json" {
+		return fmt.Errorf("unknown format %q, must be csv or json", format)
+	}
+
+	client, err := rpc.Dial(ctx.String(epochsRPCFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	from, to := ctx.Uint64(epochsFromFlag.Name), ctx.Uint64(epochsToFlag.Name)
+	if to == 0 {
+		var latest *ethapi.RPCEpochSummary
+		if err := client.CallContext(context.Background(), &latest, "abft_getEpochSummary", "latest"); err != nil {
+			return err
+		}
+		if latest == nil {
+			return errors.New("no sealed epochs yet")
+		}
+		to = uint64(latest.Epoch)
+	}
+	if from == 0 || from > to {
+		return fmt.Errorf("invalid epochs range [%d, %d]", from, to)
+	}
+
+	var summaries []*ethapi.RPCEpochSummary
+	for epoch := from; epoch <= to; epoch++ {
+		var s *ethapi.RPCEpochSummary
+		if err := client.CallContext(context.Background(), &s, "abft_getEpochSummary", hexutil.Uint64(epoch)); err != nil {
+			return fmt.Errorf("failed to fetch epoch %d: %w", epoch, err)
+		}
+		if s == nil {
+			return fmt.Errorf("epoch %d isn't sealed", epoch)
+		}
+		summaries = append(summaries, s)
+	}
+
+	out := io.Writer(os.Stdout)
+	if path := ctx.String(epochsOutputFlag.Name); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	return WriteEpochSummaries(out, format, summaries)
+}
+
+// epochsCSVHeader is the header row of the CSV export.
+var epochsCSVHeader = []string{
+	"epoch", "start", "end", "duration", "first_block", "last_block", "blocks",
+	"gas", "events", "validators", "cheaters", "rules_hash",
+}
+
+// WriteEpochSummaries writes the epoch summaries in the given format (csv or json).
+// The CSV export has one row per epoch with the aggregated stats,
+// the JSON export contains the full summaries, including the per-validator stats.
+func WriteEpochSummaries(w io.Writer, format string, summaries []*ethapi.RPCEpochSummary) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if summaries == nil {
+			summaries = []*ethapi.RPCEpochSummary{}
+		}
+		return enc.Encode(summaries)
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(epochsCSVHeader); err != nil {
+			return err
+		}
+		for _, s := range summaries {
+			u := func(v uint64) string { return strconv.FormatUint(v, 10) }
+			row := []string{
+				u(uint64(s.Epoch)), u(uint64(s.Start)), u(uint64(s.End)), u(uint64(s.Duration)),
+				u(uint64(s.FirstBlock)), u(uint64(s.LastBlock)), u(uint64(s.Blocks)),
+				u(uint64(s.Gas)), u(uint64(s.Events)), strconv.Itoa(len(s.Validators)), strconv.Itoa(len(s.Cheaters)),
+				s.RulesHash.Hex(),
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unknown format %q, must be csv or json", format)
+	}
+}
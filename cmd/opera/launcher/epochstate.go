@@ -0,0 +1,230 @@
+// This file implements `opera export epochstate` and `opera import epochstate`, which move a
+// validator's node to another machine without a full resync. The old node exports its block and
+// epoch states, the validator signs them with its key, and the new node verifies the hashes and
+// the signature before it takes the states over and resumes from them.
+
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+)
+
+// ImportedEpochStateFile is the name of the imported snapshot in the network datadir,
+// the node resumes from it on its first start.
+const ImportedEpochStateFile = "epochstate.rlp"
+
+var errChainDataExists = errors.New("the node already has a database, the epoch state may be imported only into a fresh datadir")
+
+// ExportEpochState fetches the states of the epoch (the current one if epoch is 0) from a running node
+// and signs them by the validator's key.
+func ExportEpochState(ctx context.Context, client *rpc.Client, epoch idx.Epoch, validator idx.ValidatorID, key *ecdsa.PrivateKey) (*iblockproc.StateSnapshot, error) {
+	var arg interface{} = "latest"
+	if epoch != 0 {
+		arg = hexutil.Uint64(epoch)
+	}
+	var raw hexutil.Bytes
+	if err := client.CallContext(ctx, &raw, "debug_exportEpochState", arg); err != nil {
+		return nil, err
+	}
+	s, err := iblockproc.ReadStateSnapshot(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Sign(validator, key); err != nil {
+		return nil, err
+	}
+	return s, s.Verify()
+}
+
+// ImportEpochState verifies the snapshot and places it into the network datadir of the config.
+// The snapshot must be of the configured network and, in the validator mode, of the configured validator.
+func ImportEpochState(cfg Config, r io.Reader) (*iblockproc.StateSnapshot, error) {
+	if cfg.Node.ReadOnly {
+		return nil, ErrReadOnly
+	}
+	s, err := iblockproc.ReadStateSnapshot(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Verify(); err != nil {
+		return nil, err
+	}
+	if network := s.EpochState.Rules.NetworkID; network != cfg.Opera.NetworkID {
+		return nil, fmt.Errorf("snapshot is of network %d, but the node is configured for network %d", network, cfg.Opera.NetworkID)
+	}
+	if cfg.Emitter.Enabled && idx.ValidatorID(cfg.Emitter.ValidatorID) != s.Validator {
+		return nil, fmt.Errorf("snapshot is signed by validator %d, but the node is configured for validator %d", s.Validator, cfg.Emitter.ValidatorID)
+	}
+
+	datadir := cfg.NetworkDataDir()
+	if files, err := ioutil.ReadDir(filepath.Join(datadir, cfg.OperaStore.Path)); err == nil && len(files) != 0 {
+		return nil, errChainDataExists
+	}
+	if err := ensureDir(datadir); err != nil {
+		return nil, err
+	}
+	// write to a temporary file first, so a failure never leaves a partial snapshot behind
+	path := filepath.Join(datadir, ImportedEpochStateFile)
+	var buf bytes.Buffer
+	if err := iblockproc.WriteStateSnapshot(&buf, s); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(path+".tmp", buf.Bytes(), 0600); err != nil {
+		return nil, err
+	}
+	return s, os.Rename(path+".tmp", path)
+}
+
+// -----------------------------------------------------------------------------
+// Commands
+// -----------------------------------------------------------------------------
+
+var (
+	epochStateRPCFlag = cli.StringFlag{
+		Name:  "rpc",
+		Usage: "RPC endpoint (HTTP, WS or IPC) of the node to export the epoch state from",
+		Value: "http://localhost:18545",
+	}
+	epochStateEpochFlag = cli.Uint64Flag{
+		Name:  "epoch",
+		Usage: "Epoch to export (defaults to the current epoch)",
+	}
+	epochStateValidatorFlag = cli.UintFlag{
+		Name:  "validator",
+		Usage: "ID of the validator signing the snapshot",
+	}
+	epochStateKeyFlag = cli.StringFlag{
+		Name:  "key",
+		Usage: "File with the hex private key of the validator",
+	}
+	epochStateOutputFlag = cli.StringFlag{
+		Name:  "output",
+		Usage: "Output file",
+		Value: "epochstate.rlp",
+	}
+)
+
+func exportCommand() cli.Command {
+	return cli.Command{
+		Name:     "export",
+		Usage:    "Export the node data",
+		Category: "MISCELLANEOUS COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:   "epochstate",
+				Usage:  "Export the signed block and epoch states for moving the validator to another machine",
+				Action: exportEpochStateAction,
+				Flags: []cli.Flag{
+					epochStateRPCFlag,
+					epochStateEpochFlag,
+					epochStateValidatorFlag,
+					epochStateKeyFlag,
+					epochStateOutputFlag,
+				},
+				Description: `
+    opera export epochstate --validator ID --key file [--epoch N] [--rpc url] [--output file]
+
+Fetches the block and epoch states of the epoch from a running node via debug_exportEpochState,
+signs them by the validator's key and writes the snapshot to the output file.
+Stop the old node right after the export, so it doesn't emit events the new node doesn't know about.`,
+			},
+		},
+	}
+}
+
+func importCommand() cli.Command {
+	return cli.Command{
+		Name:     "import",
+		Usage:    "Import the node data",
+		Category: "MISCELLANEOUS COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:      "epochstate",
+				Usage:     "Import the block and epoch states exported by `opera export epochstate`",
+				ArgsUsage: "<file>",
+				Action:    importEpochStateAction,
+				Flags:     configFlags(),
+				Description: `
+    opera import epochstate <file> [flags]
+
+Verifies the hashes and the validator's signature of the snapshot, checks that it's of the
+configured network (and validator, in the validator mode), and places it into the fresh
+datadir of the node, which resumes from it on its first start.`,
+			},
+		},
+	}
+}
+
+func exportEpochStateAction(ctx *cli.Context) error {
+	validator := idx.ValidatorID(ctx.Uint(epochStateValidatorFlag.Name))
+	if validator == 0 {
+		return fmt.Errorf("--%s isn't specified", epochStateValidatorFlag.Name)
+	}
+	keyFile := ctx.String(epochStateKeyFlag.Name)
+	if keyFile == "" {
+		return fmt.Errorf("--%s isn't specified", epochStateKeyFlag.Name)
+	}
+	key, err := crypto.LoadECDSA(keyFile)
+	if err != nil {
+		return err
+	}
+	epoch := idx.Epoch(ctx.Uint64(epochStateEpochFlag.Name))
+
+	client, err := rpc.Dial(ctx.String(epochStateRPCFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	s, err := ExportEpochState(context.Background(), client, epoch, validator, key)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := iblockproc.WriteStateSnapshot(&buf, s); err != nil {
+		return err
+	}
+	output := ctx.String(epochStateOutputFlag.Name)
+	if err := ioutil.WriteFile(output, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+	fmt.Printf("Exported epoch %d as of block %d to %s\n", s.EpochState.Epoch, s.BlockState.LastBlock.Idx, output)
+	return nil
+}
+
+func importEpochStateAction(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return errors.New("the snapshot file isn't specified")
+	}
+	cfg, err := makeConfig(ctx)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	s, err := ImportEpochState(cfg, f)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported epoch %d as of block %d, signed by validator %d\n", s.EpochState.Epoch, s.BlockState.LastBlock.Idx, s.Validator)
+	return nil
+}
@@ -0,0 +1,61 @@
+// This file configures the explorer lite, a web UI of the recent blocks, the validators
+// activity and the transactions, served under /explorer/ of the metrics HTTP port, see explorer.
+
+package launcher
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/rony4d/go-opera-asset/ethapi"
+	"github.com/rony4d/go-opera-asset/explorer"
+)
+
+// ExplorerPath is the path the explorer is served under.
+const ExplorerPath = "/explorer/"
+
+// ExplorerConfig is the config of the explorer lite.
+// The address is the one of the metrics HTTP server (--metrics.addr and --metrics.port).
+type ExplorerConfig struct {
+	Enabled  bool
+	HTTPAddr string
+	HTTPPort int
+}
+
+// MountExplorer serves the explorer over the backend of the JSON-RPC APIs under ExplorerPath of the mux.
+func MountExplorer(mux *http.ServeMux, b ethapi.Backend) {
+	mux.Handle(ExplorerPath, http.StripPrefix(ExplorerPath[:len(ExplorerPath)-1], explorer.New(b).Handler()))
+}
+
+// NewExplorerServer creates the HTTP server of the explorer, for the nodes which don't serve the metrics.
+func NewExplorerServer(cfg Config, b ethapi.Backend) *http.Server {
+	mux := http.NewServeMux()
+	MountExplorer(mux, b)
+	return &http.Server{
+		Addr:    net.JoinHostPort(cfg.Explorer.HTTPAddr, strconv.Itoa(cfg.Explorer.HTTPPort)),
+		Handler: mux,
+	}
+}
+
+func checkExplorer(cfg Config, report *ConfigReport) {
+	if !cfg.Explorer.Enabled {
+		report.add("explorer", CheckPass, "disabled")
+		return
+	}
+	if cfg.Explorer.HTTPPort <= 0 || cfg.Explorer.HTTPPort > 65535 {
+		report.add("explorer", CheckFail, "explorer port %d is out of range", cfg.Explorer.HTTPPort)
+		return
+	}
+	url := fmt.Sprintf("http://%s%s", net.JoinHostPort(cfg.Explorer.HTTPAddr, strconv.Itoa(cfg.Explorer.HTTPPort)), ExplorerPath)
+	if !isLoopback(cfg.Explorer.HTTPAddr) {
+		report.add("explorer", CheckWarn, "%s is served on a public interface, it has no rate limits", url)
+		return
+	}
+	if !cfg.Opera.FakeNet {
+		report.add("explorer", CheckWarn, "%s, it's meant for fakenet development", url)
+		return
+	}
+	report.add("explorer", CheckPass, "%s", url)
+}
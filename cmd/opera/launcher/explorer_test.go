@@ -0,0 +1,24 @@
+package launcher
+
+import "testing"
+
+// TestCheckExplorer verifies the config check of the block explorer.
+func TestCheckExplorer(t *testing.T) {
+	runCheckCases(t, []checkCase{
+		{
+			name: "explorer on fakenet",
+			args: []string{"--network", "fakenet", "--explorer"},
+			want: map[string]CheckStatus{"explorer": CheckPass, "ports": CheckPass},
+		},
+		{
+			name: "explorer on mainnet",
+			args: []string{"--network", "mainnet", "--explorer"},
+			want: map[string]CheckStatus{"explorer": CheckWarn},
+		},
+		{
+			name: "explorer on the rpc port",
+			args: []string{"--network", "fakenet", "--explorer", "--metrics.port", "18545"},
+			want: map[string]CheckStatus{"explorer": CheckPass, "ports": CheckFail},
+		},
+	})
+}
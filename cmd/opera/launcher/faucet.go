@@ -0,0 +1,88 @@
+// This file configures the built-in faucet of the fake and test networks.
+// The faucet sends funds from a dev account over its own HTTP endpoint, so dapps may be
+// developed against a local fakenet node without handing out keys.
+
+package launcher
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/faucet"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// FaucetConfig is the config of the faucet.
+type FaucetConfig struct {
+	Enabled  bool
+	HTTPAddr string
+	HTTPPort int
+	Amount   float64       // FTM sent per request
+	Interval time.Duration // minimum interval between the requests of a client IP
+	KeyFile  string        // hex key of the funded account, the first fake validator on fakenet if empty
+}
+
+var errFaucetKey = errors.New("faucet key file isn't specified")
+
+// FaucetKey returns the key of the account the faucet sends funds from.
+func FaucetKey(cfg Config) (*ecdsa.PrivateKey, error) {
+	if cfg.Faucet.KeyFile != "" {
+		return crypto.LoadECDSA(cfg.Faucet.KeyFile)
+	}
+	if cfg.Opera.FakeNet {
+		return evmcore.FakeKey(1), nil
+	}
+	return nil, errFaucetKey
+}
+
+// FaucetParams converts the config into the faucet parameters of the network.
+func FaucetParams(cfg Config) faucet.Config {
+	amount, _ := new(big.Float).Mul(big.NewFloat(cfg.Faucet.Amount), big.NewFloat(params.Ether)).Int(nil)
+	return faucet.Config{
+		Amount:   amount,
+		Interval: cfg.Faucet.Interval,
+		GasPrice: NetworkRules(cfg.Opera).Economy.MinGasPrice,
+	}
+}
+
+// CheckFaucetConfig checks that the faucet may run: it's never enabled on the mainnet,
+// and it has a funded account to send from.
+func CheckFaucetConfig(cfg Config) error {
+	if !cfg.Faucet.Enabled {
+		return nil
+	}
+	if !cfg.Opera.FakeNet && cfg.Opera.NetworkID != opera.TestNetworkID {
+		return fmt.Errorf("faucet is allowed only on fakenet and testnet, not on network %d", cfg.Opera.NetworkID)
+	}
+	if cfg.Faucet.HTTPPort <= 0 || cfg.Faucet.HTTPPort > 65535 {
+		return fmt.Errorf("faucet port %d is out of range", cfg.Faucet.HTTPPort)
+	}
+	if cfg.Faucet.Amount <= 0 {
+		return fmt.Errorf("faucet amount %v must be positive", cfg.Faucet.Amount)
+	}
+	if _, err := FaucetKey(cfg); err != nil {
+		return fmt.Errorf("faucet key: %w", err)
+	}
+	return nil
+}
+
+func checkFaucet(cfg Config, report *ConfigReport) {
+	if !cfg.Faucet.Enabled {
+		report.add("faucet", CheckPass, "disabled")
+		return
+	}
+	if err := CheckFaucetConfig(cfg); err != nil {
+		report.add("faucet", CheckFail, "%v", err)
+		return
+	}
+	key, _ := FaucetKey(cfg)
+	report.add("faucet", CheckPass, "%v FTM per %v from %s", cfg.Faucet.Amount, cfg.Faucet.Interval,
+		crypto.PubkeyToAddress(key.PublicKey).Hex())
+}
@@ -0,0 +1,24 @@
+package launcher
+
+import "testing"
+
+// TestCheckFaucet verifies the config check of the faucet.
+func TestCheckFaucet(t *testing.T) {
+	runCheckCases(t, []checkCase{
+		{
+			name: "faucet on mainnet",
+			args: []string{"--network", "mainnet", "--faucet"},
+			want: map[string]CheckStatus{"faucet": CheckFail},
+		},
+		{
+			name: "faucet on fakenet",
+			args: []string{"--network", "fakenet", "--faucet", "--faucet.amount", "2.5"},
+			want: map[string]CheckStatus{"faucet": CheckPass, "ports": CheckPass},
+		},
+		{
+			name: "faucet port collision",
+			args: []string{"--network", "fakenet", "--faucet", "--faucet.http.port", "18545"},
+			want: map[string]CheckStatus{"ports": CheckFail},
+		},
+	})
+}
@@ -0,0 +1,46 @@
+// This file configures the optional gRPC endpoint, which serves the key JSON-RPC methods
+// (blocks, transactions, events) over protobuf on its own port, see grpcapi.
+
+package launcher
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/rony4d/go-opera-asset/ethapi"
+	"github.com/rony4d/go-opera-asset/grpcapi"
+)
+
+// GRPCConfig is the config of the gRPC endpoint.
+type GRPCConfig struct {
+	Enabled bool
+	Addr    string
+	Port    int
+}
+
+// NewGRPCServer creates the gRPC server over the same backend as the JSON-RPC APIs.
+func NewGRPCServer(cfg Config, b ethapi.Backend) *http.Server {
+	return &http.Server{
+		Addr:    net.JoinHostPort(cfg.GRPC.Addr, strconv.Itoa(cfg.GRPC.Port)),
+		Handler: grpcapi.NewServer(b).Handler(),
+	}
+}
+
+func checkGRPC(cfg Config, report *ConfigReport) {
+	if !cfg.GRPC.Enabled {
+		report.add("grpc", CheckPass, "disabled")
+		return
+	}
+	if cfg.GRPC.Port <= 0 || cfg.GRPC.Port > 65535 {
+		report.add("grpc", CheckFail, "gRPC port %d is out of range", cfg.GRPC.Port)
+		return
+	}
+	addr := fmt.Sprintf("%s:%d", cfg.GRPC.Addr, cfg.GRPC.Port)
+	if !isLoopback(cfg.GRPC.Addr) {
+		report.add("grpc", CheckWarn, "%s serves plaintext h2c on a public interface, put it behind a TLS proxy", addr)
+		return
+	}
+	report.add("grpc", CheckPass, "%s", addr)
+}
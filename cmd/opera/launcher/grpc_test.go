@@ -0,0 +1,19 @@
+package launcher
+
+import "testing"
+
+// TestCheckGRPC verifies the config check of the gRPC endpoint.
+func TestCheckGRPC(t *testing.T) {
+	runCheckCases(t, []checkCase{
+		{
+			name: "grpc port collision",
+			args: []string{"--grpc", "--grpc.port", "18545"},
+			want: map[string]CheckStatus{"ports": CheckFail},
+		},
+		{
+			name: "grpc on public interface",
+			args: []string{"--grpc", "--grpc.addr", "0.0.0.0"},
+			want: map[string]CheckStatus{"grpc": CheckWarn, "ports": CheckPass},
+		},
+	})
+}
@@ -0,0 +1,181 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb/table"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/rony4d/go-opera-asset/indexer"
+	"github.com/rony4d/go-opera-asset/integration"
+)
+
+const (
+	// indexerDBName is the name of the indexer database in the indexer dir.
+	indexerDBName = "indexer"
+	// indexerPollInterval is how often the indexer looks for new blocks once it catches up.
+	indexerPollInterval = time.Second
+)
+
+// IndexerConfig is the config of the secondary indexer process.
+// The indexer attaches to the datadir of a running node read-only and keeps its own
+// databases and RPC endpoint, so heavy indexes don't slow down the node.
+type IndexerConfig struct {
+	Dir      string // indexer databases, empty means <network datadir>/indexer
+	HTTPAddr string
+	HTTPPort int
+}
+
+// IndexerDir returns the directory of the indexer databases.
+func (c Config) IndexerDir() string {
+	if c.Indexer.Dir != "" {
+		return c.Indexer.Dir
+	}
+	return filepath.Join(c.NetworkDataDir(), "indexer")
+}
+
+// chainDataDir returns the directory of the node's chain database.
+func (c Config) chainDataDir() string {
+	return filepath.Join(c.NetworkDataDir(), c.OperaStore.Path)
+}
+
+func indexerCommand() cli.Command {
+	return cli.Command{
+		Name:     "indexer",
+		Usage:    "Run the secondary indexer attached to an existing datadir",
+		Category: "MISCELLANEOUS COMMANDS",
+		Action:   indexerAction,
+		Flags:    configFlags(),
+		Description: `
+    opera indexer [--datadir dir] [--indexer.dir dir] [--indexer.http.port port]
+
+Attaches to the blocks database of the datadir read-only and builds the heavy indexes
+(token transfers, logs by contract) in separate databases. The progress is stored in
+the indexer databases, so the indexer resumes where it stopped. The indexes are served
+by the indexer's own HTTP-RPC endpoint (indexer_getTokenTransfers, indexer_getLogs,
+indexer_progress).`,
+	}
+}
+
+func indexerAction(ctx *cli.Context) error {
+	cfg, err := makeConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if err := CheckIndexerConfig(cfg); err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+	go func() {
+		select {
+		case <-sigc:
+			log.Info("Stopping indexer")
+			cancel()
+		case <-runCtx.Done():
+		}
+	}()
+
+	err = RunIndexer(runCtx, cfg)
+	if err == context.Canceled {
+		return nil
+	}
+	return err
+}
+
+// RunIndexer opens the blocks database of the node read-only and the indexer databases,
+// serves the indexer RPC and indexes the blocks until the context is cancelled.
+func RunIndexer(ctx context.Context, cfg Config) error {
+	cacheFdLimit := func(string) (int, int) {
+		return cfg.OperaStore.CacheMB * 1024 * 1024, cfg.OperaStore.Handles
+	}
+	chainID := new(big.Int).SetUint64(cfg.Opera.NetworkID)
+	src, err := indexer.NewDatadirSource(integration.DBProducer(cfg.chainDataDir(), cacheFdLimit, true), chainID)
+	if err != nil {
+		return fmt.Errorf("failed to open the blocks of %s: %w", cfg.chainDataDir(), err)
+	}
+	defer src.Close()
+
+	if err := ensureDir(cfg.IndexerDir()); err != nil {
+		return err
+	}
+	db, err := integration.DBProducer(cfg.IndexerDir(), cacheFdLimit, false).OpenDB(indexerDBName)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	transfers := indexer.NewTokenTransfers(table.New(db, []byte("t")))
+	logs := indexer.NewLogs(table.New(db, []byte("l")))
+	ix := indexer.New(src, table.New(db, []byte("p")), transfers, logs)
+	ix.SetThrottle(NewThrottlers(cfg.Background).Index)
+
+	srv := rpc.NewServer()
+	defer srv.Stop()
+	for _, api := range indexer.APIs(ix, transfers, logs) {
+		if err := srv.RegisterName(api.Namespace, api.Service); err != nil {
+			return err
+		}
+	}
+	addr := net.JoinHostPort(cfg.Indexer.HTTPAddr, strconv.Itoa(cfg.Indexer.HTTPPort))
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	httpSrv := &http.Server{Handler: srv}
+	go func() {
+		if err := httpSrv.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Error("Indexer RPC failed", "err", err)
+		}
+	}()
+	defer httpSrv.Close()
+
+	log.Info("Started indexer", "chaindata", cfg.chainDataDir(), "dir", cfg.IndexerDir(),
+		"http", "http://"+listener.Addr().String(), "throttle", cfg.Background.Index)
+	return ix.Run(ctx, indexerPollInterval)
+}
+
+// CheckIndexerConfig checks that the indexer may attach to the datadir:
+// the chain database exists, the indexer databases are outside of it,
+// and the indexer RPC port doesn't collide with the node's ports.
+func CheckIndexerConfig(cfg Config) error {
+	chaindata := cfg.chainDataDir()
+	if _, err := os.Stat(chaindata); err != nil {
+		return fmt.Errorf("no chain database to index at %s: %w", chaindata, err)
+	}
+	rel, err := filepath.Rel(chaindata, cfg.IndexerDir())
+	if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("indexer dir %s must be outside of the chain database", cfg.IndexerDir())
+	}
+	if cfg.Indexer.HTTPPort <= 0 || cfg.Indexer.HTTPPort > 65535 {
+		return fmt.Errorf("indexer port %d is out of range", cfg.Indexer.HTTPPort)
+	}
+	indexerRPC := endpoint{"indexer http", cfg.Indexer.HTTPAddr, cfg.Indexer.HTTPPort}
+	for _, e := range []endpoint{
+		{"p2p", cfg.Node.P2P.ListenAddr, cfg.Node.P2P.ListenPort},
+		{"http", cfg.Node.RPC.HTTPAddr, cfg.Node.RPC.HTTPPort},
+		{"ws", cfg.Node.RPC.WSAddr, cfg.Node.RPC.WSPort},
+	} {
+		if indexerRPC.collides(e) {
+			return fmt.Errorf("indexer port %d collides with the node's %s port", cfg.Indexer.HTTPPort, e.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// This file serves the node's RPC APIs over the local IPC socket. The private APIs, such as
+// the validator maintenance switch, are served only there, never over HTTP or WebSocket.
+
+package launcher
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// IPCEndpoint returns the path of the IPC socket, or an empty string if IPC is disabled.
+// A bare file name is resolved against the network datadir, as in go-ethereum.
+func (c Config) IPCEndpoint() string {
+	path := c.Node.RPC.IPCPath
+	if !c.Node.RPC.EnableIPC || path == "" {
+		return ""
+	}
+	if runtime.GOOS == "windows" {
+		if strings.HasPrefix(path, `\\.\pipe\`) {
+			return path
+		}
+		return `\\.\pipe\` + path
+	}
+	if filepath.Base(path) == path {
+		return filepath.Join(c.NetworkDataDir(), path)
+	}
+	return path
+}
+
+// StartIPC serves the APIs over the IPC socket of the config, if IPC is enabled.
+// Returns the function which stops the server.
+func StartIPC(cfg Config, apis []rpc.API) (stop func(), err error) {
+	endpoint := cfg.IPCEndpoint()
+	if endpoint == "" {
+		return func() {}, nil
+	}
+	listener, srv, err := rpc.StartIPCEndpoint(endpoint, apis)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("IPC endpoint opened", "url", endpoint)
+	return func() {
+		listener.Close()
+		srv.Stop()
+		log.Info("IPC endpoint closed", "url", endpoint)
+	}, nil
+}
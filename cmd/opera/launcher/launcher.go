@@ -7,11 +7,14 @@
 package launcher
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
-	"github.com/rony4d/go-opera-asset/flags"
 	"gopkg.in/urfave/cli.v1"
+
+	"github.com/rony4d/go-opera-asset/flags"
+	"github.com/rony4d/go-opera-asset/gossip/emitter"
 )
 
 const (
@@ -45,17 +48,92 @@ func initFlags() {
 
 }
 
-// Launch is a stub; it will eventually parse flags and start the node.
+// Launch parses flags and runs the requested command.
+// Starting the node itself is a stub for now.
 func Launch(args []string) error {
 
-	app.Flags = append(app.Flags, flags.CommonFlags()...)  //	Add the common flags to the app
-	app.Flags = append(app.Flags, flags.NetworkFlags()...) //	Add the network flags to the app
-	app.Flags = append(app.Flags, flags.NodeFlags()...)    //	Add the node flags to the app
-	app.Flags = append(app.Flags, flags.TxPoolFlags()...)  //	Add the txpool flags to the app
+	app.Flags = append(app.Flags, configFlags()...) //	Add the common, network, node and txpool flags to the app
+
+	app.Commands = []cli.Command{
+		configCommand(),
+		epochsCommand(),
+		checkCommand(),
+		indexerCommand(),
+		telemetryCommand(),
+		registryCommand(),
+		licenseCommand(),
+		exportCommand(),
+		importCommand(),
+		validatorCommand(),
+	}
+
+	// The node isn't implemented yet, only the helper commands and the snapshot bootstrap are.
+	app.Action = func(ctx *cli.Context) error {
+		cfg, err := makeConfig(ctx)
+		if err != nil {
+			return err
+		}
+		if err := adjustToSystem(&cfg); err != nil {
+			return err
+		}
+		if err := CheckFaucetConfig(cfg); err != nil {
+			return err
+		}
+		if err := CheckTelemetryConfig(cfg); err != nil {
+			return err
+		}
+		if err := CheckRegistryConfig(cfg); err != nil {
+			return err
+		}
+		notifier, err := SystemdNotifierFromEnv()
+		if err != nil {
+			return err
+		}
+		if _, err := loadRegistry(context.Background(), &cfg); err != nil {
+			return err
+		}
+		// the maintenance mode is switched by signals or over IPC
+		maintenance := emitter.NewMaintenance()
+		defer watchMaintenanceSignals(maintenance)()
+		stopIPC, err := StartIPC(cfg, emitter.MaintenanceAPIs(maintenance))
+		if err != nil {
+			return fmt.Errorf("failed to start the IPC endpoint: %w", err)
+		}
+		defer stopIPC()
+		// the emitter is switched to the rotated key at runtime
+		if cfg.Emitter.Enabled {
+			signer, password, err := openValidatorSigner(cfg)
+			if err != nil {
+				return err
+			}
+			defer WatchActiveValidatorKey(cfg, signer, password, activeValidatorKeyPoll)()
+		}
+		_ = notifier.Status("Bootstrapping")
+		if err := bootstrapNode(context.Background(), cfg); err != nil {
+			return fmt.Errorf("snapshot bootstrap failed: %w", err)
+		}
+		if !cfg.Node.ReadOnly {
+			if err := RecordNetworkID(cfg); err != nil {
+				return err
+			}
+		}
+		return errors.New("opera launcher not implemented yet")
+	}
 
 	if err := app.Run(args); err != nil {
 		fmt.Println("App Run Error:", err)
 		return err
 	}
-	return errors.New("opera launcher not implemented yet")
+	return nil
+}
+
+// configFlags returns all the flags which affect the node config.
+// Commands that build the config accept them too, so they may be passed after the command name.
+func configFlags() []cli.Flag {
+	var res []cli.Flag
+	res = append(res, flags.CommonFlags()...)  //	Add the common flags
+	res = append(res, flags.NetworkFlags()...) //	Add the network flags
+	res = append(res, flags.NodeFlags()...)    //	Add the node flags
+	res = append(res, flags.TxPoolFlags()...)  //	Add the txpool flags
+	return res
 }
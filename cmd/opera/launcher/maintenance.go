@@ -0,0 +1,46 @@
+//go:build !windows
+// +build !windows
+
+package launcher
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/rony4d/go-opera-asset/gossip/emitter"
+)
+
+// watchMaintenanceSignals switches the validator maintenance mode by signals:
+// SIGUSR1 enters the mode, SIGUSR2 leaves it.
+// Returns the function which stops watching.
+func watchMaintenanceSignals(m *emitter.Maintenance) (stop func()) {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGUSR1, syscall.SIGUSR2)
+	quit := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case sig := <-sigc:
+				if sig == syscall.SIGUSR1 {
+					if err := m.Enter(context.Background()); err != nil {
+						log.Error("Failed to enter maintenance mode", "err", err)
+					}
+				} else {
+					m.Leave()
+				}
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigc)
+		close(quit)
+	}
+}
@@ -0,0 +1,11 @@
+package launcher
+
+import (
+	"github.com/rony4d/go-opera-asset/gossip/emitter"
+)
+
+// watchMaintenanceSignals is a no-op on Windows, which has no user signals.
+// Use the validator_startMaintenance RPC method instead.
+func watchMaintenanceSignals(m *emitter.Maintenance) (stop func()) {
+	return func() {}
+}
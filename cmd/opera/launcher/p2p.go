@@ -0,0 +1,80 @@
+// This file converts the p2p config into the listeners config of the gossip, so the node
+// may listen on IPv4, IPv6 or both, and advertise the endpoints of both families.
+// It also configures the limits of the heavy requests served to the peers.
+
+package launcher
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/rony4d/go-opera-asset/gossip"
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+// P2PListenConfig returns the config of the p2p listeners.
+func P2PListenConfig(cfg P2PConfig) (gossip.ListenConfig, error) {
+	c := gossip.ListenConfig{
+		Addr:  cfg.ListenAddr,
+		Addr6: cfg.ListenAddr6,
+		Port:  cfg.ListenPort,
+	}
+	for _, ext := range []struct {
+		flag string
+		addr string
+		ip   *net.IP
+	}{
+		{"p2p.extip", cfg.ExternalIP, &c.ExternalIP},
+		{"p2p.extip6", cfg.ExternalIP6, &c.ExternalIP6},
+	} {
+		if ext.addr == "" {
+			continue
+		}
+		if *ext.ip = net.ParseIP(ext.addr); *ext.ip == nil {
+			return c, fmt.Errorf("invalid %s address %q", ext.flag, ext.addr)
+		}
+	}
+	return c, c.Validate()
+}
+
+func checkP2P(cfg Config, report *ConfigReport) {
+	c, err := P2PListenConfig(cfg.Node.P2P)
+	if err != nil {
+		report.add("p2p", CheckFail, "%v", err)
+		return
+	}
+	if c.Addr6 == "" && c.ExternalIP6 != nil {
+		if ip := net.ParseIP(c.Addr); ip != nil && ip.To4() != nil && !ip.IsUnspecified() {
+			report.add("p2p", CheckWarn, "IPv6 %v is advertised, but only IPv4 %s is listened", c.ExternalIP6, c.Addr)
+			return
+		}
+	}
+	listen := c.Addr
+	if c.Addr6 != "" {
+		listen += " and " + c.Addr6
+	}
+	report.add("p2p", CheckPass, "listening on %s port %d", listen, c.Port)
+}
+
+// NewServeLimiter creates the limiter of the heavy requests served to the peers.
+func NewServeLimiter(cfg P2PConfig, stats *gossip.PeersStats) *gossip.ServeLimiter {
+	return gossip.NewServeLimiter(cfg.Serve, stats, clock.Real{})
+}
+
+func checkServeLimits(cfg Config, report *ConfigReport) {
+	c := cfg.Node.P2P.Serve
+	if c.MaxInflight < 0 || c.MaxInflightPerPeer < 0 || c.MaxQueuedPerPeer < 0 || c.BytesPerSec < 0 {
+		report.add("serve", CheckFail, "negative heavy requests limits %+v", c)
+		return
+	}
+	if c.MaxInflight > 0 && c.MaxInflightPerPeer > c.MaxInflight {
+		report.add("serve", CheckFail, "%d requests per peer exceed the total limit of %d", c.MaxInflightPerPeer, c.MaxInflight)
+		return
+	}
+	if c.MaxInflight == 0 || c.BytesPerSec == 0 {
+		report.add("serve", CheckWarn, "heavy requests aren't fully limited, peers may overload the node")
+		return
+	}
+	report.add("serve", CheckPass, "%d requests at once, %d per peer, %.1f MB/s per peer",
+		c.MaxInflight, c.MaxInflightPerPeer, c.BytesPerSec/1024/1024)
+}
@@ -0,0 +1,40 @@
+package launcher
+
+import "testing"
+
+// TestCheckP2P verifies the config check of the p2p listeners.
+func TestCheckP2P(t *testing.T) {
+	runCheckCases(t, []checkCase{
+		{
+			name: "separate IPv4 and IPv6 p2p binds",
+			args: []string{"--p2p.addr", "0.0.0.0", "--p2p.addr6", "::", "--p2p.extip6", "2001:db8::1"},
+			want: map[string]CheckStatus{"p2p": CheckPass, "ports": CheckPass},
+		},
+		{
+			name: "IPv4 address as p2p IPv6 bind",
+			args: []string{"--p2p.addr6", "127.0.0.1"},
+			want: map[string]CheckStatus{"p2p": CheckFail},
+		},
+		{
+			name: "IPv6 advertised without IPv6 listener",
+			args: []string{"--p2p.addr", "10.0.0.1", "--p2p.extip6", "2001:db8::1"},
+			want: map[string]CheckStatus{"p2p": CheckWarn},
+		},
+	})
+}
+
+// TestCheckServeLimits verifies the config check of the heavy requests limits.
+func TestCheckServeLimits(t *testing.T) {
+	runCheckCases(t, []checkCase{
+		{
+			name: "per-peer heavy requests above the total",
+			args: []string{"--p2p.serve.inflight", "4", "--p2p.serve.peerinflight", "8"},
+			want: map[string]CheckStatus{"serve": CheckFail},
+		},
+		{
+			name: "unlimited heavy requests",
+			args: []string{"--p2p.serve.rate", "0"},
+			want: map[string]CheckStatus{"serve": CheckWarn},
+		},
+	})
+}
@@ -0,0 +1,59 @@
+// This file implements the read-only mode (--readonly), which opens the datadir for inspection
+// only: RPC queries and exports of a datadir owned by another process, or forensics of a
+// corrupted node. The databases are opened read-only (see integration.DBProducer), and
+// everything which would write to the datadir is switched off: the validator mode, the txpool
+// journal, the faucet and the snapshot bootstrap. Commands that modify the datadir refuse to run.
+
+package launcher
+
+import (
+	"errors"
+	"os"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/rony4d/go-opera-asset/integration"
+)
+
+// ErrReadOnly is returned by the operations which modify the datadir in the read-only mode.
+var ErrReadOnly = errors.New("the datadir is opened read-only")
+
+// applyReadOnly switches off the features which write to the datadir if the read-only mode is on.
+func applyReadOnly(cfg *Config) {
+	if !cfg.Node.ReadOnly {
+		return
+	}
+	if cfg.Emitter.Enabled {
+		log.Warn("Validator mode is disabled in the read-only mode", "validator", cfg.Emitter.ValidatorID)
+		cfg.Emitter.Enabled = false
+	}
+	if cfg.Faucet.Enabled {
+		log.Warn("Faucet is disabled in the read-only mode")
+		cfg.Faucet.Enabled = false
+	}
+	if cfg.Bootstrap.URL != "" {
+		log.Warn("Snapshot bootstrap is disabled in the read-only mode", "url", cfg.Bootstrap.URL)
+		cfg.Bootstrap.URL = ""
+	}
+	cfg.TxPool.Journal = ""
+}
+
+// DBProducer returns the producer of the node's databases, which opens them read-only in the read-only mode.
+func (c Config) DBProducer() kvdb.IterableDBProducer {
+	return integration.DBProducer(c.chainDataDir(), func(string) (int, int) {
+		return c.OperaStore.CacheMB * 1024 * 1024, c.OperaStore.Handles
+	}, c.Node.ReadOnly)
+}
+
+func checkReadOnly(cfg Config, report *ConfigReport) {
+	if !cfg.Node.ReadOnly {
+		report.add("readonly", CheckPass, "off")
+		return
+	}
+	if _, err := os.Stat(cfg.chainDataDir()); err != nil {
+		report.add("readonly", CheckFail, "no database to inspect at %s", cfg.chainDataDir())
+		return
+	}
+	report.add("readonly", CheckPass, "%s is opened read-only, validator mode, txpool journal and faucet are off", cfg.chainDataDir())
+}
@@ -0,0 +1,14 @@
+package launcher
+
+import "testing"
+
+// TestCheckReadOnly verifies the config check of the read-only mode.
+func TestCheckReadOnly(t *testing.T) {
+	runCheckCases(t, []checkCase{
+		{
+			name: "read-only without database",
+			args: []string{"--readonly"},
+			want: map[string]CheckStatus{"readonly": CheckFail},
+		},
+	})
+}
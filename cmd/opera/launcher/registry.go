@@ -0,0 +1,445 @@
+// This file implements the network registry: a signed JSON document published by the operators
+// of a network with its bootnodes, the hash of the recommended rules and the latest snapshot.
+// The launcher fetches it on startup, so the bootstrap data of a young network may be updated
+// without shipping new binaries, and RunRegistry refreshes it periodically. The document is
+// trusted only if it's signed with the pinned ed25519 key (--registry.key), and a document older
+// than the last accepted one is ignored, so a replayed document can't roll the data back. The last
+// accepted registry is persisted in RegistryFile, so the protection holds across restarts, and
+// the node starts with the last known data if the registry is unreachable.
+
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// RegistryConfig is the config of the network registry.
+type RegistryConfig struct {
+	URL      string        // URL of the signed registry document, empty disables the registry
+	Key      string        // hex ed25519 public key the document must be signed with
+	Interval time.Duration // interval between the refreshes
+}
+
+const (
+	// RegistryFile is the name of the last accepted registry in the network datadir.
+	RegistryFile = "registry.json"
+
+	// maxRegistrySize is the max size of the registry document.
+	maxRegistrySize = 1024 * 1024
+)
+
+var (
+	// ErrRegistrySignature is returned if the registry document isn't signed with the pinned key.
+	ErrRegistrySignature = errors.New("invalid registry signature")
+	// ErrRegistryNetwork is returned if the registry document is of another network.
+	ErrRegistryNetwork = errors.New("registry of another network")
+)
+
+// NetworkRegistry is the network metadata published in the registry.
+type NetworkRegistry struct {
+	NetworkID uint64 `json:"networkId"`
+	// Version increases with every update of the document.
+	Version      uint64    `json:"version"`
+	Bootnodes    []string  `json:"bootnodes,omitempty"`
+	RulesHash    hash.Hash `json:"rulesHash,omitempty"` // hash of the recommended rules, see iblockproc.RulesHash
+	SnapshotURL  string    `json:"snapshotUrl,omitempty"`
+	SnapshotHash string    `json:"snapshotHash,omitempty"` // hex-encoded SHA-256 of the snapshot archive
+}
+
+// SignedRegistry is the registry document: the registry JSON and the signature of its exact bytes.
+type SignedRegistry struct {
+	Registry  json.RawMessage `json:"registry"`
+	Signature hexutil.Bytes   `json:"signature"`
+}
+
+// SignRegistry returns the registry document signed with the key.
+func SignRegistry(key ed25519.PrivateKey, reg NetworkRegistry) ([]byte, error) {
+	raw, err := json.Marshal(reg)
+	if err != nil {
+		return nil, err
+	}
+	// not indented, as indenting reformats the signed bytes of the registry
+	return json.Marshal(SignedRegistry{
+		Registry:  raw,
+		Signature: ed25519.Sign(key, raw),
+	})
+}
+
+// ParseRegistry verifies the signature of the registry document and decodes the registry.
+func ParseRegistry(doc []byte, key ed25519.PublicKey) (*NetworkRegistry, error) {
+	var signed SignedRegistry
+	if err := json.Unmarshal(doc, &signed); err != nil {
+		return nil, fmt.Errorf("malformed registry document: %w", err)
+	}
+	if !ed25519.Verify(key, signed.Registry, signed.Signature) {
+		return nil, ErrRegistrySignature
+	}
+	var reg NetworkRegistry
+	if err := json.Unmarshal(signed.Registry, &reg); err != nil {
+		return nil, fmt.Errorf("malformed registry: %w", err)
+	}
+	return &reg, nil
+}
+
+// FetchRegistry downloads the registry document and verifies it.
+func FetchRegistry(ctx context.Context, client *http.Client, rawurl string, key ed25519.PublicKey) (*NetworkRegistry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawurl, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry replied %s", resp.Status)
+	}
+	doc, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxRegistrySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(doc) > maxRegistrySize {
+		return nil, fmt.Errorf("registry document exceeds %d bytes", maxRegistrySize)
+	}
+	return ParseRegistry(doc, key)
+}
+
+// CheckRules returns an error if the registry recommends other rules than the given ones.
+func (r *NetworkRegistry) CheckRules(rules opera.Rules) error {
+	if r.RulesHash == (hash.Hash{}) {
+		return nil
+	}
+	if got := iblockproc.RulesHash(rules); got != r.RulesHash {
+		return fmt.Errorf("rules hash %s differs from the recommended %s", got.String(), r.RulesHash.String())
+	}
+	return nil
+}
+
+// ApplyRegistry adds the bootnodes of the registry to the configured ones, and takes the snapshot
+// of the registry for the bootstrap of a fresh node, unless a snapshot is configured explicitly.
+func ApplyRegistry(cfg *Config, reg *NetworkRegistry) {
+	known := make(map[string]bool, len(cfg.Node.P2P.Bootnodes))
+	for _, b := range cfg.Node.P2P.Bootnodes {
+		known[b] = true
+	}
+	for _, b := range reg.Bootnodes {
+		if !known[b] {
+			cfg.Node.P2P.Bootnodes = append(cfg.Node.P2P.Bootnodes, b)
+			known[b] = true
+		}
+	}
+	if cfg.Bootstrap.URL == "" && reg.SnapshotURL != "" {
+		cfg.Bootstrap.URL = reg.SnapshotURL
+		cfg.Bootstrap.Hash = reg.SnapshotHash
+	}
+}
+
+// Registry keeps the latest accepted registry of the network. It's safe for concurrent use.
+type Registry struct {
+	networkID uint64
+	key       ed25519.PublicKey
+	path      string // file of the last accepted registry, empty if it isn't persisted
+
+	mu     sync.Mutex
+	latest *NetworkRegistry
+}
+
+// NewRegistry creates the registry of the network, trusting the documents signed with the key.
+// The accepted registry isn't persisted.
+func NewRegistry(networkID uint64, key ed25519.PublicKey) *Registry {
+	return &Registry{networkID: networkID, key: key}
+}
+
+// OpenRegistry creates the registry of the network, which persists the accepted registry in the file.
+// The registry accepted before is loaded from the file, so an older document isn't accepted after a restart.
+func OpenRegistry(path string, networkID uint64, key ed25519.PublicKey) (*Registry, error) {
+	r := &Registry{networkID: networkID, key: key, path: path}
+	latest := new(NetworkRegistry)
+	ok, err := readJSONFile(path, latest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the last accepted registry: %w", err)
+	}
+	if !ok {
+		return r, nil
+	}
+	if latest.NetworkID != networkID {
+		return nil, fmt.Errorf("%w in %s: %d, expected %d", ErrRegistryNetwork, path, latest.NetworkID, networkID)
+	}
+	r.latest = latest
+	return r, nil
+}
+
+// Update accepts the fetched registry if it's of the network and newer than the latest one.
+// Returns false if the registry isn't newer, and an error if it isn't of the network.
+func (r *Registry) Update(reg *NetworkRegistry) (bool, error) {
+	if reg.NetworkID != r.networkID {
+		return false, fmt.Errorf("%w: %d, expected %d", ErrRegistryNetwork, reg.NetworkID, r.networkID)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.latest != nil && reg.Version <= r.latest.Version {
+		return false, nil
+	}
+	if r.path != "" {
+		if err := writeJSONFile(r.path, reg); err != nil {
+			return false, fmt.Errorf("failed to persist the registry: %w", err)
+		}
+	}
+	r.latest = reg
+	return true, nil
+}
+
+// Latest returns the latest accepted registry, nil if none is accepted yet.
+func (r *Registry) Latest() *NetworkRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.latest
+}
+
+// Refresh fetches the registry document and accepts it if it's newer.
+func (r *Registry) Refresh(ctx context.Context, client *http.Client, rawurl string) (bool, error) {
+	reg, err := FetchRegistry(ctx, client, rawurl, r.key)
+	if err != nil {
+		return false, err
+	}
+	return r.Update(reg)
+}
+
+// RunRegistry refreshes the registry periodically until ctx is cancelled, calling onUpdate with
+// every newly accepted registry. The first refresh is after the interval, as the registry is
+// fetched on startup (see loadRegistry). It does nothing if the registry isn't configured.
+// Failures are only logged, as the node keeps working with the data it already has.
+func RunRegistry(ctx context.Context, cfg Config, r *Registry, onUpdate func(*NetworkRegistry)) {
+	if cfg.Registry.URL == "" {
+		return
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	ticker := time.NewTicker(cfg.Registry.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		updated, err := r.Refresh(ctx, client, cfg.Registry.URL)
+		if err != nil {
+			log.Warn("Failed to refresh the network registry", "url", cfg.Registry.URL, "err", err)
+		} else if updated {
+			reg := r.Latest()
+			log.Info("Network registry updated", "version", reg.Version, "bootnodes", len(reg.Bootnodes))
+			onUpdate(reg)
+		}
+	}
+}
+
+// loadRegistry fetches the registry on startup and applies it to the config, before the
+// snapshot bootstrap and the p2p server use it. A failed fetch isn't fatal: the node starts
+// with the last accepted registry or the configured data, and RunRegistry keeps retrying with
+// the returned registry. A read-only node neither loads nor persists the accepted registry.
+func loadRegistry(ctx context.Context, cfg *Config) (*Registry, error) {
+	if cfg.Registry.URL == "" {
+		return nil, nil
+	}
+	key, err := RegistryKey(*cfg)
+	if err != nil {
+		return nil, err
+	}
+	r := NewRegistry(cfg.Opera.NetworkID, key)
+	if !cfg.Node.ReadOnly {
+		if err := ensureDir(cfg.NetworkDataDir()); err != nil {
+			return nil, err
+		}
+		if r, err = OpenRegistry(filepath.Join(cfg.NetworkDataDir(), RegistryFile), cfg.Opera.NetworkID, key); err != nil {
+			return nil, err
+		}
+	}
+	updated, err := r.Refresh(ctx, &http.Client{Timeout: 30 * time.Second}, cfg.Registry.URL)
+	if err != nil {
+		log.Warn("Failed to fetch the network registry", "url", cfg.Registry.URL, "err", err)
+	}
+	reg := r.Latest()
+	if reg == nil {
+		return r, nil
+	}
+	ApplyRegistry(cfg, reg)
+	checkRegistryRules(*cfg, reg)
+	log.Info("Network registry loaded", "version", reg.Version, "fetched", updated, "bootnodes", len(cfg.Node.P2P.Bootnodes))
+	return r, nil
+}
+
+// checkRegistryRules warns if the registry recommends other rules than the node's ones.
+func checkRegistryRules(cfg Config, reg *NetworkRegistry) {
+	if err := reg.CheckRules(NetworkRules(cfg.Opera)); err != nil {
+		log.Warn("Network rules differ from the registry", "err", err)
+	}
+}
+
+// RegistryKey decodes the pinned public key of the registry.
+func RegistryKey(cfg Config) (ed25519.PublicKey, error) {
+	raw, err := hexutil.Decode(cfg.Registry.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry key: %w", err)
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("registry key must be %d bytes, got %d", ed25519.PublicKeySize, len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// CheckRegistryConfig checks that the registry may be fetched and verified if it's configured.
+func CheckRegistryConfig(cfg Config) error {
+	if cfg.Registry.URL == "" {
+		return nil
+	}
+	u, err := url.Parse(cfg.Registry.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid registry URL %q", cfg.Registry.URL)
+	}
+	if _, err := RegistryKey(cfg); err != nil {
+		return err
+	}
+	if cfg.Registry.Interval < time.Minute {
+		return fmt.Errorf("registry refresh interval %v is below 1m", cfg.Registry.Interval)
+	}
+	return nil
+}
+
+func checkRegistry(cfg Config, report *ConfigReport) {
+	if cfg.Registry.URL == "" {
+		report.add("registry", CheckPass, "disabled")
+		return
+	}
+	if err := CheckRegistryConfig(cfg); err != nil {
+		report.add("registry", CheckFail, "%v", err)
+		return
+	}
+	if strings.HasPrefix(cfg.Registry.URL, "http://") {
+		// the signature protects the content, but not the privacy of the fetching node
+		report.add("registry", CheckWarn, "%s is fetched over plain HTTP", cfg.Registry.URL)
+		return
+	}
+	report.add("registry", CheckPass, "%s every %v", cfg.Registry.URL, cfg.Registry.Interval)
+}
+
+// -----------------------------------------------------------------------------
+// Commands
+// -----------------------------------------------------------------------------
+
+var registrySignKeyFlag = cli.StringFlag{
+	Name:  "key",
+	Usage: "File with the hex ed25519 private key (seed) of the registry",
+}
+
+func registryCommand() cli.Command {
+	return cli.Command{
+		Name:     "registry",
+		Usage:    "Network registry helpers",
+		Category: "MISCELLANEOUS COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:      "sign",
+				Usage:     "Sign a registry JSON with the registry key",
+				ArgsUsage: "<registry.json>",
+				Action:    signRegistryAction,
+				Flags:     []cli.Flag{registrySignKeyFlag},
+				Description: `
+    opera registry sign --key keyfile registry.json
+
+Prints the registry document to publish: the registry JSON (networkId, version, bootnodes,
+rulesHash, snapshotUrl, snapshotHash) signed with the ed25519 key. The version must be
+increased with every update, the nodes ignore the documents which aren't newer.`,
+			},
+			{
+				Name:   "show",
+				Usage:  "Fetch, verify and print the registry of the node",
+				Action: showRegistryAction,
+				Flags:  configFlags(),
+				Description: `
+    opera registry show [flags]
+
+Fetches the registry document from --registry.url, verifies it with --registry.key
+and prints the registry.`,
+			},
+		},
+	}
+}
+
+func signRegistryAction(ctx *cli.Context) error {
+	if ctx.NArg() != 1 || !ctx.IsSet(registrySignKeyFlag.Name) {
+		return fmt.Errorf("usage: opera registry sign --key keyfile registry.json")
+	}
+	seedHex, err := ioutil.ReadFile(ctx.String(registrySignKeyFlag.Name))
+	if err != nil {
+		return err
+	}
+	seed, err := hexutil.Decode(string(bytes.TrimSpace(seedHex)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return fmt.Errorf("registry key must be a hex %d-byte seed", ed25519.SeedSize)
+	}
+	raw, err := ioutil.ReadFile(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	var reg NetworkRegistry
+	if err := json.Unmarshal(raw, &reg); err != nil {
+		return err
+	}
+	doc, err := SignRegistry(ed25519.NewKeyFromSeed(seed), reg)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(doc))
+	return nil
+}
+
+func showRegistryAction(ctx *cli.Context) error {
+	cfg, err := makeConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if cfg.Registry.URL == "" {
+		return fmt.Errorf("--registry.url isn't set")
+	}
+	if err := CheckRegistryConfig(cfg); err != nil {
+		return err
+	}
+	key, _ := RegistryKey(cfg)
+	reg, err := FetchRegistry(context.Background(), &http.Client{Timeout: 30 * time.Second}, cfg.Registry.URL, key)
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(reg, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	if reg.NetworkID != cfg.Opera.NetworkID {
+		return fmt.Errorf("%w: %d, expected %d", ErrRegistryNetwork, reg.NetworkID, cfg.Opera.NetworkID)
+	}
+	return nil
+}
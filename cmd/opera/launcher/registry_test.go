@@ -0,0 +1,28 @@
+package launcher
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestCheckRegistry verifies the config check of the network registry.
+func TestCheckRegistry(t *testing.T) {
+	key := "0x" + strings.Repeat("ab", 32)
+	runCheckCases(t, []checkCase{
+		{
+			name: "registry without key",
+			args: []string{"--registry.url", "https://example.com/registry.json"},
+			want: map[string]CheckStatus{"registry": CheckFail},
+		},
+		{
+			name: "registry refreshed too often",
+			args: []string{"--registry.url", "https://example.com/registry.json", "--registry.key", key, "--registry.interval", "10s"},
+			want: map[string]CheckStatus{"registry": CheckFail},
+		},
+		{
+			name: "registry over plain HTTP",
+			args: []string{"--registry.url", "http://example.com/registry.json", "--registry.key", key},
+			want: map[string]CheckStatus{"registry": CheckWarn},
+		},
+	})
+}
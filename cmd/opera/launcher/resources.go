@@ -0,0 +1,138 @@
+// This file checks the configured caches and DB handles against the system limits on
+// startup. Without it, a too large cache gets the node OOM-killed and a too large number
+// of handles makes the DB fail on open, which both look like random crashes to operators.
+
+package launcher
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// cacheMemoryShare is the max share of the memory which may be taken by the caches.
+	cacheMemoryShare = 2 // i.e. 1/2
+	// handlesFDShare is the max share of the file descriptors which may be taken by the DBs,
+	// the rest is needed for p2p and RPC connections.
+	handlesFDShare = 2 // i.e. 1/2
+
+	minCacheMB = 256
+	minHandles = 64
+)
+
+// SystemResources are the limits of the system the node runs on. Zero means unknown.
+type SystemResources struct {
+	MemoryMB uint64 // memory available to the process, including the cgroup limit
+	FDLimit  uint64 // limit of the open file descriptors
+}
+
+// DetectResources detects the system limits, raising the file descriptors limit if possible.
+func DetectResources() SystemResources {
+	return SystemResources{
+		MemoryMB: detectMemoryMB(),
+		FDLimit:  raiseFDLimit(),
+	}
+}
+
+// AdjustToResources downscales the caches and DB handles which exceed the safe shares of
+// the system resources, and returns a warning per adjusted value.
+// It fails if even the minimal values don't fit.
+func AdjustToResources(cfg *Config, res SystemResources) ([]string, error) {
+	var warnings []string
+
+	if res.MemoryMB != 0 {
+		limit := int(res.MemoryMB / cacheMemoryShare)
+		total := cfg.OperaStore.CacheMB + cfg.LachesisStore.CacheMB
+		if total > limit {
+			if limit < minCacheMB {
+				return warnings, fmt.Errorf("only %d MB of memory is available, at least %d MB is required for the caches (use a host with more memory)", res.MemoryMB, minCacheMB*cacheMemoryShare)
+			}
+			// scale both the caches proportionally
+			cfg.LachesisStore.CacheMB = cfg.LachesisStore.CacheMB * limit / total
+			cfg.OperaStore.CacheMB = limit - cfg.LachesisStore.CacheMB
+			cfg.DBs.RuntimeCache = cfg.OperaStore.CacheMB
+			warnings = append(warnings, fmt.Sprintf("cache size %d MB exceeds half of the available memory (%d MB), downscaled to %d MB (pass a lower --cache to silence)", total, res.MemoryMB, limit))
+		}
+	}
+
+	if res.FDLimit != 0 {
+		limit := int(res.FDLimit / handlesFDShare)
+		if cfg.OperaStore.Handles > limit {
+			if limit < minHandles {
+				return warnings, fmt.Errorf("file descriptors limit is %d, at least %d is required (raise it with `ulimit -n` or LimitNOFILE in the systemd unit)", res.FDLimit, minHandles*handlesFDShare)
+			}
+			warnings = append(warnings, fmt.Sprintf("DB handles %d exceed half of the file descriptors limit (%d), downscaled to %d (raise it with `ulimit -n`)", cfg.OperaStore.Handles, res.FDLimit, limit))
+			cfg.OperaStore.Handles = limit
+		}
+	}
+	return warnings, nil
+}
+
+// adjustToSystem applies AdjustToResources with the detected system limits.
+func adjustToSystem(cfg *Config) error {
+	res := DetectResources()
+	log.Debug("Detected system resources", "memory_mb", res.MemoryMB, "fd_limit", res.FDLimit)
+	warnings, err := AdjustToResources(cfg, res)
+	for _, w := range warnings {
+		log.Warn("Resource limit: " + w)
+	}
+	return err
+}
+
+// detectMemoryMB returns the physical memory, or the cgroup memory limit if it's lower.
+func detectMemoryMB() uint64 {
+	mem := readMemTotal("/proc/meminfo")
+	for _, path := range []string{
+		"/sys/fs/cgroup/memory.max",                   // cgroup v2
+		"/sys/fs/cgroup/memory/memory.limit_in_bytes", // cgroup v1
+	} {
+		if limit := readCgroupLimit(path); limit != 0 && (mem == 0 || limit < mem) {
+			mem = limit
+		}
+	}
+	return mem / 1024 / 1024
+}
+
+// readMemTotal returns MemTotal of /proc/meminfo in bytes, or 0 if it's unavailable.
+func readMemTotal(path string) uint64 {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0
+			}
+			return kb * 1024
+		}
+	}
+	return 0
+}
+
+// readCgroupLimit returns the cgroup memory limit in bytes, or 0 if there's no limit.
+func readCgroupLimit(path string) uint64 {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	limit, err := strconv.ParseUint(strings.TrimSpace(string(raw)), 10, 64)
+	if err != nil {
+		return 0 // "max"
+	}
+	// cgroup v1 reports a huge number if there's no limit
+	if limit >= 1<<62 {
+		return 0
+	}
+	return limit
+}
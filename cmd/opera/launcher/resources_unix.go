@@ -0,0 +1,25 @@
+//go:build !windows
+// +build !windows
+
+package launcher
+
+import (
+	"syscall"
+)
+
+// raiseFDLimit raises the soft limit of the open file descriptors up to the hard one,
+// and returns the resulting limit (0 if it's unknown).
+func raiseFDLimit() uint64 {
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return 0
+	}
+	if limit.Cur < limit.Max {
+		raised := limit
+		raised.Cur = limit.Max
+		if err := syscall.Setrlimit(syscall.RLIMIT_NOFILE, &raised); err == nil {
+			limit = raised
+		}
+	}
+	return uint64(limit.Cur)
+}
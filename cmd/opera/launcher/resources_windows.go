@@ -0,0 +1,6 @@
+package launcher
+
+// raiseFDLimit returns 0 on Windows, which has no file descriptors limit to check.
+func raiseFDLimit() uint64 {
+	return 0
+}
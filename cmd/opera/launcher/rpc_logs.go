@@ -0,0 +1,54 @@
+// This file converts the RPC config into the limits of eth_getLogs. Unlimited queries over a
+// public endpoint let a single client make the node scan its whole history.
+
+package launcher
+
+import (
+	"net"
+
+	"github.com/rony4d/go-opera-asset/ethapi"
+)
+
+// RPCLogsConfig returns the limits of eth_getLogs.
+func RPCLogsConfig(cfg RPCConfig) ethapi.LogsConfig {
+	return ethapi.LogsConfig{
+		MaxBlockRange: cfg.LogsBlockRange,
+		MaxResults:    cfg.LogsMaxResults,
+		Timeout:       cfg.LogsTimeout,
+	}
+}
+
+func checkRPCLogs(cfg Config, report *ConfigReport) {
+	c := cfg.Node.RPC
+	if c.LogsMaxResults < 0 || c.LogsTimeout < 0 {
+		report.add("logs", CheckFail, "eth_getLogs limits must not be negative")
+		return
+	}
+	var unlimited []string
+	if c.LogsBlockRange == 0 {
+		unlimited = append(unlimited, "block range")
+	}
+	if c.LogsMaxResults == 0 {
+		unlimited = append(unlimited, "results")
+	}
+	if c.LogsTimeout == 0 {
+		unlimited = append(unlimited, "duration")
+	}
+	if len(unlimited) != 0 {
+		if (c.HTTPEnabled && !isLoopback(c.HTTPAddr)) || (c.EnableWS && !isLoopback(c.WSAddr)) {
+			report.add("logs", CheckWarn, "eth_getLogs %v is unlimited on a public endpoint", unlimited)
+			return
+		}
+		report.add("logs", CheckPass, "eth_getLogs %v is unlimited, the RPC is local only", unlimited)
+		return
+	}
+	report.add("logs", CheckPass, "eth_getLogs up to %d blocks, %d results, %v", c.LogsBlockRange, c.LogsMaxResults, c.LogsTimeout)
+}
+
+func isLoopback(addr string) bool {
+	if addr == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(addr)
+	return ip != nil && ip.IsLoopback()
+}
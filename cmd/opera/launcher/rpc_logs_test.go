@@ -0,0 +1,19 @@
+package launcher
+
+import "testing"
+
+// TestCheckRPCLogs verifies the config check of the eth_getLogs limits.
+func TestCheckRPCLogs(t *testing.T) {
+	runCheckCases(t, []checkCase{
+		{
+			name: "unlimited eth_getLogs on public endpoint",
+			args: []string{"--http.addr", "0.0.0.0", "--rpc.logs.blockrange", "0"},
+			want: map[string]CheckStatus{"logs": CheckWarn},
+		},
+		{
+			name: "unlimited eth_getLogs on local endpoint",
+			args: []string{"--rpc.logs.timeout", "0s"},
+			want: map[string]CheckStatus{"logs": CheckPass},
+		},
+	})
+}
@@ -0,0 +1,35 @@
+// This file configures the shadow validator mode (--validator.shadow): the node computes the events
+// the configured validator would emit and serves them via RPC, but never signs nor broadcasts them,
+// so a prospective validator may verify its setup against the real load before staking.
+
+package launcher
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+
+	"github.com/rony4d/go-opera-asset/gossip/emitter"
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+// NewShadowEmitter creates the shadow emitter of the configured validator over the node's view.
+func NewShadowEmitter(cfg Config, world emitter.ShadowWorld) *emitter.ShadowEmitter {
+	shadowCfg := emitter.DefaultShadowConfig()
+	shadowCfg.Creator = idx.ValidatorID(cfg.Emitter.ValidatorID)
+	return emitter.NewShadowEmitter(shadowCfg, world, clock.Real{})
+}
+
+func checkShadow(cfg Config, report *ConfigReport) {
+	if !cfg.Emitter.Shadow {
+		report.add("shadow", CheckPass, "off")
+		return
+	}
+	if cfg.Emitter.Enabled {
+		report.add("shadow", CheckFail, "shadow mode can't be combined with the validator mode")
+		return
+	}
+	if cfg.Emitter.ValidatorID == 0 {
+		report.add("shadow", CheckWarn, "validator ID isn't set, the events are computed for an unknown validator")
+		return
+	}
+	report.add("shadow", CheckPass, "events of validator %d are computed, but never signed nor broadcast", cfg.Emitter.ValidatorID)
+}
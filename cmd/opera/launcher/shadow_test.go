@@ -0,0 +1,24 @@
+package launcher
+
+import "testing"
+
+// TestCheckShadow verifies the config check of the shadow validator mode.
+func TestCheckShadow(t *testing.T) {
+	runCheckCases(t, []checkCase{
+		{
+			name:  "shadow validator",
+			args:  []string{"--validator.shadow"},
+			setup: func(cfg *Config) { cfg.Emitter.ValidatorID = 1 },
+			want:  map[string]CheckStatus{"shadow": CheckPass, "keystore": CheckPass},
+		},
+		{
+			name: "shadow and validator modes",
+			args: []string{"--validator.shadow"},
+			setup: func(cfg *Config) {
+				cfg.Emitter.Enabled = true
+				cfg.Emitter.ValidatorID = 1
+			},
+			want: map[string]CheckStatus{"shadow": CheckFail},
+		},
+	})
+}
@@ -0,0 +1,213 @@
+// This file implements the systemd integration of a Type=notify service. The node reports
+// READY=1 once it's started (the stores are open and the RPC is listening) and STATUS= lines
+// with the sync progress. If the unit sets WatchdogSec=, the node sends WATCHDOG=1 keepalives
+// only while its health checks pass, so systemd restarts a node which is hung internally
+// even though its process is still alive. Outside of systemd, all of it does nothing.
+
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+// SystemdNotifier sends the state of the service to systemd over the notify socket.
+type SystemdNotifier struct {
+	socket   string        // path of the notify socket, empty if the node doesn't run under systemd
+	watchdog time.Duration // watchdog timeout of the unit, 0 if the watchdog is off
+}
+
+// NewSystemdNotifier creates the notifier sending to the socket. An empty socket disables
+// the notifier, a zero watchdog timeout disables the keepalives.
+func NewSystemdNotifier(socket string, watchdog time.Duration) *SystemdNotifier {
+	return &SystemdNotifier{
+		socket:   socket,
+		watchdog: watchdog,
+	}
+}
+
+// SystemdNotifierFromEnv creates the notifier configured by systemd via NOTIFY_SOCKET,
+// WATCHDOG_USEC and WATCHDOG_PID.
+func SystemdNotifierFromEnv() (*SystemdNotifier, error) {
+	watchdog, err := parseSystemdWatchdog(os.Getenv("WATCHDOG_USEC"), os.Getenv("WATCHDOG_PID"), os.Getpid())
+	if err != nil {
+		return nil, err
+	}
+	return NewSystemdNotifier(os.Getenv("NOTIFY_SOCKET"), watchdog), nil
+}
+
+// parseSystemdWatchdog returns the watchdog timeout, or 0 if the watchdog is off or
+// addressed to another process.
+func parseSystemdWatchdog(usec, pid string, self int) (time.Duration, error) {
+	if usec == "" {
+		return 0, nil
+	}
+	if pid != "" {
+		p, err := strconv.Atoi(pid)
+		if err != nil {
+			return 0, fmt.Errorf("invalid WATCHDOG_PID %q", pid)
+		}
+		if p != self {
+			return 0, nil
+		}
+	}
+	n, err := strconv.ParseUint(usec, 10, 63)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("invalid WATCHDOG_USEC %q", usec)
+	}
+	return time.Duration(n) * time.Microsecond, nil
+}
+
+// Enabled returns true if the node runs under systemd with Type=notify.
+func (n *SystemdNotifier) Enabled() bool {
+	return n.socket != ""
+}
+
+// WatchdogTimeout returns the watchdog timeout of the unit, or 0 if the watchdog is off.
+func (n *SystemdNotifier) WatchdogTimeout() time.Duration {
+	if !n.Enabled() {
+		return 0
+	}
+	return n.watchdog
+}
+
+// Notify sends the state assignments (e.g. "READY=1") to systemd. It does nothing if the notifier is disabled.
+func (n *SystemdNotifier) Notify(state ...string) error {
+	if !n.Enabled() {
+		return nil
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: n.socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(strings.Join(state, "\n")))
+	return err
+}
+
+// Ready reports that the node is started.
+func (n *SystemdNotifier) Ready() error {
+	return n.Notify("READY=1")
+}
+
+// Stopping reports that the node is stopping.
+func (n *SystemdNotifier) Stopping() error {
+	return n.Notify("STOPPING=1")
+}
+
+// Status reports the free-form status shown by `systemctl status`.
+func (n *SystemdNotifier) Status(format string, args ...interface{}) error {
+	// a new line would start another assignment
+	status := strings.ReplaceAll(fmt.Sprintf(format, args...), "\n", " ")
+	return n.Notify("STATUS=" + status)
+}
+
+// SyncStatus reports the sync progress of the node.
+func (n *SystemdNotifier) SyncStatus(current, highest idx.Block) error {
+	if current >= highest {
+		return n.Status("Synced, block %d", current)
+	}
+	return n.Status("Syncing, block %d of %d (%.1f%%)", current, highest, float64(current)*100/float64(highest))
+}
+
+// RunWatchdog sends the keepalives every half of the watchdog timeout while the health checks
+// pass, until ctx is cancelled. It returns at once if the watchdog is off.
+func (n *SystemdNotifier) RunWatchdog(ctx context.Context, health *HealthChecker) {
+	timeout := n.WatchdogTimeout()
+	if timeout == 0 {
+		return
+	}
+	ticker := time.NewTicker(timeout / 2)
+	defer ticker.Stop()
+	healthy := true
+	for {
+		err := health.Check()
+		if err == nil {
+			if !healthy {
+				log.Info("Node is healthy again, resuming the systemd watchdog keepalives")
+			}
+			err = n.Notify("WATCHDOG=1")
+			if err != nil {
+				log.Warn("Failed to send the systemd watchdog keepalive", "err", err)
+			}
+		} else if healthy {
+			// no keepalives, so systemd restarts the node once the timeout expires
+			log.Error("Node is unhealthy, stopping the systemd watchdog keepalives", "err", err, "timeout", timeout)
+			_ = n.Status("Unhealthy: %v", err)
+		}
+		healthy = err == nil
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// HealthChecker aggregates the health checks of the node's components. It's safe for concurrent use.
+type HealthChecker struct {
+	mu     sync.Mutex
+	names  []string
+	checks []func() error
+}
+
+// NewHealthChecker creates the checker without checks.
+func NewHealthChecker() *HealthChecker {
+	return &HealthChecker{}
+}
+
+// Add registers the check, which returns an error if the component is unhealthy.
+func (h *HealthChecker) Add(name string, check func() error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.names = append(h.names, name)
+	h.checks = append(h.checks, check)
+}
+
+// Check runs the checks in the order of their registration and returns the first failure.
+func (h *HealthChecker) Check() error {
+	h.mu.Lock()
+	names, checks := h.names, h.checks
+	h.mu.Unlock()
+	for i, check := range checks {
+		if err := check(); err != nil {
+			return fmt.Errorf("%s: %w", names[i], err)
+		}
+	}
+	return nil
+}
+
+// ProgressCheck returns the check which fails once progress hasn't changed for longer than
+// maxStall, e.g. the last processed block of a node whose block processing is hung.
+// It must be used only for the progress which changes regularly while the node is healthy.
+func ProgressCheck(progress func() uint64, maxStall time.Duration, c clock.Clock) func() error {
+	var (
+		mu      sync.Mutex
+		last    = progress()
+		changed = c.Now()
+	)
+	return func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		now := c.Now()
+		if cur := progress(); cur != last {
+			last, changed = cur, now
+			return nil
+		}
+		if stall := now.Sub(changed); stall > maxStall {
+			return fmt.Errorf("no progress since %d for %v", last, stall.Round(time.Second))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,258 @@
+// This file implements the opt-in anonymous telemetry. It's off unless --telemetry=on is passed.
+// When it's on, the node periodically posts a small report (version, network, OS/arch and the
+// sync status) to the configured endpoint, so the maintainers know which versions are deployed.
+// The report never contains addresses, keys, peer or validator IDs, and `opera telemetry show`
+// prints exactly what would be sent.
+
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// TelemetryConfig is the config of the telemetry.
+type TelemetryConfig struct {
+	Enabled  bool
+	Endpoint string        // URL the reports are posted to, it must be set to enable the telemetry
+	Interval time.Duration // interval between the reports
+}
+
+// Sync statuses of the telemetry report.
+const (
+	SyncStatusUnknown = "unknown"
+	SyncStatusSyncing = "syncing"
+	SyncStatusSynced  = "synced"
+)
+
+// TelemetryReport is the anonymous report of a node.
+type TelemetryReport struct {
+	Version    string `json:"version"`
+	Network    string `json:"network"` // mainnet, testnet, fakenet or custom
+	GoVersion  string `json:"goVersion"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+	SyncStatus string `json:"syncStatus"`
+}
+
+// NewTelemetryReport creates the report of the node.
+func NewTelemetryReport(cfg Config, syncStatus string) TelemetryReport {
+	// the name of a custom network may identify its operator, so it's never reported
+	network := "custom"
+	switch {
+	case cfg.Opera.NetworkID == opera.MainNetworkID:
+		network = "mainnet"
+	case cfg.Opera.NetworkID == opera.TestNetworkID:
+		network = "testnet"
+	case cfg.Opera.FakeNet:
+		network = "fakenet"
+	}
+	return TelemetryReport{
+		Version:    params.VersionWithCommit(gitCommit, gitDate),
+		Network:    network,
+		GoVersion:  runtime.Version(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		SyncStatus: syncStatus,
+	}
+}
+
+// SendTelemetry posts the report to the endpoint.
+func SendTelemetry(ctx context.Context, client *http.Client, endpoint string, report TelemetryReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("telemetry endpoint replied %s", resp.Status)
+	}
+	return nil
+}
+
+// RunTelemetry posts the reports periodically until ctx is cancelled. It does nothing
+// if the telemetry is off. Failures are only logged, as the telemetry never affects the node.
+func RunTelemetry(ctx context.Context, cfg Config, syncStatus func() string) {
+	if !cfg.Telemetry.Enabled {
+		return
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	ticker := time.NewTicker(cfg.Telemetry.Interval)
+	defer ticker.Stop()
+	for {
+		if err := SendTelemetry(ctx, client, cfg.Telemetry.Endpoint, NewTelemetryReport(cfg, syncStatus())); err != nil {
+			log.Debug("Failed to send telemetry", "err", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// parseTelemetryFlag parses the --telemetry value.
+func parseTelemetryFlag(v string) (bool, error) {
+	switch v {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid --telemetry value %q, must be on or off", v)
+}
+
+// CheckTelemetryConfig checks that the reports may be sent if the telemetry is on.
+func CheckTelemetryConfig(cfg Config) error {
+	t := cfg.Telemetry
+	if !t.Enabled {
+		return nil
+	}
+	if t.Endpoint == "" {
+		return fmt.Errorf("telemetry is on, but --telemetry.endpoint isn't set")
+	}
+	u, err := url.Parse(t.Endpoint)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid telemetry endpoint %q", t.Endpoint)
+	}
+	if t.Interval < time.Minute {
+		return fmt.Errorf("telemetry interval %v is below 1m", t.Interval)
+	}
+	return nil
+}
+
+func checkTelemetry(cfg Config, report *ConfigReport) {
+	t := cfg.Telemetry
+	if !t.Enabled {
+		report.add("telemetry", CheckPass, "off")
+		return
+	}
+	if err := CheckTelemetryConfig(cfg); err != nil {
+		report.add("telemetry", CheckFail, "%v", err)
+		return
+	}
+	if u, _ := url.Parse(t.Endpoint); u.Scheme != "https" {
+		report.add("telemetry", CheckWarn, "reports are sent unencrypted to %s", t.Endpoint)
+		return
+	}
+	report.add("telemetry", CheckPass, "reporting to %s every %v", t.Endpoint, t.Interval)
+}
+
+// -----------------------------------------------------------------------------
+// Commands
+// -----------------------------------------------------------------------------
+
+var telemetryRPCFlag = cli.StringFlag{
+	Name:  "rpc",
+	Usage: "RPC endpoint (HTTP, WS or IPC) of a running node to take the sync status from",
+}
+
+func telemetryCommand() cli.Command {
+	return cli.Command{
+		Name:     "telemetry",
+		Usage:    "Anonymous telemetry helpers",
+		Category: "MISCELLANEOUS COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:   "show",
+				Usage:  "Print exactly the report the node would send",
+				Action: showTelemetryAction,
+				Flags:  append(configFlags(), telemetryRPCFlag),
+				Description: `
+    opera telemetry show [--rpc url] [flags]
+
+Prints the telemetry report built from the config and the flags, along with whether
+and where it would be sent. The sync status is taken from a running node via
+eth_syncing if --rpc is given, and reported as "unknown" otherwise.`,
+			},
+		},
+	}
+}
+
+func showTelemetryAction(ctx *cli.Context) error {
+	cfg, err := makeConfig(ctx)
+	if err != nil {
+		return err
+	}
+	status := SyncStatusUnknown
+	if endpoint := ctx.String(telemetryRPCFlag.Name); endpoint != "" {
+		if status, err = nodeSyncStatus(context.Background(), endpoint); err != nil {
+			return err
+		}
+	}
+
+	raw, err := json.MarshalIndent(NewTelemetryReport(cfg, status), "", "  ")
+	if err != nil {
+		return err
+	}
+	if cfg.Telemetry.Enabled {
+		fmt.Printf("Telemetry is on, every %v the report is sent to %s:\n", cfg.Telemetry.Interval, cfg.Telemetry.Endpoint)
+	} else {
+		fmt.Println("Telemetry is off (pass --telemetry=on to enable), nothing is sent. The report would be:")
+	}
+	fmt.Println(string(raw))
+	return nil
+}
+
+// nodeSyncStatus returns the sync status of a running node.
+func nodeSyncStatus(ctx context.Context, endpoint string) (string, error) {
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+	var syncing interface{}
+	if err := client.CallContext(ctx, &syncing, "eth_syncing"); err != nil {
+		return "", err
+	}
+	if syncing == false {
+		return SyncStatusSynced, nil
+	}
+	return SyncStatusSyncing, nil
+}
+
+// licenseCommand reports the third-party components the binary is built of.
+func licenseCommand() cli.Command {
+	return cli.Command{
+		Name:     "license",
+		Usage:    "List the third-party components of the binary",
+		Category: "MISCELLANEOUS COMMANDS",
+		Action: func(ctx *cli.Context) error {
+			info, ok := debug.ReadBuildInfo()
+			if !ok {
+				return fmt.Errorf("the binary has no build info")
+			}
+			fmt.Printf("%s is built of the following third-party components:\n", info.Main.Path)
+			for _, dep := range info.Deps {
+				if dep.Replace != nil {
+					dep = dep.Replace
+				}
+				fmt.Printf("  %s %s\n", dep.Path, dep.Version)
+			}
+			return nil
+		},
+	}
+}
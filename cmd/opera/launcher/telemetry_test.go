@@ -0,0 +1,19 @@
+package launcher
+
+import "testing"
+
+// TestCheckTelemetry verifies the config check of the telemetry.
+func TestCheckTelemetry(t *testing.T) {
+	runCheckCases(t, []checkCase{
+		{
+			name: "telemetry without endpoint",
+			args: []string{"--telemetry", "on"},
+			want: map[string]CheckStatus{"telemetry": CheckFail},
+		},
+		{
+			name: "telemetry over plain HTTP",
+			args: []string{"--telemetry", "on", "--telemetry.endpoint", "http://example.com/report"},
+			want: map[string]CheckStatus{"telemetry": CheckWarn},
+		},
+	})
+}
@@ -0,0 +1,693 @@
+// This file implements `opera validator rotate-key`, the guided rotation of the validator's key.
+// A new key is generated into the validator keystore (encrypted by the validator's password, see
+// valkeystore), and the key change transaction is sent to the SFC by the validator's auth account.
+// Once the change is pending, the new key and the epoch it's active since are published by
+// atomically replacing ActiveValidatorKeyFile, which the running node watches (see
+// WatchActiveValidatorKey). The emitter's signer holds the new key as the pending one and signs
+// the events of that epoch with it, so the validator never signs events of one epoch with two keys.
+//
+// The progress is persisted in KeyRotationFile before each step, so an interrupted rotation is
+// resumed instead of generating another key or sending another transaction.
+
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/rony4d/go-opera-asset/gossip/emitter"
+	"github.com/rony4d/go-opera-asset/inter/validatorpk"
+	"github.com/rony4d/go-opera-asset/opera/contracts/sfc"
+	"github.com/rony4d/go-opera-asset/valkeystore"
+)
+
+const (
+	// KeyRotationFile is the name of the key rotation progress in the network datadir.
+	KeyRotationFile = "validator-rotation.json"
+	// ActiveValidatorKeyFile is the name of the emitter's key in the network datadir,
+	// it overrides the configured validator key (see applyActiveValidatorKey).
+	ActiveValidatorKeyFile = "validator-key.json"
+
+	// activeValidatorKeyPoll is the interval of checking ActiveValidatorKeyFile by the running node.
+	activeValidatorKeyPoll = time.Second
+)
+
+var (
+	errNoValidatorID      = errors.New("the validator ID isn't set")
+	errPendingKeyChange   = errors.New("the validator already has a key change pending until the next epoch")
+	errValidatorLeaving   = errors.New("the validator isn't in the validators set of the next epoch")
+	errKeyChangeReverted  = errors.New("the key change transaction is reverted")
+	errRotationInProgress = errors.New("another key rotation is in progress")
+	errNoCurrentKey       = errors.New("the current key isn't in the validator keystore")
+)
+
+// KeyRotationStage is the last completed step of the key rotation.
+type KeyRotationStage string
+
+const (
+	RotationStarted   KeyRotationStage = "started"   // the rotation may start, the new key may be not stored yet
+	RotationGenerated KeyRotationStage = "generated" // the new key is in the keystore
+	RotationSubmitted KeyRotationStage = "submitted" // the key change transaction is sent
+	RotationScheduled KeyRotationStage = "scheduled" // the emitter has the new key for its activation epoch
+	RotationSwitched  KeyRotationStage = "switched"  // the new key is active
+)
+
+// KeyRotation is the progress of the validator's key rotation.
+type KeyRotation struct {
+	Validator idx.ValidatorID
+	OldPubkey string
+	NewPubkey string      // recorded before the key is stored, so it's never orphaned
+	KeyFile   string      // the new key in the validator keystore
+	TxHash    common.Hash // the key change transaction, zero if it's sent by an interrupted run
+	Epoch     idx.Epoch   // the epoch the new key is active since, once it's scheduled
+	Stage     KeyRotationStage
+}
+
+// ActiveValidatorKey is the key the emitter signs with.
+type ActiveValidatorKey struct {
+	Validator idx.ValidatorID
+	Pubkey    string
+	// Epoch is the epoch Pubkey is active since, the earlier epochs are signed with PrevPubkey.
+	// PrevPubkey is dropped once the rotation is finished.
+	Epoch      idx.Epoch `json:",omitempty"`
+	PrevPubkey string    `json:",omitempty"`
+}
+
+// ValidatorPubkeys are the validator's keys as seen by the network.
+type ValidatorPubkeys struct {
+	Epoch   idx.Epoch
+	Current validatorpk.PubKey
+	Next    validatorpk.PubKey // empty if the validator leaves at the end of the epoch
+}
+
+// ValidatorKeyChain is the access to the network the key rotation needs.
+type ValidatorKeyChain interface {
+	// ValidatorPubkeys returns the validator's keys of the current and the next epochs.
+	ValidatorPubkeys(ctx context.Context, validator idx.ValidatorID) (ValidatorPubkeys, error)
+	// SubmitPubkeyChange sends the transaction changing the validator's key since the next epoch.
+	SubmitPubkeyChange(ctx context.Context, pubkey validatorpk.PubKey) (common.Hash, error)
+	// WaitMined waits until the transaction is executed, and returns an error if it's reverted.
+	WaitMined(ctx context.Context, tx common.Hash) error
+}
+
+// RotateValidatorKey rotates the key of the configured validator, resuming the rotation in progress.
+// The new key is encrypted by the password. poll is the interval of polling the network for the new
+// key activation.
+func RotateValidatorKey(ctx context.Context, cfg Config, chain ValidatorKeyChain, password string, poll time.Duration) (*KeyRotation, error) {
+	if cfg.Node.ReadOnly {
+		return nil, ErrReadOnly
+	}
+	if cfg.Emitter.ValidatorID == 0 {
+		return nil, errNoValidatorID
+	}
+	path := filepath.Join(cfg.NetworkDataDir(), KeyRotationFile)
+	r := new(KeyRotation)
+	ok, err := readJSONFile(path, r)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if r, err = startKeyRotation(ctx, cfg, chain, password); err != nil {
+			return nil, err
+		}
+		if err := writeJSONFile(path, r); err != nil {
+			return nil, err
+		}
+	} else if r.Validator != idx.ValidatorID(cfg.Emitter.ValidatorID) {
+		return nil, fmt.Errorf("%w for validator %d, see %s", errRotationInProgress, r.Validator, path)
+	} else {
+		log.Info("Resuming the key rotation", "validator", r.Validator, "stage", r.Stage, "pubkey", r.NewPubkey)
+	}
+
+	for r.Stage != RotationSwitched {
+		if err := advanceKeyRotation(ctx, cfg, chain, password, poll, r); err != nil {
+			return r, err
+		}
+		if err := writeJSONFile(path, r); err != nil {
+			return r, err
+		}
+		log.Info("Key rotation progress", "validator", r.Validator, "stage", r.Stage)
+	}
+	return r, os.Remove(path)
+}
+
+// startKeyRotation checks that the key may be rotated.
+func startKeyRotation(ctx context.Context, cfg Config, chain ValidatorKeyChain, password string) (*KeyRotation, error) {
+	validator := idx.ValidatorID(cfg.Emitter.ValidatorID)
+	keys, err := chain.ValidatorPubkeys(ctx, validator)
+	if err != nil {
+		return nil, err
+	}
+	if keys.Next.Empty() {
+		return nil, errValidatorLeaving
+	}
+	if !samePubkey(keys.Current, keys.Next) {
+		return nil, fmt.Errorf("%w: %s", errPendingKeyChange, keys.Next)
+	}
+	// a node which doesn't sign with the current key must not rotate it, or another node
+	// signing with it would keep emitting alongside this one after the switch
+	if cfg.Emitter.ValidatorKey != "" {
+		if emitterKey, err := validatorpk.FromString(cfg.Emitter.ValidatorKey); err != nil || !samePubkey(emitterKey, keys.Current) {
+			return nil, fmt.Errorf("the emitter's key %s isn't the key %s of validator %d", cfg.Emitter.ValidatorKey, keys.Current, validator)
+		}
+	}
+
+	// the emitter unlocks the new key by the password it unlocks the current one with,
+	// and signs the epochs before the switch with the current one
+	ks := validatorKeystore(cfg)
+	if !ks.Has(keys.Current) {
+		return nil, fmt.Errorf("%w: %s", errNoCurrentKey, ks.PathOf(keys.Current))
+	}
+	if _, err := ks.Get(keys.Current, password); err != nil {
+		return nil, fmt.Errorf("failed to unlock the current key %s: %w", keys.Current, err)
+	}
+	return &KeyRotation{
+		Validator: validator,
+		OldPubkey: keys.Current.String(),
+		Stage:     RotationStarted,
+	}, nil
+}
+
+// generateRotationKey generates the new key. Its public key is persisted before the key
+// is stored, so an interrupted run either finds the key in the keystore, or generates another
+// one, as nothing is sent yet.
+func generateRotationKey(cfg Config, password string, r *KeyRotation) error {
+	ks := validatorKeystore(cfg)
+	if r.NewPubkey != "" {
+		if pubkey, err := validatorpk.FromString(r.NewPubkey); err == nil && ks.Has(pubkey) {
+			r.Stage = RotationGenerated
+			return nil
+		}
+	}
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return err
+	}
+	pubkey := validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: crypto.FromECDSAPub(&key.PublicKey)}
+	r.NewPubkey = pubkey.String()
+	r.KeyFile = ks.PathOf(pubkey)
+	if err := writeJSONFile(filepath.Join(cfg.NetworkDataDir(), KeyRotationFile), r); err != nil {
+		return err
+	}
+	if err := ks.Add(pubkey, key, password); err != nil {
+		return err
+	}
+	log.Info("Generated the new validator key", "validator", r.Validator, "pubkey", pubkey, "file", r.KeyFile)
+	r.Stage = RotationGenerated
+	return nil
+}
+
+// advanceKeyRotation completes the next step of the rotation.
+func advanceKeyRotation(ctx context.Context, cfg Config, chain ValidatorKeyChain, password string, poll time.Duration, r *KeyRotation) error {
+	if r.Stage == RotationStarted {
+		return generateRotationKey(cfg, password, r)
+	}
+	newKey, err := validatorpk.FromString(r.NewPubkey)
+	if err != nil {
+		return err
+	}
+	activePath := filepath.Join(cfg.NetworkDataDir(), ActiveValidatorKeyFile)
+	switch r.Stage {
+	case RotationGenerated:
+		keys, err := chain.ValidatorPubkeys(ctx, r.Validator)
+		if err != nil {
+			return err
+		}
+		if !samePubkey(keys.Next, newKey) {
+			if r.TxHash, err = chain.SubmitPubkeyChange(ctx, newKey); err != nil {
+				return err
+			}
+		} // otherwise it's sent by an interrupted run
+		r.Stage = RotationSubmitted
+
+	case RotationSubmitted:
+		if r.TxHash != (common.Hash{}) {
+			if err := chain.WaitMined(ctx, r.TxHash); err != nil {
+				return err
+			}
+		}
+		keys, err := chain.ValidatorPubkeys(ctx, r.Validator)
+		if err != nil {
+			return err
+		}
+		var active ActiveValidatorKey
+		if _, err := readJSONFile(activePath, &active); err != nil {
+			return err
+		}
+		switch {
+		case active.Validator == r.Validator && active.Pubkey == r.NewPubkey:
+			// scheduled by an interrupted run
+			r.Epoch = active.Epoch
+		case samePubkey(keys.Current, newKey):
+			// the epoch is sealed before the change is noticed
+			r.Epoch = keys.Epoch
+		case samePubkey(keys.Next, newKey):
+			r.Epoch = keys.Epoch + 1
+		default:
+			return fmt.Errorf("the key change to %s isn't pending, the key of the next epoch is %s", newKey, keys.Next)
+		}
+		active = ActiveValidatorKey{Validator: r.Validator, Pubkey: r.NewPubkey, Epoch: r.Epoch, PrevPubkey: r.OldPubkey}
+		if err := writeJSONFile(activePath, active); err != nil {
+			return err
+		}
+		r.Stage = RotationScheduled
+
+	case RotationScheduled:
+		for {
+			keys, err := chain.ValidatorPubkeys(ctx, r.Validator)
+			if err != nil {
+				return err
+			}
+			if samePubkey(keys.Current, newKey) {
+				break
+			}
+			if !samePubkey(keys.Next, newKey) {
+				return fmt.Errorf("the key change to %s isn't pending, the key of the next epoch is %s", newKey, keys.Next)
+			}
+			log.Info("Waiting for the epoch boundary to activate the new key", "epoch", keys.Epoch)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(poll):
+			}
+		}
+		// the old key isn't needed anymore
+		active := ActiveValidatorKey{Validator: r.Validator, Pubkey: r.NewPubkey, Epoch: r.Epoch}
+		if err := writeJSONFile(activePath, active); err != nil {
+			return err
+		}
+		r.Stage = RotationSwitched
+
+	default:
+		return fmt.Errorf("unknown key rotation stage %q", r.Stage)
+	}
+	return nil
+}
+
+// applyActiveValidatorKey makes the emitter of the started node sign with the key switched to
+// by the key rotation, since its activation epoch (see openValidatorSigner).
+// The running node is switched by WatchActiveValidatorKey.
+func applyActiveValidatorKey(cfg *Config) {
+	var active ActiveValidatorKey
+	ok, err := readJSONFile(filepath.Join(cfg.NetworkDataDir(), ActiveValidatorKeyFile), &active)
+	if err != nil {
+		log.Warn("Failed to read the active validator key", "err", err)
+		return
+	}
+	if !ok || (cfg.Emitter.ValidatorID != 0 && idx.ValidatorID(cfg.Emitter.ValidatorID) != active.Validator) {
+		return
+	}
+	cfg.Emitter.ValidatorID = uint32(active.Validator)
+	cfg.Emitter.ValidatorKey = active.Pubkey
+}
+
+// readActiveValidatorKey returns the key switched to by the key rotation of the configured validator.
+func readActiveValidatorKey(cfg Config) (*ActiveValidatorKey, error) {
+	var active ActiveValidatorKey
+	ok, err := readJSONFile(filepath.Join(cfg.NetworkDataDir(), ActiveValidatorKeyFile), &active)
+	if err != nil || !ok || active.Validator != idx.ValidatorID(cfg.Emitter.ValidatorID) {
+		return nil, err
+	}
+	return &active, nil
+}
+
+// LoadActiveValidatorKey gives the signer the key switched to by the key rotation, decrypting
+// it by the password. The signer signs with it since its activation epoch.
+// Returns true if the signer is given a new key.
+func LoadActiveValidatorKey(cfg Config, signer *emitter.Signer, password string) (bool, error) {
+	active, err := readActiveValidatorKey(cfg)
+	if err != nil || active == nil {
+		return false, err
+	}
+	pubkey, err := validatorpk.FromString(active.Pubkey)
+	if err != nil {
+		return false, err
+	}
+	if samePubkey(pubkey, signer.Latest()) {
+		return false, nil
+	}
+	key, err := validatorKeystore(cfg).Get(pubkey, password)
+	if err != nil {
+		return false, err
+	}
+	return true, signer.SetPending(active.Epoch, pubkey, key)
+}
+
+// WatchActiveValidatorKey gives the signer of the running emitter the key the rotation switches to,
+// checking ActiveValidatorKeyFile every interval. The rotation publishes the key once the change is
+// pending, so the signer has it before the epoch boundary.
+// Returns the function which stops watching.
+func WatchActiveValidatorKey(cfg Config, signer *emitter.Signer, password string, interval time.Duration) (stop func()) {
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-quit:
+				return
+			case <-time.After(interval):
+			}
+			loaded, err := LoadActiveValidatorKey(cfg, signer, password)
+			if err != nil {
+				log.Error("Failed to load the new validator key", "err", err)
+			} else if loaded {
+				log.Info("Emitter has the new validator key", "pubkey", signer.Latest())
+			}
+		}
+	}()
+	return func() {
+		close(quit)
+		<-done
+	}
+}
+
+// openValidatorSigner unlocks the configured validator key for the emitter. If the key rotation
+// isn't finished yet, the signer signs the epochs before the switch with the previous key.
+func openValidatorSigner(cfg Config) (*emitter.Signer, string, error) {
+	pubkey, err := validatorpk.FromString(cfg.Emitter.ValidatorKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid validator key %q: %w", cfg.Emitter.ValidatorKey, err)
+	}
+	password, err := validatorPassword(cfg, false)
+	if err != nil {
+		return nil, "", err
+	}
+	active, err := readActiveValidatorKey(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	if active == nil || active.PrevPubkey == "" || active.Pubkey != cfg.Emitter.ValidatorKey {
+		key, err := validatorKeystore(cfg).Get(pubkey, password)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to unlock the validator key %s: %w", pubkey, err)
+		}
+		signer, err := emitter.NewSigner(pubkey, key)
+		return signer, password, err
+	}
+
+	prevPubkey, err := validatorpk.FromString(active.PrevPubkey)
+	if err != nil {
+		return nil, "", err
+	}
+	prevKey, err := validatorKeystore(cfg).Get(prevPubkey, password)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to unlock the validator key %s: %w", prevPubkey, err)
+	}
+	signer, err := emitter.NewSigner(prevPubkey, prevKey)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := LoadActiveValidatorKey(cfg, signer, password); err != nil {
+		return nil, "", err
+	}
+	return signer, password, nil
+}
+
+// validatorKeystore returns the keystore of the validator keys.
+// The fakenet keys are throwaway, so they're encrypted with the light KDF.
+func validatorKeystore(cfg Config) *valkeystore.FileKeystore {
+	if cfg.Opera.FakeNet {
+		return valkeystore.NewFileKeystore(keyStoreDir(cfg), keystore.LightScryptN, keystore.LightScryptP)
+	}
+	return valkeystore.NewFileKeystore(keyStoreDir(cfg), keystore.StandardScryptN, keystore.StandardScryptP)
+}
+
+// validatorPassword returns the password of the validator keys: the first line of the password file,
+// the configured password, or the one entered by the user (twice if confirm is true).
+func validatorPassword(cfg Config, confirm bool) (string, error) {
+	if cfg.Emitter.PasswordFile != "" {
+		b, err := ioutil.ReadFile(cfg.Emitter.PasswordFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the password file: %w", err)
+		}
+		return strings.TrimRight(strings.SplitN(string(b), "\n", 2)[0], "\r"), nil
+	}
+	if cfg.Emitter.Password != "" {
+		return cfg.Emitter.Password, nil
+	}
+	password, err := prompt.Stdin.PromptPassword("Validator key password: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm {
+		repeated, err := prompt.Stdin.PromptPassword("Repeat the password: ")
+		if err != nil {
+			return "", err
+		}
+		if repeated != password {
+			return "", errors.New("passwords don't match")
+		}
+	}
+	return password, nil
+}
+
+func checkKeyRotation(cfg Config, report *ConfigReport) {
+	var r KeyRotation
+	ok, err := readJSONFile(filepath.Join(cfg.NetworkDataDir(), KeyRotationFile), &r)
+	if err != nil {
+		report.add("keyrotation", CheckFail, "unreadable key rotation progress: %v", err)
+		return
+	}
+	if !ok {
+		report.add("keyrotation", CheckPass, "no key rotation in progress")
+		return
+	}
+	report.add("keyrotation", CheckWarn, "key rotation of validator %d is interrupted at stage %q, resume it with `opera validator rotate-key`", r.Validator, r.Stage)
+}
+
+func samePubkey(a, b validatorpk.PubKey) bool {
+	return a.Type == b.Type && bytes.Equal(a.Raw, b.Raw)
+}
+
+// readJSONFile decodes the file into v, and returns false if the file doesn't exist.
+func readJSONFile(path string, v interface{}) (bool, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(b, v)
+}
+
+// writeJSONFile replaces the file atomically, so it's never observed half-written.
+func writeJSONFile(path string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path+".tmp", b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// rpcValidatorKeyChain accesses the network via the RPC of a running node.
+type rpcValidatorKeyChain struct {
+	client *rpc.Client
+	eth    *ethclient.Client
+	auth   *ecdsa.PrivateKey
+	poll   time.Duration
+}
+
+// NewRPCValidatorKeyChain returns the access to the network via the RPC of a running node.
+// The key change transactions are sent by the auth account of the validator.
+func NewRPCValidatorKeyChain(client *rpc.Client, auth *ecdsa.PrivateKey, poll time.Duration) ValidatorKeyChain {
+	return &rpcValidatorKeyChain{
+		client: client,
+		eth:    ethclient.NewClient(client),
+		auth:   auth,
+		poll:   poll,
+	}
+}
+
+func (c *rpcValidatorKeyChain) ValidatorPubkeys(ctx context.Context, validator idx.ValidatorID) (ValidatorPubkeys, error) {
+	var res struct {
+		Epoch      hexutil.Uint64 `json:"epoch"`
+		Pubkey     string         `json:"pubkey"`
+		NextPubkey string         `json:"nextPubkey"`
+	}
+	if err := c.client.CallContext(ctx, &res, "abft_getValidatorPubkey", hexutil.Uint(validator)); err != nil {
+		return ValidatorPubkeys{}, err
+	}
+	keys := ValidatorPubkeys{Epoch: idx.Epoch(res.Epoch)}
+	var err error
+	if keys.Current, err = validatorpk.FromString(res.Pubkey); err != nil {
+		return keys, err
+	}
+	if res.NextPubkey != "" {
+		if keys.Next, err = validatorpk.FromString(res.NextPubkey); err != nil {
+			return keys, err
+		}
+	}
+	return keys, nil
+}
+
+func (c *rpcValidatorKeyChain) SubmitPubkeyChange(ctx context.Context, pubkey validatorpk.PubKey) (common.Hash, error) {
+	from := crypto.PubkeyToAddress(c.auth.PublicKey)
+	data := sfc.UpdateValidatorPubkey(pubkey)
+	chainID, err := c.eth.ChainID(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	nonce, err := c.eth.PendingNonceAt(ctx, from)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	gasPrice, err := c.eth.SuggestGasPrice(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	// the estimation fails if the SFC rejects the change, e.g. if the sender isn't the validator's auth account
+	gas, err := c.eth.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &sfc.ContractAddress, Data: data})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("key change is rejected: %w", err)
+	}
+	tx, err := types.SignTx(types.NewTransaction(nonce, sfc.ContractAddress, new(big.Int), gas, gasPrice, data), types.LatestSignerForChainID(chainID), c.auth)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), c.eth.SendTransaction(ctx, tx)
+}
+
+func (c *rpcValidatorKeyChain) WaitMined(ctx context.Context, tx common.Hash) error {
+	for {
+		receipt, err := c.eth.TransactionReceipt(ctx, tx)
+		if err == nil {
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				return fmt.Errorf("%w: %s", errKeyChangeReverted, tx.Hex())
+			}
+			return nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.poll):
+		}
+	}
+}
+
+var (
+	validatorRPCFlag = cli.StringFlag{
+		Name:  "rpc",
+		Usage: "RPC endpoint (HTTP, WS or IPC) of the node to send the key change to",
+		Value: "http://localhost:18545",
+	}
+	validatorIDFlag = cli.UintFlag{
+		Name:  "validator",
+		Usage: "ID of the validator whose key is rotated",
+	}
+	validatorAuthKeyFlag = cli.StringFlag{
+		Name:  "auth.key",
+		Usage: "File with the hex private key of the validator's auth account, which sends the key change",
+	}
+	validatorPollFlag = cli.DurationFlag{
+		Name:  "poll",
+		Usage: "Interval of polling the node for the new key activation",
+		Value: time.Second,
+	}
+	validatorTimeoutFlag = cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "Time to wait for the new key activation, the rotation may be resumed by running the command again",
+		Value: 24 * time.Hour,
+	}
+)
+
+func validatorCommand() cli.Command {
+	return cli.Command{
+		Name:     "validator",
+		Usage:    "Validator management",
+		Category: "MISCELLANEOUS COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:   "rotate-key",
+				Usage:  "Rotate the validator's key without downtime",
+				Action: rotateKeyAction,
+				Flags: append([]cli.Flag{
+					validatorRPCFlag,
+					validatorIDFlag,
+					validatorAuthKeyFlag,
+					validatorPollFlag,
+					validatorTimeoutFlag,
+				}, configFlags()...),
+				Description: `
+    opera validator rotate-key --validator ID --auth.key file [--rpc url] [flags]
+
+Generates a new validator key into the validator keystore, encrypted by the password (see
+--validator.password, prompted for if it isn't set), sends the key change to the SFC from the
+validator's auth account, and once the change is pending, gives the new key to the emitter
+(see validator-key.json in the network datadir), which signs with it since the next epoch.
+Then waits until the new key becomes active at the epoch boundary.
+An interrupted rotation is resumed by running the command again.`,
+			},
+		},
+	}
+}
+
+func rotateKeyAction(ctx *cli.Context) error {
+	cfg, err := makeConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if ctx.IsSet(validatorIDFlag.Name) {
+		cfg.Emitter.ValidatorID = uint32(ctx.Uint(validatorIDFlag.Name))
+	}
+	keyFile := ctx.String(validatorAuthKeyFlag.Name)
+	if keyFile == "" {
+		return fmt.Errorf("--%s isn't specified", validatorAuthKeyFlag.Name)
+	}
+	auth, err := crypto.LoadECDSA(keyFile)
+	if err != nil {
+		return err
+	}
+	if err := ensureDir(cfg.NetworkDataDir()); err != nil {
+		return err
+	}
+
+	client, err := rpc.Dial(ctx.String(validatorRPCFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	poll := ctx.Duration(validatorPollFlag.Name)
+	runCtx, cancel := context.WithTimeout(context.Background(), ctx.Duration(validatorTimeoutFlag.Name))
+	defer cancel()
+	password, err := validatorPassword(cfg, true)
+	if err != nil {
+		return err
+	}
+	r, err := RotateValidatorKey(runCtx, cfg, NewRPCValidatorKeyChain(client, auth, poll), password, poll)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Validator %d signs with the new key %s, stored in %s\n", r.Validator, r.NewPubkey, r.KeyFile)
+	return nil
+}
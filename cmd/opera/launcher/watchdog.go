@@ -0,0 +1,219 @@
+// This file implements the optional memory watchdog. Long-running nodes slowly grow their
+// resident memory because of the heap fragmentation, until they get OOM-killed at a random
+// moment. The watchdog restarts the node in a controlled way instead: once the RSS exceeds
+// the threshold, it waits for a safe point (the next sealed epoch), enters the validator
+// maintenance mode so no event is half-built, then stops the node cleanly and restarts it.
+
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/rony4d/go-opera-asset/gossip/emitter"
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+// WatchdogConfig is the config of the memory watchdog.
+type WatchdogConfig struct {
+	MaxRSSMB  uint64        // resident memory which triggers the restart, 0 disables the watchdog
+	Interval  time.Duration // interval between the memory checks
+	MinUptime time.Duration // the node isn't restarted earlier after its start, which prevents restart loops
+}
+
+// Enabled returns true if the watchdog is configured to run.
+func (c WatchdogConfig) Enabled() bool {
+	return c.MaxRSSMB != 0
+}
+
+var (
+	rssGauge               = metrics.NewRegisteredGauge("watchdog/rss", nil)
+	watchdogArmedGauge     = metrics.NewRegisteredGauge("watchdog/armed", nil)
+	errRestartNotSupported = errors.New("restart isn't supported on this platform")
+)
+
+// MemoryWatchdog restarts the node at a safe point once its memory exceeds the threshold.
+// It's safe for concurrent use.
+type MemoryWatchdog struct {
+	cfg         WatchdogConfig
+	maintenance *emitter.Maintenance
+	rss         func() uint64 // resident memory in bytes, 0 if unknown
+	restart     func() error  // stops the node and starts it again, doesn't return if the process is replaced
+	clock       clock.Clock
+	started     time.Time
+
+	sealed chan idx.Epoch
+
+	mu    sync.Mutex
+	armed bool
+}
+
+// NewMemoryWatchdog creates the watchdog. The restart function must stop the node cleanly
+// and start it again (see RestartProcess).
+func NewMemoryWatchdog(cfg WatchdogConfig, maintenance *emitter.Maintenance, rss func() uint64, restart func() error, c clock.Clock) *MemoryWatchdog {
+	return &MemoryWatchdog{
+		cfg:         cfg,
+		maintenance: maintenance,
+		rss:         rss,
+		restart:     restart,
+		clock:       c,
+		started:     c.Now(),
+		sealed:      make(chan idx.Epoch, 1),
+	}
+}
+
+// Check samples the memory and arms the restart if the memory exceeds the threshold.
+// Returns true if the restart is armed.
+func (w *MemoryWatchdog) Check() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	rss := w.rss()
+	rssGauge.Update(int64(rss))
+	if w.armed || rss == 0 || rss < w.cfg.MaxRSSMB*1024*1024 {
+		return w.armed
+	}
+	if uptime := w.clock.Now().Sub(w.started); uptime < w.cfg.MinUptime {
+		log.Warn("Memory watchdog threshold exceeded too early after the start, not restarting",
+			"rss_mb", rss/1024/1024, "threshold_mb", w.cfg.MaxRSSMB, "uptime", uptime)
+		return false
+	}
+	w.armed = true
+	watchdogArmedGauge.Update(1)
+	log.Warn("Memory watchdog threshold exceeded, restarting at the next epoch",
+		"rss_mb", rss/1024/1024, "threshold_mb", w.cfg.MaxRSSMB)
+	return true
+}
+
+// SealedEpoch must be called by the node once an epoch is sealed. It never blocks.
+func (w *MemoryWatchdog) SealedEpoch(epoch idx.Epoch) {
+	select {
+	case w.sealed <- epoch:
+	default:
+		// a safe point is already pending
+	}
+}
+
+// SafePoint restarts the node if the restart is armed. The pending event emissions are
+// finished first, so the node doesn't risk a conflicting event after the restart.
+// Returns false if the restart isn't armed. If the restart fails, the maintenance mode
+// is left (unless it was entered by the operator) and the restart is retried at the next epoch.
+func (w *MemoryWatchdog) SafePoint(ctx context.Context, epoch idx.Epoch) (bool, error) {
+	w.mu.Lock()
+	armed := w.armed
+	w.mu.Unlock()
+	if !armed {
+		return false, nil
+	}
+
+	wasEnabled, _ := w.maintenance.Status()
+	if err := w.maintenance.Enter(ctx); err != nil {
+		if !wasEnabled {
+			w.maintenance.Leave()
+		}
+		return true, fmt.Errorf("pending emissions aren't finished: %w", err)
+	}
+	log.Warn("Memory watchdog is restarting the node", "epoch", epoch)
+	err := w.restart()
+	if !wasEnabled {
+		w.maintenance.Leave()
+	}
+	if err != nil {
+		return true, fmt.Errorf("restart failed: %w", err)
+	}
+	w.mu.Lock()
+	w.armed = false
+	w.started = w.clock.Now()
+	w.mu.Unlock()
+	watchdogArmedGauge.Update(0)
+	return true, nil
+}
+
+// Run checks the memory periodically and restarts the node at the first sealed epoch
+// after the threshold is exceeded. It returns once ctx is cancelled.
+func (w *MemoryWatchdog) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.Check()
+		case epoch := <-w.sealed:
+			if _, err := w.SafePoint(ctx, epoch); err != nil {
+				log.Error("Memory watchdog failed to restart the node", "epoch", epoch, "err", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// ProcessRSS returns the resident memory of the process in bytes, or 0 if it's unknown.
+func ProcessRSS() uint64 {
+	return readRSS("/proc/self/statm")
+}
+
+// readRSS returns the resident memory of a statm file in bytes, or 0 if it's unavailable.
+func readRSS(path string) uint64 {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(raw))
+	if len(fields) < 2 {
+		return 0
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return pages * uint64(os.Getpagesize())
+}
+
+// RestartProcess returns the restart function of the watchdog: it calls stop, which must
+// stop the node cleanly, then replaces the process with a new instance of the same binary
+// with the same arguments and environment. If the process can't be replaced, it exits,
+// so the service manager starts the node again.
+func RestartProcess(stop func()) func() error {
+	return func() error {
+		stop()
+		err := execSelf()
+		log.Crit("Failed to restart the node, exiting", "err", err)
+		return err
+	}
+}
+
+// checkWatchdog warns about the thresholds which would never trigger, or would restart the node in a loop.
+func checkWatchdog(cfg Config, report *ConfigReport) {
+	w := cfg.Watchdog
+	if !w.Enabled() {
+		report.add("watchdog", CheckPass, "disabled")
+		return
+	}
+	if w.Interval <= 0 {
+		report.add("watchdog", CheckFail, "memory check interval %v must be positive", w.Interval)
+		return
+	}
+	if caches := uint64(cfg.OperaStore.CacheMB + cfg.LachesisStore.CacheMB); w.MaxRSSMB <= caches {
+		report.add("watchdog", CheckWarn, "RSS threshold %d MB doesn't exceed the caches (%d MB), the node will restart every %v",
+			w.MaxRSSMB, caches, w.MinUptime)
+		return
+	}
+	if mem := detectMemoryMB(); mem != 0 && w.MaxRSSMB >= mem {
+		report.add("watchdog", CheckWarn, "RSS threshold %d MB isn't below the available memory (%d MB), the node is OOM-killed first",
+			w.MaxRSSMB, mem)
+		return
+	}
+	report.add("watchdog", CheckPass, "restart above %d MB RSS, checked every %v", w.MaxRSSMB, w.Interval)
+}
@@ -0,0 +1,19 @@
+package launcher
+
+import "testing"
+
+// TestCheckWatchdog verifies the config check of the memory watchdog.
+func TestCheckWatchdog(t *testing.T) {
+	runCheckCases(t, []checkCase{
+		{
+			name: "watchdog below caches",
+			args: []string{"--watchdog.rss", "100"},
+			want: map[string]CheckStatus{"watchdog": CheckWarn},
+		},
+		{
+			name: "watchdog without interval",
+			args: []string{"--watchdog.rss", "1000000", "--watchdog.interval", "0s"},
+			want: map[string]CheckStatus{"watchdog": CheckFail},
+		},
+	})
+}
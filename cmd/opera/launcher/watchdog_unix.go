@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package launcher
+
+import (
+	"os"
+	"syscall"
+)
+
+// execSelf replaces the process with a new instance of the same binary.
+// It returns only on failure.
+func execSelf() error {
+	bin, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	return syscall.Exec(bin, os.Args, os.Environ())
+}
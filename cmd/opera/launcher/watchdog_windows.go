@@ -0,0 +1,7 @@
+package launcher
+
+// execSelf isn't supported on Windows, which can't replace the running process.
+// The node should be restarted by its service manager there.
+func execSelf() error {
+	return errRestartNotSupported
+}
@@ -0,0 +1,148 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+var (
+	errNotValidator = errors.New("not a validator of the epoch")
+	errNoEpochState = errors.New("epoch state isn't available")
+	errNoBlockFees  = errors.New("block fees aren't available")
+)
+
+// PublicAbftAPI provides an API to access consensus related information.
+type PublicAbftAPI struct {
+	b Backend
+}
+
+// NewPublicAbftAPI creates a new abft API instance.
+func NewPublicAbftAPI(b Backend) *PublicAbftAPI {
+	return &PublicAbftAPI{b}
+}
+
+// currentValidatorState returns the block-level state of the validator in the current epoch.
+func (s *PublicAbftAPI) currentValidatorState(ctx context.Context, validatorID hexutil.Uint) (*iblockproc.BlockState, *iblockproc.EpochState, *iblockproc.ValidatorBlockState, error) {
+	bs, es, err := s.b.GetEpochBlockState(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if bs == nil || es == nil {
+		return nil, nil, nil, errNoEpochState
+	}
+	id := idx.ValidatorID(validatorID)
+	if !es.Validators.Exists(id) {
+		return bs, es, nil, errNotValidator
+	}
+	return bs, es, bs.GetValidatorState(id, es.Validators), nil
+}
+
+// currentRules returns the rules as of the last block, i.e. including the changes
+// applied by governance during the epoch.
+func currentRules(bs *iblockproc.BlockState, es *iblockproc.EpochState) opera.Rules {
+	if bs.DirtyRules != nil {
+		return *bs.DirtyRules
+	}
+	return es.Rules
+}
+
+// GetDowntime returns how many blocks the validator missed and for how long it's been silent.
+func (s *PublicAbftAPI) GetDowntime(ctx context.Context, validatorID hexutil.Uint) (map[string]interface{}, error) {
+	bs, _, vs, err := s.currentValidatorState(ctx, validatorID)
+	if err != nil {
+		return nil, err
+	}
+	missed := vs.MissedBlocks(bs.LastBlock)
+	return map[string]interface{}{
+		"offlineBlocks": hexutil.Uint64(missed.BlocksNum),
+		"offlineTime":   hexutil.Uint64(missed.Period),
+	}, nil
+}
+
+// GetEpochUptime returns the validator's uptime in the current epoch, in nanoseconds.
+func (s *PublicAbftAPI) GetEpochUptime(ctx context.Context, validatorID hexutil.Uint) (hexutil.Uint64, error) {
+	_, _, vs, err := s.currentValidatorState(ctx, validatorID)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(vs.Uptime), nil
+}
+
+// GetValidatorStatus returns the liveness status of the validator as of the last block.
+func (s *PublicAbftAPI) GetValidatorStatus(ctx context.Context, validatorID hexutil.Uint) (map[string]interface{}, error) {
+	bs, es, vs, err := s.currentValidatorState(ctx, validatorID)
+	if err != nil {
+		return nil, err
+	}
+	rules := currentRules(bs, es)
+	return map[string]interface{}{
+		"online":         !vs.IsOffline(bs.LastBlock, rules.Economy),
+		"lastOnlineTime": hexutil.Uint64(vs.LastOnlineTime),
+		"lastBlock":      hexutil.Uint64(vs.LastBlock),
+		"uptime":         hexutil.Uint64(vs.Uptime),
+	}, nil
+}
+
+// ValidatorFees returns the transaction fees originated by the validator, i.e. carried by its events.
+// If the block is specified, the fees of that block are returned, otherwise the fees of the
+// current epoch so far, which are the input of the epoch rewards.
+func (s *PublicAbftAPI) ValidatorFees(ctx context.Context, validatorID hexutil.Uint, blockNr *rpc.BlockNumber) (*hexutil.Big, error) {
+	if blockNr != nil {
+		fees, err := s.b.GetBlockFees(ctx, *blockNr)
+		if err != nil {
+			return nil, err
+		}
+		if fees == nil {
+			return nil, errNoBlockFees
+		}
+		return (*hexutil.Big)(fees.Get(idx.ValidatorID(validatorID))), nil
+	}
+	_, _, vs, err := s.currentValidatorState(ctx, validatorID)
+	if err != nil {
+		return nil, err
+	}
+	if vs.Originated == nil {
+		return (*hexutil.Big)(new(big.Int)), nil
+	}
+	return (*hexutil.Big)(new(big.Int).Set(vs.Originated)), nil
+}
+
+// GetValidatorPubkey returns the validator's key of the current epoch and, if the validator stays
+// in the next epoch, the key of the next epoch, which differs from the current one if the key
+// was changed during the epoch. The key change takes effect at the epoch boundary.
+func (s *PublicAbftAPI) GetValidatorPubkey(ctx context.Context, validatorID hexutil.Uint) (map[string]interface{}, error) {
+	bs, es, _, err := s.currentValidatorState(ctx, validatorID)
+	if err != nil {
+		return nil, err
+	}
+	id := idx.ValidatorID(validatorID)
+	res := map[string]interface{}{
+		"epoch":  hexutil.Uint64(es.Epoch),
+		"pubkey": es.ValidatorProfiles[id].PubKey.String(),
+	}
+	if next, ok := bs.NextValidatorProfiles[id]; ok {
+		res["nextPubkey"] = next.PubKey.String()
+	}
+	return res, nil
+}
+
+// IsPaused returns true if the network is paused by governance (see opera.Upgrades.Paused).
+// While the network is paused, blocks contain no transactions.
+func (s *PublicAbftAPI) IsPaused(ctx context.Context) (bool, error) {
+	bs, es, err := s.b.GetEpochBlockState(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return false, err
+	}
+	if bs == nil || es == nil {
+		return false, errNoEpochState
+	}
+	return currentRules(bs, es).Upgrades.Paused, nil
+}
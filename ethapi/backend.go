@@ -0,0 +1,101 @@
+// Package ethapi implements the node's RPC API namespaces.
+//
+// The APIs don't access the node's internals directly, everything goes through the
+// Backend interface, so they can be served by the full node as well as tested
+// against a mock.
+package ethapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+)
+
+// Backend interface provides the common API services (that are provided by
+// both full and light clients) with access to necessary functions.
+type Backend interface {
+	// General Ethereum API
+	RPCGasCap() uint64            // global gas cap for eth_call over rpc: DoS protection
+	RPCEVMTimeout() time.Duration // global timeout for eth_call over rpc: DoS protection
+	RPCLogsConfig() LogsConfig    // limits of eth_getLogs over rpc: DoS protection
+
+	// Blockchain API
+	// StateAndHeaderByNumberOrHash returns a throwaway copy of the block's state, which may be modified freely.
+	StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *evmcore.EvmHeader, error)
+	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmHeader, error)
+	BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmBlock, error)
+	BlockByHash(ctx context.Context, h common.Hash) (*evmcore.EvmBlock, error)
+	// GetBlock returns the stored consensus block, or nil if it isn't known.
+	GetBlock(ctx context.Context, number idx.Block) (*inter.Block, error)
+	// GetReceipts returns the receipts of the block, with the block and transaction fields of their logs set.
+	GetReceipts(ctx context.Context, number idx.Block) (types.Receipts, error)
+	// EarliestReceiptsBlock returns the first block whose receipts are stored, the receipts of the earlier blocks are pruned.
+	EarliestReceiptsBlock(ctx context.Context) (idx.Block, error)
+	GetEVM(ctx context.Context, msg types.Message, state *state.StateDB, header *evmcore.EvmHeader, vmConfig *vm.Config) (*vm.EVM, func() error, error)
+
+	// Transaction pool API
+	// GetTransaction returns the transaction with its block number and index in the block.
+	GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error)
+	// SendTx submits the transaction to the txpool, which checks its nonce and the sender's balance.
+	SendTx(ctx context.Context, signedTx *types.Transaction) error
+
+	// Lachesis API
+	GetEventPayload(ctx context.Context, shortEventID string) (*inter.EventPayload, error)
+	// GetEpochBlockState returns the block and epoch states of the given epoch.
+	// rpc.LatestBlockNumber and rpc.PendingBlockNumber refer to the current epoch.
+	GetEpochBlockState(ctx context.Context, epoch rpc.BlockNumber) (*iblockproc.BlockState, *iblockproc.EpochState, error)
+	// GetEpochCounters returns the statistics counters of the current epoch.
+	GetEpochCounters(ctx context.Context) (*iblockproc.EpochCounters, error)
+	// GetEpochSummary returns the record of a sealed epoch, or nil if it isn't known.
+	// rpc.LatestBlockNumber refers to the last sealed epoch.
+	GetEpochSummary(ctx context.Context, epoch rpc.BlockNumber) (*iblockproc.EpochSummary, error)
+	// GetBlockFees returns the fees originated by the validators in the block.
+	GetBlockFees(ctx context.Context, number rpc.BlockNumber) (*iblockproc.BlockFees, error)
+}
+
+// GetAPIs returns all the API namespaces served by the backend.
+func GetAPIs(apiBackend Backend) []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewPublicBlockChainAPI(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewPublicTransactionPoolAPI(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewPublicLogsAPI(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   NewPublicDebugAPI(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "dag",
+			Version:   "1.0",
+			Service:   NewPublicDAGAPI(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "abft",
+			Version:   "1.0",
+			Service:   NewPublicAbftAPI(apiBackend),
+			Public:    true,
+		},
+	}
+}
@@ -0,0 +1,73 @@
+package ethapi
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+)
+
+// RPCEpochSummary is the JSON representation of iblockproc.EpochSummary.
+type RPCEpochSummary struct {
+	Epoch      hexutil.Uint64        `json:"epoch"`
+	Start      hexutil.Uint64        `json:"start"`
+	End        hexutil.Uint64        `json:"end"`
+	Duration   hexutil.Uint64        `json:"duration"`
+	FirstBlock hexutil.Uint64        `json:"firstBlock"`
+	LastBlock  hexutil.Uint64        `json:"lastBlock"`
+	Blocks     hexutil.Uint64        `json:"blocks"`
+	Gas        hexutil.Uint64        `json:"gas"`
+	Events     hexutil.Uint64        `json:"events"`
+	Validators []RPCValidatorSummary `json:"validators"`
+	Cheaters   []hexutil.Uint        `json:"cheaters"`
+	RulesHash  common.Hash           `json:"rulesHash"`
+}
+
+// RPCValidatorSummary is the JSON representation of iblockproc.ValidatorSummary.
+type RPCValidatorSummary struct {
+	ID     hexutil.Uint   `json:"id"`
+	Events hexutil.Uint64 `json:"events"`
+	Uptime hexutil.Uint64 `json:"uptime"`
+}
+
+// RPCMarshalEpochSummary converts the epoch summary into the RPC representation.
+func RPCMarshalEpochSummary(s *iblockproc.EpochSummary) *RPCEpochSummary {
+	res := &RPCEpochSummary{
+		Epoch:      hexutil.Uint64(s.Epoch),
+		Start:      hexutil.Uint64(s.Start),
+		End:        hexutil.Uint64(s.End),
+		Duration:   hexutil.Uint64(s.Duration()),
+		FirstBlock: hexutil.Uint64(s.FirstBlock),
+		LastBlock:  hexutil.Uint64(s.LastBlock),
+		Blocks:     hexutil.Uint64(s.Blocks()),
+		Gas:        hexutil.Uint64(s.Gas),
+		Events:     hexutil.Uint64(s.Events()),
+		Validators: make([]RPCValidatorSummary, len(s.Validators)),
+		Cheaters:   make([]hexutil.Uint, len(s.Cheaters)),
+		RulesHash:  common.Hash(s.RulesHash),
+	}
+	for i, v := range s.Validators {
+		res.Validators[i] = RPCValidatorSummary{
+			ID:     hexutil.Uint(v.ID),
+			Events: hexutil.Uint64(v.Events),
+			Uptime: hexutil.Uint64(v.Uptime),
+		}
+	}
+	for i, id := range s.Cheaters {
+		res.Cheaters[i] = hexutil.Uint(id)
+	}
+	return res
+}
+
+// GetEpochSummary returns the record of a sealed epoch.
+// The "latest" epoch is the last sealed one. Returns nil if the epoch isn't sealed yet.
+func (s *PublicAbftAPI) GetEpochSummary(ctx context.Context, epoch rpc.BlockNumber) (*RPCEpochSummary, error) {
+	summary, err := s.b.GetEpochSummary(ctx, epoch)
+	if err != nil || summary == nil {
+		return nil, err
+	}
+	return RPCMarshalEpochSummary(summary), nil
+}
@@ -0,0 +1,284 @@
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// logs.go serves eth_getLogs.
+//
+// Overview:
+//   A query is resolved into a block range first, which is rejected if it's wider than
+//   LogsConfig.MaxBlockRange or if it reaches the blocks whose receipts are pruned, so a
+//   client never gets silently incomplete results.
+//
+//   The range is then scanned in two phases: the bloom of each block header is checked
+//   against the addresses and topics, and only the receipts of the blocks which pass
+//   are read and filtered exactly. The scan stops with an error once the results exceed
+//   LogsConfig.MaxResults or the query runs longer than LogsConfig.Timeout.
+
+var (
+	// ErrLogsRangeTooWide is returned if the block range of a query exceeds LogsConfig.MaxBlockRange.
+	ErrLogsRangeTooWide = errors.New("block range is too wide")
+	// ErrLogsTooManyResults is returned if a query matches more than LogsConfig.MaxResults logs.
+	ErrLogsTooManyResults = errors.New("query returned too many results")
+	// ErrLogsTimeout is returned if a query runs longer than LogsConfig.Timeout.
+	ErrLogsTimeout = errors.New("query timed out")
+	// ErrHistoryPruned is returned if a query reaches the blocks whose receipts are pruned.
+	ErrHistoryPruned = errors.New("history is pruned")
+
+	errInvalidBlockRange = errors.New("invalid block range")
+	errBlockNotFound     = errors.New("block not found")
+)
+
+// LogsConfig limits the cost of eth_getLogs queries. Zero values mean no limit.
+type LogsConfig struct {
+	MaxBlockRange uint64        // max number of blocks in a query
+	MaxResults    int           // max number of logs returned by a query
+	Timeout       time.Duration // max duration of a query
+}
+
+// FilterCriteria is the query of eth_getLogs.
+type FilterCriteria struct {
+	BlockHash *common.Hash     // if set, only the logs of the block are returned, FromBlock and ToBlock must be nil
+	FromBlock *rpc.BlockNumber // first block of the range, the latest block if nil
+	ToBlock   *rpc.BlockNumber // last block of the range, the latest block if nil
+	Addresses []common.Address // logs of any of the addresses, or of all of them if empty
+	// Topics are matched by their position: a log matches if each of its topics is any of
+	// the topics at the same position, an empty position matches any topic
+	Topics [][]common.Hash
+}
+
+// UnmarshalJSON decodes the query, where the address and each topic position may be
+// either a single value or a list, and a topic position may be null.
+func (c *FilterCriteria) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		BlockHash *common.Hash      `json:"blockHash"`
+		FromBlock *rpc.BlockNumber  `json:"fromBlock"`
+		ToBlock   *rpc.BlockNumber  `json:"toBlock"`
+		Addresses json.RawMessage   `json:"address"`
+		Topics    []json.RawMessage `json:"topics"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.BlockHash != nil && (raw.FromBlock != nil || raw.ToBlock != nil) {
+		return errors.New("cannot specify both blockHash and fromBlock/toBlock")
+	}
+	*c = FilterCriteria{
+		BlockHash: raw.BlockHash,
+		FromBlock: raw.FromBlock,
+		ToBlock:   raw.ToBlock,
+	}
+	if err := unmarshalOneOrMany(raw.Addresses, &c.Addresses); err != nil {
+		return fmt.Errorf("invalid address: %w", err)
+	}
+	c.Topics = make([][]common.Hash, len(raw.Topics))
+	for i, topics := range raw.Topics {
+		if err := unmarshalOneOrMany(topics, &c.Topics[i]); err != nil {
+			return fmt.Errorf("invalid topic %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// unmarshalOneOrMany decodes either a single value or a list of values into the list.
+// An absent or null value leaves the list empty.
+func unmarshalOneOrMany(data json.RawMessage, list interface{}) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	if data[0] == '[' {
+		return json.Unmarshal(data, list)
+	}
+	// wrap a single value into a list
+	return json.Unmarshal(append(append([]byte{'['}, data...), ']'), list)
+}
+
+// PublicLogsAPI provides an API to query the logs of the blocks.
+type PublicLogsAPI struct {
+	b Backend
+}
+
+// NewPublicLogsAPI creates a new logs API instance.
+func NewPublicLogsAPI(b Backend) *PublicLogsAPI {
+	return &PublicLogsAPI{b}
+}
+
+// GetLogs returns the logs matching the query.
+func (s *PublicLogsAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*types.Log, error) {
+	cfg := s.b.RPCLogsConfig()
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	from, to, err := s.blockRange(ctx, crit)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return []*types.Log{}, nil
+	}
+	if cfg.MaxBlockRange > 0 && uint64(to-from)+1 > cfg.MaxBlockRange {
+		return nil, fmt.Errorf("%w: %d blocks requested, at most %d are allowed", ErrLogsRangeTooWide, uint64(to-from)+1, cfg.MaxBlockRange)
+	}
+	earliest, err := s.b.EarliestReceiptsBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if from < earliest {
+		return nil, fmt.Errorf("%w: logs are available starting with block %d", ErrHistoryPruned, earliest)
+	}
+
+	logs := []*types.Log{}
+	for n := from; n <= to; n++ {
+		if ctx.Err() != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("%w after %v, at block %d", ErrLogsTimeout, cfg.Timeout, n)
+			}
+			return nil, ctx.Err()
+		}
+		header, err := s.b.HeaderByNumber(ctx, rpc.BlockNumber(n))
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			break
+		}
+		// blocks stored before the receipts root and the bloom were recorded have an empty bloom
+		if header.ReceiptHash != (common.Hash{}) && !bloomFilter(header.Bloom, crit.Addresses, crit.Topics) {
+			continue
+		}
+		receipts, err := s.b.GetReceipts(ctx, n)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range receipts {
+			logs = append(logs, filterLogs(r.Logs, crit.Addresses, crit.Topics)...)
+		}
+		if cfg.MaxResults > 0 && len(logs) > cfg.MaxResults {
+			return nil, fmt.Errorf("%w: more than %d logs up to block %d, narrow the range", ErrLogsTooManyResults, cfg.MaxResults, n)
+		}
+	}
+	return logs, nil
+}
+
+// blockRange resolves the block range of the query. The range is empty (from > to)
+// if it starts after the latest block.
+func (s *PublicLogsAPI) blockRange(ctx context.Context, crit FilterCriteria) (idx.Block, idx.Block, error) {
+	if crit.BlockHash != nil {
+		block, err := s.b.BlockByHash(ctx, *crit.BlockHash)
+		if err != nil {
+			return 0, 0, err
+		}
+		if block == nil {
+			return 0, 0, errBlockNotFound
+		}
+		n := idx.Block(block.Number.Uint64())
+		return n, n, nil
+	}
+
+	head, err := s.b.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return 0, 0, err
+	}
+	if head == nil {
+		return 0, 0, errBlockNotFound
+	}
+	latest := idx.Block(head.Number.Uint64())
+	resolve := func(n *rpc.BlockNumber) idx.Block {
+		if n == nil || *n == rpc.LatestBlockNumber || *n == rpc.PendingBlockNumber {
+			return latest
+		}
+		if *n == rpc.EarliestBlockNumber {
+			return 0
+		}
+		return idx.Block(*n)
+	}
+	from, to := resolve(crit.FromBlock), resolve(crit.ToBlock)
+	if from > to {
+		return 0, 0, fmt.Errorf("%w: fromBlock %d is after toBlock %d", errInvalidBlockRange, from, to)
+	}
+	// the blocks after the latest one have no logs yet
+	if to > latest {
+		to = latest
+	}
+	return from, to, nil
+}
+
+// bloomFilter returns false if the bloom surely has no logs matching the addresses and topics.
+func bloomFilter(bloom types.Bloom, addresses []common.Address, topics [][]common.Hash) bool {
+	if len(addresses) > 0 {
+		included := false
+		for _, addr := range addresses {
+			if types.BloomLookup(bloom, addr) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	for _, sub := range topics {
+		included := len(sub) == 0 // an empty position matches any topic
+		for _, topic := range sub {
+			if types.BloomLookup(bloom, topic) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	return true
+}
+
+// filterLogs returns the logs matching the addresses and topics.
+func filterLogs(logs []*types.Log, addresses []common.Address, topics [][]common.Hash) []*types.Log {
+	var res []*types.Log
+Logs:
+	for _, log := range logs {
+		if len(addresses) > 0 && !containsAddress(addresses, log.Address) {
+			continue
+		}
+		// a log with fewer topics than the query can't match it
+		if len(topics) > len(log.Topics) {
+			continue
+		}
+		for i, sub := range topics {
+			match := len(sub) == 0
+			for _, topic := range sub {
+				if log.Topics[i] == topic {
+					match = true
+					break
+				}
+			}
+			if !match {
+				continue Logs
+			}
+		}
+		res = append(res, log)
+	}
+	return res
+}
+
+func containsAddress(addresses []common.Address, addr common.Address) bool {
+	for _, a := range addresses {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package ethapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+)
+
+// PublicDebugAPI is the collection of Opera APIs exposed over the public
+// debugging endpoint.
+type PublicDebugAPI struct {
+	b Backend
+}
+
+// NewPublicDebugAPI creates a new API definition for the public debug methods
+// of the Opera service.
+func NewPublicDebugAPI(b Backend) *PublicDebugAPI {
+	return &PublicDebugAPI{b: b}
+}
+
+// blockByNumberOrHash returns the requested block, or an error if it doesn't exist.
+func blockByNumberOrHash(ctx context.Context, b Backend, blockNrOrHash rpc.BlockNumberOrHash) (*evmcore.EvmBlock, error) {
+	var (
+		block *evmcore.EvmBlock
+		err   error
+	)
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		block, err = b.BlockByHash(ctx, hash)
+	} else if number, ok := blockNrOrHash.Number(); ok {
+		block, err = b.BlockByNumber(ctx, number)
+	} else {
+		return nil, errors.New("invalid arguments; neither block nor hash specified")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, errors.New("block not found")
+	}
+	return block, nil
+}
+
+// GetRawBlock retrieves the RLP encoding of the stored block (inter.Block), the same as it's
+// kept in the database, rather than of its Ethereum projection.
+func (api *PublicDebugAPI) GetRawBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	block, err := blockByNumberOrHash(ctx, api.b, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := api.b.GetBlock(ctx, idx.Block(block.Number.Uint64()))
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return nil, errors.New("block not found")
+	}
+	return rlp.EncodeToBytes(stored)
+}
+
+// GetRawTransaction returns the bytes of the transaction for the given hash.
+func (api *PublicDebugAPI) GetRawTransaction(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
+	tx, _, _, err := api.b.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, nil
+	}
+	return tx.MarshalBinary()
+}
+
+// PublicDAGAPI provides an API to access the DAG events.
+type PublicDAGAPI struct {
+	b Backend
+}
+
+// NewPublicDAGAPI creates a new DAG API instance.
+func NewPublicDAGAPI(b Backend) *PublicDAGAPI {
+	return &PublicDAGAPI{b}
+}
+
+// GetRawEvent returns the CSER encoding of the event, the same as it's sent over the network.
+// The event is identified by its full ID or by a short one ("epoch:lamport:prefix").
+func (s *PublicDAGAPI) GetRawEvent(ctx context.Context, shortEventID string) (hexutil.Bytes, error) {
+	event, err := s.b.GetEventPayload(ctx, shortEventID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, nil
+	}
+	return event.MarshalBinary()
+}
+
+// ExportEpochState returns the unsigned snapshot of the block and epoch states of the epoch
+// (see iblockproc.StateSnapshot), which the validator signs to move its node to another machine.
+// rpc.LatestBlockNumber refers to the current epoch.
+func (api *PublicDebugAPI) ExportEpochState(ctx context.Context, epoch rpc.BlockNumber) (hexutil.Bytes, error) {
+	bs, es, err := api.b.GetEpochBlockState(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	if bs == nil || es == nil {
+		return nil, errNoEpochState
+	}
+	var buf bytes.Buffer
+	if err := iblockproc.WriteStateSnapshot(&buf, iblockproc.NewStateSnapshot(*bs, *es)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,85 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+)
+
+var errNoStakeAmount = errors.New("stake change amount isn't set")
+
+// RPCStakeChange is a pending stake operation: the delegation of Amount to the validator,
+// or the undelegation of Amount from it if Unstake is set.
+type RPCStakeChange struct {
+	ValidatorID hexutil.Uint `json:"validatorId"`
+	Amount      *hexutil.Big `json:"amount"`
+	Unstake     bool         `json:"unstake"`
+}
+
+// RPCValidatorProjection is the JSON representation of iblockproc.ValidatorProjection.
+type RPCValidatorProjection struct {
+	ID         hexutil.Uint   `json:"id"`
+	Stake      *hexutil.Big   `json:"stake"`
+	NextStake  *hexutil.Big   `json:"nextStake"`
+	Weight     hexutil.Uint64 `json:"weight"`
+	NextWeight hexutil.Uint64 `json:"nextWeight"`
+}
+
+// RPCValidatorsSimulation is the validator set of the next epoch simulated with the pending stake changes.
+type RPCValidatorsSimulation struct {
+	Epoch           hexutil.Uint64           `json:"epoch"`
+	TotalWeight     hexutil.Uint64           `json:"totalWeight"`
+	NextTotalWeight hexutil.Uint64           `json:"nextTotalWeight"`
+	Validators      []RPCValidatorProjection `json:"validators"`
+}
+
+// SimulateNextValidators returns the validator set and the weights the next epoch would start
+// with if the stake changes were applied before the epoch seals, built the same way the
+// epoch sealer builds them. The weight of a validator divided by the total weight is its
+// share of the consensus votes and, normally, of the rewards.
+func (s *PublicAbftAPI) SimulateNextValidators(ctx context.Context, changes []RPCStakeChange) (*RPCValidatorsSimulation, error) {
+	bs, es, err := s.b.GetEpochBlockState(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if bs == nil || es == nil {
+		return nil, errNoEpochState
+	}
+	stakeChanges := make([]iblockproc.StakeChange, len(changes))
+	for i, c := range changes {
+		if c.Amount == nil {
+			return nil, errNoStakeAmount
+		}
+		delta := new(big.Int).Set(c.Amount.ToInt())
+		if c.Unstake {
+			delta.Neg(delta)
+		}
+		stakeChanges[i] = iblockproc.StakeChange{ValidatorID: idx.ValidatorID(c.ValidatorID), Delta: delta}
+	}
+	projections, validators, err := bs.SimulateNextValidators(stakeChanges)
+	if err != nil {
+		return nil, err
+	}
+	res := &RPCValidatorsSimulation{
+		Epoch:           hexutil.Uint64(es.Epoch + 1),
+		TotalWeight:     hexutil.Uint64(bs.NextValidatorProfiles.Validators().TotalWeight()),
+		NextTotalWeight: hexutil.Uint64(validators.TotalWeight()),
+		Validators:      make([]RPCValidatorProjection, len(projections)),
+	}
+	for i, p := range projections {
+		res.Validators[i] = RPCValidatorProjection{
+			ID:         hexutil.Uint(p.ID),
+			Stake:      (*hexutil.Big)(p.Stake),
+			NextStake:  (*hexutil.Big)(p.NextStake),
+			Weight:     hexutil.Uint64(p.Weight),
+			NextWeight: hexutil.Uint64(p.NextWeight),
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,17 @@
+// Package limitscheck rejects events which exceed the structure limits of the rules,
+// i.e. the number of parents, the extra data size, the number of transactions and the event size.
+//
+// The limits are a part of the network rules (opera.DagRules), so the check is
+// deterministic: all the nodes accept or reject the same events.
+package limitscheck
+
+import (
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// Validate event against the limits of the rules it's processed with.
+// Returns inter.ErrTooManyParents, inter.ErrTooLargeExtra, inter.ErrTooManyTxs or inter.ErrTooLargeEvent.
+func Validate(e *inter.EventPayload, rules opera.Rules) error {
+	return e.CheckLimits(rules.Dag.EventLimits())
+}
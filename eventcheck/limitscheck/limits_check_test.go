@@ -0,0 +1,32 @@
+package limitscheck
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+func TestValidate_MaxTxs(t *testing.T) {
+	rules := opera.FakeNetRules()
+	rules.Dag.MaxTxsPerEvent = 2
+
+	newEvent := func(txs int) *inter.EventPayload {
+		me := &inter.MutableEventPayload{}
+		list := make(types.Transactions, txs)
+		for i := range list {
+			list[i] = types.NewTx(&types.LegacyTx{Nonce: uint64(i)})
+		}
+		me.SetTxs(list)
+		return me.Build()
+	}
+
+	if err := Validate(newEvent(2), rules); err != nil {
+		t.Fatalf("event within the limit is rejected: %v", err)
+	}
+	if err := Validate(newEvent(3), rules); err != inter.ErrTooManyTxs {
+		t.Fatalf("expected %v, got %v", inter.ErrTooManyTxs, err)
+	}
+}
@@ -0,0 +1,26 @@
+// Package pausecheck rejects events which carry transactions while the network is paused.
+//
+// The pause is a part of the network rules (opera.Upgrades.Paused), so the check is
+// deterministic: all the nodes accept or reject the same events.
+package pausecheck
+
+import (
+	"errors"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+var (
+	// ErrTxsWhilePaused is returned if the event contains transactions while the network is paused.
+	ErrTxsWhilePaused = errors.New("event contains transactions while the network is paused")
+)
+
+// Validate event against the pause flag of the rules it's processed with.
+// Events without transactions are always valid, so validators stay alive during the pause.
+func Validate(e inter.EventPayloadI, rules opera.Rules) error {
+	if rules.Upgrades.Paused && len(e.Txs()) != 0 {
+		return ErrTxsWhilePaused
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package pausecheck
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+func TestValidate(t *testing.T) {
+	rules := opera.FakeNetRules()
+
+	empty := (&inter.MutableEventPayload{}).Build()
+	me := &inter.MutableEventPayload{}
+	me.SetTxs(types.Transactions{types.NewTx(&types.LegacyTx{Nonce: 1})})
+	withTxs := me.Build()
+
+	if err := Validate(withTxs, rules); err != nil {
+		t.Fatalf("event with txs is rejected on a live network: %v", err)
+	}
+
+	rules.Upgrades.Paused = true
+	if err := Validate(empty, rules); err != nil {
+		t.Fatalf("empty event is rejected on a paused network: %v", err)
+	}
+	if err := Validate(withTxs, rules); err != ErrTxsWhilePaused {
+		t.Fatalf("expected %v, got %v", ErrTxsWhilePaused, err)
+	}
+}
@@ -0,0 +1,45 @@
+// Package timecheck validates event timestamps against the local clock.
+//
+// It's the only event check which depends on the local time, so it must never be
+// used for consensus-critical validation (other nodes may have a different clock),
+// only to drop obviously bogus events before they're processed.
+package timecheck
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+var (
+	// ErrFutureEvent is returned if the event is created too far in the future.
+	ErrFutureEvent = errors.New("event is created in the future")
+)
+
+// DefaultMaxFuture is the default tolerance of clock drift between nodes.
+const DefaultMaxFuture = 10 * time.Second
+
+// Checker which validates event timestamps against the local clock.
+type Checker struct {
+	clock     clock.Clock
+	maxFuture time.Duration
+}
+
+// New validator which checks event timestamps. Events created after now+maxFuture are rejected.
+func New(c clock.Clock, maxFuture time.Duration) *Checker {
+	return &Checker{
+		clock:     c,
+		maxFuture: maxFuture,
+	}
+}
+
+// Validate event timestamps.
+func (v *Checker) Validate(e inter.EventI) error {
+	limit := inter.Timestamp(v.clock.Now().Add(v.maxFuture).UnixNano())
+	if e.CreationTime() > limit {
+		return ErrFutureEvent
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package timecheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+func TestChecker(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	c := clock.NewManual(now)
+	v := New(c, time.Second)
+
+	event := func(created time.Time) inter.EventI {
+		me := &inter.MutableEventPayload{}
+		me.SetCreationTime(inter.Timestamp(created.UnixNano()))
+		return me.Build()
+	}
+
+	if err := v.Validate(event(now.Add(time.Second))); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Validate(event(now.Add(time.Second + 1))); err != ErrFutureEvent {
+		t.Fatalf("expected %v, got %v", ErrFutureEvent, err)
+	}
+	c.Advance(time.Second)
+	if err := v.Validate(event(now.Add(time.Second + 1))); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -40,6 +40,7 @@ import (
 var FakeGenesisTime = inter.Timestamp(1608600000 * time.Second)
 
 // ApplyFakeGenesis initializes a fake genesis block with the specified account balances.
+// Use ApplyGenesis to place accounts with code, storage or nonce.
 //
 // This function is used for testing, development, and fake network initialization.
 // It creates a genesis block (block number 0) with pre-funded accounts, allowing
@@ -66,23 +67,14 @@ var FakeGenesisTime = inter.Timestamp(1608600000 * time.Second)
 //	}
 //	block, err := ApplyFakeGenesis(statedb, FakeGenesisTime, balances)
 func ApplyFakeGenesis(statedb *state.StateDB, time inter.Timestamp, balances map[common.Address]*big.Int) (*EvmBlock, error) {
-	// Set initial balances for all accounts specified in the map
-	// This pre-funds accounts for testing and development purposes
+	// Pre-fund the accounts for testing and development purposes
+	alloc := make(GenesisAlloc, len(balances))
 	for acc, balance := range balances {
-		statedb.SetBalance(acc, balance)
+		alloc[acc] = GenesisAccount{Balance: balance}
 	}
 
-	// Commit the state changes and compute the state root hash
-	// The 'true' parameter indicates a clean commit (no intermediate state caching)
-	root, err := flush(statedb, true)
-	if err != nil {
-		return nil, err
-	}
-
-	// Create the genesis block with the computed state root
-	block := genesisBlock(time, root)
-
-	return block, nil
+	// Write the accounts, commit the state and create the genesis block with the state root
+	return ApplyGenesis(statedb, time, alloc)
 }
 
 // flush commits state changes to the database and returns the state root hash.
@@ -204,6 +196,11 @@ func MustApplyFakeGenesis(statedb *state.StateDB, time inter.Timestamp, balances
 // Panics:
 //   - If key generation fails (should never happen in practice)
 //
+// The key is derived from the seeded reader the way ecdsa.GenerateKey did it before Go 1.20
+// (40 bytes reduced modulo N-1, plus one), so the keys, and hence the fakenet validators and
+// genesis, are the same as in upstream go-opera. ecdsa.GenerateKey itself isn't used, as since
+// Go 1.20 it doesn't consume a custom reader deterministically.
+//
 // Example:
 //
 //	key0 := FakeKey(0)  // First fake key
@@ -214,9 +211,21 @@ func FakeKey(n int) *ecdsa.PrivateKey {
 	// Using the index 'n' as the seed ensures deterministic key generation
 	reader := rand.New(rand.NewSource(int64(n)))
 
-	// Generate an ECDSA key pair using the secp256k1 curve (Bitcoin/Ethereum curve)
-	// The seeded reader ensures the same seed produces the same key
-	key, err := ecdsa.GenerateKey(crypto.S256(), reader)
+	params := crypto.S256().Params()
+	b := make([]byte, params.BitSize/8+8)
+	if _, err := reader.Read(b); err != nil {
+		// Reading from math/rand never fails, but panic if it does
+		panic(err)
+	}
+	one := big.NewInt(1)
+	k := new(big.Int).SetBytes(b)
+	k.Mod(k, new(big.Int).Sub(params.N, one))
+	k.Add(k, one)
+
+	secret := make([]byte, 32)
+	kb := k.Bytes()
+	copy(secret[len(secret)-len(kb):], kb)
+	key, err := crypto.ToECDSA(secret)
 	if err != nil {
 		// Key generation should never fail, but panic if it does
 		panic(err)
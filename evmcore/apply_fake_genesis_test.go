@@ -0,0 +1,21 @@
+package evmcore
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestFakeKey checks that the fake keys are the ones of the upstream fakenet validators.
+func TestFakeKey(t *testing.T) {
+	for n, want := range map[int]common.Address{
+		1: common.HexToAddress("0x239fA7623354eC26520dE878B52f13Fe84b06971"),
+		2: common.HexToAddress("0x02AFf1D0a9ed566E644f06FcFE7eFe00A3261D03"),
+		3: common.HexToAddress("0x83e573ad09147fc15dac762653a8EDaC9B2516d6"),
+	} {
+		if got := crypto.PubkeyToAddress(FakeKey(n).PublicKey); got != want {
+			t.Fatalf("fake key %d: address %s, want %s", n, got.Hex(), want.Hex())
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// This file implements the blob gas accounting of EIP-4844 in preparation for the Cancun upgrade.
+//
+// Overview:
+//   Blob transactions (type 3) pay for their blobs with blob gas, which is priced separately
+//   from the execution gas. The blob gas price grows exponentially with ExcessBlobGas, the blob
+//   gas used above Blocks.TargetBlobGasPerBlock by the previous blocks, and the blob gas of a
+//   block is limited by Blocks.MaxBlobGasPerBlock.
+//
+//   Until opera.Upgrades.Cancun is enabled, blob transactions are rejected as a not supported
+//   type, exactly as before. Once it's enabled, the block processor charges their blob gas
+//   (see BlockEVM.ChargeBlobGas) and skips the ones which are underpriced or don't fit the block.
+
+package evmcore
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+const (
+	// BlobTxType is the type of blob transactions.
+	BlobTxType = 0x03
+
+	// BlobGasPerBlob is the blob gas consumed by a single blob.
+	BlobGasPerBlob uint64 = 1 << 17
+
+	// MinBlobGasPrice is the blob gas price while the excess blob gas is low.
+	MinBlobGasPrice = 1
+
+	// BlobGasPriceUpdateFraction controls how fast the blob gas price follows the excess blob gas.
+	BlobGasPriceUpdateFraction = 3338477
+)
+
+var (
+	// ErrBlobFeeCapTooLow is returned if the blob gas fee cap of the transaction is below the blob gas price of the block.
+	ErrBlobFeeCapTooLow = errors.New("max fee per blob gas less than block blob gas fee")
+	// ErrBlobGasLimitReached is returned if the blobs of the transaction don't fit the blob gas left in the block.
+	ErrBlobGasLimitReached = errors.New("blob gas limit reached")
+	// ErrMissingBlobHashes is returned if a blob transaction carries no blobs.
+	ErrMissingBlobHashes = errors.New("blob transaction without blobs")
+)
+
+// BlobTx is the blob part of a blob transaction. The transactions of geth versions
+// supporting Cancun implement it.
+type BlobTx interface {
+	BlobHashes() []common.Hash
+	BlobGasFeeCap() *big.Int
+}
+
+// BlobGas returns the blob gas consumed by the blobs of the transaction.
+func BlobGas(tx BlobTx) uint64 {
+	return uint64(len(tx.BlobHashes())) * BlobGasPerBlob
+}
+
+// NextExcessBlobGas returns ExcessBlobGas of the block following the parent, nil if the Cancun
+// upgrade isn't active. The first block after the upgrade starts with zero excess blob gas.
+func NextExcessBlobGas(parent *EvmHeader, rules opera.Rules) *uint64 {
+	if !rules.Upgrades.Cancun {
+		return nil
+	}
+	var excess uint64
+	if parent != nil && parent.ExcessBlobGas != nil && parent.BlobGasUsed != nil {
+		excess = CalcExcessBlobGas(*parent.ExcessBlobGas, *parent.BlobGasUsed, rules.Blocks.TargetBlobGasPerBlock)
+	}
+	return &excess
+}
+
+// CalcExcessBlobGas returns the excess blob gas after a block with the given excess and usage.
+func CalcExcessBlobGas(parentExcess, parentUsed, target uint64) uint64 {
+	if parentExcess+parentUsed < target {
+		return 0
+	}
+	return parentExcess + parentUsed - target
+}
+
+// CalcBlobFee returns the blob gas price of a block with the given excess blob gas.
+func CalcBlobFee(excessBlobGas uint64) *big.Int {
+	return fakeExponential(big.NewInt(MinBlobGasPrice), new(big.Int).SetUint64(excessBlobGas), big.NewInt(BlobGasPriceUpdateFraction))
+}
+
+// fakeExponential approximates factor * e ** (numerator / denominator) using the Taylor expansion,
+// in the integer arithmetic, so all the nodes compute the same price.
+func fakeExponential(factor, numerator, denominator *big.Int) *big.Int {
+	var (
+		output = new(big.Int)
+		accum  = new(big.Int).Mul(factor, denominator)
+	)
+	for i := 1; accum.Sign() > 0; i++ {
+		output.Add(output, accum)
+
+		accum.Mul(accum, numerator)
+		accum.Div(accum, denominator)
+		accum.Div(accum, big.NewInt(int64(i)))
+	}
+	return output.Div(output, denominator)
+}
@@ -0,0 +1,108 @@
+package evmcore
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+type fakeBlobTx struct {
+	blobs  int
+	feeCap *big.Int
+}
+
+func (tx fakeBlobTx) BlobHashes() []common.Hash {
+	return make([]common.Hash, tx.blobs)
+}
+
+func (tx fakeBlobTx) BlobGasFeeCap() *big.Int {
+	return tx.feeCap
+}
+
+func cancunRules() opera.Rules {
+	rules := opera.FakeNetRules()
+	rules.Upgrades.Cancun = true
+	rules.Blocks.MaxBlobGasPerBlock = 6 * BlobGasPerBlob
+	rules.Blocks.TargetBlobGasPerBlock = 3 * BlobGasPerBlob
+	return rules
+}
+
+func TestCalcBlobFee(t *testing.T) {
+	for _, tt := range []struct {
+		excess uint64
+		fee    int64
+	}{
+		{0, 1},
+		{2314057, 1},
+		{2314058, 2},
+		{10 * 1024 * 1024, 23},
+	} {
+		if fee := CalcBlobFee(tt.excess); fee.Cmp(big.NewInt(tt.fee)) != 0 {
+			t.Errorf("CalcBlobFee(%d) = %v, want %d", tt.excess, fee, tt.fee)
+		}
+	}
+}
+
+func TestNextExcessBlobGas(t *testing.T) {
+	if NextExcessBlobGas(nil, opera.FakeNetRules()) != nil {
+		t.Fatal("excess blob gas before Cancun")
+	}
+	rules := cancunRules()
+	first := NextExcessBlobGas(&EvmHeader{}, rules)
+	if first == nil || *first != 0 {
+		t.Fatal("first block after Cancun must start with zero excess blob gas")
+	}
+
+	excess, used := uint64(0), 5*BlobGasPerBlob
+	next := NextExcessBlobGas(&EvmHeader{ExcessBlobGas: &excess, BlobGasUsed: &used}, rules)
+	if *next != 2*BlobGasPerBlob {
+		t.Errorf("excess after a block above the target = %d, want %d", *next, 2*BlobGasPerBlob)
+	}
+	used = BlobGasPerBlob
+	if next = NextExcessBlobGas(&EvmHeader{ExcessBlobGas: next, BlobGasUsed: &used}, rules); *next != 0 {
+		t.Errorf("excess after a block below the target = %d, want 0", *next)
+	}
+}
+
+// TestChargeBlobGas verifies that blob transactions are rejected before Cancun,
+// and priced and limited per block after it.
+func TestChargeBlobGas(t *testing.T) {
+	price := big.NewInt(1)
+	statedb, header, _ := syntheticBlock(t, opera.FakeNetRules(), 0)
+	before := NewBlockEVM(NewEvmConfig(opera.FakeNetRules(), nil), opera.DefaultVMConfig, header, statedb, emptyGetHash)
+	if err := before.ChargeBlobGas(fakeBlobTx{1, price}); !errors.Is(err, types.ErrTxTypeNotSupported) {
+		t.Fatalf("blob tx before Cancun: %v", err)
+	}
+
+	rules := cancunRules()
+	excess := uint64(2314058) // blob gas price is 2
+	header.ExcessBlobGas = &excess
+	evm := NewBlockEVM(NewEvmConfig(rules, nil), opera.DefaultVMConfig, header, statedb, emptyGetHash)
+
+	for _, tt := range []struct {
+		tx     fakeBlobTx
+		reason SkipReason
+	}{
+		{fakeBlobTx{0, big.NewInt(2)}, SkipMissingBlobs},
+		{fakeBlobTx{1, price}, SkipBlobFeeCapTooLow},
+		{fakeBlobTx{1, nil}, SkipBlobFeeCapTooLow},
+		{fakeBlobTx{4, big.NewInt(2)}, SkipNone},
+		{fakeBlobTx{3, big.NewInt(2)}, SkipBlobGasExhausted},
+		{fakeBlobTx{2, big.NewInt(3)}, SkipNone},
+	} {
+		if r := ClassifyTxError(evm.ChargeBlobGas(tt.tx)); r != tt.reason {
+			t.Errorf("ChargeBlobGas(%d blobs, fee cap %v) = %v, want %v", tt.tx.blobs, tt.tx.feeCap, r, tt.reason)
+		}
+	}
+	if evm.BlobGasUsed() != 6*BlobGasPerBlob || header.BlobGasUsed == nil || *header.BlobGasUsed != 6*BlobGasPerBlob {
+		t.Errorf("blob gas used = %d, want %d", evm.BlobGasUsed(), 6*BlobGasPerBlob)
+	}
+	if !SkipBlobGasExhausted.Retriable() || SkipMissingBlobs.Retriable() {
+		t.Error("only underpriced or not fitting blob transactions may be retried")
+	}
+}
@@ -0,0 +1,211 @@
+package evmcore
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera/contracts/blocktime"
+)
+
+// NewEVMBlockContext creates the EVM block context of the header.
+// getHash returns the hash of a previous block by its number (the BLOCKHASH opcode).
+func NewEVMBlockContext(h *EvmHeader, getHash vm.GetHashFunc) vm.BlockContext {
+	var baseFee *big.Int
+	if h.BaseFee != nil {
+		baseFee = new(big.Int).Set(h.BaseFee)
+	}
+	return vm.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		GetHash:     getHash,
+		Coinbase:    h.Coinbase,
+		GasLimit:    h.GasLimit,
+		BlockNumber: new(big.Int).Set(h.Number),
+		Time:        new(big.Int).SetUint64(uint64(h.Time.Unix())),
+		Difficulty:  big.NewInt(1),
+		BaseFee:     baseFee,
+	}
+}
+
+// withBlockTime returns a copy of the VM config with the blocktime precompile of the block time.
+// The precompiles of the original config are shared by all the blocks, so they are never modified.
+func withBlockTime(vmConfig vm.Config, time inter.Timestamp) vm.Config {
+	precompiles := make(map[common.Address]vm.PrecompiledStateContract, len(vmConfig.StatePrecompiles)+1)
+	for addr, c := range vmConfig.StatePrecompiles {
+		precompiles[addr] = c
+	}
+	precompiles[blocktime.ContractAddress] = blocktime.PreCompiledContract{Time: time}
+	vmConfig.StatePrecompiles = precompiles
+	return vmConfig
+}
+
+// BlockEVM executes the transactions of a block on a single EVM instance.
+// Creating an EVM (with its interpreter and jump tables) for every transaction is wasteful,
+// as only the transaction context differs between the transactions of a block.
+// It isn't safe for concurrent use.
+type BlockEVM struct {
+	header  *EvmHeader
+	statedb *state.StateDB
+	signer  types.Signer
+	evm     *vm.EVM
+
+	maxBlobGas  uint64
+	blobGasUsed uint64
+
+	hot *HotAccounts // journal of the accessed accounts, nil if not journaled
+}
+
+// NewBlockEVM creates the EVM for the block. cfg is normally taken from EvmConfigCache.
+func NewBlockEVM(cfg *EvmConfig, vmConfig vm.Config, header *EvmHeader, statedb *state.StateDB, getHash vm.GetHashFunc) *BlockEVM {
+	blockCtx := NewEVMBlockContext(header, getHash)
+	if cfg.MillisecondTime {
+		vmConfig = withBlockTime(vmConfig, header.Time)
+	}
+	return &BlockEVM{
+		header:  header,
+		statedb: statedb,
+		signer:  cfg.Signer,
+		evm:     vm.NewEVM(blockCtx, vm.TxContext{}, statedb, cfg.ChainConfig, vmConfig),
+
+		maxBlobGas: cfg.MaxBlobGas,
+	}
+}
+
+// SetHotAccounts makes the EVM journal the accounts accessed by the transactions, see warmup.go.
+func (b *BlockEVM) SetHotAccounts(hot *HotAccounts) {
+	b.hot = hot
+}
+
+// ApplyTransaction executes the transaction on top of the block state.
+// The EVM is reset with the transaction context instead of being created anew.
+func (b *BlockEVM) ApplyTransaction(tx *types.Transaction, txIndex int, gp *core.GasPool) (*core.ExecutionResult, error) {
+	if tx.Type() == BlobTxType {
+		// the transactions of the current geth version don't carry blobs,
+		// so blob transactions are rejected until they do
+		blobTx, ok := interface{}(tx).(BlobTx)
+		if !ok {
+			return nil, types.ErrTxTypeNotSupported
+		}
+		if err := b.ChargeBlobGas(blobTx); err != nil {
+			return nil, err
+		}
+	}
+	msg, err := tx.AsMessage(b.signer, b.header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	if b.hot != nil {
+		b.hot.RecordMessage(msg)
+	}
+	b.statedb.Prepare(tx.Hash(), txIndex)
+	b.evm.Reset(core.NewEVMTxContext(msg), b.statedb)
+	return core.ApplyMessage(b.evm, msg, gp)
+}
+
+// ChargeBlobGas checks the blobs of the transaction against the blob gas price and the blob gas
+// left in the block, and charges their blob gas. Blob transactions aren't supported before Cancun.
+func (b *BlockEVM) ChargeBlobGas(tx BlobTx) error {
+	if b.maxBlobGas == 0 || b.header.ExcessBlobGas == nil {
+		return types.ErrTxTypeNotSupported
+	}
+	blobGas := BlobGas(tx)
+	if blobGas == 0 {
+		return ErrMissingBlobHashes
+	}
+	if blobGas > b.maxBlobGas-b.blobGasUsed {
+		return fmt.Errorf("%w: have %d, want %d", ErrBlobGasLimitReached, b.maxBlobGas-b.blobGasUsed, blobGas)
+	}
+	if fee := CalcBlobFee(*b.header.ExcessBlobGas); tx.BlobGasFeeCap() == nil || tx.BlobGasFeeCap().Cmp(fee) < 0 {
+		return fmt.Errorf("%w: maxFeePerBlobGas: %v blobGasFee: %s", ErrBlobFeeCapTooLow, tx.BlobGasFeeCap(), fee)
+	}
+	b.blobGasUsed += blobGas
+	used := b.blobGasUsed
+	b.header.BlobGasUsed = &used
+	return nil
+}
+
+// BlobGasUsed returns the blob gas charged so far.
+func (b *BlockEVM) BlobGasUsed() uint64 {
+	return b.blobGasUsed
+}
+
+// SkippedTx is a transaction of the block which wasn't executed.
+type SkippedTx struct {
+	Index  uint32 // position of the transaction among all the transactions of the block
+	Reason SkipReason
+	Err    error
+}
+
+// ApplyTransactions executes the transactions of the block in order. The transactions which
+// can't be executed (i.e. not the reverted ones) are skipped, so they don't consume the block gas.
+// results has the same length as txs, with nil results of the skipped transactions.
+func (b *BlockEVM) ApplyTransactions(txs types.Transactions, gp *core.GasPool) (results []*core.ExecutionResult, skipped []SkippedTx) {
+	results = make([]*core.ExecutionResult, len(txs))
+	for i, tx := range txs {
+		res, err := b.ApplyTransaction(tx, i, gp)
+		if err != nil {
+			skipped = append(skipped, SkippedTx{
+				Index:  uint32(i),
+				Reason: ClassifyTxError(err),
+				Err:    err,
+			})
+			continue
+		}
+		results[i] = res
+	}
+	return results, skipped
+}
+
+// Receipts builds the receipts of the transactions executed by ApplyTransactions, leaving out
+// the skipped ones, and sets the receipts root and the logs bloom of the block header.
+func (b *BlockEVM) Receipts(txs types.Transactions, results []*core.ExecutionResult) types.Receipts {
+	receipts := make(types.Receipts, 0, len(txs))
+	var cumulativeGas uint64
+	for i, tx := range txs {
+		res := results[i]
+		if res == nil {
+			continue
+		}
+		cumulativeGas += res.UsedGas
+		receipt := &types.Receipt{
+			Type:              tx.Type(),
+			CumulativeGasUsed: cumulativeGas,
+			TxHash:            tx.Hash(),
+			GasUsed:           res.UsedGas,
+			Status:            types.ReceiptStatusSuccessful,
+			BlockHash:         b.header.Hash,
+			BlockNumber:       new(big.Int).Set(b.header.Number),
+			TransactionIndex:  uint(len(receipts)),
+		}
+		if res.Failed() {
+			receipt.Status = types.ReceiptStatusFailed
+		}
+		if tx.To() == nil {
+			if from, err := types.Sender(b.signer, tx); err == nil {
+				receipt.ContractAddress = crypto.CreateAddress(from, tx.Nonce())
+			}
+		}
+		receipt.Logs = b.statedb.GetLogs(tx.Hash(), b.header.Hash)
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		receipts = append(receipts, receipt)
+	}
+	b.header.SetReceipts(receipts)
+	return receipts
+}
+
+// SkippedIndexes returns the positions of the skipped transactions, i.e. inter.Block.SkippedTxs.
+func SkippedIndexes(skipped []SkippedTx) []uint32 {
+	indexes := make([]uint32, len(skipped))
+	for i, s := range skipped {
+		indexes[i] = s.Index
+	}
+	return indexes
+}
@@ -0,0 +1,225 @@
+package evmcore
+
+import (
+	"crypto/ecdsa"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+const syntheticBlockTxs = 500
+
+func emptyGetHash(uint64) common.Hash {
+	return common.Hash{}
+}
+
+// syntheticBlock returns the pre-state and a block of value transfers between fake accounts.
+func syntheticBlock(t testing.TB, rules opera.Rules, txsNum int) (*state.StateDB, *EvmHeader, types.Transactions) {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const sendersNum = 50
+	keys := make([]*ecdsa.PrivateKey, sendersNum)
+	for i := range keys {
+		keys[i] = FakeKey(i + 1)
+		statedb.SetBalance(crypto.PubkeyToAddress(keys[i].PublicKey), new(big.Int).Exp(big.NewInt(10), big.NewInt(24), nil))
+	}
+
+	signer := NewEvmConfig(rules, nil).Signer
+	txs := make(types.Transactions, 0, txsNum)
+	for i := 0; i < txsNum; i++ {
+		to := common.BigToAddress(big.NewInt(int64(1000 + i)))
+		tx, err := types.SignNewTx(keys[i%sendersNum], signer, &types.LegacyTx{
+			Nonce:    uint64(i / sendersNum),
+			GasPrice: big.NewInt(1),
+			Gas:      21000,
+			To:       &to,
+			Value:    big.NewInt(1),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs = append(txs, tx)
+	}
+
+	header := &EvmHeader{
+		Number:   big.NewInt(1),
+		Time:     FakeGenesisTime + inter.Timestamp(1),
+		GasLimit: math.MaxUint64,
+		BaseFee:  big.NewInt(1), // London is enabled on the fakenet
+	}
+	return statedb, header, txs
+}
+
+// applyNaively executes the block creating the EVM config and the EVM for every transaction.
+func applyNaively(t testing.TB, rules opera.Rules, statedb *state.StateDB, header *EvmHeader, txs types.Transactions) {
+	gp := new(core.GasPool).AddGas(header.GasLimit)
+	for i, tx := range txs {
+		cfg := NewEvmConfig(rules, nil)
+		msg, err := tx.AsMessage(cfg.Signer, header.BaseFee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		statedb.Prepare(tx.Hash(), i)
+		evm := vm.NewEVM(NewEVMBlockContext(header, emptyGetHash), core.NewEVMTxContext(msg), statedb, cfg.ChainConfig, opera.DefaultVMConfig)
+		if _, err := core.ApplyMessage(evm, msg, gp); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// applyReusing executes the block with the cached EVM config and a single EVM.
+func applyReusing(t testing.TB, cache *EvmConfigCache, rules opera.Rules, statedb *state.StateDB, header *EvmHeader, txs types.Transactions) {
+	gp := new(core.GasPool).AddGas(header.GasLimit)
+	evm := NewBlockEVM(cache.Get(1, rules, nil), opera.DefaultVMConfig, header, statedb, emptyGetHash)
+	for i, tx := range txs {
+		if _, err := evm.ApplyTransaction(tx, i, gp); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBlockEVM_SameResult(t *testing.T) {
+	rules := opera.FakeNetRules()
+	statedb, header, txs := syntheticBlock(t, rules, 100)
+
+	naive := statedb.Copy()
+	applyNaively(t, rules, naive, header, txs)
+	reusing := statedb.Copy()
+	applyReusing(t, NewEvmConfigCache(4), rules, reusing, header, txs)
+
+	if naive.IntermediateRoot(true) != reusing.IntermediateRoot(true) {
+		t.Fatal("reusing the EVM changes the state transition")
+	}
+}
+
+func TestEvmConfigCache(t *testing.T) {
+	cache := NewEvmConfigCache(4)
+	rules := opera.FakeNetRules()
+
+	cfg := cache.Get(1, rules, nil)
+	if cache.Get(1, rules, nil) != cfg {
+		t.Fatal("config isn't cached")
+	}
+	if cache.Get(2, rules, nil) == cfg {
+		t.Fatal("config of another epoch is reused")
+	}
+
+	rules.Upgrades.London = false
+	if cache.Get(1, rules, nil) == cfg {
+		t.Fatal("config of other rules is reused")
+	}
+}
+
+// BenchmarkBlockProcessing compares executing a synthetic block of value transfers
+// with the EVM config and the EVM created per transaction vs. cached and reused.
+func BenchmarkBlockProcessing(b *testing.B) {
+	rules := opera.FakeNetRules()
+	statedb, header, txs := syntheticBlock(b, rules, syntheticBlockTxs)
+
+	b.Run("per-tx", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			s := statedb.Copy()
+			b.StartTimer()
+			applyNaively(b, rules, s, header, txs)
+		}
+	})
+	b.Run("reused", func(b *testing.B) {
+		cache := NewEvmConfigCache(4)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			s := statedb.Copy()
+			b.StartTimer()
+			applyReusing(b, cache, rules, s, header, txs)
+		}
+	})
+}
+
+func TestBlockEVM_Receipts(t *testing.T) {
+	rules := opera.FakeNetRules()
+	statedb, header, txs := syntheticBlock(t, rules, 3)
+	header.Hash = common.Hash{1}
+	// the first transaction calls a contract which emits a log: PUSH1 0 PUSH1 0 LOG0 STOP
+	logger := common.Address{0xaa}
+	statedb.SetCode(logger, []byte{0x60, 0x00, 0x60, 0x00, 0xa0, 0x00})
+	call, err := types.SignNewTx(FakeKey(1), NewEvmConfig(rules, nil).Signer, &types.LegacyTx{
+		GasPrice: big.NewInt(1),
+		Gas:      100000,
+		To:       &logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs[0] = call
+	// the third transaction reuses the nonce of the first one, so it's skipped
+	txs[2] = txs[0]
+
+	evm := NewBlockEVM(NewEvmConfig(rules, nil), opera.DefaultVMConfig, header, statedb, emptyGetHash)
+	results, skipped := evm.ApplyTransactions(txs, new(core.GasPool).AddGas(header.GasLimit))
+	if len(skipped) != 1 || skipped[0].Index != 2 {
+		t.Fatalf("skipped %v, want the third transaction", skipped)
+	}
+	receipts := evm.Receipts(txs, results)
+	if len(receipts) != 2 {
+		t.Fatalf("got %d receipts, want 2", len(receipts))
+	}
+	for i, r := range receipts {
+		if r.TxHash != txs[i].Hash() || r.TransactionIndex != uint(i) || r.Status != types.ReceiptStatusSuccessful {
+			t.Errorf("receipt %d: tx %s, index %d, status %d", i, r.TxHash.Hex(), r.TransactionIndex, r.Status)
+		}
+		if r.BlockHash != header.Hash || r.GasUsed != results[i].UsedGas {
+			t.Errorf("receipt %d: block %s, gas %d", i, r.BlockHash.Hex(), r.GasUsed)
+		}
+	}
+	if receipts[1].CumulativeGasUsed != receipts[0].GasUsed+receipts[1].GasUsed {
+		t.Errorf("cumulative gas %d", receipts[1].CumulativeGasUsed)
+	}
+	if len(receipts[0].Logs) != 1 || receipts[0].Logs[0].Address != logger || len(receipts[1].Logs) != 0 {
+		t.Fatalf("unexpected logs %v, %v", receipts[0].Logs, receipts[1].Logs)
+	}
+
+	// the header commits to the receipts
+	if want := types.DeriveSha(receipts, trie.NewStackTrie(nil)); header.ReceiptHash != want {
+		t.Errorf("receipts root %s, want %s", header.ReceiptHash.Hex(), want.Hex())
+	}
+	if !types.BloomLookup(header.Bloom, logger) || types.BloomLookup(header.Bloom, *txs[1].To()) {
+		t.Error("logs bloom doesn't match the logs")
+	}
+
+	// and they survive storing the block
+	block := &inter.Block{Time: header.Time}
+	header.StoreReceipts(block)
+	restored := ToEvmHeader(block, 1, hash.Event{}, rules)
+	if restored.ReceiptHash != header.ReceiptHash || restored.Bloom != header.Bloom {
+		t.Error("receipts root or logs bloom isn't restored from the stored block")
+	}
+	if eth := NewEvmBlock(restored, nil).EthBlock(); eth.ReceiptHash() != header.ReceiptHash || eth.Bloom() != header.Bloom {
+		t.Error("receipts root or logs bloom is lost by EthBlock")
+	}
+}
+
+func TestSetReceipts_Empty(t *testing.T) {
+	h := &EvmHeader{ReceiptHash: common.Hash{1}, Bloom: types.Bloom{1}}
+	h.SetReceipts(nil)
+	if h.ReceiptHash != types.EmptyRootHash || h.Bloom != (types.Bloom{}) {
+		t.Errorf("empty block: receipts root %s, bloom %x", h.ReceiptHash.Hex(), h.Bloom[:4])
+	}
+}
@@ -0,0 +1,47 @@
+package evmcore
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+	"github.com/rony4d/go-opera-asset/opera/contracts/blocktime"
+)
+
+var timestampMsInput = common.FromHex("0x5745a677") // timestampMs()
+
+// TestBlockTimePrecompile verifies that the block time in milliseconds is exposed
+// only once the upgrade is enabled, rounded down consistently with TIMESTAMP.
+func TestBlockTimePrecompile(t *testing.T) {
+	caller := vm.AccountRef(common.HexToAddress("0x1000"))
+	call := func(rules opera.Rules, time inter.Timestamp) []byte {
+		statedb, header, _ := syntheticBlock(t, rules, 0)
+		header.Time = time
+		b := NewBlockEVM(NewEvmConfig(rules, nil), opera.DefaultVMConfig, header, statedb, emptyGetHash)
+		ret, _, err := b.evm.Call(caller, blocktime.ContractAddress, timestampMsInput, 100000, new(big.Int))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ret
+	}
+
+	blockTime := inter.FromUnix(1600000000) + inter.Timestamp(123999*time.Microsecond)
+	if ret := call(opera.FakeNetRules(), blockTime); len(ret) != 0 {
+		t.Fatalf("block time exposed before the upgrade: %x", ret)
+	}
+
+	rules := opera.FakeNetRules()
+	rules.Upgrades.MillisecondTime = true
+	ret := call(rules, blockTime)
+	if ms := new(big.Int).SetBytes(ret); len(ret) != 32 || ms.Int64() != 1600000000123 {
+		t.Fatalf("timestampMs() = %x, want 1600000000123", ret)
+	}
+	if _, ok := opera.DefaultVMConfig.StatePrecompiles[blocktime.ContractAddress]; ok {
+		t.Fatal("the shared VM config is modified")
+	}
+}
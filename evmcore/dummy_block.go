@@ -70,6 +70,13 @@ type EvmHeader struct {
 	GasUsed  uint64 // Total gas consumed by transactions in this block
 
 	BaseFee *big.Int // Base fee per gas (EIP-1559, nil if London upgrade not active)
+
+	ReceiptHash common.Hash // Receipts root (Merkle root of receipt trie)
+	Bloom       types.Bloom // Bloom filter of the logs of all the block receipts
+
+	// Blob gas accounting (EIP-4844), both nil if the Cancun upgrade isn't active
+	ExcessBlobGas *uint64 // Blob gas above the target accumulated by the previous blocks, see NextExcessBlobGas
+	BlobGasUsed   *uint64 // Total blob gas consumed by blob transactions in this block
 }
 
 // EvmBlock represents a complete EVM-compatible block containing a header
@@ -112,6 +119,25 @@ func NewEvmBlock(h *EvmHeader, txs types.Transactions) *EvmBlock {
 	return b
 }
 
+// SetReceipts computes the receipts root and the logs bloom of the executed block.
+// It must be called by the block processor once all the block transactions are executed.
+func (h *EvmHeader) SetReceipts(receipts types.Receipts) {
+	if len(receipts) == 0 {
+		h.ReceiptHash = types.EmptyRootHash
+		h.Bloom = types.Bloom{}
+		return
+	}
+	h.ReceiptHash = types.DeriveSha(receipts, trie.NewStackTrie(nil))
+	h.Bloom = types.CreateBloom(receipts)
+}
+
+// StoreReceipts copies the receipts root and the logs bloom set by SetReceipts into the
+// consensus block, so ToEvmHeader restores them once the block is stored.
+func (h *EvmHeader) StoreReceipts(block *inter.Block) {
+	block.ReceiptHash = hash.Hash(h.ReceiptHash)
+	block.Bloom = h.Bloom
+}
+
 // ToEvmHeader converts an Opera consensus block (inter.Block) into an EVM-compatible
 // header format. This is the primary conversion function used when Opera's consensus
 // produces a new block and it needs to be executed by the EVM.
@@ -129,6 +155,7 @@ func NewEvmBlock(h *EvmHeader, txs types.Transactions) *EvmBlock {
 //   - block.Atropos (consensus event hash) -> Hash
 //   - block.Root (state root) -> Root
 //   - block.Time (Opera timestamp) -> Time
+//   - block.ReceiptHash, block.Bloom (stored execution results) -> ReceiptHash, Bloom
 //   - GasLimit always set to MaxUint64 (Opera doesn't limit gas per-block)
 //   - BaseFee only set if London upgrade (EIP-1559) is active
 func ToEvmHeader(block *inter.Block, index idx.Block, prevHash hash.Event, rules opera.Rules) *EvmHeader {
@@ -147,6 +174,9 @@ func ToEvmHeader(block *inter.Block, index idx.Block, prevHash hash.Event, rules
 		GasLimit:   math.MaxUint64,             // Unlimited gas (Opera manages gas per-event)
 		GasUsed:    block.GasUsed,              // Actual gas consumed by transactions
 		BaseFee:    baseFee,                    // Base fee (nil if London not active)
+
+		ReceiptHash: common.Hash(block.ReceiptHash), // Receipts root computed by the block processor
+		Bloom:       block.Bloom,                    // Logs bloom computed by the block processor
 	}
 }
 
@@ -176,6 +206,9 @@ func ConvertFromEthHeader(h *types.Header) *EvmHeader {
 		Time:       inter.FromUnix(int64(h.Time)), // Convert Unix timestamp to Opera timestamp
 		Hash:       common.BytesToHash(h.Extra),   // Store Opera hash in Extra field (hack for compatibility)
 		BaseFee:    h.BaseFee,                     // Base fee (EIP-1559)
+
+		ReceiptHash: h.ReceiptHash, // Receipts root
+		Bloom:       h.Bloom,       // Logs bloom
 	}
 }
 
@@ -209,6 +242,9 @@ func (h *EvmHeader) EthHeader() *types.Header {
 		Extra:      h.Hash.Bytes(),        // Store Opera hash in Extra field
 		BaseFee:    h.BaseFee,             // Base fee (EIP-1559)
 
+		ReceiptHash: h.ReceiptHash, // Receipts root
+		Bloom:       h.Bloom,       // Logs bloom
+
 		Difficulty: new(big.Int), // Zero difficulty (Opera doesn't use PoW)
 	}
 
@@ -226,6 +262,7 @@ func (h *EvmHeader) EthHeader() *types.Header {
 // The copy includes:
 //   - All struct fields (copied by value)
 //   - Number and BaseFee (big.Int pointers are deep-copied)
+//   - ExcessBlobGas and BlobGasUsed (uint64 pointers are deep-copied)
 func (b *EvmBlock) Header() *EvmHeader {
 	if b == nil {
 		return nil
@@ -239,6 +276,14 @@ func (b *EvmBlock) Header() *EvmHeader {
 	if b.BaseFee != nil {
 		h.BaseFee = new(big.Int).Set(b.BaseFee)
 	}
+	if b.ExcessBlobGas != nil {
+		excess := *b.ExcessBlobGas
+		h.ExcessBlobGas = &excess
+	}
+	if b.BlobGasUsed != nil {
+		used := *b.BlobGasUsed
+		h.BlobGasUsed = &used
+	}
 
 	return &h
 }
@@ -254,7 +299,7 @@ func (b *EvmBlock) Header() *EvmHeader {
 //   - Header conversion (via EthHeader())
 //   - Transaction list (direct copy)
 //   - Empty uncles list (Opera doesn't have uncle blocks)
-//   - Empty receipts (receipts are computed during execution, not stored in block)
+//   - Receipts root and logs bloom from the header (receipts themselves aren't stored in block)
 func (b *EvmBlock) EthBlock() *types.Block {
 	if b == nil {
 		return nil
@@ -266,13 +311,22 @@ func (b *EvmBlock) EthBlock() *types.Block {
 	//   - nil uncles (Opera doesn't have uncle blocks)
 	//   - nil receipts (computed during execution)
 	//   - StackTrie for efficient hashing
-	return types.NewBlock(
+	block := types.NewBlock(
 		b.EvmHeader.EthHeader(), // Convert header to Ethereum format
 		b.Transactions,          // Include transactions
 		nil,                     // No uncles (Opera doesn't have them)
 		nil,                     // No receipts (computed during execution)
 		trie.NewStackTrie(nil),  // Efficient trie for hashing
 	)
+	if b.ReceiptHash == (common.Hash{}) {
+		// receipts root is unknown (e.g. block was executed before it got stored)
+		return block
+	}
+	// NewBlock resets the receipts root without receipts, so restore the computed one
+	header := block.Header()
+	header.ReceiptHash = b.ReceiptHash
+	header.Bloom = b.Bloom
+	return block.WithSeal(header)
 }
 
 // EstimateSize returns an approximate size estimate of the block in bytes.
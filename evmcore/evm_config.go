@@ -0,0 +1,86 @@
+package evmcore
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// EvmConfig is the EVM configuration derived from the network rules.
+// It's immutable and shared by all the blocks of an epoch, so it must never be modified.
+type EvmConfig struct {
+	ChainConfig *params.ChainConfig
+	Signer      types.Signer
+	MaxBlobGas  uint64 // blob gas limit per block, 0 if the Cancun upgrade isn't active
+	// MillisecondTime registers the blocktime precompile, see opera.Upgrades.MillisecondTime
+	MillisecondTime bool
+}
+
+// NewEvmConfig derives the EVM configuration from the rules and the upgrade heights.
+func NewEvmConfig(rules opera.Rules, upgradeHeights []opera.UpgradeHeight) *EvmConfig {
+	chainConfig := rules.EvmChainConfig(upgradeHeights)
+	cfg := &EvmConfig{
+		ChainConfig: chainConfig,
+		Signer:      types.LatestSigner(chainConfig),
+
+		MillisecondTime: rules.Upgrades.MillisecondTime,
+	}
+	if rules.Upgrades.Cancun {
+		cfg.MaxBlobGas = rules.Blocks.MaxBlobGasPerBlock
+	}
+	return cfg
+}
+
+type evmConfigKey struct {
+	epoch idx.Epoch
+	rules hash.Hash
+}
+
+// EvmConfigCache caches the EVM configurations per (epoch, rules hash), as the rules
+// and the upgrade heights change only at epoch boundaries (the rules hash covers the
+// rules updated by governance in the middle of the epoch).
+// It's safe for concurrent use.
+type EvmConfigCache struct {
+	mu    sync.Mutex
+	cache *lru.Cache // evmConfigKey -> *EvmConfig
+}
+
+// NewEvmConfigCache creates the cache which keeps configs of the `size` most recent epochs.
+func NewEvmConfigCache(size int) *EvmConfigCache {
+	cache, _ := lru.New(size)
+	return &EvmConfigCache{
+		cache: cache,
+	}
+}
+
+// Get returns the EVM configuration of the epoch, deriving it if it isn't cached.
+// upgradeHeights must be the heights as of the epoch.
+func (c *EvmConfigCache) Get(epoch idx.Epoch, rules opera.Rules, upgradeHeights []opera.UpgradeHeight) *EvmConfig {
+	key := evmConfigKey{epoch, rulesHash(rules)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cfg, ok := c.cache.Get(key); ok {
+		return cfg.(*EvmConfig)
+	}
+	cfg := NewEvmConfig(rules, upgradeHeights)
+	c.cache.Add(key, cfg)
+	return cfg
+}
+
+// rulesHash returns the hash of all the rules fields, including the upgrades,
+// which are skipped by the RLP encoding.
+func rulesHash(rules opera.Rules) hash.Hash {
+	b, err := json.Marshal(&rules)
+	if err != nil {
+		panic("can't hash rules: " + err.Error())
+	}
+	return hash.Of(b)
+}
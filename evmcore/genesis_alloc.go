@@ -0,0 +1,118 @@
+package evmcore
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/state"
+
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+// GenesisAccount is an account in the state of the genesis block.
+// Besides the balance, it may hold a pre-deployed contract (code and storage).
+type GenesisAccount struct {
+	Code    []byte
+	Storage map[common.Hash]common.Hash
+	Balance *big.Int
+	Nonce   uint64
+}
+
+// GenesisAlloc specifies the initial state that is part of the genesis block.
+// Its JSON format is the same as geth's genesis "alloc" section.
+type GenesisAlloc map[common.Address]GenesisAccount
+
+// genesisAccountJSON is the JSON representation of GenesisAccount.
+type genesisAccountJSON struct {
+	Code    hexutil.Bytes               `json:"code,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+	Balance *math.HexOrDecimal256       `json:"balance"`
+	Nonce   math.HexOrDecimal64         `json:"nonce,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a GenesisAccount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(genesisAccountJSON{
+		Code:    a.Code,
+		Storage: a.Storage,
+		Balance: (*math.HexOrDecimal256)(a.Balance),
+		Nonce:   math.HexOrDecimal64(a.Nonce),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *GenesisAccount) UnmarshalJSON(input []byte) error {
+	var dec genesisAccountJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.Balance == nil {
+		return errors.New("missing required field 'balance' for GenesisAccount")
+	}
+	a.Code = dec.Code
+	a.Storage = dec.Storage
+	a.Balance = (*big.Int)(dec.Balance)
+	a.Nonce = uint64(dec.Nonce)
+	return nil
+}
+
+// genesisFileJSON is the part of a genesis file which holds the allocation.
+type genesisFileJSON struct {
+	Alloc GenesisAlloc `json:"alloc"`
+}
+
+// ReadGenesisAlloc reads the "alloc" section of a JSON genesis file.
+func ReadGenesisAlloc(r io.Reader) (GenesisAlloc, error) {
+	var file genesisFileJSON
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+	if len(file.Alloc) == 0 {
+		return nil, errors.New("genesis file has no accounts")
+	}
+	return file.Alloc, nil
+}
+
+// LoadGenesisAlloc reads the allocation of the genesis file at the path.
+func LoadGenesisAlloc(path string) (GenesisAlloc, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadGenesisAlloc(f)
+}
+
+// Apply writes the accounts into the state.
+func (ga GenesisAlloc) Apply(statedb *state.StateDB) {
+	for addr, account := range ga {
+		if account.Balance != nil {
+			statedb.SetBalance(addr, account.Balance)
+		}
+		statedb.SetNonce(addr, account.Nonce)
+		if len(account.Code) != 0 {
+			statedb.SetCode(addr, account.Code)
+		}
+		for key, value := range account.Storage {
+			statedb.SetState(addr, key, value)
+		}
+	}
+}
+
+// ApplyGenesis initializes the genesis block with the specified accounts.
+// It's used for both the real genesis files and the fake genesis.
+func ApplyGenesis(statedb *state.StateDB, time inter.Timestamp, alloc GenesisAlloc) (*EvmBlock, error) {
+	alloc.Apply(statedb)
+
+	root, err := flush(statedb, true)
+	if err != nil {
+		return nil, err
+	}
+	return genesisBlock(time, root), nil
+}
@@ -0,0 +1,130 @@
+package evmcore
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	genesisContract = common.HexToAddress("0xd100a01e00000000000000000000000000000000")
+	genesisHolder   = common.HexToAddress("0x1000")
+)
+
+func testGenesisAlloc() GenesisAlloc {
+	return GenesisAlloc{
+		genesisContract: {
+			Code:    []byte{0x60, 0x00, 0x54, 0x00}, // PUSH1 0 SLOAD STOP
+			Storage: map[common.Hash]common.Hash{{1}: {2}, {3}: {4}},
+			Balance: big.NewInt(0),
+			Nonce:   1,
+		},
+		genesisHolder: {
+			Balance: new(big.Int).Exp(big.NewInt(10), big.NewInt(24), nil),
+		},
+	}
+}
+
+func TestGenesisAlloc_JSON(t *testing.T) {
+	alloc := testGenesisAlloc()
+	data, err := json.Marshal(alloc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded GenesisAlloc
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if again, err := json.Marshal(decoded); err != nil || string(again) != string(data) {
+		t.Errorf("round-trip changed the allocation: %s != %s (%v)", again, data, err)
+	}
+	for addr, account := range alloc {
+		got := decoded[addr]
+		if got.Balance.Cmp(account.Balance) != 0 || got.Nonce != account.Nonce || string(got.Code) != string(account.Code) || len(got.Storage) != len(account.Storage) {
+			t.Errorf("%s: decoded %+v, want %+v", addr.Hex(), got, account)
+		}
+		for key, value := range account.Storage {
+			if got.Storage[key] != value {
+				t.Errorf("%s: storage %s = %s, want %s", addr.Hex(), key.Hex(), got.Storage[key].Hex(), value.Hex())
+			}
+		}
+	}
+
+	// geth's alloc section is accepted, with decimal or hex numbers
+	geth := `{"alloc": {
+		"0x0000000000000000000000000000000000001000": {"balance": "1000000000000000000000000"},
+		"0xd100a01e00000000000000000000000000000000": {
+			"code": "0x6000540000", "nonce": "0x1", "balance": "0x0",
+			"storage": {"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000002"}
+		}
+	}}`
+	read, err := ReadGenesisAlloc(strings.NewReader(geth))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read[genesisHolder].Balance.Cmp(alloc[genesisHolder].Balance) != 0 {
+		t.Errorf("balance %s, want %s", read[genesisHolder].Balance, alloc[genesisHolder].Balance)
+	}
+	if c := read[genesisContract]; c.Nonce != 1 || len(c.Code) != 5 || c.Storage[common.BigToHash(big.NewInt(1))] != common.BigToHash(big.NewInt(2)) {
+		t.Errorf("unexpected contract %+v", c)
+	}
+
+	if _, err := ReadGenesisAlloc(strings.NewReader(`{"alloc": {"0x1000": {"nonce": "1"}}}`)); err == nil {
+		t.Error("account without balance is accepted")
+	}
+	if _, err := ReadGenesisAlloc(strings.NewReader(`{"config": {}}`)); err == nil {
+		t.Error("genesis without accounts is accepted")
+	}
+}
+
+func TestApplyGenesis(t *testing.T) {
+	alloc := testGenesisAlloc()
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ApplyGenesis(statedb, FakeGenesisTime, alloc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the accounts are committed into the genesis state
+	committed, err := state.New(block.Root, statedb.Database(), nil)
+	if err != nil {
+		t.Fatalf("genesis state isn't committed: %v", err)
+	}
+	for addr, account := range alloc {
+		if got := committed.GetBalance(addr); got.Cmp(account.Balance) != 0 {
+			t.Errorf("%s: balance %s, want %s", addr.Hex(), got, account.Balance)
+		}
+		if got := committed.GetNonce(addr); got != account.Nonce {
+			t.Errorf("%s: nonce %d, want %d", addr.Hex(), got, account.Nonce)
+		}
+		if got := committed.GetCodeHash(addr); len(account.Code) != 0 && got != crypto.Keccak256Hash(account.Code) {
+			t.Errorf("%s: code hash %s", addr.Hex(), got.Hex())
+		}
+		for key, value := range account.Storage {
+			if got := committed.GetState(addr, key); got != value {
+				t.Errorf("%s: storage %s = %s, want %s", addr.Hex(), key.Hex(), got.Hex(), value.Hex())
+			}
+		}
+	}
+	if committed.GetCodeSize(genesisHolder) != 0 || committed.GetState(genesisContract, common.Hash{5}) != (common.Hash{}) {
+		t.Error("unexpected code or storage")
+	}
+
+	// the genesis is deterministic
+	again, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block2, err := ApplyGenesis(again, FakeGenesisTime, alloc); err != nil || block2.Root != block.Root {
+		t.Errorf("genesis root %s != %s (%v)", block2.Root.Hex(), block.Root.Hex(), err)
+	}
+}
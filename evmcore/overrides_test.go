@@ -0,0 +1,95 @@
+package evmcore
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+func TestStateOverride(t *testing.T) {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replaced, diffed := common.Address{1}, common.Address{2}
+	for _, addr := range []common.Address{replaced, diffed} {
+		statedb.SetState(addr, common.Hash{1}, common.Hash{0xa})
+		statedb.SetState(addr, common.Hash{2}, common.Hash{0xb})
+	}
+
+	nonce := hexutil.Uint64(7)
+	code := hexutil.Bytes{0x60, 0x00}
+	balance := (*hexutil.Big)(big.NewInt(1000))
+	storage := map[common.Hash]common.Hash{{1}: {0xc}}
+	diff := map[common.Hash]common.Hash{{1}: {0xd}, {3}: {0xe}}
+	overrides := StateOverride{
+		replaced: {Nonce: &nonce, Code: &code, Balance: &balance, State: &storage},
+		diffed:   {StateDiff: &diff},
+	}
+	if err := overrides.Apply(statedb); err != nil {
+		t.Fatal(err)
+	}
+	if statedb.GetNonce(replaced) != 7 || !bytes.Equal(statedb.GetCode(replaced), code) || statedb.GetBalance(replaced).Int64() != 1000 {
+		t.Fatal("account fields aren't overridden")
+	}
+	// state replaces the whole storage
+	if statedb.GetState(replaced, common.Hash{1}) != (common.Hash{0xc}) || statedb.GetState(replaced, common.Hash{2}) != (common.Hash{}) {
+		t.Fatal("storage isn't replaced")
+	}
+	// stateDiff modifies only the given slots
+	if statedb.GetState(diffed, common.Hash{1}) != (common.Hash{0xd}) || statedb.GetState(diffed, common.Hash{2}) != (common.Hash{0xb}) ||
+		statedb.GetState(diffed, common.Hash{3}) != (common.Hash{0xe}) {
+		t.Fatal("storage diff isn't applied")
+	}
+
+	conflict := StateOverride{replaced: {State: &storage, StateDiff: &diff}}
+	if err := conflict.Apply(statedb); err == nil || !strings.Contains(err.Error(), "both 'state' and 'stateDiff'") {
+		t.Fatalf("state and stateDiff conflict isn't rejected: %v", err)
+	}
+	var none *StateOverride
+	if err := none.Apply(statedb); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBlockOverrides(t *testing.T) {
+	baseFee := big.NewInt(100)
+	h := EvmHeader{Number: big.NewInt(5), Time: inter.FromUnix(1000), GasLimit: 1e6, BaseFee: baseFee}
+
+	var none *BlockOverrides
+	none.Apply(&h)
+	if h.Number.Int64() != 5 || h.Time != inter.FromUnix(1000) {
+		t.Fatal("nil overrides modify the header")
+	}
+
+	number, fee := (*hexutil.Big)(big.NewInt(77)), (*hexutil.Big)(big.NewInt(1))
+	tm, gasLimit := hexutil.Uint64(2000), hexutil.Uint64(42)
+	coinbase := common.Address{9}
+	(&BlockOverrides{Number: number, Time: &tm, GasLimit: &gasLimit, Coinbase: &coinbase, BaseFee: fee}).Apply(&h)
+	if h.Number.Int64() != 77 || h.Time != inter.FromUnix(2000) || h.GasLimit != 42 || h.Coinbase != coinbase || h.BaseFee.Int64() != 1 {
+		t.Fatalf("header isn't overridden: %+v", h)
+	}
+	// the big.Int fields are replaced, not modified in place
+	if baseFee.Int64() != 100 || number.ToInt().Int64() != 77 {
+		t.Fatal("overrides share big.Int fields with the header")
+	}
+	h.Number.SetInt64(1)
+	if number.ToInt().Int64() != 77 {
+		t.Fatal("header shares the number with the overrides")
+	}
+
+	// only the set fields are overridden
+	h2 := EvmHeader{Number: big.NewInt(5), Time: inter.FromUnix(1000), GasLimit: 1e6}
+	(&BlockOverrides{GasLimit: &gasLimit}).Apply(&h2)
+	if h2.Number.Int64() != 5 || h2.Time != inter.FromUnix(1000) || h2.GasLimit != 42 || h2.BaseFee != nil {
+		t.Fatalf("unset fields are overridden: %+v", h2)
+	}
+}
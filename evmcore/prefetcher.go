@@ -0,0 +1,81 @@
+// This file implements the prefetching of the state of a block which is being decided.
+//
+// Overview:
+//   The transactions of a block are known before the block is decided: they are the
+//   transactions of the events already confirmed by the consensus, and the events of the
+//   next Atropos mostly carry them. While the block is being decided, the Prefetcher executes
+//   these candidate transactions on a throwaway copy of the parent block's state, which loads
+//   the trie nodes and the code they touch into the caches of the state database, so the
+//   actual execution of the block mostly hits warm caches.
+//
+//   The speculative execution never affects the block: it runs on its own StateDB, the
+//   failed transactions are ignored, and it stops once its context is done, i.e. once the block
+//   is decided and its actual execution starts. At the end, the intermediate root of the
+//   speculative state is computed, which also loads the trie nodes on the paths of the
+//   modified accounts, needed to compute the state root of the block.
+
+package evmcore
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// PrefetchStats is the outcome of Prefetch.
+type PrefetchStats struct {
+	Txs      int // executed transactions, including the failed ones
+	Failed   int // transactions which couldn't be executed on the speculative state
+	Duration time.Duration
+}
+
+// Prefetcher warms the caches of the state database for the blocks being decided.
+// It's safe for concurrent use, if the state database is.
+type Prefetcher struct {
+	cfg      *EvmConfig
+	vmConfig vm.Config
+	db       state.Database
+}
+
+// NewPrefetcher creates the prefetcher of the state database. cfg is normally taken from EvmConfigCache.
+func NewPrefetcher(cfg *EvmConfig, vmConfig vm.Config, db state.Database) *Prefetcher {
+	return &Prefetcher{
+		cfg:      cfg,
+		vmConfig: vmConfig,
+		db:       db,
+	}
+}
+
+// Prefetch speculatively executes the candidate transactions of the block with the header
+// on top of the parent state with the root. It stops once ctx is done, returning the stats of
+// the partial prefetch along with the ctx error.
+func (p *Prefetcher) Prefetch(ctx context.Context, root common.Hash, header *EvmHeader, txs types.Transactions, getHash vm.GetHashFunc) (PrefetchStats, error) {
+	start := time.Now()
+	var stats PrefetchStats
+	statedb, err := state.New(root, p.db, nil)
+	if err != nil {
+		return stats, err
+	}
+	// the header may be modified by the execution (blob gas)
+	h := *header
+	evm := NewBlockEVM(p.cfg, p.vmConfig, &h, statedb, getHash)
+	gp := new(core.GasPool).AddGas(h.GasLimit)
+	for i, tx := range txs {
+		if err := ctx.Err(); err != nil {
+			stats.Duration = time.Since(start)
+			return stats, err
+		}
+		stats.Txs++
+		if _, err := evm.ApplyTransaction(tx, i, gp); err != nil {
+			stats.Failed++
+		}
+	}
+	statedb.IntermediateRoot(true)
+	stats.Duration = time.Since(start)
+	return stats, nil
+}
@@ -0,0 +1,114 @@
+package evmcore
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// persistedBlock returns the disk DB with the pre-state of a synthetic block, among many
+// other accounts so the trie is deep, the root of the pre-state and the block.
+func persistedBlock(t testing.TB, rules opera.Rules, txsNum, accounts int) (ethdb.Database, common.Hash, *EvmHeader, types.Transactions) {
+	diskdb := rawdb.NewMemoryDatabase()
+	sdb := state.NewDatabase(diskdb)
+	pre, header, txs := syntheticBlock(t, rules, txsNum)
+	statedb, err := state.New(common.Hash{}, sdb, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < accounts; i++ {
+		statedb.SetBalance(common.BigToAddress(big.NewInt(int64(1000+i))), big.NewInt(1))
+	}
+	for _, tx := range txs {
+		from, _ := types.Sender(NewEvmConfig(rules, nil).Signer, tx)
+		statedb.SetBalance(from, pre.GetBalance(from))
+	}
+	root, err := statedb.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sdb.TrieDB().Commit(root, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	return diskdb, root, header, txs
+}
+
+// coldDatabase returns the state database of the disk DB with empty caches.
+func coldDatabase(diskdb ethdb.Database) state.Database {
+	return state.NewDatabaseWithConfig(diskdb, &trie.Config{Cache: 64})
+}
+
+func executeBlock(t testing.TB, rules opera.Rules, sdb state.Database, root common.Hash, header *EvmHeader, txs types.Transactions) common.Hash {
+	statedb, err := state.New(root, sdb, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := *header
+	applyReusing(t, NewEvmConfigCache(1), rules, statedb, &h, txs)
+	return statedb.IntermediateRoot(true)
+}
+
+func TestPrefetcher(t *testing.T) {
+	rules := opera.FakeNetRules()
+	diskdb, root, header, txs := persistedBlock(t, rules, 100, 1000)
+	want := executeBlock(t, rules, coldDatabase(diskdb), root, header, txs)
+
+	sdb := coldDatabase(diskdb)
+	p := NewPrefetcher(NewEvmConfig(rules, nil), opera.DefaultVMConfig, sdb)
+	// a candidate transaction which fails doesn't stop the prefetch
+	candidates := append(types.Transactions{txs[len(txs)-1]}, txs...)
+	stats, err := p.Prefetch(context.Background(), root, header, candidates, emptyGetHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Txs != len(candidates) || stats.Failed != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if got := executeBlock(t, rules, sdb, root, header, txs); got != want {
+		t.Fatal("prefetching changes the state transition")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stats, err = p.Prefetch(ctx, root, header, txs, emptyGetHash)
+	if err != context.Canceled || stats.Txs != 0 {
+		t.Fatalf("prefetch isn't stopped: %+v, %v", stats, err)
+	}
+}
+
+// BenchmarkPrefetchedBlock compares the execution of a block on cold caches with the
+// execution after the block is prefetched while it's being decided.
+func BenchmarkPrefetchedBlock(b *testing.B) {
+	rules := opera.FakeNetRules()
+	diskdb, root, header, txs := persistedBlock(b, rules, syntheticBlockTxs, 50000)
+
+	b.Run("cold", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			sdb := coldDatabase(diskdb)
+			b.StartTimer()
+			executeBlock(b, rules, sdb, root, header, txs)
+		}
+	})
+	b.Run("prefetched", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			sdb := coldDatabase(diskdb)
+			p := NewPrefetcher(NewEvmConfig(rules, nil), opera.DefaultVMConfig, sdb)
+			if _, err := p.Prefetch(context.Background(), root, header, txs, emptyGetHash); err != nil {
+				b.Fatal(err)
+			}
+			b.StartTimer()
+			executeBlock(b, rules, sdb, root, header, txs)
+		}
+	})
+}
@@ -0,0 +1,210 @@
+// This file is the single place where transactions are validated and their failures
+// are classified, shared by the txpool (and eth_sendRawTransaction) and the block processor.
+//
+// Overview:
+//   Every failure is mapped to a canonical SkipReason with a stable numeric code.
+//   The classification depends only on the error, which depends only on the transaction,
+//   the rules and the state, so all the validators skip the same transactions of a block
+//   for the same reasons (inter.Block.SkippedTxs), and the txpool drops exactly the
+//   transactions the block processor would skip forever.
+
+package evmcore
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// txMaxSize is the maximum size of a transaction accepted by the txpool.
+const txMaxSize = 4 * 32 * 1024
+
+// SkipReason is the canonical reason of a transaction failure.
+// The codes are a part of the protocol: never renumber or reuse them, only append new ones.
+type SkipReason uint8
+
+const (
+	SkipNone               SkipReason = 0 // the transaction is valid
+	SkipTxTypeNotSupported SkipReason = 1
+	SkipOversized          SkipReason = 2
+	SkipNegativeValue      SkipReason = 3
+	SkipGasAboveBlockLimit SkipReason = 4
+	SkipFeeCapVeryHigh     SkipReason = 5
+	SkipTipVeryHigh        SkipReason = 6
+	SkipTipAboveFeeCap     SkipReason = 7
+	SkipInvalidChainID     SkipReason = 8
+	SkipInvalidSender      SkipReason = 9
+	SkipUnderpriced        SkipReason = 10
+	SkipFeeCapTooLow       SkipReason = 11
+	SkipIntrinsicGas       SkipReason = 12
+	SkipNonceTooLow        SkipReason = 13
+	SkipNonceTooHigh       SkipReason = 14
+	SkipInsufficientFunds  SkipReason = 15
+	SkipBlockGasExhausted  SkipReason = 16
+	SkipSenderNoEOA        SkipReason = 17
+	SkipGasUintOverflow    SkipReason = 18
+	SkipBlobFeeCapTooLow   SkipReason = 19
+	SkipBlobGasExhausted   SkipReason = 20
+	SkipMissingBlobs       SkipReason = 21
+	SkipUnknown            SkipReason = 255 // an error which isn't classified yet
+)
+
+var skipReasons = []struct {
+	reason SkipReason
+	err    error
+	name   string
+}{
+	{SkipTxTypeNotSupported, types.ErrTxTypeNotSupported, "tx type not supported"},
+	{SkipOversized, core.ErrOversizedData, "oversized"},
+	{SkipNegativeValue, core.ErrNegativeValue, "negative value"},
+	{SkipGasAboveBlockLimit, core.ErrGasLimit, "gas above block limit"},
+	{SkipFeeCapVeryHigh, core.ErrFeeCapVeryHigh, "fee cap very high"},
+	{SkipTipVeryHigh, core.ErrTipVeryHigh, "tip very high"},
+	{SkipTipAboveFeeCap, core.ErrTipAboveFeeCap, "tip above fee cap"},
+	{SkipInvalidChainID, types.ErrInvalidChainId, "invalid chain id"},
+	{SkipInvalidSender, core.ErrInvalidSender, "invalid sender"},
+	{SkipInvalidSender, types.ErrInvalidSig, "invalid sender"},
+	{SkipUnderpriced, core.ErrUnderpriced, "underpriced"},
+	{SkipFeeCapTooLow, core.ErrFeeCapTooLow, "fee cap below base fee"},
+	{SkipIntrinsicGas, core.ErrIntrinsicGas, "intrinsic gas too low"},
+	{SkipNonceTooLow, core.ErrNonceTooLow, "nonce too low"},
+	{SkipNonceTooHigh, core.ErrNonceTooHigh, "nonce too high"},
+	{SkipInsufficientFunds, core.ErrInsufficientFunds, "insufficient funds"},
+	{SkipInsufficientFunds, core.ErrInsufficientFundsForTransfer, "insufficient funds"},
+	{SkipBlockGasExhausted, core.ErrGasLimitReached, "block gas exhausted"},
+	{SkipSenderNoEOA, core.ErrSenderNoEOA, "sender not an EOA"},
+	{SkipGasUintOverflow, core.ErrGasUintOverflow, "gas uint64 overflow"},
+	{SkipBlobFeeCapTooLow, ErrBlobFeeCapTooLow, "blob fee cap below blob gas fee"},
+	{SkipBlobGasExhausted, ErrBlobGasLimitReached, "block blob gas exhausted"},
+	{SkipMissingBlobs, ErrMissingBlobHashes, "missing blobs"},
+}
+
+// ClassifyTxError returns the canonical reason of the transaction failure.
+func ClassifyTxError(err error) SkipReason {
+	if err == nil {
+		return SkipNone
+	}
+	for _, r := range skipReasons {
+		if errors.Is(err, r.err) {
+			return r.reason
+		}
+	}
+	return SkipUnknown
+}
+
+func (r SkipReason) String() string {
+	if r == SkipNone {
+		return "none"
+	}
+	for _, known := range skipReasons {
+		if known.reason == r {
+			return known.name
+		}
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(r))
+}
+
+// Retriable returns true if the transaction may become valid later (i.e. with another
+// state or base fee), so the txpool keeps it instead of dropping it.
+func (r SkipReason) Retriable() bool {
+	switch r {
+	case SkipNone, SkipFeeCapTooLow, SkipNonceTooHigh, SkipInsufficientFunds, SkipBlockGasExhausted,
+		SkipBlobFeeCapTooLow, SkipBlobGasExhausted:
+		return true
+	}
+	return false
+}
+
+// ValidateTx checks the transaction against the rules before it's submitted to the txpool.
+// baseFee is the base fee of the last block, nil if unknown.
+//
+// The checks don't depend on the state (see ValidateTxState), and the errors are the same
+// as geth returns, so wallets can tell them apart.
+func ValidateTx(tx *types.Transaction, rules opera.Rules, baseFee *big.Int) error {
+	switch tx.Type() {
+	case types.LegacyTxType:
+	case types.AccessListTxType:
+		if !rules.Upgrades.Berlin {
+			return types.ErrTxTypeNotSupported
+		}
+	case types.DynamicFeeTxType:
+		if !rules.Upgrades.London {
+			return types.ErrTxTypeNotSupported
+		}
+	case BlobTxType:
+		// even after Cancun, the txpool can't keep blob transactions until the transactions
+		// of the geth version carry blobs (see BlockEVM.ChargeBlobGas)
+		return types.ErrTxTypeNotSupported
+	default:
+		return types.ErrTxTypeNotSupported
+	}
+	if tx.Size() > txMaxSize {
+		return core.ErrOversizedData
+	}
+	if tx.Value().Sign() < 0 {
+		return core.ErrNegativeValue
+	}
+	if tx.Gas() > rules.Blocks.MaxBlockGas {
+		return core.ErrGasLimit
+	}
+	if tx.GasFeeCap().BitLen() > 256 {
+		return core.ErrFeeCapVeryHigh
+	}
+	if tx.GasTipCap().BitLen() > 256 {
+		return core.ErrTipVeryHigh
+	}
+	if tx.GasFeeCapIntCmp(tx.GasTipCap()) < 0 {
+		return core.ErrTipAboveFeeCap
+	}
+
+	// unprotected legacy transactions are replayable on any chain, but they're allowed by geth too
+	chainID := new(big.Int).SetUint64(rules.NetworkID)
+	if tx.Protected() && tx.ChainId().Cmp(chainID) != 0 {
+		return fmt.Errorf("%w: have %d want %d", types.ErrInvalidChainId, tx.ChainId(), chainID)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		return core.ErrInvalidSender
+	}
+
+	if tx.GasFeeCapIntCmp(rules.Economy.MinGasPrice) < 0 {
+		return core.ErrUnderpriced
+	}
+	if baseFee != nil && tx.GasFeeCapIntCmp(baseFee) < 0 {
+		return fmt.Errorf("%w: address %v, maxFeePerGas: %s baseFee: %s", core.ErrFeeCapTooLow,
+			from.Hex(), tx.GasFeeCap(), baseFee)
+	}
+
+	intrGas, err := core.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, true, true)
+	if err != nil {
+		return err
+	}
+	if tx.Gas() < intrGas {
+		return fmt.Errorf("%w: have %d, want %d", core.ErrIntrinsicGas, tx.Gas(), intrGas)
+	}
+	return nil
+}
+
+// StateReader is the part of the state the transactions are validated against.
+type StateReader interface {
+	GetNonce(addr common.Address) uint64
+	GetBalance(addr common.Address) *big.Int
+}
+
+// ValidateTxState checks the nonce and the balance of the sender, the same way as the
+// block processor does. Nonces above the state nonce are allowed, as the txpool queues them.
+func ValidateTxState(tx *types.Transaction, from common.Address, state StateReader) error {
+	if nonce := state.GetNonce(from); nonce > tx.Nonce() {
+		return fmt.Errorf("%w: address %v, tx: %d state: %d", core.ErrNonceTooLow, from.Hex(), tx.Nonce(), nonce)
+	}
+	if balance := state.GetBalance(from); balance.Cmp(tx.Cost()) < 0 {
+		return fmt.Errorf("%w: address %v have %v want %v", core.ErrInsufficientFunds, from.Hex(), balance, tx.Cost())
+	}
+	return nil
+}
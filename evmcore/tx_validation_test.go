@@ -0,0 +1,69 @@
+package evmcore
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// TestSkipReasons verifies that the block processor skips the same transactions
+// for the same reasons on every run, and that the txpool classifies them the same way.
+func TestSkipReasons(t *testing.T) {
+	rules := opera.FakeNetRules()
+	statedb, header, txs := syntheticBlock(t, rules, 3)
+	signer := NewEvmConfig(rules, nil).Signer
+
+	sign := func(key int, nonce uint64) *types.Transaction {
+		to := common.HexToAddress("0x01")
+		tx, err := types.SignNewTx(FakeKey(key), signer, &types.LegacyTx{
+			Nonce: nonce, GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(1),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	replayed := txs[0]
+	unfunded := sign(100, 0)
+	txs = append(txs, replayed, unfunded, sign(2, 5))
+
+	apply := func() []SkippedTx {
+		evm := NewBlockEVM(NewEvmConfig(rules, nil), opera.DefaultVMConfig, header, statedb.Copy(), emptyGetHash)
+		_, skipped := evm.ApplyTransactions(txs, new(core.GasPool).AddGas(header.GasLimit))
+		return skipped
+	}
+	skipped := apply()
+	reasons := make([]SkipReason, len(skipped))
+	for i, s := range skipped {
+		reasons[i] = s.Reason
+	}
+	if !reflect.DeepEqual(SkippedIndexes(skipped), []uint32{3, 4, 5}) ||
+		!reflect.DeepEqual(reasons, []SkipReason{SkipNonceTooLow, SkipInsufficientFunds, SkipNonceTooHigh}) {
+		t.Fatalf("unexpected skipped txs %+v", skipped)
+	}
+	if again := apply(); !reflect.DeepEqual(skipped, again) {
+		t.Fatalf("skipped txs differ between runs: %+v and %+v", skipped, again)
+	}
+
+	// the txpool drops the replayed transaction and keeps the unfunded one
+	after := statedb.Copy()
+	after.SetNonce(crypto.PubkeyToAddress(FakeKey(1).PublicKey), 1)
+	if r := ClassifyTxError(ValidateTxState(replayed, crypto.PubkeyToAddress(FakeKey(1).PublicKey), after)); r != SkipNonceTooLow || r.Retriable() {
+		t.Fatalf("replayed tx is classified as %v", r)
+	}
+	if r := ClassifyTxError(ValidateTxState(unfunded, crypto.PubkeyToAddress(FakeKey(100).PublicKey), after)); r != SkipInsufficientFunds || !r.Retriable() {
+		t.Fatalf("unfunded tx is classified as %v", r)
+	}
+
+	if r := ClassifyTxError(errors.New("unexpected")); r != SkipUnknown || r.String() != "unknown(255)" {
+		t.Fatalf("unclassified error is %v", r)
+	}
+}
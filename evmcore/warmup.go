@@ -0,0 +1,214 @@
+// This file implements the warm-up of the state cache after a restart.
+//
+// Overview:
+//   After a restart, the trie node and code caches are cold, so the first blocks read most of
+//   the state from the disk, and a validator may miss its emission windows while it catches up.
+//
+//   HotAccounts is a persistent LRU journal of the accounts and storage slots accessed by the
+//   recent transactions (their senders, recipients and access lists), recorded by BlockEVM.
+//   On startup, WarmUp reads the journaled entries, most recent first, which loads them into the
+//   caches of the state database, and the emitter is held until it's done (see emitter.Maintenance.Hold).
+//   The state accessed by contracts internally isn't journaled, unless it's in the access lists.
+
+package evmcore
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/common/bigendian"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// HotEntry is an account or, if Storage is true, a storage slot of the account.
+type HotEntry struct {
+	Address common.Address
+	Slot    common.Hash
+	Storage bool
+}
+
+// key returns the journal key: the address, or the address followed by the slot.
+func (e HotEntry) key() string {
+	if e.Storage {
+		return string(append(e.Address.Bytes(), e.Slot.Bytes()...))
+	}
+	return string(e.Address.Bytes())
+}
+
+func hotEntryFromKey(key string) (HotEntry, bool) {
+	switch len(key) {
+	case common.AddressLength:
+		return HotEntry{Address: common.BytesToAddress([]byte(key))}, true
+	case common.AddressLength + common.HashLength:
+		return HotEntry{
+			Address: common.BytesToAddress([]byte(key[:common.AddressLength])),
+			Slot:    common.BytesToHash([]byte(key[common.AddressLength:])),
+			Storage: true,
+		}, true
+	default:
+		return HotEntry{}, false
+	}
+}
+
+// HotAccounts is the persistent LRU journal of the recently accessed accounts and storage slots.
+// The accesses are kept in memory and written to the DB by Flush. It's safe for concurrent use.
+type HotAccounts struct {
+	mu      sync.Mutex
+	db      kvdb.Store
+	lru     *lru.Cache // key -> access sequence number
+	seq     uint64
+	dirty   map[string]struct{} // accessed since the last flush
+	evicted map[string]struct{} // evicted since the last flush
+}
+
+// NewHotAccounts loads the journal of up to size entries from the DB (table).
+func NewHotAccounts(db kvdb.Store, size int) (*HotAccounts, error) {
+	h := &HotAccounts{
+		db:      db,
+		dirty:   make(map[string]struct{}),
+		evicted: make(map[string]struct{}),
+	}
+	cache, err := lru.NewWithEvict(size, func(key, _ interface{}) {
+		h.evicted[key.(string)] = struct{}{}
+		delete(h.dirty, key.(string))
+	})
+	if err != nil {
+		return nil, err
+	}
+	h.lru = cache
+
+	type stored struct {
+		key string
+		seq uint64
+	}
+	var entries []stored
+	it := db.NewIterator(nil, nil)
+	for it.Next() {
+		entries = append(entries, stored{string(it.Key()), bigendian.BytesToUint64(it.Value())})
+	}
+	err = it.Error()
+	it.Release()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].seq < entries[j].seq
+	})
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, e := range entries {
+		h.lru.Add(e.key, e.seq)
+		h.seq = e.seq
+	}
+	return h, nil
+}
+
+func (h *HotAccounts) touch(e HotEntry) {
+	key := e.key()
+	h.seq++
+	h.lru.Add(key, h.seq)
+	h.dirty[key] = struct{}{}
+	delete(h.evicted, key)
+}
+
+// RecordMessage journals the accounts and slots accessed by the transaction message.
+func (h *HotAccounts) RecordMessage(msg types.Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.touch(HotEntry{Address: msg.From()})
+	if msg.To() != nil {
+		h.touch(HotEntry{Address: *msg.To()})
+	}
+	for _, tuple := range msg.AccessList() {
+		h.touch(HotEntry{Address: tuple.Address})
+		for _, slot := range tuple.StorageKeys {
+			h.touch(HotEntry{Address: tuple.Address, Slot: slot, Storage: true})
+		}
+	}
+}
+
+// Entries returns the journaled entries, the most recently accessed first.
+func (h *HotAccounts) Entries() []HotEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	keys := h.lru.Keys() // the oldest first
+	entries := make([]HotEntry, 0, len(keys))
+	for i := len(keys) - 1; i >= 0; i-- {
+		if e, ok := hotEntryFromKey(keys[i].(string)); ok {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Flush writes the accesses since the last flush to the DB.
+func (h *HotAccounts) Flush() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	batch := h.db.NewBatch()
+	for key := range h.evicted {
+		if err := batch.Delete([]byte(key)); err != nil {
+			return err
+		}
+	}
+	for key := range h.dirty {
+		seq, ok := h.lru.Peek(key)
+		if !ok {
+			continue
+		}
+		if err := batch.Put([]byte(key), bigendian.Uint64ToBytes(seq.(uint64))); err != nil {
+			return err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	h.dirty = make(map[string]struct{})
+	h.evicted = make(map[string]struct{})
+	return nil
+}
+
+// WarmUpStats is the outcome of WarmUp.
+type WarmUpStats struct {
+	Accounts int
+	Slots    int
+	Duration time.Duration
+}
+
+// WarmUp reads the entries from the state with the root, in the given order, which loads their trie
+// nodes and code into the caches of the state database. It stops once ctx is done, returning
+// the stats of the partial warm-up along with the ctx error, as a partially warmed cache is
+// better than a missed emission.
+func WarmUp(ctx context.Context, db state.Database, root common.Hash, entries []HotEntry) (WarmUpStats, error) {
+	start := time.Now()
+	var stats WarmUpStats
+	statedb, err := state.New(root, db, nil)
+	if err != nil {
+		return stats, err
+	}
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			stats.Duration = time.Since(start)
+			return stats, err
+		}
+		if e.Storage {
+			statedb.GetState(e.Address, e.Slot)
+			stats.Slots++
+		} else {
+			statedb.GetBalance(e.Address)
+			statedb.GetCode(e.Address)
+			stats.Accounts++
+		}
+	}
+	stats.Duration = time.Since(start)
+	return stats, nil
+}
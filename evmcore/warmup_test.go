@@ -0,0 +1,109 @@
+package evmcore
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func hotMessage(from, to common.Address, access types.AccessList) types.Message {
+	return types.NewMessage(from, &to, 0, new(big.Int), 21000, new(big.Int), new(big.Int), new(big.Int), nil, access, false)
+}
+
+func TestHotAccountsJournal(t *testing.T) {
+	db := memorydb.New()
+	hot, err := NewHotAccounts(db, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b, c, d := common.Address{1}, common.Address{2}, common.Address{3}, common.Address{4}
+	slot := common.Hash{0xaa}
+
+	hot.RecordMessage(hotMessage(a, b, nil))
+	hot.RecordMessage(hotMessage(a, c, types.AccessList{{Address: d, StorageKeys: []common.Hash{slot}}}))
+	// a, b are evicted by the limit of 4 entries
+	want := []HotEntry{
+		{Address: d, Slot: slot, Storage: true},
+		{Address: d},
+		{Address: c},
+		{Address: a},
+	}
+	assertHotEntries(t, hot.Entries(), want)
+
+	if err := hot.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := NewHotAccounts(db, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertHotEntries(t, reloaded.Entries(), want)
+
+	// the evicted entries are removed from the DB
+	reloaded.RecordMessage(hotMessage(b, b, nil))
+	if err := reloaded.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	again, err := NewHotAccounts(db, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertHotEntries(t, again.Entries(), []HotEntry{
+		{Address: b},
+		{Address: d, Slot: slot, Storage: true},
+		{Address: d},
+		{Address: c},
+	})
+}
+
+func assertHotEntries(t *testing.T, got, want []HotEntry) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries %v, want %v", len(got), got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("entry %d is %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWarmUp(t *testing.T) {
+	sdb := state.NewDatabase(rawdb.NewMemoryDatabase())
+	statedb, _ := state.New(common.Hash{}, sdb, nil)
+	a := common.Address{1}
+	statedb.SetBalance(a, big.NewInt(1))
+	statedb.SetState(a, common.Hash{1}, common.Hash{2})
+	root, err := statedb.Commit(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sdb.TrieDB().Commit(root, false, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []HotEntry{{Address: a}, {Address: a, Slot: common.Hash{1}, Storage: true}, {Address: common.Address{2}}}
+	stats, err := WarmUp(context.Background(), sdb, root, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Accounts != 2 || stats.Slots != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stats, err = WarmUp(ctx, sdb, root, entries)
+	if err != context.Canceled {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+	if stats.Accounts != 0 || stats.Slots != 0 {
+		t.Fatalf("warmed up after cancellation: %+v", stats)
+	}
+}
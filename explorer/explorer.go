@@ -0,0 +1,263 @@
+// Package explorer implements a lite block explorer for the local development networks:
+// a single web page showing the recent blocks, the events of the validators in the current
+// epoch, the current epoch stats and the transactions looked up by hash.
+//
+// The page is static, it polls the JSON endpoints of the explorer, which read the chain
+// through the same backend as the JSON-RPC APIs (see ethapi.Backend). The explorer keeps
+// no state and no index, so it's meant for fakenet development rather than for the public.
+package explorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/ethapi"
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+)
+
+const (
+	// DefaultBlocks is the number of the recent blocks listed if the request doesn't specify it.
+	DefaultBlocks = 20
+	// MaxBlocks is the max number of the recent blocks listed by a request.
+	MaxBlocks = 100
+)
+
+var (
+	// ErrInvalidHash is returned if the looked up transaction hash isn't a hex hash.
+	ErrInvalidHash = errors.New("invalid transaction hash")
+	// ErrNotFound is returned if the looked up transaction isn't known.
+	ErrNotFound = errors.New("not found")
+)
+
+// Block is the summary of a block.
+type Block struct {
+	Number  hexutil.Uint64 `json:"number"`
+	Hash    common.Hash    `json:"hash"`
+	Time    hexutil.Uint64 `json:"time"`
+	Txs     int            `json:"txs"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+}
+
+// Transaction is a transaction looked up by hash, with the outcome of its execution.
+type Transaction struct {
+	Hash        common.Hash     `json:"hash"`
+	BlockNumber hexutil.Uint64  `json:"blockNumber"`
+	Index       hexutil.Uint64  `json:"index"`
+	From        common.Address  `json:"from"`
+	To          *common.Address `json:"to"`
+	Value       *hexutil.Big    `json:"value"`
+	Gas         hexutil.Uint64  `json:"gas"`
+	GasPrice    *hexutil.Big    `json:"gasPrice"`
+	Status      *hexutil.Uint64 `json:"status"`  // nil if the receipt isn't available
+	GasUsed     *hexutil.Uint64 `json:"gasUsed"` // nil if the receipt isn't available
+}
+
+// Validator is the activity of a validator in the current epoch.
+type Validator struct {
+	ID        hexutil.Uint   `json:"id"`
+	Weight    hexutil.Uint64 `json:"weight"`
+	Events    hexutil.Uint64 `json:"events"`
+	LastEvent hexutil.Uint64 `json:"lastEvent"` // time of the last confirmed event
+	LastBlock hexutil.Uint64 `json:"lastBlock"`
+	Uptime    hexutil.Uint64 `json:"uptime"`
+}
+
+// Epoch is the stats of the current epoch.
+type Epoch struct {
+	Epoch      hexutil.Uint64 `json:"epoch"`
+	Start      hexutil.Uint64 `json:"start"`
+	LastBlock  hexutil.Uint64 `json:"lastBlock"`
+	Gas        hexutil.Uint64 `json:"gas"`
+	Cheaters   int            `json:"cheaters"`
+	Validators []Validator    `json:"validators"`
+}
+
+// Explorer serves the explorer page and its JSON endpoints.
+type Explorer struct {
+	b ethapi.Backend
+}
+
+// New creates the explorer over the backend of the RPC APIs.
+func New(b ethapi.Backend) *Explorer {
+	return &Explorer{b: b}
+}
+
+// Blocks returns the summaries of the last n blocks, the latest first.
+func (e *Explorer) Blocks(ctx context.Context, n int) ([]Block, error) {
+	latest, err := e.b.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil || latest == nil {
+		return nil, err
+	}
+	res := make([]Block, 0, n)
+	for number := latest.Number.Int64(); number >= 0 && len(res) < n; number-- {
+		block, err := e.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			break
+		}
+		res = append(res, Block{
+			Number:  hexutil.Uint64(block.Number.Uint64()),
+			Hash:    block.Hash,
+			Time:    hexutil.Uint64(block.Time),
+			Txs:     len(block.Transactions),
+			GasUsed: hexutil.Uint64(block.GasUsed),
+		})
+	}
+	return res, nil
+}
+
+// Transaction looks up the transaction by hash.
+func (e *Explorer) Transaction(ctx context.Context, hash common.Hash) (*Transaction, error) {
+	tx, blockNumber, index, err := e.b.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, ErrNotFound
+	}
+	res := &Transaction{
+		Hash:        hash,
+		BlockNumber: hexutil.Uint64(blockNumber),
+		Index:       hexutil.Uint64(index),
+		To:          tx.To(),
+		Value:       (*hexutil.Big)(tx.Value()),
+		Gas:         hexutil.Uint64(tx.Gas()),
+		GasPrice:    (*hexutil.Big)(tx.GasPrice()),
+	}
+	if from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+		res.From = from
+	}
+	// the receipts of the pruned blocks aren't available
+	receipts, err := e.b.GetReceipts(ctx, idx.Block(blockNumber))
+	if err == nil && index < uint64(len(receipts)) {
+		status := hexutil.Uint64(receipts[index].Status)
+		gasUsed := hexutil.Uint64(receipts[index].GasUsed)
+		res.Status = &status
+		res.GasUsed = &gasUsed
+	}
+	return res, nil
+}
+
+// Epoch returns the stats of the current epoch.
+func (e *Explorer) Epoch(ctx context.Context) (*Epoch, error) {
+	bs, es, err := e.b.GetEpochBlockState(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if bs == nil || es == nil {
+		return nil, ErrNotFound
+	}
+	counters, err := e.b.GetEpochCounters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if counters == nil {
+		counters = &iblockproc.EpochCounters{}
+	}
+	res := &Epoch{
+		Epoch:     hexutil.Uint64(es.Epoch),
+		Start:     hexutil.Uint64(es.EpochStart),
+		LastBlock: hexutil.Uint64(bs.LastBlock.Idx),
+		Gas:       hexutil.Uint64(bs.EpochGas),
+		Cheaters:  len(bs.EpochCheaters),
+	}
+	for i, id := range es.Validators.SortedIDs() {
+		v := Validator{
+			ID:     hexutil.Uint(id),
+			Weight: hexutil.Uint64(es.Validators.Get(id)),
+			Events: hexutil.Uint64(counters.EventsOf(id, es)),
+		}
+		if i < len(bs.ValidatorStates) {
+			vs := bs.ValidatorStates[i]
+			v.LastEvent = hexutil.Uint64(vs.LastEvent.Time)
+			v.LastBlock = hexutil.Uint64(vs.LastBlock)
+			v.Uptime = hexutil.Uint64(vs.Uptime)
+		}
+		res.Validators = append(res.Validators, v)
+	}
+	return res, nil
+}
+
+// Handler returns the handler of the explorer page at "/" and of its JSON endpoints at
+// "/api/blocks?count=N", "/api/tx?hash=0x..." and "/api/epoch". The page refers to the
+// endpoints relatively, so the handler may be mounted under any prefix with http.StripPrefix.
+func (e *Explorer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", e.servePage)
+	mux.HandleFunc("/api/blocks", e.serveBlocks)
+	mux.HandleFunc("/api/tx", e.serveTransaction)
+	mux.HandleFunc("/api/epoch", e.serveEpoch)
+	return mux
+}
+
+func (e *Explorer) servePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(page))
+}
+
+func (e *Explorer) serveBlocks(w http.ResponseWriter, r *http.Request) {
+	n := DefaultBlocks
+	if s := r.FormValue("count"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			reply(w, http.StatusBadRequest, nil, errors.New("invalid blocks count"))
+			return
+		}
+		n = v
+	}
+	if n > MaxBlocks {
+		n = MaxBlocks
+	}
+	blocks, err := e.Blocks(r.Context(), n)
+	reply(w, http.StatusOK, blocks, err)
+}
+
+func (e *Explorer) serveTransaction(w http.ResponseWriter, r *http.Request) {
+	raw, err := hexutil.Decode(r.FormValue("hash"))
+	if err != nil || len(raw) != common.HashLength {
+		reply(w, http.StatusBadRequest, nil, ErrInvalidHash)
+		return
+	}
+	tx, err := e.Transaction(r.Context(), common.BytesToHash(raw))
+	reply(w, http.StatusOK, tx, err)
+}
+
+func (e *Explorer) serveEpoch(w http.ResponseWriter, r *http.Request) {
+	epoch, err := e.Epoch(r.Context())
+	reply(w, http.StatusOK, epoch, err)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// reply writes the result, or the error with the matching status.
+func reply(w http.ResponseWriter, status int, res interface{}, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case err == ErrNotFound:
+		status = http.StatusNotFound
+	case err != nil && status == http.StatusOK:
+		status = http.StatusInternalServerError
+	}
+	w.WriteHeader(status)
+	if err != nil {
+		res = errorResponse{Error: err.Error()}
+	}
+	_ = json.NewEncoder(w).Encode(res)
+}
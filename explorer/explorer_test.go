@@ -0,0 +1,169 @@
+package explorer
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/ethapi"
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+)
+
+// testBackend serves a chain of blocks with a transaction in every block.
+type testBackend struct {
+	ethapi.Backend
+	blocks []*evmcore.EvmBlock
+}
+
+func newTestBackend(n int) *testBackend {
+	b := &testBackend{}
+	key := evmcore.FakeKey(1)
+	signer := types.LatestSignerForChainID(big.NewInt(4003))
+	for i := 0; i < n; i++ {
+		tx, err := types.SignNewTx(key, signer, &types.LegacyTx{Nonce: uint64(i), GasPrice: big.NewInt(1), Gas: 21000, To: &common.Address{1}})
+		if err != nil {
+			panic(err)
+		}
+		b.blocks = append(b.blocks, &evmcore.EvmBlock{
+			EvmHeader:    evmcore.EvmHeader{Number: big.NewInt(int64(i)), Hash: common.Hash{byte(i + 1)}, GasUsed: 21000},
+			Transactions: types.Transactions{tx},
+		})
+	}
+	return b
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmHeader, error) {
+	block, err := b.BlockByNumber(ctx, number)
+	if block == nil {
+		return nil, err
+	}
+	return &block.EvmHeader, nil
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmBlock, error) {
+	if number == rpc.LatestBlockNumber {
+		number = rpc.BlockNumber(len(b.blocks) - 1)
+	}
+	if number < 0 || int(number) >= len(b.blocks) {
+		return nil, nil
+	}
+	return b.blocks[number], nil
+}
+
+func (b *testBackend) GetTransaction(ctx context.Context, hash common.Hash) (*types.Transaction, uint64, uint64, error) {
+	for _, block := range b.blocks {
+		for i, tx := range block.Transactions {
+			if tx.Hash() == hash {
+				return tx, block.Number.Uint64(), uint64(i), nil
+			}
+		}
+	}
+	return nil, 0, 0, nil
+}
+
+func (b *testBackend) GetReceipts(ctx context.Context, number idx.Block) (types.Receipts, error) {
+	return types.Receipts{{Status: types.ReceiptStatusSuccessful, GasUsed: 21000}}, nil
+}
+
+func (b *testBackend) GetEpochBlockState(ctx context.Context, epoch rpc.BlockNumber) (*iblockproc.BlockState, *iblockproc.EpochState, error) {
+	vb := pos.NewBuilder()
+	vb.Set(1, 100)
+	vb.Set(2, 200)
+	bs := &iblockproc.BlockState{
+		LastBlock:       iblockproc.BlockCtx{Idx: idx.Block(len(b.blocks) - 1)},
+		EpochGas:        21000 * uint64(len(b.blocks)),
+		ValidatorStates: []iblockproc.ValidatorBlockState{{}, {}},
+	}
+	return bs, &iblockproc.EpochState{Epoch: 7, Validators: vb.Build()}, nil
+}
+
+func (b *testBackend) GetEpochCounters(ctx context.Context) (*iblockproc.EpochCounters, error) {
+	return &iblockproc.EpochCounters{Epoch: 7, Events: []uint32{3, 5}}, nil
+}
+
+func get(t *testing.T, url string, res interface{}) int {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != nil && resp.StatusCode == http.StatusOK {
+		if err := json.Unmarshal(body, res); err != nil {
+			t.Fatalf("%s: %v", body, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestExplorer(t *testing.T) {
+	b := newTestBackend(30)
+	mux := http.NewServeMux()
+	mux.Handle("/explorer/", http.StripPrefix("/explorer", New(b).Handler()))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/explorer/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(page), `get("api/blocks")`) {
+		t.Fatalf("page isn't served: %d", resp.StatusCode)
+	}
+
+	var blocks []Block
+	if status := get(t, srv.URL+"/explorer/api/blocks", &blocks); status != http.StatusOK {
+		t.Fatalf("blocks status %d", status)
+	}
+	if len(blocks) != DefaultBlocks || blocks[0].Number != 29 || blocks[0].Txs != 1 {
+		t.Fatalf("unexpected blocks %+v", blocks)
+	}
+	if get(t, srv.URL+"/explorer/api/blocks?count=1000", &blocks); len(blocks) != 30 {
+		t.Fatalf("not all the blocks are listed: %d", len(blocks))
+	}
+	if status := get(t, srv.URL+"/explorer/api/blocks?count=x", nil); status != http.StatusBadRequest {
+		t.Fatalf("invalid count status %d", status)
+	}
+
+	var tx Transaction
+	want := b.blocks[5].Transactions[0]
+	if status := get(t, srv.URL+"/explorer/api/tx?hash="+want.Hash().Hex(), &tx); status != http.StatusOK {
+		t.Fatalf("tx status %d", status)
+	}
+	from, _ := types.Sender(types.LatestSignerForChainID(want.ChainId()), want)
+	if tx.BlockNumber != 5 || tx.From != from || tx.Status == nil || *tx.Status != 1 {
+		t.Fatalf("unexpected tx %+v", tx)
+	}
+	if status := get(t, srv.URL+"/explorer/api/tx?hash="+(common.Hash{}).Hex(), nil); status != http.StatusNotFound {
+		t.Fatalf("unknown tx status %d", status)
+	}
+	if status := get(t, srv.URL+"/explorer/api/tx?hash=0x12", nil); status != http.StatusBadRequest {
+		t.Fatalf("invalid hash status %d", status)
+	}
+
+	var epoch Epoch
+	if status := get(t, srv.URL+"/explorer/api/epoch", &epoch); status != http.StatusOK {
+		t.Fatalf("epoch status %d", status)
+	}
+	if epoch.Epoch != 7 || len(epoch.Validators) != 2 || epoch.Validators[0].ID != 2 || epoch.Validators[0].Events != 3 {
+		t.Fatalf("unexpected epoch %+v", epoch)
+	}
+}
@@ -0,0 +1,103 @@
+package explorer
+
+// page is the explorer web page. It refreshes the blocks and the epoch stats every few seconds
+// and looks up the transactions on request. It has no external dependencies, so it works on
+// an offline development machine.
+const page = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Opera explorer lite</title>
+<style>
+body { font-family: sans-serif; margin: 1em 2em; color: #222; }
+h2 { margin-top: 1.5em; font-size: 1.1em; }
+table { border-collapse: collapse; font-size: 0.9em; }
+th, td { padding: 0.2em 0.8em; border-bottom: 1px solid #ddd; text-align: left; }
+td.hash { font-family: monospace; }
+#error { color: #b00; }
+input { font-family: monospace; width: 45em; }
+pre { background: #f4f4f4; padding: 0.5em; }
+</style>
+</head>
+<body>
+<h1>Opera explorer lite</h1>
+<div id="error"></div>
+
+<h2>Transaction</h2>
+<form id="search">
+<input id="hash" placeholder="0x... transaction hash"> <button>Search</button>
+</form>
+<pre id="tx" hidden></pre>
+
+<h2>Epoch</h2>
+<div id="epoch"></div>
+<table>
+<thead><tr><th>Validator</th><th>Weight</th><th>Events</th><th>Last event</th><th>Last block</th><th>Uptime, s</th></tr></thead>
+<tbody id="validators"></tbody>
+</table>
+
+<h2>Recent blocks</h2>
+<table>
+<thead><tr><th>Number</th><th>Hash</th><th>Time</th><th>Txs</th><th>Gas used</th></tr></thead>
+<tbody id="blocks"></tbody>
+</table>
+
+<script>
+"use strict";
+const num = (hex) => parseInt(hex, 16);
+const time = (hex) => new Date(num(hex) / 1e6).toLocaleString();
+
+function row(cells, hashCol) {
+	const tr = document.createElement("tr");
+	cells.forEach((c, i) => {
+		const td = document.createElement("td");
+		if (i === hashCol) td.className = "hash";
+		td.textContent = c;
+		tr.appendChild(td);
+	});
+	return tr;
+}
+
+async function get(path) {
+	const resp = await fetch(path);
+	const body = await resp.json();
+	if (!resp.ok) throw new Error(body.error || resp.statusText);
+	return body;
+}
+
+async function refresh() {
+	try {
+		const blocks = await get("api/blocks");
+		document.getElementById("blocks").replaceChildren(...(blocks || []).map((b) =>
+			row([num(b.number), b.hash, time(b.time), b.txs, num(b.gasUsed)], 1)));
+		const epoch = await get("api/epoch");
+		document.getElementById("epoch").textContent = "Epoch " + num(epoch.epoch) +
+			", started " + time(epoch.start) + ", last block " + num(epoch.lastBlock) +
+			", gas used " + num(epoch.gas) + ", cheaters " + epoch.cheaters;
+		document.getElementById("validators").replaceChildren(...(epoch.validators || []).map((v) =>
+			row([num(v.id), num(v.weight), num(v.events), num(v.lastEvent) ? time(v.lastEvent) : "-",
+				num(v.lastBlock), Math.round(num(v.uptime) / 1e9)])));
+		document.getElementById("error").textContent = "";
+	} catch (e) {
+		document.getElementById("error").textContent = "Refresh failed: " + e.message;
+	}
+}
+
+document.getElementById("search").addEventListener("submit", async (ev) => {
+	ev.preventDefault();
+	const out = document.getElementById("tx");
+	out.hidden = false;
+	try {
+		const hash = document.getElementById("hash").value.trim();
+		out.textContent = JSON.stringify(await get("api/tx?hash=" + encodeURIComponent(hash)), null, 2);
+	} catch (e) {
+		out.textContent = e.message;
+	}
+});
+
+refresh();
+setInterval(refresh, 3000);
+</script>
+</body>
+</html>
+`
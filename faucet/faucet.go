@@ -0,0 +1,166 @@
+// Package faucet implements a faucet for the fake and test networks: an HTTP endpoint
+// which sends a fixed amount from a funded dev account to the requested address.
+//
+// Requests are rate limited per client IP. The transactions go through the same
+// validation and txpool submission as eth_sendRawTransaction, and the faucet keeps
+// its own nonce, so a burst of requests doesn't produce conflicting transactions.
+package faucet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/rony4d/go-opera-asset/ethapi"
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+var (
+	// ErrRateLimited is returned if the client requested funds too recently.
+	ErrRateLimited = errors.New("too many requests, try again later")
+	// ErrInvalidAddress is returned if the recipient isn't a hex address.
+	ErrInvalidAddress = errors.New("invalid address")
+)
+
+// maxTrackedClients is the number of client IPs remembered for the rate limiting.
+const maxTrackedClients = 65536
+
+// transferGas is the gas of a plain value transfer.
+const transferGas = 21000
+
+// Config is the config of the faucet.
+type Config struct {
+	Amount   *big.Int      // wei sent per request
+	Interval time.Duration // minimum interval between the requests of a client IP
+	GasPrice *big.Int      // gas price of the transactions, raised to the base fee if it's lower
+}
+
+// Faucet sends funds from the dev account. It's safe for concurrent use.
+type Faucet struct {
+	cfg    Config
+	b      ethapi.Backend
+	key    *ecdsa.PrivateKey
+	from   common.Address
+	signer types.Signer
+	clock  clock.Clock
+
+	mu       sync.Mutex
+	nonce    uint64
+	nonceSet bool
+	served   *lru.Cache // client IP -> time.Time of the last funded request
+}
+
+// New creates the faucet which sends funds from the account of the key.
+func New(cfg Config, b ethapi.Backend, key *ecdsa.PrivateKey, chainID *big.Int, c clock.Clock) *Faucet {
+	served, _ := lru.New(maxTrackedClients)
+	return &Faucet{
+		cfg:    cfg,
+		b:      b,
+		key:    key,
+		from:   crypto.PubkeyToAddress(key.PublicKey),
+		signer: types.LatestSignerForChainID(chainID),
+		clock:  c,
+		served: served,
+	}
+}
+
+// Address returns the dev account the funds are sent from.
+func (f *Faucet) Address() common.Address {
+	return f.from
+}
+
+// Fund sends the configured amount to the address on request of the client IP.
+func (f *Faucet) Fund(ctx context.Context, client string, to common.Address) (common.Hash, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.clock.Now()
+	if last, ok := f.served.Get(client); ok && now.Sub(last.(time.Time)) < f.cfg.Interval {
+		return common.Hash{}, ErrRateLimited
+	}
+
+	statedb, header, err := f.b.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if nonce := statedb.GetNonce(f.from); !f.nonceSet || nonce > f.nonce {
+		f.nonce = nonce
+		f.nonceSet = true
+	}
+	gasPrice := f.cfg.GasPrice
+	if header.BaseFee != nil && header.BaseFee.Cmp(gasPrice) > 0 {
+		gasPrice = header.BaseFee
+	}
+
+	tx, err := types.SignNewTx(f.key, f.signer, &types.LegacyTx{
+		Nonce:    f.nonce,
+		GasPrice: gasPrice,
+		Gas:      transferGas,
+		To:       &to,
+		Value:    f.cfg.Amount,
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	txHash, err := ethapi.SubmitTransaction(ctx, f.b, tx)
+	if err != nil {
+		// the nonce is re-read from the state on the next request
+		f.nonceSet = false
+		return common.Hash{}, err
+	}
+	f.nonce++
+	f.served.Add(client, now)
+	log.Info("Faucet sent funds", "to", to, "amount", f.cfg.Amount, "client", client, "tx", txHash)
+	return txHash, nil
+}
+
+type fundResponse struct {
+	TxHash common.Hash `json:"txHash,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// ServeHTTP funds the address of the "address" query or form parameter.
+// The client is identified by the remote IP of the connection, proxy headers aren't trusted.
+func (f *Faucet) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	reply := func(status int, res fundResponse) {
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(res)
+	}
+	if r.Method != http.MethodPost {
+		reply(http.StatusMethodNotAllowed, fundResponse{Error: "use POST"})
+		return
+	}
+	addr := r.FormValue("address")
+	if !common.IsHexAddress(addr) {
+		reply(http.StatusBadRequest, fundResponse{Error: ErrInvalidAddress.Error()})
+		return
+	}
+	client, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		client = r.RemoteAddr
+	}
+
+	txHash, err := f.Fund(r.Context(), client, common.HexToAddress(addr))
+	switch {
+	case err == ErrRateLimited:
+		reply(http.StatusTooManyRequests, fundResponse{Error: err.Error()})
+	case err != nil:
+		reply(http.StatusInternalServerError, fundResponse{Error: err.Error()})
+	default:
+		reply(http.StatusOK, fundResponse{TxHash: txHash})
+	}
+}
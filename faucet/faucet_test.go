@@ -0,0 +1,107 @@
+package faucet
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/ethapi"
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+	"github.com/rony4d/go-opera-asset/opera"
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+// testBackend serves the latest state and collects the submitted transactions.
+type testBackend struct {
+	ethapi.Backend
+	rules   opera.Rules
+	statedb *state.StateDB
+	sent    []*types.Transaction
+}
+
+func (b *testBackend) StateAndHeaderByNumberOrHash(ctx context.Context, _ rpc.BlockNumberOrHash) (*state.StateDB, *evmcore.EvmHeader, error) {
+	return b.statedb.Copy(), &evmcore.EvmHeader{Number: big.NewInt(1)}, nil
+}
+
+func (b *testBackend) GetEpochBlockState(ctx context.Context, _ rpc.BlockNumber) (*iblockproc.BlockState, *iblockproc.EpochState, error) {
+	return &iblockproc.BlockState{}, &iblockproc.EpochState{Rules: b.rules}, nil
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, _ rpc.BlockNumber) (*evmcore.EvmBlock, error) {
+	return nil, nil
+}
+
+func (b *testBackend) SendTx(ctx context.Context, tx *types.Transaction) error {
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+func TestFaucet(t *testing.T) {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := opera.FakeNetRules()
+	b := &testBackend{rules: rules, statedb: statedb}
+	c := clock.NewManual(time.Unix(1600000000, 0))
+	f := New(Config{
+		Amount:   big.NewInt(1e18),
+		Interval: time.Minute,
+		GasPrice: rules.Economy.MinGasPrice,
+	}, b, evmcore.FakeKey(1), new(big.Int).SetUint64(rules.NetworkID), c)
+	statedb.SetNonce(f.Address(), 7)
+
+	request := func(addr, remote string) (int, fundResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(url.Values{"address": {addr}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.RemoteAddr = remote
+		rec := httptest.NewRecorder()
+		f.ServeHTTP(rec, req)
+		var res fundResponse
+		if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Code, res
+	}
+
+	to := common.HexToAddress("0x0102").Hex()
+	if code, res := request(to, "10.0.0.1:1000"); code != http.StatusOK || res.TxHash != b.sent[0].Hash() {
+		t.Fatalf("unexpected response %d %+v", code, res)
+	}
+	if code, _ := request(to, "10.0.0.1:2000"); code != http.StatusTooManyRequests {
+		t.Fatalf("the same IP isn't rate limited, got %d", code)
+	}
+	if code, _ := request(to, "10.0.0.2:1000"); code != http.StatusOK {
+		t.Fatalf("another IP is rate limited, got %d", code)
+	}
+	if code, _ := request("0xnot-an-address", "10.0.0.3:1000"); code != http.StatusBadRequest {
+		t.Fatalf("invalid address is accepted, got %d", code)
+	}
+	c.Advance(time.Minute)
+	if code, _ := request(to, "10.0.0.1:1000"); code != http.StatusOK {
+		t.Fatalf("the IP is still rate limited after the interval, got %d", code)
+	}
+
+	// the pending transactions don't reuse the nonce
+	if len(b.sent) != 3 {
+		t.Fatalf("expected 3 transactions, got %d", len(b.sent))
+	}
+	for i, tx := range b.sent {
+		if tx.Nonce() != uint64(7+i) || tx.Value().Cmp(big.NewInt(1e18)) != 0 || *tx.To() != common.HexToAddress(to) {
+			t.Fatalf("unexpected transaction %d: nonce %d, value %s", i, tx.Nonce(), tx.Value())
+		}
+	}
+}
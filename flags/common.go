@@ -10,6 +10,10 @@ import (
 
 func CommonFlags() []cli.Flag {
 	return []cli.Flag{
+		cli.StringFlag{
+			Name:  "config",
+			Usage: "TOML configuration file",
+		},
 		cli.StringFlag{
 			Name:  "datadir",
 			Usage: "Data directory for the Opera Asset Chain Node",
@@ -90,11 +94,38 @@ func CommonFlags() []cli.Flag {
 			Usage: "Metrics server listening port",
 			Value: 6060,
 		},
+		cli.BoolFlag{
+			Name:  "explorer",
+			Usage: "Serve the explorer lite web UI under /explorer/ of the metrics server address",
+		},
 		cli.DurationFlag{
 			Name:  "rpc.timeout",
 			Usage: "Global JSON-RPC request timeout",
 			Value: 30 * time.Second,
 		},
+		cli.Uint64Flag{
+			Name:  "rpc.logs.blockrange",
+			Usage: "Max number of blocks an eth_getLogs query may span (0 = unlimited)",
+			Value: 10000,
+		},
+		cli.IntFlag{
+			Name:  "rpc.logs.maxresults",
+			Usage: "Max number of logs an eth_getLogs query may return (0 = unlimited)",
+			Value: 10000,
+		},
+		cli.DurationFlag{
+			Name:  "rpc.logs.timeout",
+			Usage: "Max duration of an eth_getLogs query (0 = unlimited)",
+			Value: 10 * time.Second,
+		},
+		cli.StringFlag{
+			Name:  "network",
+			Usage: "Network to join (mainnet|testnet|fakenet), its data is stored in <datadir>/<network>",
+		},
+		cli.StringFlag{
+			Name:  "rules.template",
+			Usage: "Rules template of an app-chain genesis (high-throughput|low-latency|storage-cheap)",
+		},
 		cli.StringFlag{
 			Name:  "genesis",
 			Usage: "Path to the genesis file",
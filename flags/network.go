@@ -18,6 +18,37 @@ func NetworkFlags() []cli.Flag {
 			Usage: "Maximum number of peer connections",
 			Value: 50,
 		},
+		cli.StringFlag{
+			Name:  "p2p.addr",
+			Usage: "P2P listening address (\"::\" listens on both IPv4 and IPv6 on dual-stack hosts)",
+		},
+		cli.StringFlag{
+			Name:  "p2p.addr6",
+			Usage: "Additional IPv6 P2P listening address, bound separately from the IPv4 --p2p.addr",
+		},
+		cli.StringFlag{
+			Name:  "p2p.extip",
+			Usage: "External IPv4 address advertised to the peers",
+		},
+		cli.StringFlag{
+			Name:  "p2p.extip6",
+			Usage: "External IPv6 address advertised to the peers",
+		},
+		cli.IntFlag{
+			Name:  "p2p.serve.inflight",
+			Usage: "Maximum number of heavy requests (events, epoch packs) served to all the peers at once (0 = unlimited)",
+			Value: 32,
+		},
+		cli.IntFlag{
+			Name:  "p2p.serve.peerinflight",
+			Usage: "Maximum number of heavy requests served to a single peer at once (0 = unlimited)",
+			Value: 2,
+		},
+		cli.Float64Flag{
+			Name:  "p2p.serve.rate",
+			Usage: "Megabytes per second served to a single peer (0 = unlimited)",
+			Value: 4,
+		},
 		cli.StringFlag{
 			Name:  "nat",
 			Usage: "NAT mechanism (any|none|extip:<ip>|upnp|pmp|pmp:<addr>)",
@@ -26,6 +57,18 @@ func NetworkFlags() []cli.Flag {
 			Name:  "bootnodes",
 			Usage: "Comma-separated enode URLs for bootstrap peers",
 		},
+		cli.StringFlag{
+			Name:  "registry.url",
+			Usage: "URL of the signed network registry with the bootnodes, rules hash and snapshot to use",
+		},
+		cli.StringFlag{
+			Name:  "registry.key",
+			Usage: "Hex ed25519 public key the network registry must be signed with",
+		},
+		cli.DurationFlag{
+			Name:  "registry.interval",
+			Usage: "Interval between the refreshes of the network registry",
+		},
 		cli.StringSliceFlag{
 			Name:  "staticnodes",
 			Usage: "List of enode URLs to maintain persistent connections with",
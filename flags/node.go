@@ -22,6 +22,15 @@ func NodeFlags() []cli.Flag {
 			Usage: "Megabytes of memory allocated to internal caching",
 			Value: 1024,
 		},
+		cli.IntFlag{
+			Name:  "cache.warmup",
+			Usage: "Number of recently accessed accounts and storage slots to preload into the state cache on startup (0 disables)",
+			Value: 10000,
+		},
+		cli.StringFlag{
+			Name:  "preset",
+			Usage: "Storage/cache preset (default|lite|full|archive)",
+		},
 		cli.BoolFlag{
 			Name:  "nousb",
 			Usage: "Disable monitoring for new USB hardware wallets",
@@ -34,6 +43,128 @@ func NodeFlags() []cli.Flag {
 			Name:  "keystore",
 			Usage: "Directory for storing encrypted account keys",
 		},
+		cli.StringFlag{
+			Name:  "bootstrap-url",
+			Usage: "HTTPS URL of a snapshot archive to bootstrap a fresh node from",
+		},
+		cli.StringFlag{
+			Name:  "bootstrap-hash",
+			Usage: "Expected SHA-256 of the --bootstrap-url archive (hex)",
+		},
+		cli.StringFlag{
+			Name:  "indexer.dir",
+			Usage: "Directory of the indexer databases (defaults to <datadir>/<network>/indexer)",
+		},
+		cli.StringFlag{
+			Name:  "indexer.http.addr",
+			Usage: "HTTP-RPC server listening interface of the indexer process",
+		},
+		cli.IntFlag{
+			Name:  "indexer.http.port",
+			Usage: "HTTP-RPC server listening port of the indexer process",
+		},
+		cli.IntFlag{
+			Name:  "background.sync.concurrency",
+			Usage: "Max parallel workers of the historical sync (0 = unlimited, defaults to the preset)",
+		},
+		cli.Float64Flag{
+			Name:  "background.sync.rate",
+			Usage: "Max blocks per second of the historical sync (0 = unlimited, defaults to the preset)",
+		},
+		cli.IntFlag{
+			Name:  "background.index.concurrency",
+			Usage: "Max parallel workers of the index building (0 = unlimited, defaults to the preset)",
+		},
+		cli.Float64Flag{
+			Name:  "background.index.rate",
+			Usage: "Max blocks per second of the index building (0 = unlimited, defaults to the preset)",
+		},
+		cli.IntFlag{
+			Name:  "background.compaction.concurrency",
+			Usage: "Max parallel workers of the DB compaction (0 = unlimited, defaults to the preset)",
+		},
+		cli.Float64Flag{
+			Name:  "background.compaction.rate",
+			Usage: "Max MB per second of the DB compaction (0 = unlimited, defaults to the preset)",
+		},
+		cli.BoolFlag{
+			Name:  "blockproc.serial",
+			Usage: "Process the stages of every block before starting the next block (safety switch of the pipelined processing)",
+		},
+		cli.IntFlag{
+			Name:  "blockproc.queue",
+			Usage: "Number of blocks queued in front of every stage of the pipelined block processing",
+			Value: 4,
+		},
+		cli.StringFlag{
+			Name:  "validator.password",
+			Usage: "File with the password of the validator keys (the first line is used)",
+		},
+		cli.BoolFlag{
+			Name:  "validator.shadow",
+			Usage: "Compute the events the validator would emit and serve them via RPC, without signing or broadcasting them",
+		},
+		cli.BoolFlag{
+			Name:  "grpc",
+			Usage: "Enable the gRPC endpoint mirroring the key JSON-RPC methods",
+		},
+		cli.StringFlag{
+			Name:  "grpc.addr",
+			Usage: "gRPC server listening interface",
+		},
+		cli.IntFlag{
+			Name:  "grpc.port",
+			Usage: "gRPC server listening port",
+		},
+		cli.BoolFlag{
+			Name:  "faucet",
+			Usage: "Enable the faucet HTTP endpoint (fakenet and testnet only)",
+		},
+		cli.StringFlag{
+			Name:  "faucet.http.addr",
+			Usage: "Faucet HTTP server listening interface",
+		},
+		cli.IntFlag{
+			Name:  "faucet.http.port",
+			Usage: "Faucet HTTP server listening port",
+		},
+		cli.Float64Flag{
+			Name:  "faucet.amount",
+			Usage: "FTM sent by the faucet per request",
+		},
+		cli.DurationFlag{
+			Name:  "faucet.interval",
+			Usage: "Minimum interval between the faucet requests of a client IP",
+		},
+		cli.StringFlag{
+			Name:  "faucet.key",
+			Usage: "File with the hex private key of the faucet account (defaults to the first fake validator on fakenet)",
+		},
+		cli.Uint64Flag{
+			Name:  "watchdog.rss",
+			Usage: "Restart the node at the next epoch once its resident memory exceeds this many MB (0 = disabled)",
+		},
+		cli.DurationFlag{
+			Name:  "watchdog.interval",
+			Usage: "Interval between the memory checks of the watchdog",
+		},
+		cli.DurationFlag{
+			Name:  "watchdog.minuptime",
+			Usage: "Minimum uptime before the watchdog may restart the node",
+		},
+		cli.StringFlag{
+			Name:  "telemetry",
+			Usage: "Anonymous telemetry of the version, network, OS/arch and sync status (on|off), see `opera telemetry show`",
+			Value: "off",
+		},
+		cli.StringFlag{
+			Name:  "telemetry.endpoint",
+			Usage: "URL the telemetry reports are posted to",
+		},
+		cli.DurationFlag{
+			Name:  "telemetry.interval",
+			Usage: "Interval between the telemetry reports",
+		},
 		cli.StringFlag{
 			Name:  "datadir.chaindata",
 			Usage: "Override path to the chaindata DB (defaults to <datadir>/chaindata)",
@@ -42,5 +173,9 @@ func NodeFlags() []cli.Flag {
 			Name:  "datadir.errlock",
 			Usage: "Override path to the errlock file (defaults to <datadir>)",
 		},
+		cli.BoolFlag{
+			Name:  "readonly",
+			Usage: "Open the datadir read-only for inspection: no validator mode, txpool journal or database writes",
+		},
 	}
 }
@@ -0,0 +1,57 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+	"github.com/rony4d/go-opera-asset/inter/keys"
+)
+
+// block_fees.go persists the fees originated by the validators in every block.
+//
+// Overview:
+//   The record is written by the block processor along with the block (see BlockState.AttributeFees),
+//   keyed by keys.Block. Blocks without executed transactions have no record, which reads as no fees.
+
+// BlockFeesStore is the storage of the per-block fee attribution.
+type BlockFeesStore struct {
+	db kvdb.Store
+}
+
+// NewBlockFeesStore wraps the DB table.
+func NewBlockFeesStore(db kvdb.Store) *BlockFeesStore {
+	return &BlockFeesStore{db}
+}
+
+// Set stores the fees originated in the block.
+func (s *BlockFeesStore) Set(n idx.Block, fees iblockproc.BlockFees) {
+	if len(fees.Validators) == 0 {
+		return
+	}
+	b, err := rlp.EncodeToBytes(&fees)
+	if err != nil {
+		log.Crit("Failed to encode block fees", "err", err)
+	}
+	if err := s.db.Put(keys.Block(n), b); err != nil {
+		log.Crit("Failed to put block fees", "err", err)
+	}
+}
+
+// Get returns the fees originated in the block.
+func (s *BlockFeesStore) Get(n idx.Block) iblockproc.BlockFees {
+	b, err := s.db.Get(keys.Block(n))
+	if err != nil {
+		log.Crit("Failed to get block fees", "err", err)
+	}
+	var fees iblockproc.BlockFees
+	if b == nil {
+		return fees
+	}
+	if err := rlp.DecodeBytes(b, &fees); err != nil {
+		log.Crit("Failed to decode block fees", "block", n, "err", err)
+	}
+	return fees
+}
@@ -0,0 +1,24 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+)
+
+func TestBlockFeesStore(t *testing.T) {
+	s := NewBlockFeesStore(memorydb.New())
+	if fees := s.Get(1); len(fees.Validators) != 0 {
+		t.Fatalf("empty storage has fees %+v", fees)
+	}
+	s.Set(1, iblockproc.BlockFees{Validators: []iblockproc.ValidatorFee{{ID: 2, Fee: big.NewInt(7)}}})
+	if fee := s.Get(1).Get(2); fee.Cmp(big.NewInt(7)) != 0 {
+		t.Fatalf("unexpected fee %s", fee)
+	}
+	if fee := s.Get(1).Get(1); fee.Sign() != 0 {
+		t.Fatalf("unexpected fee %s", fee)
+	}
+}
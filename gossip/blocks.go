@@ -0,0 +1,133 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/table"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/inter/keys"
+)
+
+// blocks.go persists the processed blocks.
+//
+// Overview:
+//   A block is stored once it's processed, along with its executed transactions (in the block
+//   order) and their receipts. Each of them is in its own table, keyed by keys.Block:
+//     "b" - inter.Block
+//     "t" - the executed transactions
+//     "r" - the receipts, in the storage form (without the fields derived from the block)
+//   The last stored block is remembered separately, so the readers (e.g. the indexer, which
+//   opens the database read-only) know which blocks are complete.
+
+// BlocksDBName is the name of the blocks database in the chaindata directory.
+const BlocksDBName = "blocks"
+
+// lastBlockKey is the key of the last stored block. It's shorter than any block key.
+var lastBlockKey = []byte("l")
+
+// Blocks is the storage of the processed blocks.
+type Blocks struct {
+	db       kvdb.Store
+	blocks   kvdb.Store
+	txs      kvdb.Store
+	receipts kvdb.Store
+}
+
+// NewBlocks wraps the DB.
+func NewBlocks(db kvdb.Store) *Blocks {
+	return &Blocks{
+		db:       db,
+		blocks:   table.New(db, []byte("b")),
+		txs:      table.New(db, []byte("t")),
+		receipts: table.New(db, []byte("r")),
+	}
+}
+
+// Set stores the processed block with its executed transactions and their receipts.
+// The block becomes the last one if it's above the last stored block.
+func (s *Blocks) Set(n idx.Block, block *inter.Block, txs types.Transactions, receipts types.Receipts) {
+	stored := make([]*types.ReceiptForStorage, len(receipts))
+	for i, r := range receipts {
+		stored[i] = (*types.ReceiptForStorage)(r)
+	}
+	s.put(s.blocks, n, block)
+	s.put(s.txs, n, txs)
+	s.put(s.receipts, n, stored)
+	if n >= s.LastBlock() {
+		if err := s.db.Put(lastBlockKey, n.Bytes()); err != nil {
+			log.Crit("Failed to put last block", "err", err)
+		}
+	}
+}
+
+func (s *Blocks) put(t kvdb.Store, n idx.Block, v interface{}) {
+	b, err := rlp.EncodeToBytes(v)
+	if err != nil {
+		log.Crit("Failed to encode block data", "block", n, "err", err)
+	}
+	if err := t.Put(keys.Block(n), b); err != nil {
+		log.Crit("Failed to put block data", "block", n, "err", err)
+	}
+}
+
+func (s *Blocks) get(t kvdb.Store, n idx.Block, v interface{}) bool {
+	b, err := t.Get(keys.Block(n))
+	if err != nil {
+		log.Crit("Failed to get block data", "block", n, "err", err)
+	}
+	if b == nil {
+		return false
+	}
+	if err := rlp.DecodeBytes(b, v); err != nil {
+		log.Crit("Failed to decode block data", "block", n, "err", err)
+	}
+	return true
+}
+
+// LastBlock returns the last stored block, or 0 if there are none.
+func (s *Blocks) LastBlock() idx.Block {
+	b, err := s.db.Get(lastBlockKey)
+	if err != nil {
+		log.Crit("Failed to get last block", "err", err)
+	}
+	if b == nil {
+		return 0
+	}
+	return idx.BytesToBlock(b)
+}
+
+// GetBlock returns the stored block, or nil if it isn't stored.
+func (s *Blocks) GetBlock(n idx.Block) *inter.Block {
+	block := &inter.Block{}
+	if !s.get(s.blocks, n, block) {
+		return nil
+	}
+	return block
+}
+
+// Block returns the stored block with its executed transactions and their receipts,
+// or nil if the block isn't stored. The derived fields of the receipts and their logs
+// are filled in, the signer is needed only for the addresses of the created contracts.
+func (s *Blocks) Block(n idx.Block, signer types.Signer) (*inter.Block, types.Transactions, types.Receipts) {
+	block := s.GetBlock(n)
+	if block == nil {
+		return nil, nil, nil
+	}
+	var txs types.Transactions
+	s.get(s.txs, n, &txs)
+	var stored []*types.ReceiptForStorage
+	s.get(s.receipts, n, &stored)
+	receipts := make(types.Receipts, len(stored))
+	for i, r := range stored {
+		receipts[i] = (*types.Receipt)(r)
+	}
+	if err := receipts.DeriveFields(signer, common.Hash(block.Atropos), uint64(n), txs); err != nil {
+		log.Crit("Failed to derive receipts fields", "block", n, "err", err)
+	}
+	return block, txs, receipts
+}
@@ -0,0 +1,56 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+func TestBlocks(t *testing.T) {
+	s := NewBlocks(memorydb.New())
+	if s.LastBlock() != 0 {
+		t.Fatal("empty storage has blocks")
+	}
+	if block, _, _ := s.Block(1, nil); block != nil {
+		t.Fatal("empty storage has block 1")
+	}
+
+	key, _ := crypto.GenerateKey()
+	signer := types.LatestSignerForChainID(big.NewInt(4003))
+	call, _ := types.SignTx(types.NewTransaction(0, common.Address{1}, big.NewInt(1), 50000, big.NewInt(1), nil), signer, key)
+	create, _ := types.SignTx(types.NewContractCreation(1, big.NewInt(0), 100000, big.NewInt(1), nil), signer, key)
+	receipts := types.Receipts{
+		{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 30000, Logs: []*types.Log{{Address: common.Address{2}}}},
+		{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 90000, Logs: []*types.Log{{Address: common.Address{3}}, {Address: common.Address{4}}}},
+	}
+	s.Set(2, &inter.Block{Atropos: hash.Event{5}, GasUsed: 90000}, types.Transactions{call, create}, receipts)
+	s.Set(1, &inter.Block{Atropos: hash.Event{4}}, nil, nil)
+
+	if s.LastBlock() != 2 {
+		t.Fatalf("unexpected last block %d", s.LastBlock())
+	}
+	block, txs, got := s.Block(2, signer)
+	if block == nil || block.GasUsed != 90000 || len(txs) != 2 || txs[1].Hash() != create.Hash() {
+		t.Fatalf("unexpected block %+v, txs %d", block, len(txs))
+	}
+	if len(got) != 2 || got[1].GasUsed != 60000 || got[1].TxHash != create.Hash() || got[1].BlockNumber.Uint64() != 2 {
+		t.Fatalf("unexpected receipt %+v", got[1])
+	}
+	from, _ := types.Sender(signer, create)
+	if got[1].ContractAddress != crypto.CreateAddress(from, 1) {
+		t.Fatalf("unexpected contract address %s", got[1].ContractAddress.Hex())
+	}
+	if l := got[1].Logs[1]; l.Index != 2 || l.TxIndex != 1 || l.BlockHash != common.Hash(block.Atropos) || l.Address != (common.Address{4}) {
+		t.Fatalf("unexpected log %+v", l)
+	}
+	if block, txs, got := s.Block(1, signer); block == nil || len(txs) != 0 || len(got) != 0 {
+		t.Fatal("empty block isn't stored")
+	}
+}
@@ -0,0 +1,116 @@
+package gossip
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+// capabilities.go implements the capabilities negotiation of the handshake.
+//
+// Overview:
+//   Instead of bumping the protocol version for every wire change (which forces all
+//   the nodes to upgrade at once), peers advertise what they support in the handshake
+//   and use only the intersection. New features roll out incrementally: upgraded
+//   peers use them with each other, while still talking to older peers the old way.
+
+// CapabilityFlags is a bitmask of optional protocol features.
+type CapabilityFlags uint64
+
+const (
+	// CapCompression means the peer accepts compressed event batches, see compression.go.
+	CapCompression CapabilityFlags = 1 << iota
+	// CapLlrServing means the peer serves LLR block and epoch votes/records.
+	CapLlrServing
+	// CapSnapshotServing means the peer serves state snapshots.
+	CapSnapshotServing
+	// CapChecksums means the peer seals the messages with checksums, see checksums.go.
+	CapChecksums
+)
+
+var capabilityNames = []struct {
+	flag CapabilityFlags
+	name string
+}{
+	{CapCompression, "compression"},
+	{CapLlrServing, "llr"},
+	{CapSnapshotServing, "snapshot"},
+	{CapChecksums, "checksums"},
+}
+
+// Has returns true if all the given flags are set.
+func (f CapabilityFlags) Has(flags CapabilityFlags) bool {
+	return f&flags == flags
+}
+
+// String returns the human-readable list of the flags.
+func (f CapabilityFlags) String() string {
+	var names []string
+	for _, c := range capabilityNames {
+		if f.Has(c.flag) {
+			names = append(names, c.name)
+		}
+	}
+	return "[" + strings.Join(names, ",") + "]"
+}
+
+// Capabilities is the set of protocol features supported by a peer.
+type Capabilities struct {
+	// MinSerialization and MaxSerialization define the range of supported event serialization versions.
+	MinSerialization uint8
+	MaxSerialization uint8
+
+	// Flags are the optional features.
+	Flags CapabilityFlags
+}
+
+// LocalCapabilities returns the capabilities of this node.
+func LocalCapabilities(llrServing, snapshotServing bool) Capabilities {
+	c := Capabilities{
+		MinSerialization: 0,
+		MaxSerialization: inter.MaxSerializationVersion,
+		Flags:            CapCompression | CapChecksums,
+	}
+	if llrServing {
+		c.Flags |= CapLlrServing
+	}
+	if snapshotServing {
+		c.Flags |= CapSnapshotServing
+	}
+	return c
+}
+
+// LegacyCapabilities returns the implicit capabilities of peers which don't negotiate them.
+func LegacyCapabilities() Capabilities {
+	return Capabilities{
+		MinSerialization: 0,
+		MaxSerialization: inter.MaxSerializationVersion,
+	}
+}
+
+// Negotiate returns the capabilities supported by both the peers.
+// The serialization version to use is the MaxSerialization of the result.
+func Negotiate(local, remote Capabilities) (Capabilities, error) {
+	res := Capabilities{
+		MinSerialization: local.MinSerialization,
+		MaxSerialization: local.MaxSerialization,
+		Flags:            local.Flags & remote.Flags,
+	}
+	if remote.MinSerialization > res.MinSerialization {
+		res.MinSerialization = remote.MinSerialization
+	}
+	if remote.MaxSerialization < res.MaxSerialization {
+		res.MaxSerialization = remote.MaxSerialization
+	}
+	if res.MinSerialization > res.MaxSerialization {
+		return Capabilities{}, fmt.Errorf("%w: local [%d,%d], remote [%d,%d]", ErrNoCommonSerialization,
+			local.MinSerialization, local.MaxSerialization, remote.MinSerialization, remote.MaxSerialization)
+	}
+	return res, nil
+}
+
+// String returns the human-readable description of the capabilities.
+func (c Capabilities) String() string {
+	return fmt.Sprintf("serialization=[%d,%d] flags=%s", c.MinSerialization, c.MaxSerialization, c.Flags)
+}
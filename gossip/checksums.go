@@ -0,0 +1,203 @@
+package gossip
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"sync"
+	"time"
+
+	"github.com/rony4d/go-opera-asset/utils/clock"
+	"github.com/rony4d/go-opera-asset/utils/cser"
+)
+
+// checksums.go detects the messages corrupted on the wire.
+//
+// Overview:
+//   If both the peers negotiated CapChecksums, every message but the handshake is sealed
+//   with the CRC-32C of its payload, so a frame damaged by a faulty NIC, memory or middlebox
+//   is told apart from a malformed message the peer has encoded on purpose.
+//
+//   A decoding failure is classified as either corruption or protocol abuse:
+//     - a checksum mismatch is corruption;
+//     - a message which matches its checksum, but doesn't decode, is abuse, since the peer
+//       has sent exactly these bytes;
+//     - without the checksums, truncated or non-canonical CSER, or a compressed message which
+//       doesn't decompress, is consistent with corruption (a flipped bit rarely yields a
+//       canonical encoding), while an encoding which asks for huge allocations or fails
+//       the semantic checks is abuse.
+//   An abusive peer is disconnected at once. Corruption is counted per peer (admin_peerStats,
+//   "gossip/peers/<peer id>/corrupted"), and only a peer which delivers corrupted messages
+//   repeatedly is disconnected, as a single bit flip isn't the peer's fault.
+
+var (
+	// ErrFrameChecksum is returned if the message doesn't match its checksum.
+	ErrFrameChecksum = errors.New("message checksum mismatch")
+	// ErrProtocolAbuse is the reason of disconnecting a peer which sent a malformed message.
+	ErrProtocolAbuse = errors.New("malformed message")
+	// ErrTooManyCorrupted is the reason of disconnecting a peer which repeatedly delivers corrupted messages.
+	ErrTooManyCorrupted = errors.New("too many corrupted messages")
+)
+
+// frameChecksumSize is the size of the checksum appended to the sealed messages.
+const frameChecksumSize = 4
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// checksummed returns true if the message of the code is sealed once the checksums are negotiated.
+func checksummed(code uint64) bool {
+	return code != HandshakeMsg
+}
+
+// SealFrame appends the checksum to the payload.
+func SealFrame(payload []byte) []byte {
+	frame := make([]byte, len(payload)+frameChecksumSize)
+	copy(frame, payload)
+	binary.BigEndian.PutUint32(frame[len(payload):], crc32.Checksum(payload, castagnoli))
+	return frame
+}
+
+// OpenFrame verifies the checksum of the frame and returns its payload.
+func OpenFrame(frame []byte) ([]byte, error) {
+	if len(frame) < frameChecksumSize {
+		return nil, fmt.Errorf("%w: frame of %d bytes", ErrFrameChecksum, len(frame))
+	}
+	payload := frame[:len(frame)-frameChecksumSize]
+	if binary.BigEndian.Uint32(frame[len(payload):]) != crc32.Checksum(payload, castagnoli) {
+		return nil, ErrFrameChecksum
+	}
+	return payload, nil
+}
+
+// DecodeFailure is the kind of a message decoding failure.
+type DecodeFailure uint8
+
+const (
+	// FailureAbuse means the peer has sent a malformed message.
+	FailureAbuse DecodeFailure = iota
+	// FailureCorruption means the message was likely damaged on the wire.
+	FailureCorruption
+)
+
+func (f DecodeFailure) String() string {
+	if f == FailureCorruption {
+		return "corruption"
+	}
+	return "abuse"
+}
+
+// ClassifyDecodeError tells the corruption apart from the protocol abuse.
+// verified is true if the message matched its checksum.
+func ClassifyDecodeError(err error, verified bool) DecodeFailure {
+	if errors.Is(err, ErrFrameChecksum) {
+		return FailureCorruption
+	}
+	if verified {
+		return FailureAbuse
+	}
+	if errors.Is(err, cser.ErrMalformedEncoding) || errors.Is(err, cser.ErrNonCanonicalEncoding) || errors.Is(err, ErrDecompression) {
+		return FailureCorruption
+	}
+	return FailureAbuse
+}
+
+// CorruptionPolicy defines how many corrupted messages a peer may deliver.
+type CorruptionPolicy struct {
+	MaxCorrupted int           // corrupted messages tolerated within Window
+	Window       time.Duration // period the corrupted messages are counted over
+}
+
+// DefaultCorruptionPolicy tolerates sporadic corruption, but not a faulty link.
+func DefaultCorruptionPolicy() CorruptionPolicy {
+	return CorruptionPolicy{
+		MaxCorrupted: 3,
+		Window:       10 * time.Minute,
+	}
+}
+
+// CorruptionGuard decides which peers to disconnect for the messages they fail to deliver intact.
+// It's safe for concurrent use.
+type CorruptionGuard struct {
+	policy CorruptionPolicy
+	stats  *PeersStats
+	clock  clock.Clock
+
+	mu     sync.Mutex
+	recent map[string][]time.Time // peer -> times of the corrupted messages within the window
+}
+
+// NewCorruptionGuard creates the guard accounting the failures in the peers stats.
+func NewCorruptionGuard(policy CorruptionPolicy, stats *PeersStats, c clock.Clock) *CorruptionGuard {
+	return &CorruptionGuard{
+		policy: policy,
+		stats:  stats,
+		clock:  c,
+		recent: make(map[string][]time.Time),
+	}
+}
+
+// DecodeFailed accounts the failure to open or decode a message of the peer.
+// It returns the reason to disconnect the peer with, or nil if the peer may stay.
+func (g *CorruptionGuard) DecodeFailed(peer string, err error, verified bool) error {
+	stats := g.stats.Get(peer)
+	if ClassifyDecodeError(err, verified) == FailureAbuse {
+		if stats != nil {
+			stats.InvalidMsg()
+		}
+		return fmt.Errorf("%w: %v", ErrProtocolAbuse, err)
+	}
+	if stats != nil {
+		stats.CorruptedMsg()
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.clock.Now()
+	recent := g.recent[peer]
+	for len(recent) != 0 && now.Sub(recent[0]) > g.policy.Window {
+		recent = recent[1:]
+	}
+	recent = append(recent, now)
+	g.recent[peer] = recent
+	if len(recent) > g.policy.MaxCorrupted {
+		return fmt.Errorf("%w: %d within %v", ErrTooManyCorrupted, len(recent), g.policy.Window)
+	}
+	return nil
+}
+
+// Forget drops the history of a disconnected peer.
+func (g *CorruptionGuard) Forget(peer string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.recent, peer)
+}
+
+// ReadPayload returns the payload of a received message, verifying its checksum and decompressing
+// it if the checksums and the compression are negotiated with the peer (see Capabilities).
+func ReadPayload(caps Capabilities, code uint64, data []byte) ([]byte, error) {
+	if caps.Flags.Has(CapChecksums) && checksummed(code) {
+		var err error
+		if data, err = OpenFrame(data); err != nil {
+			return nil, err
+		}
+	}
+	if caps.Flags.Has(CapCompression) && compressed(code) {
+		return DecompressPayload(data)
+	}
+	return data, nil
+}
+
+// WritePayload returns the data of a message to send, compressing and sealing it if the
+// compression and the checksums are negotiated with the peer.
+func WritePayload(caps Capabilities, code uint64, payload []byte) []byte {
+	if caps.Flags.Has(CapCompression) && compressed(code) {
+		payload = CompressPayload(payload)
+	}
+	if caps.Flags.Has(CapChecksums) && checksummed(code) {
+		payload = SealFrame(payload)
+	}
+	return payload
+}
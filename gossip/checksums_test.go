@@ -0,0 +1,138 @@
+package gossip
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rony4d/go-opera-asset/utils/clock"
+	"github.com/rony4d/go-opera-asset/utils/cser"
+)
+
+func TestFrameChecksum(t *testing.T) {
+	payload := []byte("event payload")
+	frame := SealFrame(payload)
+	got, err := OpenFrame(frame)
+	if err != nil || string(got) != string(payload) {
+		t.Fatalf("OpenFrame() = %q, %v", got, err)
+	}
+
+	for i := range frame {
+		damaged := append([]byte{}, frame...)
+		damaged[i] ^= 0x10
+		if _, err := OpenFrame(damaged); !errors.Is(err, ErrFrameChecksum) {
+			t.Fatalf("bit flip at %d: error = %v, want %v", i, err, ErrFrameChecksum)
+		}
+	}
+	if _, err := OpenFrame(frame[:3]); !errors.Is(err, ErrFrameChecksum) {
+		t.Fatalf("truncated frame: error = %v", err)
+	}
+
+	// the checksums apply only if negotiated, and never to the handshake
+	caps := Capabilities{Flags: CapChecksums}
+	if data := WritePayload(Capabilities{}, EventsMsg, payload); string(data) != string(payload) {
+		t.Error("sealed without the negotiated checksums")
+	}
+	if data := WritePayload(caps, HandshakeMsg, payload); string(data) != string(payload) {
+		t.Error("sealed the handshake")
+	}
+	if got, err := ReadPayload(caps, EventsMsg, WritePayload(caps, EventsMsg, payload)); err != nil || string(got) != string(payload) {
+		t.Errorf("ReadPayload() = %q, %v", got, err)
+	}
+}
+
+func TestClassifyDecodeError(t *testing.T) {
+	other := errors.New("invalid event")
+	for _, tt := range []struct {
+		err      error
+		verified bool
+		want     DecodeFailure
+	}{
+		{ErrFrameChecksum, false, FailureCorruption},
+		{cser.ErrMalformedEncoding, false, FailureCorruption},
+		{cser.ErrNonCanonicalEncoding, false, FailureCorruption},
+		{ErrDecompression, false, FailureCorruption},
+		{ErrDecompression, true, FailureAbuse},
+		{cser.ErrTooLargeAlloc, false, FailureAbuse},
+		{other, false, FailureAbuse},
+		{cser.ErrMalformedEncoding, true, FailureAbuse},
+	} {
+		if got := ClassifyDecodeError(tt.err, tt.verified); got != tt.want {
+			t.Errorf("ClassifyDecodeError(%v, %v) = %s, want %s", tt.err, tt.verified, got, tt.want)
+		}
+	}
+}
+
+func TestCorruptionGuard(t *testing.T) {
+	c := clock.NewManual(time.Unix(1600000000, 0))
+	stats := NewPeersStats(c)
+	p := stats.Register("peer1")
+	guard := NewCorruptionGuard(CorruptionPolicy{MaxCorrupted: 2, Window: time.Minute}, stats, c)
+
+	// sporadic corruption is tolerated
+	for i := 0; i < 4; i++ {
+		if err := guard.DecodeFailed("peer1", ErrFrameChecksum, false); err != nil {
+			t.Fatalf("corrupted message %d: %v", i, err)
+		}
+		c.Advance(time.Minute + time.Second)
+	}
+	// a faulty link isn't
+	for i := 0; i < 2; i++ {
+		if err := guard.DecodeFailed("peer1", cser.ErrMalformedEncoding, false); err != nil {
+			t.Fatalf("corrupted message in a burst %d: %v", i, err)
+		}
+	}
+	if err := guard.DecodeFailed("peer1", ErrFrameChecksum, false); !errors.Is(err, ErrTooManyCorrupted) {
+		t.Fatalf("error = %v, want %v", err, ErrTooManyCorrupted)
+	}
+
+	// abuse is never tolerated
+	if err := guard.DecodeFailed("peer1", cser.ErrMalformedEncoding, true); !errors.Is(err, ErrProtocolAbuse) {
+		t.Fatalf("error = %v, want %v", err, ErrProtocolAbuse)
+	}
+	if snap := p.Snapshot(); snap.CorruptedMsgs != 7 || snap.InvalidMsgs != 1 {
+		t.Fatalf("unexpected counters %+v", snap)
+	}
+
+	guard.Forget("peer1")
+	if err := guard.DecodeFailed("peer1", ErrFrameChecksum, false); err != nil {
+		t.Fatalf("forgotten peer: %v", err)
+	}
+	// peers which aren't registered are still guarded
+	if err := guard.DecodeFailed("peer2", errors.New("invalid event"), true); !errors.Is(err, ErrProtocolAbuse) {
+		t.Fatalf("error = %v, want %v", err, ErrProtocolAbuse)
+	}
+}
+
+func TestCompressedPayload(t *testing.T) {
+	payload := bytes.Repeat([]byte("event"), 100)
+
+	// the compression applies only if negotiated, and only to the event batches
+	caps := Capabilities{Flags: CapCompression}
+	if data := WritePayload(Capabilities{}, EventsMsg, payload); !bytes.Equal(data, payload) {
+		t.Error("compressed without the negotiated compression")
+	}
+	if data := WritePayload(caps, NewEventIDsMsg, payload); !bytes.Equal(data, payload) {
+		t.Error("compressed the event IDs")
+	}
+	for _, code := range []uint64{EventsMsg, EventsStreamResponse} {
+		data := WritePayload(caps, code, payload)
+		if len(data) >= len(payload) {
+			t.Errorf("message %d isn't compressed: %d bytes", code, len(data))
+		}
+		if got, err := ReadPayload(caps, code, data); err != nil || !bytes.Equal(got, payload) {
+			t.Errorf("ReadPayload(%d) = %q, %v", code, got, err)
+		}
+	}
+
+	// the checksum covers the compressed bytes
+	both := Capabilities{Flags: CapCompression | CapChecksums}
+	data := WritePayload(both, EventsMsg, payload)
+	if _, err := OpenFrame(data); err != nil {
+		t.Fatalf("compressed message isn't sealed: %v", err)
+	}
+	if got, err := ReadPayload(both, EventsMsg, data); err != nil || !bytes.Equal(got, payload) {
+		t.Errorf("ReadPayload() = %q, %v", got, err)
+	}
+}
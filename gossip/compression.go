@@ -0,0 +1,44 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/snappy"
+)
+
+// compression.go compresses the event batches once both the peers negotiated CapCompression.
+//
+// Overview:
+//   Only the batches of events are compressed, as the other messages are either small or
+//   consist of hashes, which don't compress. The payload is compressed before it's sealed
+//   with the checksum (see checksums.go), so the checksum covers the bytes on the wire.
+
+// ErrDecompression is returned if a compressed message can't be decompressed.
+var ErrDecompression = errors.New("malformed compressed message")
+
+// compressed returns true if the message of the code is compressed once the compression is negotiated.
+func compressed(code uint64) bool {
+	return code == EventsMsg || code == EventsStreamResponse
+}
+
+// CompressPayload compresses the payload.
+func CompressPayload(payload []byte) []byte {
+	return snappy.Encode(nil, payload)
+}
+
+// DecompressPayload decompresses the payload, refusing the ones which exceed the message size limit.
+func DecompressPayload(data []byte) ([]byte, error) {
+	size, err := snappy.DecodedLen(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecompression, err)
+	}
+	if size > protocolMaxMsgSize {
+		return nil, fmt.Errorf("%w: %d bytes exceed the limit of %d", ErrDecompression, size, protocolMaxMsgSize)
+	}
+	payload, err := snappy.Decode(nil, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecompression, err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,32 @@
+package gossip
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/snappy"
+)
+
+func TestCompressPayload(t *testing.T) {
+	payload := bytes.Repeat([]byte("event"), 100)
+	data := CompressPayload(payload)
+	if len(data) >= len(payload) {
+		t.Errorf("payload isn't compressed: %d bytes", len(data))
+	}
+	if got, err := DecompressPayload(data); err != nil || !bytes.Equal(got, payload) {
+		t.Errorf("DecompressPayload() = %q, %v", got, err)
+	}
+}
+
+func TestDecompressPayload(t *testing.T) {
+	if _, err := DecompressPayload([]byte{0xff, 0xff, 0xff}); !errors.Is(err, ErrDecompression) {
+		t.Errorf("malformed: error = %v, want %v", err, ErrDecompression)
+	}
+
+	// a small message mustn't decompress into a huge one
+	bomb := snappy.Encode(nil, make([]byte, protocolMaxMsgSize+1))
+	if _, err := DecompressPayload(bomb); !errors.Is(err, ErrDecompression) {
+		t.Errorf("oversized: error = %v, want %v", err, ErrDecompression)
+	}
+}
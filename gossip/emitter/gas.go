@@ -0,0 +1,35 @@
+package emitter
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// GasPowerUsed returns the gas power the event consumes under the rules: the base event gas,
+// the parents above Dag.MaxFreeParents, the extra data, the votes, the misbehaviour proofs
+// and the gas limits of the transactions (plus their data since Upgrades.GasV2).
+func GasPowerUsed(rules opera.Rules, e inter.EventPayloadI) uint64 {
+	gas := rules.Economy.Gas
+
+	used := gas.EventGas
+	if parents := idx.Event(len(e.Parents())); parents > rules.Dag.MaxFreeParents {
+		used += uint64(parents-rules.Dag.MaxFreeParents) * gas.ParentGas
+	}
+	used += uint64(len(e.Extra())) * gas.ExtraDataGas
+	for _, tx := range e.Txs() {
+		used += tx.Gas()
+		if rules.Upgrades.GasV2 {
+			used += uint64(len(tx.Data())) * gas.TxDataGas
+		}
+	}
+	if e.BlockVotes().Start != 0 {
+		used += gas.BlockVotesBaseGas + uint64(len(e.BlockVotes().Votes))*gas.BlockVoteGas
+	}
+	if e.EpochVote().Epoch != 0 {
+		used += gas.EpochVoteGas
+	}
+	used += uint64(len(e.MisbehaviourProofs())) * gas.MisbehaviourProofGas
+	return used
+}
@@ -0,0 +1,65 @@
+package emitter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+// EmitIntervals is the config of the emitter's pace.
+type EmitIntervals struct {
+	Min time.Duration // never emit more often than this
+	Max time.Duration // emit at least this often, even with no txs
+}
+
+// DefaultEmitIntervals returns the default emitter's pace.
+func DefaultEmitIntervals() EmitIntervals {
+	return EmitIntervals{
+		Min: 150 * time.Millisecond,
+		Max: 10 * time.Minute,
+	}
+}
+
+// Pacer decides when the next event should be emitted.
+// It takes the time from the injected clock, so the emission timing is deterministic in tests.
+type Pacer struct {
+	mu        sync.Mutex
+	clock     clock.Clock
+	intervals EmitIntervals
+
+	prevEmittedAt time.Time
+}
+
+// NewPacer creates the pacer. The first event may be emitted immediately.
+func NewPacer(c clock.Clock, intervals EmitIntervals) *Pacer {
+	return &Pacer{
+		clock:     c,
+		intervals: intervals,
+	}
+}
+
+// Ready returns true if an event should be emitted now.
+// An event is emitted once the Min interval passes if there's something to emit
+// (txs, votes, etc.), and after the Max interval in any case.
+func (p *Pacer) Ready(anythingToEmit bool) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.prevEmittedAt.IsZero() {
+		return true
+	}
+	passed := p.clock.Now().Sub(p.prevEmittedAt)
+	if passed < p.intervals.Min {
+		return false
+	}
+	return anythingToEmit || passed >= p.intervals.Max
+}
+
+// Emitted records that an event was just emitted.
+func (p *Pacer) Emitted() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.prevEmittedAt = p.clock.Now()
+}
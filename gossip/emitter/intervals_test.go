@@ -0,0 +1,34 @@
+package emitter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+func TestPacer(t *testing.T) {
+	c := clock.NewManual(time.Unix(1600000000, 0))
+	p := NewPacer(c, EmitIntervals{Min: time.Second, Max: time.Minute})
+
+	if !p.Ready(false) {
+		t.Fatal("first event isn't ready")
+	}
+	p.Emitted()
+
+	c.Advance(500 * time.Millisecond)
+	if p.Ready(true) {
+		t.Fatal("event is ready before the min interval")
+	}
+	c.Advance(500 * time.Millisecond)
+	if !p.Ready(true) {
+		t.Fatal("event with txs isn't ready after the min interval")
+	}
+	if p.Ready(false) {
+		t.Fatal("empty event is ready before the max interval")
+	}
+	c.Advance(time.Minute)
+	if !p.Ready(false) {
+		t.Fatal("empty event isn't ready after the max interval")
+	}
+}
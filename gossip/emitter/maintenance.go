@@ -0,0 +1,199 @@
+// Package emitter implements creation of new events by a validator node.
+package emitter
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maintenance.go implements the validator maintenance mode.
+//
+// Overview:
+//   Stopping a validator node abruptly is risky: an event may be half-built (a restart
+//   could then emit a conflicting event with the same seq, i.e. double-sign), and the
+//   validator silently disappears until the network flags it offline.
+//
+//   In the maintenance mode the emitter stops starting new events, while the events
+//   already being built are finished and broadcast. The node keeps validating and
+//   gossiping events of other validators. Once Enter returns, no event emission is in
+//   flight and the node may be safely upgraded and restarted.
+//
+//   The mode is switched at runtime via the RPC API (validator_startMaintenance,
+//   validator_stopMaintenance) or via signals (see the launcher), and is reported by
+//   the "emitter/maintenance" metric (1 means maintenance).
+
+var maintenanceGauge = metrics.NewRegisteredGauge("emitter/maintenance", nil)
+
+// ErrMaintenanceLeft is returned by Enter if the maintenance mode was left before the
+// pending emissions were finished.
+var ErrMaintenanceLeft = errors.New("maintenance mode was left before the emissions were finished")
+
+// Maintenance tracks whether the emitter is allowed to start new events.
+// It's safe for concurrent use.
+type Maintenance struct {
+	mu       sync.Mutex
+	enabled  bool
+	inflight int
+	drained  chan struct{} // closed when inflight drops to zero in the maintenance mode
+}
+
+// NewMaintenance creates the maintenance mode switch, with the mode off.
+func NewMaintenance() *Maintenance {
+	return &Maintenance{}
+}
+
+// StartEmission must be called by the emitter before it starts building an event.
+// Returns false if the node is in the maintenance mode and the event must not be emitted.
+// If true is returned, DoneEmission must be called once the event is emitted or dropped.
+func (m *Maintenance) StartEmission() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.enabled {
+		return false
+	}
+	m.inflight++
+	return true
+}
+
+// DoneEmission marks an event emission started by StartEmission as finished.
+func (m *Maintenance) DoneEmission() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.inflight == 0 {
+		// an unpaired call must not make the pending emissions negative, or Enter would never return
+		log.Error("Event emission is finished without being started")
+		return
+	}
+	m.inflight--
+	if m.inflight == 0 && m.drained != nil {
+		close(m.drained)
+		m.drained = nil
+	}
+}
+
+// Enter switches the maintenance mode on and waits until the pending emissions are finished.
+// The mode stays on even if ctx is cancelled before the pending emissions are finished.
+func (m *Maintenance) Enter(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.enabled {
+		m.enabled = true
+		maintenanceGauge.Update(1)
+		log.Warn("Entering validator maintenance mode, no new events will be emitted")
+	}
+	for m.inflight != 0 {
+		if m.drained == nil {
+			m.drained = make(chan struct{})
+		}
+		drained := m.drained
+		m.mu.Unlock()
+
+		select {
+		case <-drained:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		m.mu.Lock()
+		if !m.enabled {
+			m.mu.Unlock()
+			return ErrMaintenanceLeft
+		}
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+// Leave switches the maintenance mode off, so the emitter resumes emitting events.
+func (m *Maintenance) Leave() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.enabled {
+		m.enabled = false
+		maintenanceGauge.Update(0)
+		log.Info("Leaving validator maintenance mode")
+	}
+	// wake up the pending Enter calls, a next Enter waits for the emissions started since then
+	if m.drained != nil {
+		close(m.drained)
+		m.drained = nil
+	}
+}
+
+// Hold keeps the emission off while fn runs, e.g. while the state cache is warmed up on startup.
+// The maintenance mode is left afterwards only if it wasn't on before, and even if fn fails.
+func (m *Maintenance) Hold(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.mu.Lock()
+	wasEnabled := m.enabled
+	m.mu.Unlock()
+
+	if err := m.Enter(ctx); err != nil {
+		return err
+	}
+	if !wasEnabled {
+		defer m.Leave()
+	}
+	return fn(ctx)
+}
+
+// Status returns whether the maintenance mode is on and the number of pending emissions.
+func (m *Maintenance) Status() (enabled bool, inflight int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.enabled, m.inflight
+}
+
+// PrivateMaintenanceAPI exposes the maintenance mode switch over RPC.
+// It must be served only over private transports (IPC), as it affects validator's liveness.
+type PrivateMaintenanceAPI struct {
+	m *Maintenance
+}
+
+// NewPrivateMaintenanceAPI creates the maintenance mode API.
+func NewPrivateMaintenanceAPI(m *Maintenance) *PrivateMaintenanceAPI {
+	return &PrivateMaintenanceAPI{m}
+}
+
+// StartMaintenance switches the maintenance mode on. It returns once the pending emissions are finished.
+func (api *PrivateMaintenanceAPI) StartMaintenance(ctx context.Context) error {
+	return api.m.Enter(ctx)
+}
+
+// StopMaintenance switches the maintenance mode off.
+func (api *PrivateMaintenanceAPI) StopMaintenance() {
+	api.m.Leave()
+}
+
+// MaintenanceStatus returns "maintenance" or "active", and the number of pending emissions.
+func (api *PrivateMaintenanceAPI) MaintenanceStatus() map[string]interface{} {
+	enabled, inflight := api.m.Status()
+	status := "active"
+	if enabled {
+		status = "maintenance"
+	}
+	return map[string]interface{}{
+		"status":   status,
+		"inflight": inflight,
+	}
+}
+
+// MaintenanceAPIs returns the RPC APIs of the maintenance mode. They aren't public,
+// so they are served only over IPC.
+func MaintenanceAPIs(m *Maintenance) []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "validator",
+			Version:   "1.0",
+			Service:   NewPrivateMaintenanceAPI(m),
+			Public:    false,
+		},
+	}
+}
@@ -0,0 +1,145 @@
+package emitter
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMaintenance(t *testing.T) {
+	m := NewMaintenance()
+
+	if !m.StartEmission() {
+		t.Fatal("emission is blocked outside of the maintenance mode")
+	}
+
+	entered := make(chan error, 1)
+	go func() {
+		entered <- m.Enter(context.Background())
+	}()
+
+	// wait until the mode is switched on
+	for {
+		if enabled, _ := m.Status(); enabled {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if m.StartEmission() {
+		t.Fatal("new emission is allowed in the maintenance mode")
+	}
+	select {
+	case <-entered:
+		t.Fatal("Enter returned before the pending emission is finished")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	m.DoneEmission()
+	if err := <-entered; err != nil {
+		t.Fatalf("Enter failed: %v", err)
+	}
+
+	m.Leave()
+	if !m.StartEmission() {
+		t.Fatal("emission is blocked after leaving the maintenance mode")
+	}
+	m.DoneEmission()
+}
+
+func TestMaintenance_EnterCancelled(t *testing.T) {
+	m := NewMaintenance()
+	m.StartEmission()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.Enter(ctx); err != context.Canceled {
+		t.Fatalf("Enter() = %v, want %v", err, context.Canceled)
+	}
+	if enabled, inflight := m.Status(); !enabled || inflight != 1 {
+		t.Fatalf("Status() = %v, %d, want true, 1", enabled, inflight)
+	}
+}
+
+func TestMaintenanceHold(t *testing.T) {
+	m := NewMaintenance()
+
+	err := m.Hold(context.Background(), func(context.Context) error {
+		if m.StartEmission() {
+			t.Fatal("emission is allowed while held")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Hold failed: %v", err)
+	}
+	if enabled, _ := m.Status(); enabled {
+		t.Fatal("maintenance mode isn't left after Hold")
+	}
+
+	// the mode switched on by the operator stays on
+	if err := m.Enter(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Hold(context.Background(), func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("Hold failed: %v", err)
+	}
+	if enabled, _ := m.Status(); !enabled {
+		t.Fatal("Hold left the maintenance mode entered before it")
+	}
+}
+
+func TestMaintenance_UnpairedDone(t *testing.T) {
+	m := NewMaintenance()
+	m.DoneEmission()
+	if _, inflight := m.Status(); inflight != 0 {
+		t.Fatalf("unpaired DoneEmission made the pending emissions %d", inflight)
+	}
+	m.StartEmission()
+	entered := make(chan error, 1)
+	go func() {
+		entered <- m.Enter(context.Background())
+	}()
+	time.Sleep(10 * time.Millisecond)
+	m.DoneEmission()
+	if err := <-entered; err != nil {
+		t.Fatalf("Enter failed: %v", err)
+	}
+}
+
+func TestMaintenance_LeaveWhileEntering(t *testing.T) {
+	m := NewMaintenance()
+	m.StartEmission()
+
+	entered := make(chan error, 1)
+	go func() {
+		entered <- m.Enter(context.Background())
+	}()
+	for {
+		if enabled, _ := m.Status(); enabled {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	m.Leave()
+	if err := <-entered; err != ErrMaintenanceLeft {
+		t.Fatalf("Enter() = %v, want %v", err, ErrMaintenanceLeft)
+	}
+
+	// the next Enter waits for the emission still pending, and for the one started since then
+	if !m.StartEmission() {
+		t.Fatal("emission is blocked after leaving the maintenance mode")
+	}
+	go func() {
+		entered <- m.Enter(context.Background())
+	}()
+	m.DoneEmission()
+	select {
+	case err := <-entered:
+		t.Fatalf("Enter returned with a pending emission: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	m.DoneEmission()
+	if err := <-entered; err != nil {
+		t.Fatalf("Enter failed: %v", err)
+	}
+}
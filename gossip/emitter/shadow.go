@@ -0,0 +1,363 @@
+package emitter
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/dag"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+// shadow.go implements the shadow validator mode.
+//
+// Overview:
+//   A prospective validator wants to know whether its node keeps up with the network
+//   before it stakes: whether the events would be emitted in time, how many transactions
+//   they would carry and whether the gas power would suffice.
+//
+//   In the shadow mode the node computes the events it would emit, with the same pace
+//   (Pacer), the same transactions (PickTxs) and the same gas power accounting
+//   (GasPowerUsed) as a validator, on top of the real DAG heads. The events are built,
+//   but never signed nor broadcast: they're logged, counted and kept in a bounded history
+//   served by the RPC API (validator_shadowEvents, validator_shadowStats).
+//
+//   The shadow events form their own self-parent chain, which the network never sees, so
+//   a shadow node doesn't affect the consensus and needs no validator key.
+
+// ShadowWorld is the view of the node the shadow events are built on.
+type ShadowWorld interface {
+	// Rules returns the rules of the current epoch.
+	Rules() opera.Rules
+	// Epoch returns the current epoch.
+	Epoch() idx.Epoch
+	// Heads returns the DAG heads of the current epoch, the parents candidates.
+	Heads() []dag.Event
+	// PendingTxs returns the executable transactions of the txpool, in the emission order.
+	PendingTxs() types.Transactions
+	// GasPowerLeft returns the gas power the validator would have available now.
+	GasPowerLeft() inter.GasPowerLeft
+}
+
+// ShadowConfig is the config of the shadow emitter.
+type ShadowConfig struct {
+	// Creator is the validator the events are computed for. It may be a validator ID which
+	// isn't staked yet, the events are never signed.
+	Creator   idx.ValidatorID
+	Intervals EmitIntervals
+	// History is the number of the last events kept for the RPC API.
+	History int
+}
+
+// DefaultShadowConfig returns the default config of the shadow emitter.
+func DefaultShadowConfig() ShadowConfig {
+	return ShadowConfig{
+		Intervals: DefaultEmitIntervals(),
+		History:   1000,
+	}
+}
+
+// ShadowEvent is an event the validator would have emitted.
+type ShadowEvent struct {
+	ID           hash.Event
+	Epoch        idx.Epoch
+	Seq          idx.Event
+	Lamport      idx.Lamport
+	Parents      hash.Events
+	CreationTime inter.Timestamp
+	Txs          []common.Hash
+	// SkippedTxs is the number of picked transactions left out for the lack of gas power.
+	SkippedTxs   int
+	GasPowerUsed uint64
+	GasPowerLeft inter.GasPowerLeft
+	PayloadHash  hash.Hash
+	Size         int
+	// BuildTime is how long the node took to compute the event.
+	BuildTime time.Duration
+}
+
+// ShadowStats are the totals of the shadow emission.
+type ShadowStats struct {
+	Events       uint64
+	Txs          uint64
+	SkippedTxs   uint64
+	GasPowerUsed uint64
+	// Starved is the number of events which couldn't afford even an empty event.
+	Starved uint64
+	// MaxBuildTime is the longest time the node took to compute an event.
+	MaxBuildTime time.Duration
+}
+
+// ShadowEmitter computes the events of a validator without signing or broadcasting them.
+// It's safe for concurrent use.
+type ShadowEmitter struct {
+	mu    sync.Mutex
+	cfg   ShadowConfig
+	world ShadowWorld
+	clock clock.Clock
+	pacer *Pacer
+
+	last    *inter.EventPayload // the self-parent of the next event, nil at the epoch start
+	history []ShadowEvent       // ring buffer of the last cfg.History events
+	next    int                 // index of the oldest event once the history is full
+	stats   ShadowStats
+}
+
+// NewShadowEmitter creates the shadow emitter.
+func NewShadowEmitter(cfg ShadowConfig, world ShadowWorld, c clock.Clock) *ShadowEmitter {
+	if cfg.History <= 0 {
+		cfg.History = DefaultShadowConfig().History
+	}
+	return &ShadowEmitter{
+		cfg:   cfg,
+		world: world,
+		clock: c,
+		pacer: NewPacer(c, cfg.Intervals),
+	}
+}
+
+// Tick computes the next event if it's time to emit one, the same way a validator would.
+// Returns nil if no event would be emitted now.
+func (s *ShadowEmitter) Tick() *ShadowEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rules := s.world.Rules()
+	txs := PickTxs(rules, s.world.PendingTxs())
+	if !s.pacer.Ready(len(txs) != 0) {
+		return nil
+	}
+	start := s.clock.Now()
+
+	epoch := s.world.Epoch()
+	if s.last != nil && s.last.Epoch() != epoch {
+		s.last = nil
+	}
+	me := &inter.MutableEventPayload{}
+	me.SetVersion(1)
+	me.SetEpoch(epoch)
+	me.SetCreator(s.cfg.Creator)
+	s.setParents(me, rules)
+	creationTime := inter.Timestamp(start.UnixNano())
+	if s.last != nil && creationTime <= s.last.CreationTime() {
+		creationTime = s.last.CreationTime() + 1
+	}
+	me.SetCreationTime(creationTime)
+
+	// drop the transactions which don't fit into the gas power, a validator would leave
+	// them for the next events
+	gasPowerLeft := s.world.GasPowerLeft()
+	skipped := 0
+	for {
+		me.SetTxs(txs)
+		me.SetPayloadHash(inter.CalcPayloadHash(me))
+		if GasPowerUsed(rules, me) <= gasPowerLeft.Min() || len(txs) == 0 {
+			break
+		}
+		txs = txs[:len(txs)-1]
+		skipped++
+	}
+	used := GasPowerUsed(rules, me)
+	if used > gasPowerLeft.Min() {
+		s.stats.Starved++
+		log.Warn("Shadow validator has no gas power for an event", "epoch", epoch, "needed", used, "left", gasPowerLeft)
+		return nil
+	}
+	for i := range gasPowerLeft.Gas {
+		gasPowerLeft.Gas[i] -= used
+	}
+	me.SetGasPowerUsed(used)
+	me.SetGasPowerLeft(gasPowerLeft)
+	e := me.Build()
+	s.last = e
+	s.pacer.Emitted()
+
+	res := ShadowEvent{
+		ID:           e.ID(),
+		Epoch:        e.Epoch(),
+		Seq:          e.Seq(),
+		Lamport:      e.Lamport(),
+		Parents:      e.Parents(),
+		CreationTime: e.CreationTime(),
+		Txs:          make([]common.Hash, len(txs)),
+		SkippedTxs:   skipped,
+		GasPowerUsed: used,
+		GasPowerLeft: gasPowerLeft,
+		PayloadHash:  e.PayloadHash(),
+		Size:         e.Size(),
+		BuildTime:    s.clock.Now().Sub(start),
+	}
+	for i, tx := range txs {
+		res.Txs[i] = tx.Hash()
+	}
+	s.record(res)
+	log.Info("Shadow event computed", "id", res.ID, "parents", len(res.Parents), "txs", len(res.Txs),
+		"skipped", skipped, "gas", used, "left", gasPowerLeft, "elapsed", res.BuildTime)
+	return &res
+}
+
+// setParents sets the self-parent, the freshest heads as the other parents, and the
+// seq and lamport following them.
+func (s *ShadowEmitter) setParents(me *inter.MutableEventPayload, rules opera.Rules) {
+	heads := append([]dag.Event(nil), s.world.Heads()...)
+	sort.SliceStable(heads, func(i, j int) bool {
+		return heads[i].Lamport() > heads[j].Lamport()
+	})
+
+	parents := hash.Events{}
+	lamport := idx.Lamport(0)
+	seq := idx.Event(1)
+	if s.last != nil {
+		parents.Add(s.last.ID())
+		lamport = s.last.Lamport()
+		seq = s.last.Seq() + 1
+	}
+	for _, h := range heads {
+		if idx.Event(len(parents)) >= rules.Dag.MaxParents {
+			break
+		}
+		// the real events of the creator (if it's already a validator) would fork the shadow self-parent chain
+		if h.Creator() == s.cfg.Creator {
+			continue
+		}
+		parents.Add(h.ID())
+		if h.Lamport() > lamport {
+			lamport = h.Lamport()
+		}
+	}
+	me.SetParents(parents)
+	me.SetSeq(seq)
+	me.SetLamport(lamport + 1)
+}
+
+func (s *ShadowEmitter) record(e ShadowEvent) {
+	if len(s.history) < s.cfg.History {
+		s.history = append(s.history, e)
+	} else {
+		s.history[s.next] = e
+		s.next = (s.next + 1) % len(s.history)
+	}
+
+	s.stats.Events++
+	s.stats.Txs += uint64(len(e.Txs))
+	s.stats.SkippedTxs += uint64(e.SkippedTxs)
+	s.stats.GasPowerUsed += e.GasPowerUsed
+	if e.BuildTime > s.stats.MaxBuildTime {
+		s.stats.MaxBuildTime = e.BuildTime
+	}
+}
+
+// Events returns up to n last computed events, the most recent first.
+func (s *ShadowEmitter) Events(n int) []ShadowEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n <= 0 || n > len(s.history) {
+		n = len(s.history)
+	}
+	res := make([]ShadowEvent, n)
+	for i := range res {
+		res[i] = s.history[(s.next+len(s.history)-1-i)%len(s.history)]
+	}
+	return res
+}
+
+// Stats returns the totals of the shadow emission.
+func (s *ShadowEmitter) Stats() ShadowStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stats
+}
+
+// PublicShadowAPI exposes the events computed in the shadow mode over RPC.
+type PublicShadowAPI struct {
+	s *ShadowEmitter
+}
+
+// NewPublicShadowAPI creates the shadow mode API.
+func NewPublicShadowAPI(s *ShadowEmitter) *PublicShadowAPI {
+	return &PublicShadowAPI{s}
+}
+
+// ShadowAPIs returns the RPC APIs of the shadow mode.
+func ShadowAPIs(s *ShadowEmitter) []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "validator",
+			Version:   "1.0",
+			Service:   NewPublicShadowAPI(s),
+			Public:    true,
+		},
+	}
+}
+
+// RPCShadowEvent is the RPC representation of ShadowEvent.
+type RPCShadowEvent struct {
+	ID           hexutil.Bytes                         `json:"id"`
+	Epoch        hexutil.Uint64                        `json:"epoch"`
+	Seq          hexutil.Uint64                        `json:"seq"`
+	Lamport      hexutil.Uint64                        `json:"lamport"`
+	Parents      []hexutil.Bytes                       `json:"parents"`
+	CreationTime hexutil.Uint64                        `json:"creationTime"`
+	Txs          []common.Hash                         `json:"transactions"`
+	SkippedTxs   hexutil.Uint64                        `json:"skippedTransactions"`
+	GasPowerUsed hexutil.Uint64                        `json:"gasPowerUsed"`
+	GasPowerLeft [inter.GasPowerConfigs]hexutil.Uint64 `json:"gasPowerLeft"`
+	PayloadHash  common.Hash                           `json:"payloadHash"`
+	Size         hexutil.Uint64                        `json:"size"`
+	BuildTime    string                                `json:"buildTime"`
+}
+
+// ShadowEvents returns up to n last events the validator would have emitted, the most recent first.
+// All the kept events are returned if n is 0.
+func (api *PublicShadowAPI) ShadowEvents(n int) []RPCShadowEvent {
+	events := api.s.Events(n)
+	res := make([]RPCShadowEvent, len(events))
+	for i, e := range events {
+		res[i] = RPCShadowEvent{
+			ID:           e.ID.Bytes(),
+			Epoch:        hexutil.Uint64(e.Epoch),
+			Seq:          hexutil.Uint64(e.Seq),
+			Lamport:      hexutil.Uint64(e.Lamport),
+			Parents:      make([]hexutil.Bytes, len(e.Parents)),
+			CreationTime: hexutil.Uint64(e.CreationTime),
+			Txs:          e.Txs,
+			SkippedTxs:   hexutil.Uint64(e.SkippedTxs),
+			GasPowerUsed: hexutil.Uint64(e.GasPowerUsed),
+			PayloadHash:  common.Hash(e.PayloadHash),
+			Size:         hexutil.Uint64(e.Size),
+			BuildTime:    e.BuildTime.String(),
+		}
+		for j, p := range e.Parents {
+			res[i].Parents[j] = p.Bytes()
+		}
+		for j, gas := range e.GasPowerLeft.Gas {
+			res[i].GasPowerLeft[j] = hexutil.Uint64(gas)
+		}
+	}
+	return res
+}
+
+// ShadowStats returns the totals of the shadow emission.
+func (api *PublicShadowAPI) ShadowStats() map[string]interface{} {
+	stats := api.s.Stats()
+	return map[string]interface{}{
+		"events":       hexutil.Uint64(stats.Events),
+		"transactions": hexutil.Uint64(stats.Txs),
+		"skipped":      hexutil.Uint64(stats.SkippedTxs),
+		"gasPowerUsed": hexutil.Uint64(stats.GasPowerUsed),
+		"starved":      hexutil.Uint64(stats.Starved),
+		"maxBuildTime": stats.MaxBuildTime.String(),
+	}
+}
@@ -0,0 +1,174 @@
+package emitter
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/dag"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+type testShadowWorld struct {
+	rules   opera.Rules
+	epoch   idx.Epoch
+	heads   []dag.Event
+	pending types.Transactions
+	gas     uint64
+}
+
+func (w *testShadowWorld) Rules() opera.Rules             { return w.rules }
+func (w *testShadowWorld) Epoch() idx.Epoch               { return w.epoch }
+func (w *testShadowWorld) Heads() []dag.Event             { return w.heads }
+func (w *testShadowWorld) PendingTxs() types.Transactions { return w.pending }
+func (w *testShadowWorld) GasPowerLeft() inter.GasPowerLeft {
+	return inter.GasPowerLeft{Gas: [2]uint64{w.gas, w.gas}}
+}
+
+func testHead(creator idx.ValidatorID, lamport idx.Lamport) dag.Event {
+	e := &inter.MutableEventPayload{}
+	e.SetVersion(1)
+	e.SetEpoch(1)
+	e.SetCreator(creator)
+	e.SetSeq(1)
+	e.SetLamport(lamport)
+	return e.Build()
+}
+
+func testTxs(n int) types.Transactions {
+	txs := make(types.Transactions, n)
+	for i := range txs {
+		txs[i] = types.NewTx(&types.LegacyTx{Nonce: uint64(i), Gas: 21000, GasPrice: big.NewInt(1)})
+	}
+	return txs
+}
+
+func TestGasPowerUsed(t *testing.T) {
+	rules := opera.FakeNetRules()
+	gas := rules.Economy.Gas
+
+	me := &inter.MutableEventPayload{}
+	if got := GasPowerUsed(rules, me); got != gas.EventGas {
+		t.Fatalf("empty event: got %d, want %d", got, gas.EventGas)
+	}
+	parents := hash.Events{}
+	for i := 0; i < int(rules.Dag.MaxFreeParents)+2; i++ {
+		parents.Add(hash.Event{byte(i + 1)})
+	}
+	me.SetParents(parents)
+	me.SetTxs(testTxs(2))
+	me.SetExtra([]byte{1, 2, 3})
+	want := gas.EventGas + 2*gas.ParentGas + 3*gas.ExtraDataGas + 2*21000
+	if got := GasPowerUsed(rules, me); got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
+
+func TestShadowEmitter(t *testing.T) {
+	rules := opera.FakeNetRules()
+	world := &testShadowWorld{
+		rules:   rules,
+		epoch:   1,
+		heads:   []dag.Event{testHead(2, 5), testHead(3, 7)},
+		pending: testTxs(3),
+		gas:     1e9,
+	}
+	c := clock.NewManual(time.Unix(1600000000, 0))
+	s := NewShadowEmitter(ShadowConfig{Creator: 1, Intervals: EmitIntervals{Min: time.Second, Max: time.Minute}}, world, c)
+
+	first := s.Tick()
+	if first == nil {
+		t.Fatal("first event isn't computed")
+	}
+	if first.Seq != 1 || first.Lamport != 8 || len(first.Parents) != 2 || first.Parents[0] != world.heads[1].ID() {
+		t.Fatalf("unexpected first event %+v", first)
+	}
+	if len(first.Txs) != 3 || first.GasPowerUsed != GasPowerUsed(rules, shadowPayload(t, first, world)) {
+		t.Fatalf("unexpected first event payload %+v", first)
+	}
+	if first.GasPowerLeft.Min() != world.gas-first.GasPowerUsed {
+		t.Fatalf("gas power isn't spent: %v", first.GasPowerLeft)
+	}
+
+	if s.Tick() != nil {
+		t.Fatal("event is computed before the min interval")
+	}
+	c.Advance(time.Second)
+	second := s.Tick()
+	if second == nil || second.Seq != 2 || second.Parents[0] != first.ID || second.Lamport != first.Lamport+1 {
+		t.Fatalf("self-parent chain is broken: %+v", second)
+	}
+
+	// the transactions beyond the gas power are left for the next events
+	world.gas = rules.Economy.Gas.EventGas + 3*rules.Economy.Gas.ParentGas + 21000
+	c.Advance(time.Second)
+	third := s.Tick()
+	if third == nil || len(third.Txs) != 1 || third.SkippedTxs != 2 {
+		t.Fatalf("transactions aren't trimmed to the gas power: %+v", third)
+	}
+	world.gas = 0
+	c.Advance(time.Second)
+	if s.Tick() != nil {
+		t.Fatal("event is computed without gas power")
+	}
+
+	// the self-parent chain restarts with the epoch
+	world.gas = 1e9
+	world.epoch = 2
+	world.heads = nil
+	fourth := s.Tick()
+	if fourth == nil || fourth.Seq != 1 || fourth.Lamport != 1 || len(fourth.Parents) != 0 {
+		t.Fatalf("unexpected first event of the epoch %+v", fourth)
+	}
+
+	events := s.Events(2)
+	if len(events) != 2 || events[0].ID != fourth.ID || events[1].ID != third.ID {
+		t.Fatalf("unexpected history %+v", events)
+	}
+	stats := s.Stats()
+	if stats.Events != 4 || stats.Txs != 3+3+1+3 || stats.SkippedTxs != 2 || stats.Starved != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+// shadowPayload rebuilds the event payload of the shadow event, for the gas power calculation.
+func shadowPayload(t *testing.T, e *ShadowEvent, w *testShadowWorld) *inter.MutableEventPayload {
+	t.Helper()
+	me := &inter.MutableEventPayload{}
+	me.SetParents(e.Parents)
+	me.SetTxs(w.pending[:len(e.Txs)])
+	return me
+}
+
+func TestShadowHistory(t *testing.T) {
+	world := &testShadowWorld{rules: opera.FakeNetRules(), epoch: 1, gas: 1e9}
+	c := clock.NewManual(time.Unix(1600000000, 0))
+	s := NewShadowEmitter(ShadowConfig{Creator: 1, Intervals: EmitIntervals{Max: time.Second}, History: 3}, world, c)
+
+	for i := 0; i < 5; i++ {
+		if s.Tick() == nil {
+			t.Fatalf("event %d isn't computed", i)
+		}
+		c.Advance(time.Second)
+	}
+	events := s.Events(0)
+	if len(events) != 3 {
+		t.Fatalf("history isn't bounded: %d events", len(events))
+	}
+	for i, e := range events {
+		if want := idx.Event(5 - i); e.Seq != want {
+			t.Fatalf("event %d: got seq %d, want %d", i, e.Seq, want)
+		}
+	}
+
+	api := NewPublicShadowAPI(s)
+	if got := api.ShadowEvents(1); len(got) != 1 || uint64(got[0].Seq) != 5 {
+		t.Fatalf("unexpected RPC events %+v", got)
+	}
+}
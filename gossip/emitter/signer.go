@@ -0,0 +1,103 @@
+package emitter
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"fmt"
+	"sync"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/rony4d/go-opera-asset/inter/validatorpk"
+)
+
+// signer.go implements the validator key the emitter signs the events with.
+//
+// Overview:
+//   The validator's key may be rotated while the node runs (see `opera validator rotate-key`).
+//   The new key becomes active at an epoch boundary, so the signer is given it in advance as
+//   the pending key of its activation epoch, and signs the events of that epoch (and of the later
+//   ones) with it. So the validator never signs an event of an epoch by the key of another epoch,
+//   however late the emitter learns about the epoch change.
+
+// Signer signs the events with the validator key. It's safe for concurrent use.
+type Signer struct {
+	mu sync.Mutex
+
+	pubkey validatorpk.PubKey
+	key    *ecdsa.PrivateKey
+
+	// the pending key, active since the epoch
+	nextPubkey validatorpk.PubKey
+	nextKey    *ecdsa.PrivateKey
+	since      idx.Epoch
+}
+
+// NewSigner creates the signer of the key. The key must match the public key.
+func NewSigner(pubkey validatorpk.PubKey, key *ecdsa.PrivateKey) (*Signer, error) {
+	if err := checkKey(pubkey, key); err != nil {
+		return nil, err
+	}
+	return &Signer{pubkey: pubkey, key: key}, nil
+}
+
+func checkKey(pubkey validatorpk.PubKey, key *ecdsa.PrivateKey) error {
+	if pubkey.Type != validatorpk.Types.Secp256k1 || !bytes.Equal(pubkey.Raw, crypto.FromECDSAPub(&key.PublicKey)) {
+		return fmt.Errorf("private key doesn't match the public key %s", pubkey)
+	}
+	return nil
+}
+
+// SetPending makes the signer sign the events of the epoch since, and of the later epochs, with
+// another key. The key must match the public key. It replaces the previous pending key, if any.
+func (s *Signer) SetPending(since idx.Epoch, pubkey validatorpk.PubKey, key *ecdsa.PrivateKey) error {
+	if err := checkKey(pubkey, key); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextPubkey = pubkey
+	s.nextKey = key
+	s.since = since
+	return nil
+}
+
+// activate makes the pending key the current one if it's active in the epoch.
+func (s *Signer) activate(epoch idx.Epoch) {
+	if s.nextKey != nil && epoch >= s.since {
+		s.pubkey, s.key = s.nextPubkey, s.nextKey
+		s.nextPubkey, s.nextKey = validatorpk.PubKey{}, nil
+	}
+}
+
+// PubKey returns the public key the events of the epoch are signed with.
+func (s *Signer) PubKey(epoch idx.Epoch) validatorpk.PubKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.activate(epoch)
+	return s.pubkey
+}
+
+// Latest returns the public key of the latest epoch: the pending key if any, the current one otherwise.
+func (s *Signer) Latest() validatorpk.PubKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.nextKey != nil {
+		return s.nextPubkey
+	}
+	return s.pubkey
+}
+
+// Sign signs the digest of an event of the epoch by the key of the epoch,
+// the signature is R|S (64 bytes).
+func (s *Signer) Sign(epoch idx.Epoch, digest []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.activate(epoch)
+	sig, err := crypto.Sign(digest, s.key)
+	if err != nil {
+		return nil, err
+	}
+	return sig[:64], nil
+}
@@ -0,0 +1,57 @@
+package emitter
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/rony4d/go-opera-asset/inter/validatorpk"
+)
+
+func TestSigner(t *testing.T) {
+	oldKey, _ := crypto.GenerateKey()
+	newKey, _ := crypto.GenerateKey()
+	oldPub := validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: crypto.FromECDSAPub(&oldKey.PublicKey)}
+	newPub := validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: crypto.FromECDSAPub(&newKey.PublicKey)}
+
+	if _, err := NewSigner(oldPub, newKey); err == nil {
+		t.Fatal("signer of a key which doesn't match the public key")
+	}
+	s, err := NewSigner(oldPub, oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := crypto.Keccak256([]byte("event"))
+	verify := func(epoch idx.Epoch, pub validatorpk.PubKey) {
+		t.Helper()
+		sig, err := s.Sign(epoch, digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sig) != 64 || !crypto.VerifySignature(pub.Raw, digest, sig) {
+			t.Fatalf("event of epoch %d isn't signed by %s", epoch, pub)
+		}
+		if s.PubKey(epoch).String() != pub.String() {
+			t.Fatalf("key of epoch %d isn't %s", epoch, pub)
+		}
+	}
+	verify(5, oldPub)
+
+	if err := s.SetPending(7, newPub, oldKey); err == nil {
+		t.Fatal("pending key which doesn't match the public key")
+	}
+	if s.Latest().String() != oldPub.String() {
+		t.Fatal("rejected pending key is set")
+	}
+	if err := s.SetPending(7, newPub, newKey); err != nil {
+		t.Fatal(err)
+	}
+	if s.Latest().String() != newPub.String() {
+		t.Fatal("pending key isn't the latest one")
+	}
+	// the old key signs until the activation epoch
+	verify(6, oldPub)
+	verify(7, newPub)
+	verify(8, newPub)
+}
@@ -0,0 +1,22 @@
+package emitter
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// PickTxs returns the pending transactions which may be included into the next event.
+// Nothing is picked while the network is paused (opera.Upgrades.Paused): the emitter
+// keeps emitting empty events at the Max interval, so the validator isn't flagged offline.
+// At most Dag.MaxTxsPerEvent transactions are picked, the rest are left for the next events
+// (the rules of the releases before the limit have zero, which isn't a limit).
+func PickTxs(rules opera.Rules, pending types.Transactions) types.Transactions {
+	if rules.Upgrades.Paused {
+		return nil
+	}
+	if max := int(rules.Dag.MaxTxsPerEvent); max != 0 && len(pending) > max {
+		return pending[:max]
+	}
+	return pending
+}
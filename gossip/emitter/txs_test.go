@@ -0,0 +1,59 @@
+package emitter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/rony4d/go-opera-asset/opera"
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+func TestPickTxs_Paused(t *testing.T) {
+	rules := opera.FakeNetRules()
+	pending := types.Transactions{types.NewTx(&types.LegacyTx{Nonce: 1})}
+
+	if len(PickTxs(rules, pending)) != 1 {
+		t.Fatal("txs aren't picked on a live network")
+	}
+
+	rules.Upgrades.Paused = true
+	txs := PickTxs(rules, pending)
+	if len(txs) != 0 {
+		t.Fatal("txs are picked on a paused network")
+	}
+
+	// the validator still emits empty events to stay alive
+	c := clock.NewManual(time.Unix(1600000000, 0))
+	p := NewPacer(c, EmitIntervals{Min: time.Second, Max: time.Minute})
+	p.Emitted()
+	c.Advance(time.Second)
+	if p.Ready(len(txs) != 0) {
+		t.Fatal("empty event is ready before the max interval")
+	}
+	c.Advance(time.Minute)
+	if !p.Ready(len(txs) != 0) {
+		t.Fatal("empty event isn't ready after the max interval")
+	}
+}
+
+func TestPickTxs_MaxTxsPerEvent(t *testing.T) {
+	rules := opera.FakeNetRules()
+	rules.Dag.MaxTxsPerEvent = 2
+	pending := types.Transactions{
+		types.NewTx(&types.LegacyTx{Nonce: 1}),
+		types.NewTx(&types.LegacyTx{Nonce: 2}),
+		types.NewTx(&types.LegacyTx{Nonce: 3}),
+	}
+
+	txs := PickTxs(rules, pending)
+	if len(txs) != 2 || txs[0] != pending[0] || txs[1] != pending[1] {
+		t.Fatalf("picked %d txs, want the first 2", len(txs))
+	}
+
+	rules.Dag.MaxTxsPerEvent = 0 // no limit
+	if txs := PickTxs(rules, pending); len(txs) != 3 {
+		t.Fatalf("picked %d txs without a limit, want all 3", len(txs))
+	}
+}
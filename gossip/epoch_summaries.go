@@ -0,0 +1,108 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+	"github.com/rony4d/go-opera-asset/inter/keys"
+)
+
+// epoch_summaries.go persists the records of sealed epochs.
+//
+// Overview:
+//   An iblockproc.EpochSummary is created when an epoch is sealed and never changes afterwards.
+//   Summaries are keyed by keys.Epoch, i.e. the big-endian epoch number, so they are iterated in the epochs order.
+//   The last sealed epoch is remembered separately, to serve "latest" without a reverse iteration.
+//   The counters of the current epoch (iblockproc.EpochCounters), which the summary is created
+//   from, are kept here too, as they aren't a part of the consensus state.
+
+var (
+	// lastSummaryKey is the key of the last stored epoch. It's shorter than any epoch key.
+	lastSummaryKey = []byte("l")
+	// countersKey is the key of the current epoch counters. It's shorter than any epoch key.
+	countersKey = []byte("c")
+)
+
+// EpochSummaries is the storage of the sealed epochs records.
+type EpochSummaries struct {
+	db kvdb.Store
+}
+
+// NewEpochSummaries wraps the DB table.
+func NewEpochSummaries(db kvdb.Store) *EpochSummaries {
+	return &EpochSummaries{db}
+}
+
+// Set stores the summary of a sealed epoch.
+func (s *EpochSummaries) Set(summary iblockproc.EpochSummary) {
+	b, err := rlp.EncodeToBytes(&summary)
+	if err != nil {
+		log.Crit("Failed to encode epoch summary", "err", err)
+	}
+	if err := s.db.Put(keys.Epoch(summary.Epoch), b); err != nil {
+		log.Crit("Failed to put epoch summary", "err", err)
+	}
+	if summary.Epoch >= s.Last() {
+		if err := s.db.Put(lastSummaryKey, summary.Epoch.Bytes()); err != nil {
+			log.Crit("Failed to put last epoch summary", "err", err)
+		}
+	}
+}
+
+// Get returns the summary of the epoch, or nil if it isn't stored.
+func (s *EpochSummaries) Get(epoch idx.Epoch) *iblockproc.EpochSummary {
+	b, err := s.db.Get(keys.Epoch(epoch))
+	if err != nil {
+		log.Crit("Failed to get epoch summary", "err", err)
+	}
+	if b == nil {
+		return nil
+	}
+	summary := &iblockproc.EpochSummary{}
+	if err := rlp.DecodeBytes(b, summary); err != nil {
+		log.Crit("Failed to decode epoch summary", "epoch", epoch, "err", err)
+	}
+	return summary
+}
+
+// Last returns the last sealed epoch which has a summary, or 0 if there are none.
+func (s *EpochSummaries) Last() idx.Epoch {
+	b, err := s.db.Get(lastSummaryKey)
+	if err != nil {
+		log.Crit("Failed to get last epoch summary", "err", err)
+	}
+	if b == nil {
+		return 0
+	}
+	return idx.BytesToEpoch(b)
+}
+
+// SetCounters stores the counters of the current epoch.
+func (s *EpochSummaries) SetCounters(c iblockproc.EpochCounters) {
+	b, err := rlp.EncodeToBytes(&c)
+	if err != nil {
+		log.Crit("Failed to encode epoch counters", "err", err)
+	}
+	if err := s.db.Put(countersKey, b); err != nil {
+		log.Crit("Failed to put epoch counters", "err", err)
+	}
+}
+
+// Counters returns the counters of the current epoch, or empty counters if none are stored.
+func (s *EpochSummaries) Counters() iblockproc.EpochCounters {
+	var c iblockproc.EpochCounters
+	b, err := s.db.Get(countersKey)
+	if err != nil {
+		log.Crit("Failed to get epoch counters", "err", err)
+	}
+	if b == nil {
+		return c
+	}
+	if err := rlp.DecodeBytes(b, &c); err != nil {
+		log.Crit("Failed to decode epoch counters", "err", err)
+	}
+	return c
+}
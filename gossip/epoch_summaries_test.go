@@ -0,0 +1,53 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/Fantom-foundation/lachesis-base/lachesis"
+
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+)
+
+func TestEpochSummaries(t *testing.T) {
+	s := NewEpochSummaries(memorydb.New())
+	if s.Last() != 0 || s.Get(1) != nil {
+		t.Fatal("empty storage has summaries")
+	}
+
+	s.Set(iblockproc.EpochSummary{
+		Epoch:      2,
+		FirstBlock: 10,
+		LastBlock:  19,
+		Gas:        100,
+		Validators: []iblockproc.ValidatorSummary{{ID: 1, Events: 5, Uptime: 7}},
+		Cheaters:   lachesis.Cheaters{3},
+	})
+	s.Set(iblockproc.EpochSummary{Epoch: 1, FirstBlock: 1, LastBlock: 9})
+
+	if s.Last() != 2 {
+		t.Fatalf("unexpected last epoch %d", s.Last())
+	}
+	got := s.Get(2)
+	if got == nil || got.Blocks() != 10 || got.Gas != 100 || got.Events() != 5 || len(got.Cheaters) != 1 {
+		t.Fatalf("unexpected summary %+v", got)
+	}
+	if got := s.Get(1); got == nil || got.Blocks() != 9 {
+		t.Fatalf("unexpected summary %+v", got)
+	}
+}
+
+func TestEpochSummaries_Counters(t *testing.T) {
+	s := NewEpochSummaries(memorydb.New())
+	if c := s.Counters(); c.Epoch != 0 || len(c.Events) != 0 {
+		t.Fatalf("empty storage has counters %+v", c)
+	}
+	s.SetCounters(iblockproc.EpochCounters{Epoch: 3, Events: []uint32{4, 0, 5}})
+	s.Set(iblockproc.EpochSummary{Epoch: 2})
+	if c := s.Counters(); c.Epoch != 3 || len(c.Events) != 3 || c.Events[0] != 4 || c.Events[2] != 5 {
+		t.Fatalf("unexpected counters %+v", c)
+	}
+	if s.Last() != 2 {
+		t.Fatalf("counters are mixed up with the summaries: last epoch %d", s.Last())
+	}
+}
@@ -0,0 +1,253 @@
+package gossip
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+// firehose.go streams every confirmed event and finalized block to external consumers.
+//
+// Overview:
+//   Every published item gets the next cursor (a sequence number starting from 0) and is kept
+//   in a ring buffer of the most recent items. A consumer subscribes from a cursor: the buffered
+//   items from the cursor are replayed, then the live items follow, so a reconnecting indexer
+//   resumes from the last cursor it has processed without gaps or polling.
+//
+//   The items carry the canonical bytes (CSER of the event payload, RLP of the block) along with
+//   the decoded JSON. A consumer which doesn't keep up is disconnected with ErrSlowConsumer
+//   instead of slowing down the node, and it resumes from its last cursor.
+//
+//   The stream is served over WebSocket/IPC as the firehose_subscribe("stream", cursor) subscription.
+
+var (
+	// ErrCursorTooOld is returned if the items from the cursor aren't buffered anymore.
+	ErrCursorTooOld = errors.New("cursor is older than the buffered items")
+	// ErrCursorAhead is returned if the cursor is beyond the last published item.
+	ErrCursorAhead = errors.New("cursor is ahead of the stream")
+	// ErrSlowConsumer is the reason of closing a subscription which doesn't keep up with the stream.
+	ErrSlowConsumer = errors.New("consumer is too slow")
+)
+
+// firehoseQueue is the number of live items queued for a subscriber on top of the replayed ones.
+const firehoseQueue = 1024
+
+// Item kinds of the firehose.
+const (
+	FirehoseEvent = "event"
+	FirehoseBlock = "block"
+)
+
+// FirehoseItem is a confirmed event or a finalized block.
+type FirehoseItem struct {
+	Cursor  hexutil.Uint64         `json:"cursor"`
+	Kind    string                 `json:"kind"`
+	Raw     hexutil.Bytes          `json:"raw"`
+	Decoded map[string]interface{} `json:"decoded"`
+}
+
+// FirehoseSub is a subscription to the firehose.
+type FirehoseSub struct {
+	fh    *Firehose
+	items chan FirehoseItem
+	err   error
+}
+
+// Items returns the channel of the items. It's closed once the subscription ends.
+func (s *FirehoseSub) Items() <-chan FirehoseItem {
+	return s.items
+}
+
+// Err returns the reason of the subscription end, if it was ended by the firehose.
+// It must be called only after the items channel is closed.
+func (s *FirehoseSub) Err() error {
+	return s.err
+}
+
+// Unsubscribe ends the subscription.
+func (s *FirehoseSub) Unsubscribe() {
+	s.fh.mu.Lock()
+	defer s.fh.mu.Unlock()
+	s.fh.drop(s, nil)
+}
+
+// Firehose is the stream of confirmed events and finalized blocks. It's safe for concurrent use.
+type Firehose struct {
+	mu   sync.Mutex
+	next uint64         // cursor of the next item
+	buf  []FirehoseItem // ring buffer of the last items, item i is at i % len(buf)
+	subs map[*FirehoseSub]struct{}
+}
+
+// NewFirehose creates the firehose which buffers the `size` most recent items for resuming.
+func NewFirehose(size int) *Firehose {
+	return &Firehose{
+		buf:  make([]FirehoseItem, size),
+		subs: make(map[*FirehoseSub]struct{}),
+	}
+}
+
+// PublishEvent streams the confirmed event.
+func (fh *Firehose) PublishEvent(e *inter.EventPayload) error {
+	raw, err := e.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	decoded, err := inter.RPCMarshalEventPayload(e, true, false)
+	if err != nil {
+		return err
+	}
+	fh.publish(FirehoseEvent, raw, decoded)
+	return nil
+}
+
+// PublishBlock streams the finalized block.
+func (fh *Firehose) PublishBlock(n idx.Block, b *inter.Block) error {
+	raw, err := rlp.EncodeToBytes(b)
+	if err != nil {
+		return err
+	}
+	skipped := make([]hexutil.Uint64, len(b.SkippedTxs))
+	for i, s := range b.SkippedTxs {
+		skipped[i] = hexutil.Uint64(s)
+	}
+	fh.publish(FirehoseBlock, raw, map[string]interface{}{
+		"number":       hexutil.Uint64(n),
+		"timestamp":    hexutil.Uint64(b.Time.Unix()),
+		"atropos":      hexutil.Bytes(b.Atropos.Bytes()),
+		"events":       inter.EventIDsToHex(b.Events),
+		"transactions": b.Txs,
+		"skippedTxs":   skipped,
+		"gasUsed":      hexutil.Uint64(b.GasUsed),
+		"stateRoot":    hexutil.Bytes(b.Root.Bytes()),
+	})
+	return nil
+}
+
+func (fh *Firehose) publish(kind string, raw []byte, decoded map[string]interface{}) {
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+
+	item := FirehoseItem{
+		Cursor:  hexutil.Uint64(fh.next),
+		Kind:    kind,
+		Raw:     raw,
+		Decoded: decoded,
+	}
+	fh.buf[fh.next%uint64(len(fh.buf))] = item
+	fh.next++
+	for s := range fh.subs {
+		select {
+		case s.items <- item:
+		default:
+			fh.drop(s, ErrSlowConsumer)
+		}
+	}
+}
+
+// drop ends the subscription. It must be called under the lock.
+func (fh *Firehose) drop(s *FirehoseSub, err error) {
+	if _, ok := fh.subs[s]; !ok {
+		return
+	}
+	delete(fh.subs, s)
+	s.err = err
+	close(s.items)
+}
+
+// Next returns the cursor of the next published item.
+func (fh *Firehose) Next() uint64 {
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+	return fh.next
+}
+
+// Subscribe streams the items starting from the cursor.
+func (fh *Firehose) Subscribe(cursor uint64) (*FirehoseSub, error) {
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+
+	if cursor > fh.next {
+		return nil, ErrCursorAhead
+	}
+	if fh.next-cursor > uint64(len(fh.buf)) {
+		return nil, ErrCursorTooOld
+	}
+	s := &FirehoseSub{
+		fh:    fh,
+		items: make(chan FirehoseItem, len(fh.buf)+firehoseQueue),
+	}
+	for c := cursor; c < fh.next; c++ {
+		s.items <- fh.buf[c%uint64(len(fh.buf))]
+	}
+	fh.subs[s] = struct{}{}
+	return s, nil
+}
+
+// PublicFirehoseAPI serves the firehose subscription.
+type PublicFirehoseAPI struct {
+	fh *Firehose
+}
+
+// NewPublicFirehoseAPI creates the firehose API.
+func NewPublicFirehoseAPI(fh *Firehose) *PublicFirehoseAPI {
+	return &PublicFirehoseAPI{fh}
+}
+
+// FirehoseAPIs returns the RPC APIs of the firehose.
+func FirehoseAPIs(fh *Firehose) []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "firehose",
+			Version:   "1.0",
+			Service:   NewPublicFirehoseAPI(fh),
+			Public:    true,
+		},
+	}
+}
+
+// Stream subscribes to the confirmed events and finalized blocks, starting from the cursor,
+// or from the next item if the cursor isn't specified.
+func (api *PublicFirehoseAPI) Stream(ctx context.Context, cursor *hexutil.Uint64) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	from := api.fh.Next()
+	if cursor != nil {
+		from = uint64(*cursor)
+	}
+	sub, err := api.fh.Subscribe(from)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case item, ok := <-sub.Items():
+				if !ok {
+					// the consumer resumes from the last received cursor
+					return
+				}
+				if err := notifier.Notify(rpcSub.ID, item); err != nil {
+					return
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
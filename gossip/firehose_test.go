@@ -0,0 +1,138 @@
+package gossip
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+func firehoseEvent(lamport idx.Lamport) *inter.EventPayload {
+	e := inter.MutableEventPayload{}
+	e.SetVersion(1)
+	e.SetEpoch(1)
+	e.SetCreator(1)
+	e.SetSeq(idx.Event(lamport))
+	e.SetLamport(lamport)
+	e.SetFrame(1)
+	e.SetPayloadHash(inter.EmptyPayloadHash(1))
+	return e.Build()
+}
+
+func TestFirehose(t *testing.T) {
+	fh := NewFirehose(4)
+	e := firehoseEvent(1)
+	if err := fh.PublishEvent(e); err != nil {
+		t.Fatal(err)
+	}
+	block := &inter.Block{Atropos: e.ID(), Events: hash.Events{e.ID()}, SkippedTxs: []uint32{1}, GasUsed: 21000}
+	if err := fh.PublishBlock(1, block); err != nil {
+		t.Fatal(err)
+	}
+
+	// resume from the first item
+	sub, err := fh.Subscribe(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := <-sub.Items()
+	var decoded inter.EventPayload
+	if err := decoded.UnmarshalBinary(got.Raw); err != nil || got.Kind != FirehoseEvent || got.Cursor != 0 || decoded.ID() != e.ID() {
+		t.Fatalf("unexpected event item %+v, err %v", got, err)
+	}
+	got = <-sub.Items()
+	var decodedBlock inter.Block
+	if err := rlp.DecodeBytes(got.Raw, &decodedBlock); err != nil || got.Kind != FirehoseBlock || decodedBlock.Atropos != e.ID() {
+		t.Fatalf("unexpected block item %+v, err %v", got, err)
+	}
+	if got.Decoded["number"] != hexutil.Uint64(1) {
+		t.Fatalf("unexpected decoded block %+v", got.Decoded)
+	}
+
+	// live items follow the replayed ones
+	for l := idx.Lamport(2); l <= 5; l++ {
+		if err := fh.PublishEvent(firehoseEvent(l)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for c := hexutil.Uint64(2); c <= 5; c++ {
+		if got := <-sub.Items(); got.Cursor != c {
+			t.Fatalf("expected cursor %d, got %d", c, got.Cursor)
+		}
+	}
+	sub.Unsubscribe()
+	if _, ok := <-sub.Items(); ok || sub.Err() != nil {
+		t.Fatalf("subscription isn't closed cleanly, err %v", sub.Err())
+	}
+
+	if _, err := fh.Subscribe(1); err != ErrCursorTooOld {
+		t.Fatalf("expected %v, got %v", ErrCursorTooOld, err)
+	}
+	if _, err := fh.Subscribe(7); err != ErrCursorAhead {
+		t.Fatalf("expected %v, got %v", ErrCursorAhead, err)
+	}
+
+	// a consumer which doesn't read is disconnected
+	slow, err := fh.Subscribe(fh.Next())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i <= 4+firehoseQueue; i++ {
+		fh.publish(FirehoseEvent, nil, nil)
+	}
+	for range slow.Items() {
+	}
+	if slow.Err() != ErrSlowConsumer {
+		t.Fatalf("expected %v, got %v", ErrSlowConsumer, slow.Err())
+	}
+}
+
+func TestFirehoseAPI(t *testing.T) {
+	fh := NewFirehose(16)
+	for l := idx.Lamport(1); l <= 3; l++ {
+		if err := fh.PublishEvent(firehoseEvent(l)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	for _, api := range FirehoseAPIs(fh) {
+		if err := server.RegisterName(api.Namespace, api.Service); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	items := make(chan FirehoseItem, 16)
+	sub, err := client.Subscribe(ctx, "firehose", items, "stream", hexutil.Uint64(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+	if err := fh.PublishEvent(firehoseEvent(4)); err != nil {
+		t.Fatal(err)
+	}
+	for c := hexutil.Uint64(1); c <= 3; c++ {
+		select {
+		case got := <-items:
+			if got.Cursor != c || got.Kind != FirehoseEvent || got.Decoded["lamport"] == nil {
+				t.Fatalf("unexpected item %+v", got)
+			}
+		case err := <-sub.Err():
+			t.Fatal(err)
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package gossip
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/metrics"
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+// known_events.go suppresses duplicate events before their payloads are decoded.
+//
+// Overview:
+//   With a high peers degree, the same event is delivered many times. Decoding a full
+//   payload (txs, MPs, votes) only to find out that the event is already known wastes
+//   CPU exactly when the node is the busiest, i.e. during bursts.
+//
+//   KnownEvents remembers IDs of recently seen events of the current epoch:
+//   - a short-term bloom filter answers "definitely new" without touching the LRU
+//   - an LRU of event IDs gives the exact answer for the "maybe known" case
+//   Both are dropped when the epoch is sealed, as events of past epochs are rejected anyway.
+//
+//   Incoming raw events are checked via FilterRaw, which decodes only the event header
+//   (inter.UnmarshalEventHeader) to get the event ID.
+
+// DefaultKnownEventsSize is the default number of event IDs remembered per epoch.
+const DefaultKnownEventsSize = 16384
+
+var (
+	knownEventsHitCounter  = metrics.NewRegisteredCounter("gossip/known_events/hit", nil)
+	knownEventsMissCounter = metrics.NewRegisteredCounter("gossip/known_events/miss", nil)
+	knownEventsBloomSkips  = metrics.NewRegisteredCounter("gossip/known_events/bloom_skip", nil)
+)
+
+// KnownEvents is an epoch-scoped set of recently seen event IDs.
+// It's safe for concurrent use.
+type KnownEvents struct {
+	mu sync.Mutex
+
+	epoch idx.Epoch
+	size  int
+	ids   *lru.Cache // hash.Event -> struct{}
+
+	// The bloom has 2 generations, each of them covers `size` insertions.
+	// Together they always cover all the IDs in the LRU, so a negative bloom answer is exact.
+	bloomCur  *eventsBloom
+	bloomPrev *eventsBloom
+
+	hits   uint64
+	misses uint64
+}
+
+// NewKnownEvents creates the set which remembers up to size event IDs.
+func NewKnownEvents(size int) *KnownEvents {
+	if size <= 0 {
+		size = DefaultKnownEventsSize
+	}
+	k := &KnownEvents{
+		size: size,
+	}
+	k.reset()
+	return k
+}
+
+func (k *KnownEvents) reset() {
+	k.ids, _ = lru.New(k.size)
+	k.bloomCur = newEventsBloom(k.size)
+	k.bloomPrev = newEventsBloom(k.size)
+}
+
+// SetEpoch drops all the remembered IDs if the epoch has changed.
+func (k *KnownEvents) SetEpoch(epoch idx.Epoch) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.epoch == epoch {
+		return
+	}
+	k.epoch = epoch
+	k.reset()
+}
+
+// Epoch returns the epoch of the remembered IDs.
+func (k *KnownEvents) Epoch() idx.Epoch {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return k.epoch
+}
+
+// Has returns true if the event was seen recently. Updates the hit-rate metrics.
+func (k *KnownEvents) Has(id hash.Event) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	known := k.has(id)
+	if known {
+		k.hits++
+		knownEventsHitCounter.Inc(1)
+	} else {
+		k.misses++
+		knownEventsMissCounter.Inc(1)
+	}
+	return known
+}
+
+func (k *KnownEvents) has(id hash.Event) bool {
+	if !k.bloomCur.mayContain(id) && !k.bloomPrev.mayContain(id) {
+		knownEventsBloomSkips.Inc(1)
+		return false
+	}
+	// contains doesn't update the recentness, so the LRU evicts in the insertion order
+	return k.ids.Contains(id)
+}
+
+// Add remembers the event ID. IDs of other epochs are ignored.
+func (k *KnownEvents) Add(id hash.Event) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if id.Epoch() != k.epoch || k.has(id) {
+		return
+	}
+	if k.bloomCur.count >= k.size {
+		k.bloomPrev = k.bloomCur
+		k.bloomCur = newEventsBloom(k.size)
+	}
+	k.bloomCur.add(id)
+	k.ids.Add(id, struct{}{})
+}
+
+// FilterRaw decodes only the header of a serialized event payload and checks whether
+// the event is already known, so the full payload is decoded only for new events.
+// Returns the decoded header, which may be used to validate the event before the body is decoded.
+func (k *KnownEvents) FilterRaw(raw []byte) (header *inter.Event, known bool, err error) {
+	header, err = inter.UnmarshalEventHeader(raw)
+	if err != nil {
+		return nil, false, err
+	}
+	return header, k.Has(header.ID()), nil
+}
+
+// HitRate returns the share of checked events which were already known.
+func (k *KnownEvents) HitRate() float64 {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	total := k.hits + k.misses
+	if total == 0 {
+		return 0
+	}
+	return float64(k.hits) / float64(total)
+}
+
+// eventsBloom is a simple bloom filter over event IDs.
+// Event IDs are hashes (except for the epoch and lamport prefix), so their bytes
+// are used directly as the bloom hash functions.
+type eventsBloom struct {
+	bits  []uint64
+	count int
+}
+
+// eventsBloomHashes is the number of hash functions, ~10 bits per element keep
+// the false positive rate around 1%.
+const (
+	eventsBloomHashes     = 3
+	eventsBloomBitsPerKey = 10
+)
+
+func newEventsBloom(size int) *eventsBloom {
+	return &eventsBloom{
+		bits: make([]uint64, (size*eventsBloomBitsPerKey)/64+1),
+	}
+}
+
+func (b *eventsBloom) positions(id hash.Event) [eventsBloomHashes]uint64 {
+	var res [eventsBloomHashes]uint64
+	total := uint64(len(b.bits)) * 64
+	for i := range res {
+		// skip the first 8 bytes which contain the epoch and lamport
+		res[i] = binary.LittleEndian.Uint64(id[8+i*8:]) % total
+	}
+	return res
+}
+
+func (b *eventsBloom) add(id hash.Event) {
+	for _, pos := range b.positions(id) {
+		b.bits[pos/64] |= 1 << (pos % 64)
+	}
+	b.count++
+}
+
+func (b *eventsBloom) mayContain(id hash.Event) bool {
+	for _, pos := range b.positions(id) {
+		if b.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
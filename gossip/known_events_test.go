@@ -0,0 +1,60 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+)
+
+func fakeEventID(epoch idx.Epoch, seed byte) hash.Event {
+	var h hash.Hash
+	for i := range h {
+		h[i] = seed + byte(i)*31
+	}
+	return hash.BytesToEvent(append(append(epoch.Bytes(), idx.Lamport(1).Bytes()...), h[8:]...))
+}
+
+func TestKnownEvents(t *testing.T) {
+	k := NewKnownEvents(4)
+	k.SetEpoch(1)
+
+	ids := make([]hash.Event, 10)
+	for i := range ids {
+		ids[i] = fakeEventID(1, byte(i))
+	}
+
+	for i, id := range ids {
+		if k.Has(id) {
+			t.Fatalf("event %d is unexpectedly known", i)
+		}
+		k.Add(id)
+		if !k.Has(id) {
+			t.Fatalf("event %d isn't known after adding", i)
+		}
+	}
+	// the oldest IDs are evicted
+	if k.Has(ids[0]) {
+		t.Errorf("evicted event is still known")
+	}
+	if !k.Has(ids[len(ids)-1]) {
+		t.Errorf("last event isn't known")
+	}
+
+	// other epochs are ignored
+	other := fakeEventID(2, 100)
+	k.Add(other)
+	if k.Has(other) {
+		t.Errorf("event of another epoch is known")
+	}
+
+	// epoch change drops everything
+	k.SetEpoch(2)
+	if k.Has(ids[len(ids)-1]) {
+		t.Errorf("event of the previous epoch is known")
+	}
+
+	if rate := k.HitRate(); rate <= 0 || rate >= 1 {
+		t.Errorf("unexpected hit rate %f", rate)
+	}
+}
@@ -0,0 +1,215 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+)
+
+// listen.go binds the p2p listeners and advertises them in the node record.
+//
+// Overview:
+//   A node listens either on a single address (IPv4, IPv6, or "::" which accepts both
+//   families on dual-stack hosts), or on an IPv4 address plus a separate IPv6 address.
+//   The separate binds are IPv6-only and IPv4-only sockets, so they never collide with
+//   each other on the same port, and the accepted connections of both are merged into
+//   a single net.Listener.
+//
+//   Both endpoints are advertised in the ENR: the "ip" and "ip6" entries carry the
+//   external addresses of each family, the "tcp"/"udp" entries the shared port, so peers
+//   in IPv6-only networks can dial the node as well as IPv4-only ones.
+
+var (
+	// ErrNotIPv4 is returned if an IPv4 address is expected, but another one is given.
+	ErrNotIPv4 = errors.New("not an IPv4 address")
+	// ErrNotIPv6 is returned if an IPv6 address is expected, but another one is given.
+	ErrNotIPv6 = errors.New("not an IPv6 address")
+	// ErrListenerClosed is returned by Accept of a closed listener.
+	ErrListenerClosed = errors.New("listener is closed")
+)
+
+// ListenConfig is the config of the p2p listeners.
+type ListenConfig struct {
+	Addr        string // address to bind, "0.0.0.0" or "::" (both families) if empty
+	Addr6       string // IPv6 address bound in addition to Addr, empty if none
+	Port        int
+	ExternalIP  net.IP // advertised IPv4 address, nil if unknown
+	ExternalIP6 net.IP // advertised IPv6 address, nil if unknown
+}
+
+// bind is a single listening socket.
+type bind struct {
+	network string // "tcp" binds both families for the unspecified address, "tcp4"/"tcp6" only one
+	addr    string
+}
+
+// binds returns the sockets to listen on.
+func (c ListenConfig) binds() ([]bind, error) {
+	if c.Port < 0 || c.Port > 65535 {
+		return nil, fmt.Errorf("port %d is out of range", c.Port)
+	}
+	if c.ExternalIP != nil && c.ExternalIP.To4() == nil {
+		return nil, fmt.Errorf("external IP %v: %w", c.ExternalIP, ErrNotIPv4)
+	}
+	if c.ExternalIP6 != nil && c.ExternalIP6.To4() != nil {
+		return nil, fmt.Errorf("external IPv6 %v: %w", c.ExternalIP6, ErrNotIPv6)
+	}
+	port := strconv.Itoa(c.Port)
+	if c.Addr6 == "" {
+		addr := c.Addr
+		if addr == "" {
+			addr = "::"
+		}
+		if net.ParseIP(addr) == nil {
+			return nil, fmt.Errorf("invalid listen address %q", addr)
+		}
+		return []bind{{"tcp", net.JoinHostPort(addr, port)}}, nil
+	}
+
+	addr4 := c.Addr
+	if addr4 == "" {
+		addr4 = "0.0.0.0"
+	}
+	if ip := net.ParseIP(addr4); ip == nil || ip.To4() == nil {
+		return nil, fmt.Errorf("listen address %q: %w", addr4, ErrNotIPv4)
+	}
+	if ip := net.ParseIP(c.Addr6); ip == nil || ip.To4() != nil {
+		return nil, fmt.Errorf("listen address %q: %w", c.Addr6, ErrNotIPv6)
+	}
+	return []bind{
+		{"tcp4", net.JoinHostPort(addr4, port)},
+		{"tcp6", net.JoinHostPort(c.Addr6, port)},
+	}, nil
+}
+
+// Validate checks the addresses of the config.
+func (c ListenConfig) Validate() error {
+	_, err := c.binds()
+	return err
+}
+
+// Listen binds the configured sockets. If the port is 0, the IPv6 socket gets the same
+// port as the one picked by the system for the IPv4 socket.
+func Listen(c ListenConfig) (net.Listener, error) {
+	binds, err := c.binds()
+	if err != nil {
+		return nil, err
+	}
+	first, err := net.Listen(binds[0].network, binds[0].addr)
+	if err != nil {
+		return nil, err
+	}
+	if len(binds) == 1 {
+		return first, nil
+	}
+	c.Port = first.Addr().(*net.TCPAddr).Port
+	second, err := net.Listen(binds[1].network, net.JoinHostPort(c.Addr6, strconv.Itoa(c.Port)))
+	if err != nil {
+		first.Close()
+		return nil, err
+	}
+	return newMultiListener(first, second), nil
+}
+
+// acceptResult is a connection accepted by one of the merged listeners.
+type acceptResult struct {
+	conn net.Conn
+	err  error
+}
+
+// multiListener merges the connections accepted by several listeners.
+type multiListener struct {
+	listeners []net.Listener
+	accepted  chan acceptResult
+	closed    chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func newMultiListener(listeners ...net.Listener) *multiListener {
+	ml := &multiListener{
+		listeners: listeners,
+		accepted:  make(chan acceptResult),
+		closed:    make(chan struct{}),
+	}
+	for _, l := range listeners {
+		ml.wg.Add(1)
+		go ml.acceptLoop(l)
+	}
+	return ml
+}
+
+func (ml *multiListener) acceptLoop(l net.Listener) {
+	defer ml.wg.Done()
+	for {
+		conn, err := l.Accept()
+		select {
+		case ml.accepted <- acceptResult{conn, err}:
+		case <-ml.closed:
+			if conn != nil {
+				conn.Close()
+			}
+			return
+		}
+		if err != nil {
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Temporary() {
+				continue
+			}
+			return
+		}
+	}
+}
+
+// Accept returns the next connection accepted by any of the listeners.
+func (ml *multiListener) Accept() (net.Conn, error) {
+	select {
+	case res := <-ml.accepted:
+		return res.conn, res.err
+	case <-ml.closed:
+		return nil, ErrListenerClosed
+	}
+}
+
+// Close closes all the listeners.
+func (ml *multiListener) Close() error {
+	var err error
+	ml.closeOnce.Do(func() {
+		close(ml.closed)
+		for _, l := range ml.listeners {
+			if e := l.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+		ml.wg.Wait()
+	})
+	return err
+}
+
+// Addr returns the address of the first (IPv4) listener.
+func (ml *multiListener) Addr() net.Addr {
+	return ml.listeners[0].Addr()
+}
+
+// Advertise sets the endpoints of the config into the node record.
+// port is the actual listening port, i.e. the one picked by the system if the configured port is 0.
+func Advertise(ln *enode.LocalNode, c ListenConfig, port int) {
+	for _, ip := range []net.IP{c.ExternalIP, c.ExternalIP6} {
+		if ip != nil {
+			ln.SetStaticIP(ip)
+		}
+	}
+	// without external IPs, a specific bind address is the best guess
+	for _, addr := range []string{c.Addr, c.Addr6} {
+		if ip := net.ParseIP(addr); ip != nil && !ip.IsUnspecified() {
+			ln.SetFallbackIP(ip)
+		}
+	}
+	ln.Set(enr.TCP(port))
+	ln.SetFallbackUDP(port)
+}
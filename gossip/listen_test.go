@@ -0,0 +1,133 @@
+package gossip
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+)
+
+func TestListenConfigValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ListenConfig
+		want error // nil if valid, errAny if any error
+	}{
+		{"dual-stack wildcard", ListenConfig{Addr: "::", Port: 5050}, nil},
+		{"default address", ListenConfig{Port: 5050}, nil},
+		{"IPv4 only", ListenConfig{Addr: "0.0.0.0", Port: 5050, ExternalIP: net.ParseIP("1.2.3.4")}, nil},
+		{"IPv6 only", ListenConfig{Addr: "2001:db8::1", Port: 5050, ExternalIP6: net.ParseIP("2001:db8::1")}, nil},
+		{"separate binds", ListenConfig{Addr: "0.0.0.0", Addr6: "::", Port: 5050}, nil},
+		{"IPv6 as IPv4 bind", ListenConfig{Addr: "::", Addr6: "::", Port: 5050}, ErrNotIPv4},
+		{"IPv4 as IPv6 bind", ListenConfig{Addr6: "127.0.0.1", Port: 5050}, ErrNotIPv6},
+		{"IPv6 as external IPv4", ListenConfig{Port: 5050, ExternalIP: net.ParseIP("::1")}, ErrNotIPv4},
+		{"IPv4 as external IPv6", ListenConfig{Port: 5050, ExternalIP6: net.ParseIP("1.2.3.4")}, ErrNotIPv6},
+		{"hostname", ListenConfig{Addr: "localhost", Port: 5050}, errAny},
+		{"port out of range", ListenConfig{Port: 70000}, errAny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			switch {
+			case tt.want == nil && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.want == errAny && err == nil:
+				t.Fatal("expected an error")
+			case tt.want != nil && tt.want != errAny && !errors.Is(err, tt.want):
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+var errAny = errors.New("any error")
+
+func TestListenSeparateBinds(t *testing.T) {
+	if l, err := net.Listen("tcp6", "[::1]:0"); err != nil {
+		t.Skip("IPv6 isn't available:", err)
+	} else {
+		l.Close()
+	}
+
+	l, err := Listen(ListenConfig{Addr: "127.0.0.1", Addr6: "::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	for _, addr := range []string{"127.0.0.1", "::1"} {
+		conn, err := net.Dial("tcp", net.JoinHostPort(addr, strconv.Itoa(port)))
+		if err != nil {
+			t.Fatalf("dial %s: %v", addr, err)
+		}
+		accepted, err := l.Accept()
+		if err != nil {
+			t.Fatal(err)
+		}
+		remote := accepted.RemoteAddr().(*net.TCPAddr).IP
+		if !remote.Equal(net.ParseIP(addr)) {
+			t.Fatalf("accepted %v, want a connection from %s", remote, addr)
+		}
+		accepted.Close()
+		conn.Close()
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Accept(); err != ErrListenerClosed {
+		t.Fatalf("Accept after Close = %v", err)
+	}
+}
+
+func TestAdvertise(t *testing.T) {
+	db, err := enode.OpenDB("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	key, _ := crypto.GenerateKey()
+	ln := enode.NewLocalNode(db, key)
+
+	Advertise(ln, ListenConfig{
+		Addr:        "0.0.0.0",
+		Addr6:       "::",
+		ExternalIP:  net.ParseIP("1.2.3.4"),
+		ExternalIP6: net.ParseIP("2001:db8::1"),
+	}, 5050)
+
+	var (
+		ip4 enr.IPv4
+		ip6 enr.IPv6
+		tcp enr.TCP
+		udp enr.UDP
+	)
+	rec := ln.Node().Record()
+	if err := rec.Load(&ip4); err != nil || !net.IP(ip4).Equal(net.ParseIP("1.2.3.4")) {
+		t.Fatalf("ip = %v, %v", net.IP(ip4), err)
+	}
+	if err := rec.Load(&ip6); err != nil || !net.IP(ip6).Equal(net.ParseIP("2001:db8::1")) {
+		t.Fatalf("ip6 = %v, %v", net.IP(ip6), err)
+	}
+	if err := rec.Load(&tcp); err != nil || tcp != 5050 {
+		t.Fatalf("tcp = %d, %v", tcp, err)
+	}
+	if err := rec.Load(&udp); err != nil || udp != 5050 {
+		t.Fatalf("udp = %d, %v", udp, err)
+	}
+
+	// an IPv6-only node is advertised by its bind address
+	ln = enode.NewLocalNode(db, key)
+	Advertise(ln, ListenConfig{Addr: "2001:db8::2"}, 5050)
+	if err := ln.Node().Record().Load(&ip6); err != nil || !net.IP(ip6).Equal(net.ParseIP("2001:db8::2")) {
+		t.Fatalf("ip6 = %v, %v", net.IP(ip6), err)
+	}
+	if err := ln.Node().Record().Load(&ip4); err == nil {
+		t.Fatalf("unexpected ip %v", net.IP(ip4))
+	}
+}
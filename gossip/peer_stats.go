@@ -0,0 +1,320 @@
+package gossip
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+// peer_stats.go tracks the usefulness of every connected peer.
+//
+// Overview:
+//   Every peer has its own counters of the delivered and sent events, of the traffic, of the
+//   invalid messages and of the messages corrupted on the wire (see checksums.go). The
+//   announce-to-delivery latency is measured from the first NewEventIDsMsg announcing an event
+//   to the moment the event arrives from the same peer.
+//   Announces which are never followed by the event are forgotten by the LRU.
+//
+//   The counters are served by admin_peerStats, and mirrored into the metrics registry
+//   under the "gossip/peers/<peer id>/" prefix, i.e. the peer ID acts as the metric label.
+//   The metrics of a peer are unregistered when it disconnects.
+
+// ErrUnknownPeer is returned if the stats of a not connected peer are requested.
+var ErrUnknownPeer = errors.New("unknown peer")
+
+// maxPendingAnnounces is the number of announced event IDs remembered per peer to measure the latency.
+const maxPendingAnnounces = 4096
+
+// PeerStatsSnapshot is a copy of the peer's counters.
+type PeerStatsSnapshot struct {
+	Peer           string
+	Connected      time.Time
+	EventsSent     uint64
+	EventsReceived uint64
+	InvalidMsgs    uint64
+	CorruptedMsgs  uint64
+	BytesSent      uint64
+	BytesReceived  uint64
+	AvgLatency     time.Duration
+}
+
+// PeerStats are the counters of a single peer. It's safe for concurrent use.
+type PeerStats struct {
+	id    string
+	clock clock.Clock
+
+	mu        sync.Mutex
+	snap      PeerStatsSnapshot
+	latencies uint64
+	latency   time.Duration // sum of the measured latencies
+	announced *lru.Cache    // hash.Event -> time.Time
+
+	metricNames []string
+	eventsIn    metrics.Counter
+	eventsOut   metrics.Counter
+	invalid     metrics.Counter
+	corrupted   metrics.Counter
+	bytesIn     metrics.Counter
+	bytesOut    metrics.Counter
+	latencyHist metrics.Timer
+}
+
+func newPeerStats(id string, c clock.Clock) *PeerStats {
+	announced, _ := lru.New(maxPendingAnnounces)
+	s := &PeerStats{
+		id:        id,
+		clock:     c,
+		announced: announced,
+		snap: PeerStatsSnapshot{
+			Peer:      id,
+			Connected: c.Now(),
+		},
+	}
+	prefix := "gossip/peers/" + id + "/"
+	counter := func(name string) metrics.Counter {
+		s.metricNames = append(s.metricNames, prefix+name)
+		return metrics.GetOrRegisterCounter(prefix+name, nil)
+	}
+	s.eventsIn = counter("events/in")
+	s.eventsOut = counter("events/out")
+	s.invalid = counter("invalid")
+	s.corrupted = counter("corrupted")
+	s.bytesIn = counter("bytes/in")
+	s.bytesOut = counter("bytes/out")
+	s.metricNames = append(s.metricNames, prefix+"latency")
+	s.latencyHist = metrics.GetOrRegisterTimer(prefix+"latency", nil)
+	return s
+}
+
+// MsgReceived accounts an incoming message of the size.
+func (s *PeerStats) MsgReceived(size uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snap.BytesReceived += size
+	s.bytesIn.Inc(int64(size))
+}
+
+// MsgSent accounts an outgoing message of the size.
+func (s *PeerStats) MsgSent(size uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snap.BytesSent += size
+	s.bytesOut.Inc(int64(size))
+}
+
+// InvalidMsg accounts a malformed message or an invalid event received from the peer.
+func (s *PeerStats) InvalidMsg() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snap.InvalidMsgs++
+	s.invalid.Inc(1)
+}
+
+// CorruptedMsg accounts a message received from the peer corrupted on the wire.
+func (s *PeerStats) CorruptedMsg() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snap.CorruptedMsgs++
+	s.corrupted.Inc(1)
+}
+
+// EventsAnnounced remembers when the peer announced the events. Only the first announce counts.
+func (s *PeerStats) EventsAnnounced(ids hash.Events) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	for _, id := range ids {
+		s.announced.ContainsOrAdd(id, now)
+	}
+}
+
+// EventsReceived accounts the events delivered by the peer, and measures the latency
+// of the events which were announced by it beforehand.
+func (s *PeerStats) EventsReceived(ids hash.Events) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	for _, id := range ids {
+		if at, ok := s.announced.Get(id); ok {
+			s.announced.Remove(id)
+			latency := now.Sub(at.(time.Time))
+			s.latencies++
+			s.latency += latency
+			s.latencyHist.Update(latency)
+		}
+	}
+	s.snap.EventsReceived += uint64(len(ids))
+	s.eventsIn.Inc(int64(len(ids)))
+}
+
+// EventsSent accounts the events sent to the peer.
+func (s *PeerStats) EventsSent(num int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snap.EventsSent += uint64(num)
+	s.eventsOut.Inc(int64(num))
+}
+
+// Snapshot returns a copy of the counters.
+func (s *PeerStats) Snapshot() PeerStatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap := s.snap
+	if s.latencies != 0 {
+		snap.AvgLatency = s.latency / time.Duration(s.latencies)
+	}
+	return snap
+}
+
+func (s *PeerStats) unregisterMetrics() {
+	for _, name := range s.metricNames {
+		metrics.DefaultRegistry.Unregister(name)
+	}
+}
+
+// PeersStats keeps the stats of the connected peers. It's safe for concurrent use.
+type PeersStats struct {
+	clock clock.Clock
+
+	mu    sync.RWMutex
+	peers map[string]*PeerStats
+}
+
+// NewPeersStats creates the stats registry.
+func NewPeersStats(c clock.Clock) *PeersStats {
+	return &PeersStats{
+		clock: c,
+		peers: make(map[string]*PeerStats),
+	}
+}
+
+// Register starts tracking a newly connected peer.
+func (ps *PeersStats) Register(id string) *PeerStats {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if s, ok := ps.peers[id]; ok {
+		return s
+	}
+	s := newPeerStats(id, ps.clock)
+	ps.peers[id] = s
+	return s
+}
+
+// Unregister drops the stats of a disconnected peer.
+func (ps *PeersStats) Unregister(id string) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if s, ok := ps.peers[id]; ok {
+		s.unregisterMetrics()
+		delete(ps.peers, id)
+	}
+}
+
+// Get returns the stats of the peer, or nil if it isn't connected.
+func (ps *PeersStats) Get(id string) *PeerStats {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	return ps.peers[id]
+}
+
+// Snapshot returns the counters of all the connected peers, ordered by the peer ID.
+func (ps *PeersStats) Snapshot() []PeerStatsSnapshot {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	res := make([]PeerStatsSnapshot, 0, len(ps.peers))
+	for _, s := range ps.peers {
+		res = append(res, s.Snapshot())
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Peer < res[j].Peer
+	})
+	return res
+}
+
+// RPCPeerStats is the JSON representation of PeerStatsSnapshot.
+type RPCPeerStats struct {
+	Peer           string         `json:"peer"`
+	ConnectedFor   hexutil.Uint64 `json:"connectedFor"` // seconds
+	EventsSent     hexutil.Uint64 `json:"eventsSent"`
+	EventsReceived hexutil.Uint64 `json:"eventsReceived"`
+	InvalidMsgs    hexutil.Uint64 `json:"invalidMsgs"`
+	CorruptedMsgs  hexutil.Uint64 `json:"corruptedMsgs"`
+	BytesSent      hexutil.Uint64 `json:"bytesSent"`
+	BytesReceived  hexutil.Uint64 `json:"bytesReceived"`
+	AvgLatency     hexutil.Uint64 `json:"avgLatency"` // milliseconds
+}
+
+// PrivateAdminAPI serves the peers stats to the node operator.
+type PrivateAdminAPI struct {
+	stats *PeersStats
+}
+
+// NewPrivateAdminAPI creates the admin API of the gossip service.
+func NewPrivateAdminAPI(stats *PeersStats) *PrivateAdminAPI {
+	return &PrivateAdminAPI{stats}
+}
+
+// APIs returns the RPC APIs of the gossip service.
+func APIs(stats *PeersStats) []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateAdminAPI(stats),
+		},
+	}
+}
+
+func (api *PrivateAdminAPI) rpcPeerStats(s PeerStatsSnapshot) RPCPeerStats {
+	return RPCPeerStats{
+		Peer:           s.Peer,
+		ConnectedFor:   hexutil.Uint64(api.stats.clock.Now().Sub(s.Connected) / time.Second),
+		EventsSent:     hexutil.Uint64(s.EventsSent),
+		EventsReceived: hexutil.Uint64(s.EventsReceived),
+		InvalidMsgs:    hexutil.Uint64(s.InvalidMsgs),
+		CorruptedMsgs:  hexutil.Uint64(s.CorruptedMsgs),
+		BytesSent:      hexutil.Uint64(s.BytesSent),
+		BytesReceived:  hexutil.Uint64(s.BytesReceived),
+		AvgLatency:     hexutil.Uint64(s.AvgLatency / time.Millisecond),
+	}
+}
+
+// PeerStats returns the message statistics of the connected peers.
+// If the peer ID is specified, only the stats of that peer are returned.
+func (api *PrivateAdminAPI) PeerStats(ctx context.Context, peer *string) ([]RPCPeerStats, error) {
+	if peer != nil {
+		s := api.stats.Get(*peer)
+		if s == nil {
+			return nil, ErrUnknownPeer
+		}
+		return []RPCPeerStats{api.rpcPeerStats(s.Snapshot())}, nil
+	}
+	snaps := api.stats.Snapshot()
+	res := make([]RPCPeerStats, len(snaps))
+	for i, s := range snaps {
+		res[i] = api.rpcPeerStats(s)
+	}
+	return res, nil
+}
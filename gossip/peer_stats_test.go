@@ -0,0 +1,55 @@
+package gossip
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+func TestPeersStats(t *testing.T) {
+	c := clock.NewManual(time.Unix(1600000000, 0))
+	stats := NewPeersStats(c)
+	p := stats.Register("peer1")
+	stats.Register("peer0")
+
+	a, b := fakeEventID(1, 1), fakeEventID(1, 2)
+	p.EventsAnnounced(hash.Events{a, b})
+	c.Advance(100 * time.Millisecond)
+	p.EventsAnnounced(hash.Events{a}) // a repeated announce doesn't reset the latency
+	c.Advance(100 * time.Millisecond)
+	p.EventsReceived(hash.Events{a})
+	c.Advance(200 * time.Millisecond)
+	p.EventsReceived(hash.Events{b, fakeEventID(1, 3)}) // the last one wasn't announced
+	p.EventsSent(5)
+	p.MsgReceived(100)
+	p.MsgSent(50)
+	p.InvalidMsg()
+	p.CorruptedMsg()
+
+	snap := p.Snapshot()
+	if snap.EventsReceived != 3 || snap.EventsSent != 5 || snap.InvalidMsgs != 1 || snap.CorruptedMsgs != 1 ||
+		snap.BytesReceived != 100 || snap.BytesSent != 50 {
+		t.Fatalf("unexpected counters %+v", snap)
+	}
+	if snap.AvgLatency != 300*time.Millisecond {
+		t.Fatalf("average latency is %v, want 300ms", snap.AvgLatency)
+	}
+
+	api := NewPrivateAdminAPI(stats)
+	all, err := api.PeerStats(context.Background(), nil)
+	if err != nil || len(all) != 2 || all[0].Peer != "peer0" {
+		t.Fatalf("unexpected stats of all peers %+v, err %v", all, err)
+	}
+	if all[1].AvgLatency != 300 || all[1].ConnectedFor != 0 {
+		t.Fatalf("unexpected RPC stats %+v", all[1])
+	}
+
+	stats.Unregister("peer1")
+	if _, err := api.PeerStats(context.Background(), &p.id); err != ErrUnknownPeer {
+		t.Fatalf("expected %v, got %v", ErrUnknownPeer, err)
+	}
+}
@@ -0,0 +1,102 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+// Constants to match up protocol versions and messages
+const (
+	// FTM62 is the initial version of the gossip protocol.
+	FTM62 = 62
+	// FTM63 adds the capabilities negotiation into the handshake.
+	FTM63 = 63
+
+	// ProtocolName is the official short name of the protocol used during capability negotiation.
+	ProtocolName = "opera"
+)
+
+// ProtocolVersions are the supported versions of the protocol (first is primary).
+var ProtocolVersions = []uint{FTM63, FTM62}
+
+// protocolLengths are the number of implemented message corresponding to different protocol versions.
+var protocolLengths = map[uint]uint64{FTM62: EventsStreamResponse + 1, FTM63: EventsStreamResponse + 1}
+
+const protocolMaxMsgSize = inter.ProtocolMaxMsgSize // Maximum cap on the size of a protocol message
+
+// protocol message codes
+const (
+	HandshakeMsg = 0
+
+	// Signals about the current synchronization status.
+	// The current peer's status is used during packs downloading,
+	// and to estimate may peer be interested in the new event or not
+	// (based on peer's epoch).
+	ProgressMsg = 1
+
+	EvmTxsMsg         = 2
+	NewEvmTxHashesMsg = 3
+	GetEvmTxsMsg      = 4
+
+	// Non-aggressive events propagation. Signals about newly-connected
+	// batch of events, sending only their IDs.
+	NewEventIDsMsg = 5
+
+	// Request the batch of events by IDs
+	GetEventsMsg = 6
+	// Contains the batch of events.
+	// May be an answer to GetEventsMsg, or be sent during aggressive events propagation.
+	EventsMsg = 7
+
+	// Request a range of events by a selector
+	RequestEventsStream = 8
+	// Contains the requested events by RequestEventsStream
+	EventsStreamResponse = 9
+)
+
+// Errors of the handshake.
+var (
+	ErrNetworkIDMismatch       = errors.New("network ID mismatch")
+	ErrGenesisMismatch         = errors.New("genesis mismatch")
+	ErrProtocolVersionMismatch = errors.New("protocol version mismatch")
+	ErrNoCommonSerialization   = errors.New("no common event serialization version")
+)
+
+// handshakeData is the network packet for the initial handshake message
+type handshakeData struct {
+	ProtocolVersion uint32
+	NetworkID       uint64
+	Genesis         common.Hash
+
+	// Capabilities are advertised since FTM63. FTM62 peers don't send them,
+	// which decodes into nil, i.e. legacy capabilities. A peer which advertises
+	// no features sends the zero value, which isn't nil.
+	Capabilities *Capabilities `rlp:"optional"`
+}
+
+// capabilities returns the advertised capabilities, substituting the implicit ones of legacy peers.
+func (h *handshakeData) capabilities() Capabilities {
+	if h.ProtocolVersion < FTM63 || h.Capabilities == nil {
+		return LegacyCapabilities()
+	}
+	return *h.Capabilities
+}
+
+// checkHandshake validates the remote handshake against the local one, and returns
+// the capabilities which both the peers support.
+func checkHandshake(local, remote *handshakeData) (Capabilities, error) {
+	if local.NetworkID != remote.NetworkID {
+		return Capabilities{}, fmt.Errorf("%w: %d (!= %d)", ErrNetworkIDMismatch, remote.NetworkID, local.NetworkID)
+	}
+	if local.Genesis != remote.Genesis {
+		return Capabilities{}, fmt.Errorf("%w: %x (!= %x)", ErrGenesisMismatch, remote.Genesis[:8], local.Genesis[:8])
+	}
+	if _, ok := protocolLengths[uint(remote.ProtocolVersion)]; !ok {
+		return Capabilities{}, fmt.Errorf("%w: %d", ErrProtocolVersionMismatch, remote.ProtocolVersion)
+	}
+	return Negotiate(local.capabilities(), remote.capabilities())
+}
@@ -0,0 +1,105 @@
+package gossip
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestNegotiate(t *testing.T) {
+	local := Capabilities{MinSerialization: 0, MaxSerialization: 2, Flags: CapCompression | CapLlrServing}
+
+	got, err := Negotiate(local, Capabilities{MinSerialization: 1, MaxSerialization: 3, Flags: CapLlrServing | CapSnapshotServing})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Capabilities{MinSerialization: 1, MaxSerialization: 2, Flags: CapLlrServing}
+	if got != want {
+		t.Errorf("Negotiate() = %s, want %s", got, want)
+	}
+
+	_, err = Negotiate(local, Capabilities{MinSerialization: 3, MaxSerialization: 4})
+	if !errors.Is(err, ErrNoCommonSerialization) {
+		t.Errorf("Negotiate() error = %v, want %v", err, ErrNoCommonSerialization)
+	}
+}
+
+func TestCheckHandshake(t *testing.T) {
+	local := &handshakeData{
+		ProtocolVersion: FTM63,
+		NetworkID:       1,
+		Genesis:         common.Hash{1},
+		Capabilities:    capsPtr(LocalCapabilities(true, true)),
+	}
+
+	// legacy peer doesn't send capabilities
+	legacy := &handshakeData{ProtocolVersion: FTM62, NetworkID: 1, Genesis: common.Hash{1}}
+	b, err := rlp.EncodeToBytes(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded handshakeData
+	if err := rlp.DecodeBytes(b, &decoded); err != nil {
+		t.Fatalf("failed to decode legacy handshake: %v", err)
+	}
+	caps, err := checkHandshake(local, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if caps.Flags != 0 {
+		t.Errorf("legacy peer got flags %s", caps.Flags)
+	}
+
+	// upgraded peer
+	upgraded := *local
+	upgraded.Capabilities = capsPtr(LocalCapabilities(true, false))
+	caps, err = checkHandshake(local, &upgraded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !caps.Flags.Has(CapLlrServing) || caps.Flags.Has(CapSnapshotServing) {
+		t.Errorf("unexpected flags %s", caps.Flags)
+	}
+
+	// upgraded peer which advertises no features isn't a legacy one
+	bare := *local
+	bare.Capabilities = &Capabilities{}
+	b, err = rlp.EncodeToBytes(&bare)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded = handshakeData{}
+	if err := rlp.DecodeBytes(b, &decoded); err != nil {
+		t.Fatalf("failed to decode bare handshake: %v", err)
+	}
+	caps, err = checkHandshake(local, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Capabilities{}); caps != want {
+		t.Errorf("bare peer got %s, want %s", caps, want)
+	}
+
+	// mismatches
+	wrongNet := upgraded
+	wrongNet.NetworkID = 2
+	if _, err := checkHandshake(local, &wrongNet); !errors.Is(err, ErrNetworkIDMismatch) {
+		t.Errorf("checkHandshake() error = %v, want %v", err, ErrNetworkIDMismatch)
+	}
+	wrongGenesis := upgraded
+	wrongGenesis.Genesis = common.Hash{2}
+	if _, err := checkHandshake(local, &wrongGenesis); !errors.Is(err, ErrGenesisMismatch) {
+		t.Errorf("checkHandshake() error = %v, want %v", err, ErrGenesisMismatch)
+	}
+	wrongVersion := upgraded
+	wrongVersion.ProtocolVersion = 1
+	if _, err := checkHandshake(local, &wrongVersion); !errors.Is(err, ErrProtocolVersionMismatch) {
+		t.Errorf("checkHandshake() error = %v, want %v", err, ErrProtocolVersionMismatch)
+	}
+}
+
+func capsPtr(c Capabilities) *Capabilities {
+	return &c
+}
@@ -0,0 +1,306 @@
+package gossip
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+// serve_limits.go limits the heavy requests served to the peers.
+//
+// Overview:
+//   Serving the events by IDs, the events streams (epoch packs), state ranges or traces
+//   costs much more than asking for them, so a few peers could keep the node busy
+//   with the requests and starve the live processing.
+//
+//   Every heavy request takes an inflight slot before it's served: at most
+//   MaxInflightPerPeer requests of a peer and MaxInflight requests of all the peers are
+//   served at once. The requests waiting for a slot are queued, but at most
+//   MaxQueuedPerPeer of a peer; the rest are rejected with ErrTooManyRequests, which the
+//   protocol handler treats as a peer misbehaviour.
+//
+//   The size of a response is unknown until it's built, so the served bytes are charged
+//   to the peer's token bucket once the request is done: a peer which has been served more
+//   than BytesPerSec (with bursts up to BurstBytes) waits before its next request starts.
+//
+//   Peers which only consume are deprioritized: once a peer has been served more than
+//   LeechGrace bytes, while it has sent less than LeechRatio of that back (see PeerStats),
+//   the free slots are given to the other peers first.
+
+// ErrTooManyRequests is returned if a peer has too many heavy requests queued.
+var ErrTooManyRequests = errors.New("too many inflight requests")
+
+var (
+	serveInflightGauge        = metrics.NewRegisteredGauge("gossip/serve/inflight", nil)
+	serveRejectedCounter      = metrics.NewRegisteredCounter("gossip/serve/rejected", nil)
+	serveThrottledCounter     = metrics.NewRegisteredCounter("gossip/serve/throttled", nil)
+	serveDeprioritizedCounter = metrics.NewRegisteredCounter("gossip/serve/deprioritized", nil)
+)
+
+// ServeLimitsConfig is the config of the heavy requests limits. Zero values mean no limit.
+type ServeLimitsConfig struct {
+	MaxInflight        int     // requests served at once to all the peers
+	MaxInflightPerPeer int     // requests served at once to a single peer
+	MaxQueuedPerPeer   int     // requests of a single peer waiting for a slot
+	BytesPerSec        float64 // bytes served to a single peer per second
+	BurstBytes         int     // bytes which may be served to a peer at once, defaults to BytesPerSec
+	LeechRatio         float64 // min share of the served bytes a peer must send back to keep the priority
+	LeechGrace         uint64  // bytes served to a peer before LeechRatio applies
+}
+
+// DefaultServeLimitsConfig returns the default heavy requests limits.
+func DefaultServeLimitsConfig() ServeLimitsConfig {
+	return ServeLimitsConfig{
+		MaxInflight:        32,
+		MaxInflightPerPeer: 2,
+		MaxQueuedPerPeer:   8,
+		BytesPerSec:        4 * 1024 * 1024,
+		BurstBytes:         16 * 1024 * 1024,
+		LeechRatio:         0.01,
+		LeechGrace:         256 * 1024 * 1024,
+	}
+}
+
+// servePeer is the requests accounting of a single peer.
+type servePeer struct {
+	inflight int
+	queued   int
+	tokens   float64 // bytes which may be served, negative once the peer is over the rate
+	last     time.Time
+	gone     bool // the peer is disconnected
+}
+
+// serveWaiter is a request waiting for a slot.
+type serveWaiter struct {
+	peer  string
+	leech bool
+	ready chan struct{} // closed once the slot is granted
+}
+
+// ServeLimiter accounts the heavy requests of the peers. It's safe for concurrent use.
+type ServeLimiter struct {
+	cfg   ServeLimitsConfig
+	stats *PeersStats // traffic of the peers, to detect the leeches, may be nil
+	clock clock.Clock
+
+	mu       sync.Mutex
+	inflight int
+	peers    map[string]*servePeer
+	waiters  []*serveWaiter // in the arrival order
+}
+
+// NewServeLimiter creates the limiter of the heavy requests.
+func NewServeLimiter(cfg ServeLimitsConfig, stats *PeersStats, c clock.Clock) *ServeLimiter {
+	if cfg.BurstBytes <= 0 {
+		cfg.BurstBytes = int(cfg.BytesPerSec)
+	}
+	return &ServeLimiter{
+		cfg:   cfg,
+		stats: stats,
+		clock: c,
+		peers: make(map[string]*servePeer),
+	}
+}
+
+// Acquire waits until a heavy request of the peer may be served. The returned function must
+// be called with the size of the response once the request is served (or 0 if it's dropped).
+func (l *ServeLimiter) Acquire(ctx context.Context, peer string) (release func(served uint64), err error) {
+	l.mu.Lock()
+	p := l.peer(peer)
+	if l.cfg.MaxQueuedPerPeer > 0 && p.queued >= l.cfg.MaxQueuedPerPeer {
+		l.mu.Unlock()
+		serveRejectedCounter.Inc(1)
+		return nil, ErrTooManyRequests
+	}
+	p.queued++
+	delay := l.debt(p)
+	l.mu.Unlock()
+
+	if delay > 0 {
+		serveThrottledCounter.Inc(1)
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			l.mu.Lock()
+			p.queued--
+			l.forgetIdle(peer, p)
+			l.mu.Unlock()
+			return nil, ctx.Err()
+		}
+	}
+
+	w := &serveWaiter{
+		peer:  peer,
+		leech: l.isLeech(peer),
+		ready: make(chan struct{}),
+	}
+	if w.leech {
+		serveDeprioritizedCounter.Inc(1)
+	}
+	l.mu.Lock()
+	l.waiters = append(l.waiters, w)
+	l.dispatch()
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		select {
+		case <-w.ready:
+			// the slot is granted concurrently, give it back
+			l.done(peer, p, 0)
+		default:
+			l.removeWaiter(w)
+			p.queued--
+			l.forgetIdle(peer, p)
+		}
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func(served uint64) {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.done(peer, p, served)
+		})
+	}, nil
+}
+
+// Forget drops the accounting of a disconnected peer.
+// The requests still being served keep their slots until released.
+func (l *ServeLimiter) Forget(peer string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if p, ok := l.peers[peer]; ok {
+		p.gone = true
+		l.forgetIdle(peer, p)
+	}
+}
+
+// Inflight returns the number of the requests being served to the peer and to all the peers.
+func (l *ServeLimiter) Inflight(peer string) (peerInflight, total int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if p, ok := l.peers[peer]; ok {
+		peerInflight = p.inflight
+	}
+	return peerInflight, l.inflight
+}
+
+func (l *ServeLimiter) peer(id string) *servePeer {
+	p, ok := l.peers[id]
+	if !ok {
+		p = &servePeer{
+			tokens: float64(l.cfg.BurstBytes),
+			last:   l.clock.Now(),
+		}
+		l.peers[id] = p
+	}
+	p.gone = false
+	return p
+}
+
+// forgetIdle drops the accounting of the peer if it has no requests, and either no debt
+// or is disconnected.
+func (l *ServeLimiter) forgetIdle(id string, p *servePeer) {
+	if p.inflight != 0 || p.queued != 0 || l.peers[id] != p {
+		return
+	}
+	if p.gone || p.tokens >= float64(l.cfg.BurstBytes) {
+		delete(l.peers, id)
+	}
+}
+
+// debt refills the peer's token bucket and returns how long the peer must wait
+// until it isn't over the rate.
+func (l *ServeLimiter) debt(p *servePeer) time.Duration {
+	if l.cfg.BytesPerSec <= 0 {
+		return 0
+	}
+	now := l.clock.Now()
+	if elapsed := now.Sub(p.last); elapsed > 0 {
+		p.tokens += elapsed.Seconds() * l.cfg.BytesPerSec
+		if p.tokens > float64(l.cfg.BurstBytes) {
+			p.tokens = float64(l.cfg.BurstBytes)
+		}
+	}
+	p.last = now
+	if p.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-p.tokens / l.cfg.BytesPerSec * float64(time.Second))
+}
+
+// done frees the slot of a served request and charges the response to the peer.
+func (l *ServeLimiter) done(id string, p *servePeer, served uint64) {
+	p.inflight--
+	l.inflight--
+	serveInflightGauge.Update(int64(l.inflight))
+	if l.cfg.BytesPerSec > 0 {
+		l.debt(p)
+		p.tokens -= float64(served)
+	}
+	l.dispatch()
+	l.forgetIdle(id, p)
+}
+
+// dispatch grants the free slots to the waiting requests, the requests of the leeches last.
+func (l *ServeLimiter) dispatch() {
+	for _, leeches := range []bool{false, true} {
+		for i := 0; i < len(l.waiters); {
+			if l.cfg.MaxInflight > 0 && l.inflight >= l.cfg.MaxInflight {
+				return
+			}
+			w := l.waiters[i]
+			p := l.peers[w.peer]
+			if w.leech != leeches || (l.cfg.MaxInflightPerPeer > 0 && p.inflight >= l.cfg.MaxInflightPerPeer) {
+				i++
+				continue
+			}
+			l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+			p.queued--
+			p.inflight++
+			l.inflight++
+			serveInflightGauge.Update(int64(l.inflight))
+			close(w.ready)
+		}
+	}
+}
+
+func (l *ServeLimiter) removeWaiter(w *serveWaiter) {
+	for i, other := range l.waiters {
+		if other == w {
+			l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// isLeech returns true if the peer only consumes: it has been served more than LeechGrace
+// bytes, but has sent less than LeechRatio of them.
+func (l *ServeLimiter) isLeech(peer string) bool {
+	if l.stats == nil || l.cfg.LeechRatio <= 0 {
+		return false
+	}
+	s := l.stats.Get(peer)
+	if s == nil {
+		return false
+	}
+	snap := s.Snapshot()
+	if snap.BytesSent <= l.cfg.LeechGrace {
+		return false
+	}
+	return float64(snap.BytesReceived) < l.cfg.LeechRatio*float64(snap.BytesSent)
+}
@@ -0,0 +1,153 @@
+package gossip
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+// acquireAsync requests a slot in the background.
+func acquireAsync(l *ServeLimiter, ctx context.Context, peer string) <-chan func(uint64) {
+	res := make(chan func(uint64), 1)
+	go func() {
+		release, err := l.Acquire(ctx, peer)
+		if err != nil {
+			release = nil
+		}
+		res <- release
+	}()
+	return res
+}
+
+// waitQueued waits until the peer has n requests waiting for a slot.
+func waitQueued(t *testing.T, l *ServeLimiter, peer string, n int) {
+	t.Helper()
+	for i := 0; i < 1000; i++ {
+		l.mu.Lock()
+		queued := 0
+		if p, ok := l.peers[peer]; ok {
+			queued = p.queued
+		}
+		l.mu.Unlock()
+		if queued == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("peer %s has no %d queued requests", peer, n)
+}
+
+func mustAcquire(t *testing.T, l *ServeLimiter, peer string) func(uint64) {
+	t.Helper()
+	release, err := l.Acquire(context.Background(), peer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return release
+}
+
+func TestServeLimiterInflight(t *testing.T) {
+	l := NewServeLimiter(ServeLimitsConfig{MaxInflight: 3, MaxInflightPerPeer: 2, MaxQueuedPerPeer: 1}, nil, clock.Real{})
+
+	a1 := mustAcquire(t, l, "a")
+	mustAcquire(t, l, "a")
+	waiting := acquireAsync(l, context.Background(), "a")
+	waitQueued(t, l, "a", 1)
+	if _, err := l.Acquire(context.Background(), "a"); err != ErrTooManyRequests {
+		t.Fatalf("queue limit isn't applied: %v", err)
+	}
+
+	// the global limit
+	mustAcquire(t, l, "b")
+	waitingB := acquireAsync(l, context.Background(), "b")
+	waitQueued(t, l, "b", 1)
+
+	// the freed slot goes to the first waiting request
+	a1(100)
+	if release := <-waiting; release == nil {
+		t.Fatal("waiting request isn't served")
+	}
+	if peer, total := l.Inflight("a"); peer != 2 || total != 3 {
+		t.Fatalf("unexpected inflight: peer %d, total %d", peer, total)
+	}
+	select {
+	case <-waitingB:
+		t.Fatal("request is served above the global limit")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// a cancelled request leaves the queue
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := acquireAsync(l, ctx, "c")
+	waitQueued(t, l, "c", 1)
+	cancel()
+	if release := <-cancelled; release != nil {
+		t.Fatal("cancelled request is served")
+	}
+	if _, ok := l.peers["c"]; ok {
+		t.Fatal("idle peer isn't forgotten")
+	}
+}
+
+func TestServeLimiterRate(t *testing.T) {
+	c := clock.NewManual(time.Unix(1600000000, 0))
+	l := NewServeLimiter(ServeLimitsConfig{BytesPerSec: 1000, BurstBytes: 1000}, nil, c)
+
+	mustAcquire(t, l, "a")(3000)
+	l.mu.Lock()
+	delay := l.debt(l.peers["a"])
+	l.mu.Unlock()
+	if delay != 2*time.Second {
+		t.Fatalf("delay is %v, want 2s", delay)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(ctx, "a"); err != context.DeadlineExceeded {
+		t.Fatalf("request above the rate isn't delayed: %v", err)
+	}
+	// other peers aren't affected
+	mustAcquire(t, l, "b")(0)
+
+	c.Advance(2 * time.Second)
+	mustAcquire(t, l, "a")(0)
+
+	// the debt of a disconnected peer is dropped
+	mustAcquire(t, l, "c")(5000)
+	l.Forget("c")
+	if _, ok := l.peers["c"]; ok {
+		t.Fatal("disconnected peer isn't forgotten")
+	}
+}
+
+func TestServeLimiterLeeches(t *testing.T) {
+	c := clock.NewManual(time.Unix(1600000000, 0))
+	stats := NewPeersStats(c)
+	stats.Register("leech").MsgSent(2000)
+	provider := stats.Register("provider")
+	provider.MsgSent(2000)
+	provider.MsgReceived(1000)
+	l := NewServeLimiter(ServeLimitsConfig{MaxInflight: 1, LeechRatio: 0.1, LeechGrace: 1000}, stats, c)
+
+	busy := mustAcquire(t, l, "other")
+	leech := acquireAsync(l, context.Background(), "leech")
+	waitQueued(t, l, "leech", 1)
+	prov := acquireAsync(l, context.Background(), "provider")
+	waitQueued(t, l, "provider", 1)
+
+	busy(0)
+	release := <-prov
+	if release == nil {
+		t.Fatal("provider isn't served")
+	}
+	select {
+	case <-leech:
+		t.Fatal("leech is served before the provider")
+	default:
+	}
+	release(0)
+	if release := <-leech; release == nil {
+		t.Fatal("leech isn't served once the slot is free")
+	}
+}
@@ -0,0 +1,3 @@
+// Package gossip implements the Opera event gossip protocol: propagating events
+// between peers, filtering duplicates and handing new events over to the node.
+package gossip
@@ -0,0 +1,200 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// tx_sync.go exchanges the pooled transactions when peers connect.
+//
+// Overview:
+//   Transactions are broadcast only once, when they enter the pool. If the topology
+//   changes (a node restarts, connects to other peers), the pending transactions
+//   would stay stuck in the pools of the nodes which already got them.
+//
+//   So after the handshake each side announces (NewEvmTxHashesMsg) the hashes of its
+//   pooled transactions, the most profitable first. The other side requests (GetEvmTxsMsg)
+//   only the ones missing in its pool, and gets them in EvmTxsMsg.
+//   The number of hashes and the response size are limited on both sides.
+
+var (
+	// ErrTooManyTxHashes is returned if a peer sends more tx hashes in a message than allowed.
+	ErrTooManyTxHashes = errors.New("too many tx hashes in a message")
+	// ErrInvalidTxSyncConfig is returned if a limit of TxSyncConfig isn't positive.
+	ErrInvalidTxSyncConfig = errors.New("invalid tx sync config")
+)
+
+var (
+	txSyncAnnouncedCounter = metrics.NewRegisteredCounter("gossip/txsync/announced", nil)
+	txSyncRequestedCounter = metrics.NewRegisteredCounter("gossip/txsync/requested", nil)
+	txSyncServedCounter    = metrics.NewRegisteredCounter("gossip/txsync/served", nil)
+)
+
+// TxPool is the part of the txpool used by the transactions sync.
+type TxPool interface {
+	// SampleHashes returns up to max hashes of the pooled transactions, the most profitable first.
+	SampleHashes(max int) []common.Hash
+	// Has returns true if the transaction is in the pool.
+	Has(hash common.Hash) bool
+	// Get returns the pooled transaction, or nil if it isn't in the pool.
+	Get(hash common.Hash) *types.Transaction
+}
+
+// TxSyncConfig is the config of the transactions sync.
+type TxSyncConfig struct {
+	MaxAnnounced    int    // max number of tx hashes announced on connect
+	HashesPerMsg    int    // max number of tx hashes in a single announcement or request
+	MaxResponseSize uint64 // soft limit of the requested txs response size
+	KnownTxs        int    // number of tx hashes remembered per peer
+}
+
+// DefaultTxSyncConfig returns the default config of the transactions sync.
+func DefaultTxSyncConfig() TxSyncConfig {
+	return TxSyncConfig{
+		MaxAnnounced:    16384,
+		HashesPerMsg:    4096,
+		MaxResponseSize: 2 * 1024 * 1024,
+		KnownTxs:        32768,
+	}
+}
+
+// Validate checks that the limits of the config are positive.
+func (c TxSyncConfig) Validate() error {
+	switch {
+	case c.MaxAnnounced <= 0:
+		return fmt.Errorf("%w: MaxAnnounced %d", ErrInvalidTxSyncConfig, c.MaxAnnounced)
+	case c.HashesPerMsg <= 0:
+		return fmt.Errorf("%w: HashesPerMsg %d", ErrInvalidTxSyncConfig, c.HashesPerMsg)
+	case c.MaxResponseSize == 0:
+		return fmt.Errorf("%w: MaxResponseSize %d", ErrInvalidTxSyncConfig, c.MaxResponseSize)
+	case c.KnownTxs <= 0:
+		return fmt.Errorf("%w: KnownTxs %d", ErrInvalidTxSyncConfig, c.KnownTxs)
+	}
+	return nil
+}
+
+// TxSync is the transactions sync state of a single peer.
+// It's safe for concurrent use.
+type TxSync struct {
+	cfg  TxSyncConfig
+	pool TxPool
+
+	mu    sync.Mutex
+	known *lru.Cache // common.Hash -> struct{}, txs which the peer has or was told about
+}
+
+// NewTxSync creates the transactions sync state of a newly connected peer.
+func NewTxSync(pool TxPool, cfg TxSyncConfig) (*TxSync, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	known, err := lru.New(cfg.KnownTxs)
+	if err != nil {
+		return nil, err
+	}
+	return &TxSync{
+		cfg:   cfg,
+		pool:  pool,
+		known: known,
+	}, nil
+}
+
+// MarkKnown remembers that the peer has the transactions.
+func (s *TxSync) MarkKnown(hashes ...common.Hash) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, h := range hashes {
+		s.known.Add(h, struct{}{})
+	}
+}
+
+// OnConnect returns the announcements to send after the handshake,
+// each of them fits into a single NewEvmTxHashesMsg.
+func (s *TxSync) OnConnect() [][]common.Hash {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		batches [][]common.Hash
+		batch   []common.Hash
+	)
+	for _, h := range s.pool.SampleHashes(s.cfg.MaxAnnounced) {
+		if s.known.Contains(h) {
+			continue
+		}
+		s.known.Add(h, struct{}{})
+		batch = append(batch, h)
+		if len(batch) >= s.cfg.HashesPerMsg {
+			batches = append(batches, batch)
+			batch = nil
+		}
+	}
+	if len(batch) != 0 {
+		batches = append(batches, batch)
+	}
+	for _, b := range batches {
+		txSyncAnnouncedCounter.Inc(int64(len(b)))
+	}
+	return batches
+}
+
+// OnTxHashes handles the announcement of the peer.
+// It returns the hashes of the transactions to request, i.e. the ones missing in the pool.
+func (s *TxSync) OnTxHashes(hashes []common.Hash) ([]common.Hash, error) {
+	if len(hashes) > s.cfg.HashesPerMsg {
+		return nil, ErrTooManyTxHashes
+	}
+	s.MarkKnown(hashes...)
+
+	var request []common.Hash
+	seen := make(map[common.Hash]struct{}, len(hashes))
+	for _, h := range hashes {
+		if _, ok := seen[h]; ok {
+			continue
+		}
+		seen[h] = struct{}{}
+		if !s.pool.Has(h) {
+			request = append(request, h)
+		}
+	}
+	txSyncRequestedCounter.Inc(int64(len(request)))
+	return request, nil
+}
+
+// OnGetTxs handles the request of the peer.
+// It returns the requested pooled transactions, up to the response size limit.
+// Only the returned transactions become known to the peer, so the ones which are cut
+// by the limit or missing in the pool may be announced to it later.
+func (s *TxSync) OnGetTxs(hashes []common.Hash) (types.Transactions, error) {
+	if len(hashes) > s.cfg.HashesPerMsg {
+		return nil, ErrTooManyTxHashes
+	}
+
+	var (
+		txs  types.Transactions
+		size common.StorageSize
+	)
+	for _, h := range hashes {
+		if uint64(size) >= s.cfg.MaxResponseSize {
+			break
+		}
+		tx := s.pool.Get(h)
+		if tx == nil {
+			continue
+		}
+		txs = append(txs, tx)
+		size += tx.Size()
+	}
+	for _, tx := range txs {
+		s.MarkKnown(tx.Hash())
+	}
+	txSyncServedCounter.Inc(int64(len(txs)))
+	return txs, nil
+}
@@ -0,0 +1,161 @@
+package gossip
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// testTxPool is an in-memory TxPool.
+type testTxPool struct {
+	order []common.Hash
+	txs   map[common.Hash]*types.Transaction
+}
+
+func newTestTxPool(txs ...*types.Transaction) *testTxPool {
+	p := &testTxPool{txs: map[common.Hash]*types.Transaction{}}
+	for _, tx := range txs {
+		p.order = append(p.order, tx.Hash())
+		p.txs[tx.Hash()] = tx
+	}
+	return p
+}
+
+func (p *testTxPool) SampleHashes(max int) []common.Hash {
+	if len(p.order) > max {
+		return p.order[:max]
+	}
+	return p.order
+}
+
+func (p *testTxPool) Has(hash common.Hash) bool { return p.txs[hash] != nil }
+
+func (p *testTxPool) Get(hash common.Hash) *types.Transaction { return p.txs[hash] }
+
+func testTxs(n int, data int) []*types.Transaction {
+	txs := make([]*types.Transaction, n)
+	for i := range txs {
+		txs[i] = types.NewTransaction(uint64(i), common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), make([]byte, data))
+	}
+	return txs
+}
+
+func TestTxSync(t *testing.T) {
+	txs := testTxs(5, 0)
+	cfg := TxSyncConfig{MaxAnnounced: 4, HashesPerMsg: 3, MaxResponseSize: 1024 * 1024, KnownTxs: 100}
+
+	// node A has all the txs, node B has only the first one
+	a, err := NewTxSync(newTestTxPool(txs...), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewTxSync(newTestTxPool(txs[0]), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	announced := a.OnConnect()
+	if len(announced) != 2 || len(announced[0]) != 3 || len(announced[1]) != 1 {
+		t.Fatalf("unexpected announcement batches %v", announced)
+	}
+	if again := a.OnConnect(); len(again) != 0 {
+		t.Fatalf("known txs are announced twice: %v", again)
+	}
+
+	var received types.Transactions
+	for _, batch := range announced {
+		request, err := b.OnTxHashes(batch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := a.OnGetTxs(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		received = append(received, got...)
+	}
+	// tx 0 is known to B, tx 4 is beyond the announcement limit
+	if len(received) != 3 {
+		t.Fatalf("expected 3 txs, got %d", len(received))
+	}
+	for i, tx := range received {
+		if tx.Hash() != txs[i+1].Hash() {
+			t.Fatalf("unexpected tx #%d", i)
+		}
+	}
+}
+
+func TestTxSync_limits(t *testing.T) {
+	txs := testTxs(4, 1000)
+	cfg := TxSyncConfig{MaxAnnounced: 10, HashesPerMsg: 4, MaxResponseSize: 1500, KnownTxs: 100}
+	s, err := NewTxSync(newTestTxPool(txs...), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tooMany := make([]common.Hash, 5)
+	if _, err := s.OnTxHashes(tooMany); !errors.Is(err, ErrTooManyTxHashes) {
+		t.Fatalf("expected %v, got %v", ErrTooManyTxHashes, err)
+	}
+	if _, err := s.OnGetTxs(tooMany); !errors.Is(err, ErrTooManyTxHashes) {
+		t.Fatalf("expected %v, got %v", ErrTooManyTxHashes, err)
+	}
+
+	hashes := []common.Hash{txs[0].Hash(), txs[1].Hash(), txs[2].Hash(), txs[3].Hash()}
+	got, err := s.OnGetTxs(hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the response is cut once it exceeds the soft size limit
+	if len(got) != 2 {
+		t.Fatalf("expected 2 txs within the response limit, got %d", len(got))
+	}
+	// only the served txs are known to the peer, the cut ones are still announced
+	announced := s.OnConnect()
+	if len(announced) != 1 || len(announced[0]) != 2 || announced[0][0] != txs[2].Hash() || announced[0][1] != txs[3].Hash() {
+		t.Fatalf("unexpected announcement %v", announced)
+	}
+}
+
+func TestTxSync_unknownRequested(t *testing.T) {
+	txs := testTxs(2, 0)
+	pool := newTestTxPool(txs[0])
+	s, err := NewTxSync(pool, DefaultTxSyncConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.OnGetTxs([]common.Hash{txs[0].Hash(), txs[1].Hash()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 tx, got %d", len(got))
+	}
+	// the tx enters the pool after the peer asked for it
+	pool.order = append(pool.order, txs[1].Hash())
+	pool.txs[txs[1].Hash()] = txs[1]
+	if announced := s.OnConnect(); len(announced) != 1 || len(announced[0]) != 1 || announced[0][0] != txs[1].Hash() {
+		t.Fatalf("tx missing on request isn't announced later: %v", announced)
+	}
+}
+
+func TestTxSyncConfig_Validate(t *testing.T) {
+	if err := DefaultTxSyncConfig().Validate(); err != nil {
+		t.Fatalf("default config is invalid: %v", err)
+	}
+	for _, broken := range []func(*TxSyncConfig){
+		func(c *TxSyncConfig) { c.MaxAnnounced = 0 },
+		func(c *TxSyncConfig) { c.HashesPerMsg = -1 },
+		func(c *TxSyncConfig) { c.MaxResponseSize = 0 },
+		func(c *TxSyncConfig) { c.KnownTxs = 0 },
+	} {
+		cfg := DefaultTxSyncConfig()
+		broken(&cfg)
+		if _, err := NewTxSync(newTestTxPool(), cfg); !errors.Is(err, ErrInvalidTxSyncConfig) {
+			t.Errorf("config %+v: error = %v, want %v", cfg, err, ErrInvalidTxSyncConfig)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package grpcapi
+
+import (
+	"errors"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// messages.go implements the protobuf encoding of the messages of opera.proto.
+// The field numbers must match the schema, TestSchema_messages checks them against opera.proto.
+
+// errMalformedMessage is returned if a message isn't a valid protobuf encoding.
+var errMalformedMessage = errors.New("malformed protobuf message")
+
+// message is a protobuf message of opera.proto.
+type message interface {
+	marshal() []byte
+	unmarshal(b []byte) error
+}
+
+// GetBlockByNumberRequest is opera.v1.GetBlockByNumberRequest.
+type GetBlockByNumberRequest struct {
+	Number           int64
+	FullTransactions bool
+}
+
+// GetBlockByHashRequest is opera.v1.GetBlockByHashRequest.
+type GetBlockByHashRequest struct {
+	Hash             []byte
+	FullTransactions bool
+}
+
+// Block is opera.v1.Block.
+type Block struct {
+	Number            uint64
+	Hash              []byte
+	ParentHash        []byte
+	StateRoot         []byte
+	Time              uint64
+	Coinbase          []byte
+	GasUsed           uint64
+	BaseFee           []byte
+	TransactionHashes [][]byte
+	Transactions      [][]byte
+}
+
+// GetTransactionRequest is opera.v1.GetTransactionRequest.
+type GetTransactionRequest struct {
+	Hash []byte
+}
+
+// Transaction is opera.v1.Transaction.
+type Transaction struct {
+	Raw         []byte
+	BlockNumber uint64
+	Index       uint64
+}
+
+// SendRawTransactionRequest is opera.v1.SendRawTransactionRequest.
+type SendRawTransactionRequest struct {
+	Raw []byte
+}
+
+// SendRawTransactionResponse is opera.v1.SendRawTransactionResponse.
+type SendRawTransactionResponse struct {
+	Hash []byte
+}
+
+// GetEventRequest is opera.v1.GetEventRequest.
+type GetEventRequest struct {
+	ID string
+}
+
+// Event is opera.v1.Event.
+type Event struct {
+	ID  []byte
+	Raw []byte
+}
+
+// The proto3 default values aren't encoded.
+
+func appendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func appendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendBool(b []byte, num protowire.Number, v bool) []byte {
+	return appendVarint(b, num, protowire.EncodeBool(v))
+}
+
+// field is a decoded field, either a varint or a length-delimited value.
+type field struct {
+	num    protowire.Number
+	varint uint64
+	bytes  []byte
+}
+
+// decodeFields decodes the fields of a message, skipping the fields of other types.
+func decodeFields(b []byte, fn func(f field) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return errMalformedMessage
+		}
+		b = b[n:]
+		f := field{num: num}
+		switch typ {
+		case protowire.VarintType:
+			f.varint, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			f.bytes, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return errMalformedMessage
+			}
+			b = b[n:]
+			continue
+		}
+		if n < 0 {
+			return errMalformedMessage
+		}
+		b = b[n:]
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *GetBlockByNumberRequest) marshal() []byte {
+	var b []byte
+	b = appendVarint(b, 1, uint64(m.Number))
+	b = appendBool(b, 2, m.FullTransactions)
+	return b
+}
+
+func (m *GetBlockByNumberRequest) unmarshal(b []byte) error {
+	return decodeFields(b, func(f field) error {
+		switch f.num {
+		case 1:
+			m.Number = int64(f.varint)
+		case 2:
+			m.FullTransactions = protowire.DecodeBool(f.varint)
+		}
+		return nil
+	})
+}
+
+func (m *GetBlockByHashRequest) marshal() []byte {
+	var b []byte
+	b = appendBytes(b, 1, m.Hash)
+	b = appendBool(b, 2, m.FullTransactions)
+	return b
+}
+
+func (m *GetBlockByHashRequest) unmarshal(b []byte) error {
+	return decodeFields(b, func(f field) error {
+		switch f.num {
+		case 1:
+			m.Hash = f.bytes
+		case 2:
+			m.FullTransactions = protowire.DecodeBool(f.varint)
+		}
+		return nil
+	})
+}
+
+func (m *Block) marshal() []byte {
+	var b []byte
+	b = appendVarint(b, 1, m.Number)
+	b = appendBytes(b, 2, m.Hash)
+	b = appendBytes(b, 3, m.ParentHash)
+	b = appendBytes(b, 4, m.StateRoot)
+	b = appendVarint(b, 5, m.Time)
+	b = appendBytes(b, 6, m.Coinbase)
+	b = appendVarint(b, 7, m.GasUsed)
+	b = appendBytes(b, 8, m.BaseFee)
+	// the repeated fields keep the empty elements, so the indexes aren't shifted
+	for _, h := range m.TransactionHashes {
+		b = protowire.AppendTag(b, 9, protowire.BytesType)
+		b = protowire.AppendBytes(b, h)
+	}
+	for _, tx := range m.Transactions {
+		b = protowire.AppendTag(b, 10, protowire.BytesType)
+		b = protowire.AppendBytes(b, tx)
+	}
+	return b
+}
+
+func (m *Block) unmarshal(b []byte) error {
+	return decodeFields(b, func(f field) error {
+		switch f.num {
+		case 1:
+			m.Number = f.varint
+		case 2:
+			m.Hash = f.bytes
+		case 3:
+			m.ParentHash = f.bytes
+		case 4:
+			m.StateRoot = f.bytes
+		case 5:
+			m.Time = f.varint
+		case 6:
+			m.Coinbase = f.bytes
+		case 7:
+			m.GasUsed = f.varint
+		case 8:
+			m.BaseFee = f.bytes
+		case 9:
+			m.TransactionHashes = append(m.TransactionHashes, f.bytes)
+		case 10:
+			m.Transactions = append(m.Transactions, f.bytes)
+		}
+		return nil
+	})
+}
+
+func (m *GetTransactionRequest) marshal() []byte {
+	return appendBytes(nil, 1, m.Hash)
+}
+
+func (m *GetTransactionRequest) unmarshal(b []byte) error {
+	return decodeFields(b, func(f field) error {
+		if f.num == 1 {
+			m.Hash = f.bytes
+		}
+		return nil
+	})
+}
+
+func (m *Transaction) marshal() []byte {
+	var b []byte
+	b = appendBytes(b, 1, m.Raw)
+	b = appendVarint(b, 2, m.BlockNumber)
+	b = appendVarint(b, 3, m.Index)
+	return b
+}
+
+func (m *Transaction) unmarshal(b []byte) error {
+	return decodeFields(b, func(f field) error {
+		switch f.num {
+		case 1:
+			m.Raw = f.bytes
+		case 2:
+			m.BlockNumber = f.varint
+		case 3:
+			m.Index = f.varint
+		}
+		return nil
+	})
+}
+
+func (m *SendRawTransactionRequest) marshal() []byte {
+	return appendBytes(nil, 1, m.Raw)
+}
+
+func (m *SendRawTransactionRequest) unmarshal(b []byte) error {
+	return decodeFields(b, func(f field) error {
+		if f.num == 1 {
+			m.Raw = f.bytes
+		}
+		return nil
+	})
+}
+
+func (m *SendRawTransactionResponse) marshal() []byte {
+	return appendBytes(nil, 1, m.Hash)
+}
+
+func (m *SendRawTransactionResponse) unmarshal(b []byte) error {
+	return decodeFields(b, func(f field) error {
+		if f.num == 1 {
+			m.Hash = f.bytes
+		}
+		return nil
+	})
+}
+
+func (m *GetEventRequest) marshal() []byte {
+	return appendBytes(nil, 1, []byte(m.ID))
+}
+
+func (m *GetEventRequest) unmarshal(b []byte) error {
+	return decodeFields(b, func(f field) error {
+		if f.num == 1 {
+			m.ID = string(f.bytes)
+		}
+		return nil
+	})
+}
+
+func (m *Event) marshal() []byte {
+	var b []byte
+	b = appendBytes(b, 1, m.ID)
+	b = appendBytes(b, 2, m.Raw)
+	return b
+}
+
+func (m *Event) unmarshal(b []byte) error {
+	return decodeFields(b, func(f field) error {
+		switch f.num {
+		case 1:
+			m.ID = f.bytes
+		case 2:
+			m.Raw = f.bytes
+		}
+		return nil
+	})
+}
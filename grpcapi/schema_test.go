@@ -0,0 +1,237 @@
+package grpcapi
+
+import (
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// schema_test.go checks the hand-written messages and methods against opera.proto,
+// so the schema and the code can't drift apart.
+
+// goMessages are the Go types of the messages of opera.proto.
+var goMessages = map[string]message{
+	"GetBlockByNumberRequest":    new(GetBlockByNumberRequest),
+	"GetBlockByHashRequest":      new(GetBlockByHashRequest),
+	"Block":                      new(Block),
+	"GetTransactionRequest":      new(GetTransactionRequest),
+	"Transaction":                new(Transaction),
+	"SendRawTransactionRequest":  new(SendRawTransactionRequest),
+	"SendRawTransactionResponse": new(SendRawTransactionResponse),
+	"GetEventRequest":            new(GetEventRequest),
+	"Event":                      new(Event),
+}
+
+var (
+	protoComment = regexp.MustCompile(`//[^\n]*`)
+	protoPackage = regexp.MustCompile(`package\s+([\w.]+)\s*;`)
+	protoService = regexp.MustCompile(`service\s+(\w+)\s*\{([^}]*)\}`)
+	protoRPC     = regexp.MustCompile(`rpc\s+(\w+)\s*\(\s*(\w+)\s*\)\s*returns\s*\(\s*(\w+)\s*\)\s*;`)
+	protoMessage = regexp.MustCompile(`message\s+(\w+)\s*\{([^}]*)\}`)
+	protoField   = regexp.MustCompile(`(repeated\s+)?(\w+)\s+(\w+)\s*=\s*(\d+)\s*;`)
+)
+
+var protoScalars = map[string]descriptorpb.FieldDescriptorProto_Type{
+	"int64":  descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	"uint64": descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+	"bool":   descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+	"string": descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	"bytes":  descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+}
+
+// loadSchema parses opera.proto. Only the subset of the syntax the schema uses is supported:
+// the flat messages of scalar fields, and a service of unary methods.
+func loadSchema(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+	b, err := ioutil.ReadFile("opera.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := protoComment.ReplaceAllString(string(b), "")
+	pkg := protoPackage.FindStringSubmatch(src)
+	if pkg == nil {
+		t.Fatal("opera.proto has no package")
+	}
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("opera.proto"),
+		Package: proto.String(pkg[1]),
+		Syntax:  proto.String("proto3"),
+	}
+	for _, m := range protoMessage.FindAllStringSubmatch(src, -1) {
+		msg := &descriptorpb.DescriptorProto{Name: proto.String(m[1])}
+		for _, f := range protoField.FindAllStringSubmatch(m[2], -1) {
+			typ, ok := protoScalars[f[2]]
+			if !ok {
+				t.Fatalf("%s.%s: unsupported type %s", m[1], f[3], f[2])
+			}
+			num, _ := strconv.Atoi(f[4])
+			label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+			if f[1] != "" {
+				label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+			}
+			msg.Field = append(msg.Field, &descriptorpb.FieldDescriptorProto{
+				Name:     proto.String(f[3]),
+				JsonName: proto.String(f[3]),
+				Number:   proto.Int32(int32(num)),
+				Type:     typ.Enum(),
+				Label:    label.Enum(),
+			})
+		}
+		file.MessageType = append(file.MessageType, msg)
+	}
+	for _, s := range protoService.FindAllStringSubmatch(src, -1) {
+		svc := &descriptorpb.ServiceDescriptorProto{Name: proto.String(s[1])}
+		for _, r := range protoRPC.FindAllStringSubmatch(s[2], -1) {
+			svc.Method = append(svc.Method, &descriptorpb.MethodDescriptorProto{
+				Name:       proto.String(r[1]),
+				InputType:  proto.String("." + pkg[1] + "." + r[2]),
+				OutputType: proto.String("." + pkg[1] + "." + r[3]),
+			})
+		}
+		file.Service = append(file.Service, svc)
+	}
+	fd, err := protodesc.NewFile(file, nil)
+	if err != nil {
+		t.Fatalf("invalid opera.proto: %v", err)
+	}
+	return fd
+}
+
+// goFieldName converts the proto field name to the Go one, e.g. full_transactions to FullTransactions.
+func goFieldName(name string) string {
+	parts := strings.Split(name, "_")
+	for i, p := range parts {
+		if p == "id" {
+			parts[i] = "ID"
+		} else {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// testValue returns a distinct non-default value of the field.
+func testValue(fd protoreflect.FieldDescriptor, i int) protoreflect.Value {
+	n := int(fd.Number())*10 + i + 1
+	switch fd.Kind() {
+	case protoreflect.Int64Kind:
+		return protoreflect.ValueOfInt64(-int64(n))
+	case protoreflect.Uint64Kind:
+		return protoreflect.ValueOfUint64(uint64(n) << 40)
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(true)
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString("s" + strconv.Itoa(n))
+	default:
+		return protoreflect.ValueOfBytes([]byte{byte(n), byte(n >> 8), 0xff})
+	}
+}
+
+func TestSchema_messages(t *testing.T) {
+	fd := loadSchema(t)
+	msgs := fd.Messages()
+	if msgs.Len() != len(goMessages) {
+		t.Fatalf("opera.proto has %d messages, the Go code has %d", msgs.Len(), len(goMessages))
+	}
+	for i := 0; i < msgs.Len(); i++ {
+		md := msgs.Get(i)
+		name := string(md.Name())
+		goMsg, ok := goMessages[name]
+		if !ok {
+			t.Errorf("message %s has no Go type", name)
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			// the Go struct has exactly the fields of the schema
+			goType := reflect.TypeOf(goMsg).Elem()
+			var goFields, protoFields []string
+			for j := 0; j < goType.NumField(); j++ {
+				goFields = append(goFields, goType.Field(j).Name)
+			}
+			fields := md.Fields()
+			for j := 0; j < fields.Len(); j++ {
+				protoFields = append(protoFields, goFieldName(string(fields.Get(j).Name())))
+			}
+			sort.Strings(goFields)
+			sort.Strings(protoFields)
+			if !reflect.DeepEqual(goFields, protoFields) {
+				t.Fatalf("Go fields %v, schema fields %v", goFields, protoFields)
+			}
+
+			// every field is decoded from its number and type, and is encoded back the same way
+			want := dynamicpb.NewMessage(md)
+			for j := 0; j < fields.Len(); j++ {
+				f := fields.Get(j)
+				if f.IsList() {
+					list := want.Mutable(f).List()
+					list.Append(testValue(f, 0))
+					list.Append(testValue(f, 1))
+				} else {
+					want.Set(f, testValue(f, 0))
+				}
+			}
+			encoded, err := proto.MarshalOptions{Deterministic: true}.Marshal(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m := reflect.New(goType).Interface().(message)
+			if err := m.unmarshal(encoded); err != nil {
+				t.Fatal(err)
+			}
+			got := dynamicpb.NewMessage(md)
+			if err := proto.Unmarshal(m.marshal(), got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got.GetUnknown()) != 0 {
+				t.Fatalf("encoded fields unknown to the schema: %x", got.GetUnknown())
+			}
+			if !proto.Equal(want, got) {
+				t.Fatalf("round-trip via the Go type: want %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestSchema_methods(t *testing.T) {
+	fd := loadSchema(t)
+	services := fd.Services()
+	if services.Len() != 1 || string(services.Get(0).FullName()) != ServiceName {
+		t.Fatalf("opera.proto must have the single service %s", ServiceName)
+	}
+	methods := services.Get(0).Methods()
+	s := NewServer(nil)
+	if methods.Len() != len(s.methods) {
+		t.Fatalf("opera.proto has %d methods, the server has %d", methods.Len(), len(s.methods))
+	}
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		name := string(md.Name())
+		if _, ok := s.methods[name]; !ok {
+			t.Errorf("method %s isn't served", name)
+			continue
+		}
+		// the handler calls the Server method of the same name
+		fn := reflect.ValueOf(s).MethodByName(name)
+		if !fn.IsValid() {
+			t.Errorf("Server has no method %s", name)
+			continue
+		}
+		typ := fn.Type()
+		if in := typ.In(1).Elem().Name(); in != string(md.Input().Name()) {
+			t.Errorf("%s takes %s, the schema has %s", name, in, md.Input().Name())
+		}
+		if out := typ.Out(0).Elem().Name(); out != string(md.Output().Name()) {
+			t.Errorf("%s returns %s, the schema has %s", name, out, md.Output().Name())
+		}
+	}
+}
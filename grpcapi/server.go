@@ -0,0 +1,329 @@
+// Package grpcapi serves the key JSON-RPC methods over gRPC, for the infrastructure
+// which prefers protobuf schemas (see opera.proto).
+//
+// The methods go through the same ethapi.Backend as the JSON-RPC ones, so both the
+// APIs return the same data, and the submitted transactions are validated the same way.
+//
+// Only the unary calls of the gRPC protocol are implemented, over HTTP/2 without TLS
+// (h2c), without the compression. Any gRPC client generated from opera.proto may call them.
+package grpcapi
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/rony4d/go-opera-asset/ethapi"
+	"github.com/rony4d/go-opera-asset/evmcore"
+)
+
+// ServiceName is the full name of the service of opera.proto.
+const ServiceName = "opera.v1.Opera"
+
+// MaxMessageSize is the size limit of the request messages, the same as the default of gRPC.
+const MaxMessageSize = 4 * 1024 * 1024
+
+// Code is a gRPC status code.
+type Code int
+
+// The gRPC status codes returned by the server.
+const (
+	CodeOK              Code = 0
+	CodeUnknown         Code = 2
+	CodeInvalidArgument Code = 3
+	CodeNotFound        Code = 5
+	CodeUnimplemented   Code = 12
+	CodeInternal        Code = 13
+)
+
+// StatusError is a failed call with its gRPC status.
+type StatusError struct {
+	Code    Code
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("grpc status %d: %s", e.Code, e.Message)
+}
+
+func statusf(code Code, format string, args ...interface{}) *StatusError {
+	return &StatusError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// handler decodes the request message and calls the method.
+type handler func(ctx context.Context, req []byte) (message, error)
+
+// Server implements the Opera service. It's safe for concurrent use.
+type Server struct {
+	b       ethapi.Backend
+	methods map[string]handler
+}
+
+// NewServer creates the service over the backend.
+func NewServer(b ethapi.Backend) *Server {
+	s := &Server{b: b}
+	s.methods = map[string]handler{
+		"GetBlockByNumber": func(ctx context.Context, raw []byte) (message, error) {
+			req := new(GetBlockByNumberRequest)
+			if err := req.unmarshal(raw); err != nil {
+				return nil, err
+			}
+			return s.GetBlockByNumber(ctx, req)
+		},
+		"GetBlockByHash": func(ctx context.Context, raw []byte) (message, error) {
+			req := new(GetBlockByHashRequest)
+			if err := req.unmarshal(raw); err != nil {
+				return nil, err
+			}
+			return s.GetBlockByHash(ctx, req)
+		},
+		"GetTransaction": func(ctx context.Context, raw []byte) (message, error) {
+			req := new(GetTransactionRequest)
+			if err := req.unmarshal(raw); err != nil {
+				return nil, err
+			}
+			return s.GetTransaction(ctx, req)
+		},
+		"SendRawTransaction": func(ctx context.Context, raw []byte) (message, error) {
+			req := new(SendRawTransactionRequest)
+			if err := req.unmarshal(raw); err != nil {
+				return nil, err
+			}
+			return s.SendRawTransaction(ctx, req)
+		},
+		"GetEvent": func(ctx context.Context, raw []byte) (message, error) {
+			req := new(GetEventRequest)
+			if err := req.unmarshal(raw); err != nil {
+				return nil, err
+			}
+			return s.GetEvent(ctx, req)
+		},
+	}
+	return s
+}
+
+// Handler returns the HTTP handler serving the gRPC calls over h2c.
+func (s *Server) Handler() http.Handler {
+	return h2c.NewHandler(s, &http2.Server{})
+}
+
+// GetBlockByNumber mirrors eth_getBlockByNumber. A negative number refers to the latest block.
+func (s *Server) GetBlockByNumber(ctx context.Context, req *GetBlockByNumberRequest) (*Block, error) {
+	number := rpc.BlockNumber(req.Number)
+	if req.Number < 0 {
+		number = rpc.LatestBlockNumber
+	}
+	block, err := s.b.BlockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, statusf(CodeNotFound, "block %d not found", req.Number)
+	}
+	return newBlock(block, req.FullTransactions)
+}
+
+// GetBlockByHash mirrors eth_getBlockByHash.
+func (s *Server) GetBlockByHash(ctx context.Context, req *GetBlockByHashRequest) (*Block, error) {
+	if len(req.Hash) != common.HashLength {
+		return nil, statusf(CodeInvalidArgument, "block hash must be %d bytes", common.HashLength)
+	}
+	block, err := s.b.BlockByHash(ctx, common.BytesToHash(req.Hash))
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, statusf(CodeNotFound, "block %x not found", req.Hash)
+	}
+	return newBlock(block, req.FullTransactions)
+}
+
+func newBlock(block *evmcore.EvmBlock, fullTxs bool) (*Block, error) {
+	res := &Block{
+		Number:            block.Number.Uint64(),
+		Hash:              block.Hash.Bytes(),
+		ParentHash:        block.ParentHash.Bytes(),
+		StateRoot:         block.Root.Bytes(),
+		Time:              uint64(block.Time),
+		Coinbase:          block.Coinbase.Bytes(),
+		GasUsed:           block.GasUsed,
+		TransactionHashes: make([][]byte, len(block.Transactions)),
+	}
+	if block.BaseFee != nil {
+		res.BaseFee = block.BaseFee.Bytes()
+	}
+	for i, tx := range block.Transactions {
+		res.TransactionHashes[i] = tx.Hash().Bytes()
+	}
+	if fullTxs {
+		res.Transactions = make([][]byte, len(block.Transactions))
+		for i, tx := range block.Transactions {
+			raw, err := tx.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			res.Transactions[i] = raw
+		}
+	}
+	return res, nil
+}
+
+// GetTransaction mirrors eth_getTransactionByHash.
+func (s *Server) GetTransaction(ctx context.Context, req *GetTransactionRequest) (*Transaction, error) {
+	if len(req.Hash) != common.HashLength {
+		return nil, statusf(CodeInvalidArgument, "transaction hash must be %d bytes", common.HashLength)
+	}
+	tx, blockNumber, index, err := s.b.GetTransaction(ctx, common.BytesToHash(req.Hash))
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, statusf(CodeNotFound, "transaction %x not found", req.Hash)
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{Raw: raw, BlockNumber: blockNumber, Index: index}, nil
+}
+
+// SendRawTransaction mirrors eth_sendRawTransaction.
+func (s *Server) SendRawTransaction(ctx context.Context, req *SendRawTransactionRequest) (*SendRawTransactionResponse, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(req.Raw); err != nil {
+		return nil, statusf(CodeInvalidArgument, "%v", err)
+	}
+	hash, err := ethapi.SubmitTransaction(ctx, s.b, tx)
+	if err != nil {
+		return nil, err
+	}
+	return &SendRawTransactionResponse{Hash: hash.Bytes()}, nil
+}
+
+// GetEvent mirrors debug_getRawEvent.
+func (s *Server) GetEvent(ctx context.Context, req *GetEventRequest) (*Event, error) {
+	event, err := s.b.GetEventPayload(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, statusf(CodeNotFound, "event %s not found", req.ID)
+	}
+	raw, err := event.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	id := event.ID()
+	return &Event{ID: id.Bytes(), Raw: raw}, nil
+}
+
+// ServeHTTP serves a unary gRPC call.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ProtoMajor != 2 ||
+		!strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "gRPC over HTTP/2 is expected", http.StatusUnsupportedMediaType)
+		return
+	}
+	w.Header().Set("Content-Type", "application/grpc+proto")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	// the status is always sent in the trailers, even if there's no response message
+	w.WriteHeader(http.StatusOK)
+
+	resp, err := s.call(r)
+	if err == nil {
+		_, err = w.Write(frame(resp.marshal()))
+	}
+	writeStatus(w, err)
+}
+
+func (s *Server) call(r *http.Request) (message, error) {
+	name := strings.TrimPrefix(r.URL.Path, "/"+ServiceName+"/")
+	method, ok := s.methods[name]
+	if !ok || name == r.URL.Path {
+		return nil, statusf(CodeUnimplemented, "unknown method %s", r.URL.Path)
+	}
+	req, err := readFrame(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := method(r.Context(), req)
+	if errors.Is(err, errMalformedMessage) {
+		return nil, statusf(CodeInvalidArgument, "%v", err)
+	}
+	return resp, err
+}
+
+// readFrame reads the single length-prefixed message of a unary call.
+func readFrame(body io.Reader) ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(body, prefix[:]); err != nil {
+		return nil, statusf(CodeInvalidArgument, "truncated message: %v", err)
+	}
+	if prefix[0] != 0 {
+		return nil, statusf(CodeUnimplemented, "compressed messages aren't supported")
+	}
+	size := binary.BigEndian.Uint32(prefix[1:])
+	if size > MaxMessageSize {
+		return nil, statusf(CodeInvalidArgument, "message of %d bytes exceeds the limit of %d bytes", size, MaxMessageSize)
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(body, msg); err != nil {
+		return nil, statusf(CodeInvalidArgument, "truncated message: %v", err)
+	}
+	// a unary call carries exactly one message
+	if rest, _ := ioutil.ReadAll(io.LimitReader(body, 1)); len(rest) != 0 {
+		return nil, statusf(CodeUnimplemented, "streaming calls aren't supported")
+	}
+	return msg, nil
+}
+
+// frame prefixes the message with the gRPC frame header.
+func frame(msg []byte) []byte {
+	b := make([]byte, 5+len(msg))
+	binary.BigEndian.PutUint32(b[1:5], uint32(len(msg)))
+	copy(b[5:], msg)
+	return b
+}
+
+// writeStatus writes the status trailers of the call. The errors without a status are
+// reported as CodeUnknown, the same as gRPC servers do.
+func writeStatus(w http.ResponseWriter, err error) {
+	status := &StatusError{Code: CodeOK}
+	if err != nil {
+		if !errors.As(err, &status) {
+			status = &StatusError{Code: CodeUnknown, Message: err.Error()}
+		}
+		log.Debug("gRPC call failed", "code", status.Code, "err", status.Message)
+	}
+	w.Header().Set("Grpc-Status", strconv.Itoa(int(status.Code)))
+	if status.Message != "" {
+		w.Header().Set("Grpc-Message", encodeGrpcMessage(status.Message))
+	}
+}
+
+// encodeGrpcMessage percent-encodes the status message, as gRPC requires.
+func encodeGrpcMessage(msg string) string {
+	var sb strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if c >= ' ' && c <= '~' && c != '%' {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
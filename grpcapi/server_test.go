@@ -0,0 +1,157 @@
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/net/http2"
+
+	"github.com/rony4d/go-opera-asset/ethapi"
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+// testBackend serves a single block with a single transaction.
+type testBackend struct {
+	ethapi.Backend
+	block *evmcore.EvmBlock
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmBlock, error) {
+	if number == rpc.LatestBlockNumber || number == rpc.BlockNumber(b.block.Number.Int64()) {
+		return b.block, nil
+	}
+	return nil, nil
+}
+
+func (b *testBackend) GetTransaction(ctx context.Context, hash common.Hash) (*types.Transaction, uint64, uint64, error) {
+	for i, tx := range b.block.Transactions {
+		if tx.Hash() == hash {
+			return tx, b.block.Number.Uint64(), uint64(i), nil
+		}
+	}
+	return nil, 0, 0, nil
+}
+
+func (b *testBackend) GetEventPayload(ctx context.Context, id string) (*inter.EventPayload, error) {
+	return nil, nil
+}
+
+// invoke makes a unary gRPC call over h2c and returns its status.
+func invoke(t *testing.T, url, method string, req, resp message) (Code, string) {
+	t.Helper()
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		},
+	}}
+	httpReq, err := http.NewRequest(http.MethodPost, url+"/"+ServiceName+"/"+method, bytes.NewReader(frame(req.marshal())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpReq.Header.Set("Content-Type", "application/grpc")
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer httpResp.Body.Close()
+	body, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := strconv.Atoi(httpResp.Trailer.Get("Grpc-Status"))
+	if err != nil {
+		t.Fatalf("no status: %v", err)
+	}
+	if Code(code) == CodeOK {
+		msg, err := readFrame(bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := resp.unmarshal(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return Code(code), httpResp.Trailer.Get("Grpc-Message")
+}
+
+func TestServer(t *testing.T) {
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000, To: &common.Address{1}, Value: big.NewInt(1)})
+	block := evmcore.NewEvmBlock(&evmcore.EvmHeader{
+		Number:  big.NewInt(5),
+		Hash:    common.Hash{5},
+		Time:    inter.Timestamp(1600000000000000000),
+		GasUsed: 21000,
+		BaseFee: big.NewInt(1e9),
+	}, types.Transactions{tx})
+
+	srv := httptest.NewServer(NewServer(&testBackend{block: block}).Handler())
+	defer srv.Close()
+
+	var got Block
+	if code, msg := invoke(t, srv.URL, "GetBlockByNumber", &GetBlockByNumberRequest{Number: -1, FullTransactions: true}, &got); code != CodeOK {
+		t.Fatalf("GetBlockByNumber failed: %d %s", code, msg)
+	}
+	if got.Number != 5 || !bytes.Equal(got.Hash, block.Hash.Bytes()) || got.Time != uint64(block.Time) ||
+		new(big.Int).SetBytes(got.BaseFee).Cmp(block.BaseFee) != 0 {
+		t.Fatalf("unexpected block %+v", got)
+	}
+	if len(got.TransactionHashes) != 1 || !bytes.Equal(got.TransactionHashes[0], tx.Hash().Bytes()) || len(got.Transactions) != 1 {
+		t.Fatalf("unexpected block transactions %+v", got)
+	}
+	decoded := new(types.Transaction)
+	if err := decoded.UnmarshalBinary(got.Transactions[0]); err != nil || decoded.Hash() != tx.Hash() {
+		t.Fatalf("transaction isn't decoded: %v", err)
+	}
+
+	var gotTx Transaction
+	if code, msg := invoke(t, srv.URL, "GetTransaction", &GetTransactionRequest{Hash: tx.Hash().Bytes()}, &gotTx); code != CodeOK {
+		t.Fatalf("GetTransaction failed: %d %s", code, msg)
+	}
+	if gotTx.BlockNumber != 5 || gotTx.Index != 0 {
+		t.Fatalf("unexpected transaction position %+v", gotTx)
+	}
+
+	if code, _ := invoke(t, srv.URL, "GetBlockByNumber", &GetBlockByNumberRequest{Number: 6}, &got); code != CodeNotFound {
+		t.Fatalf("missing block: got status %d", code)
+	}
+	if code, _ := invoke(t, srv.URL, "GetBlockByHash", &GetBlockByHashRequest{Hash: []byte{1}}, &got); code != CodeInvalidArgument {
+		t.Fatalf("short hash: got status %d", code)
+	}
+	if code, _ := invoke(t, srv.URL, "GetEvent", &GetEventRequest{ID: "1:2:abcd"}, &Event{}); code != CodeNotFound {
+		t.Fatalf("missing event: got status %d", code)
+	}
+	if code, _ := invoke(t, srv.URL, "Subscribe", &GetEventRequest{}, &Event{}); code != CodeUnimplemented {
+		t.Fatalf("unknown method: got status %d", code)
+	}
+}
+
+func TestMessagesRoundTrip(t *testing.T) {
+	in := &Block{
+		Number:            1,
+		Hash:              []byte{1, 2},
+		TransactionHashes: [][]byte{{3}, {}},
+	}
+	out := new(Block)
+	if err := out.unmarshal(in.marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if out.Number != 1 || !bytes.Equal(out.Hash, in.Hash) || len(out.TransactionHashes) != 2 {
+		t.Fatalf("got %+v, want %+v", out, in)
+	}
+	if err := out.unmarshal([]byte{0x0a, 0x05}); err != errMalformedMessage {
+		t.Fatalf("truncated message isn't rejected: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package indexer
+
+import (
+	"context"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// maxTransfersPerCall limits the result of indexer_getTokenTransfers.
+	maxTransfersPerCall = 1000
+	// maxLogsPerCall limits the result of indexer_getLogs.
+	maxLogsPerCall = 1000
+)
+
+// PublicIndexerAPI serves the indexes over the indexer's own RPC endpoint.
+type PublicIndexerAPI struct {
+	ix        *Indexer
+	transfers *TokenTransfers
+	logs      *Logs
+}
+
+// NewPublicIndexerAPI creates a new indexer API instance.
+func NewPublicIndexerAPI(ix *Indexer, transfers *TokenTransfers, logs *Logs) *PublicIndexerAPI {
+	return &PublicIndexerAPI{ix, transfers, logs}
+}
+
+// APIs returns the RPC APIs of the indexer.
+func APIs(ix *Indexer, transfers *TokenTransfers, logs *Logs) []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "indexer",
+			Version:   "1.0",
+			Service:   NewPublicIndexerAPI(ix, transfers, logs),
+			Public:    true,
+		},
+	}
+}
+
+// RPCTokenTransfer is the JSON representation of TokenTransfer.
+type RPCTokenTransfer struct {
+	Token       common.Address `json:"token"`
+	From        common.Address `json:"from"`
+	To          common.Address `json:"to"`
+	Value       *hexutil.Big   `json:"value"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	TxHash      common.Hash    `json:"transactionHash"`
+	LogIndex    hexutil.Uint   `json:"logIndex"`
+}
+
+// GetTokenTransfers returns the token transfers sent or received by the address, starting with the block.
+func (s *PublicIndexerAPI) GetTokenTransfers(ctx context.Context, addr common.Address, fromBlock hexutil.Uint64, limit *hexutil.Uint) ([]RPCTokenTransfer, error) {
+	max := maxTransfersPerCall
+	if limit != nil && int(*limit) < max {
+		max = int(*limit)
+	}
+	transfers, err := s.transfers.Transfers(addr, idx.Block(fromBlock), max)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]RPCTokenTransfer, len(transfers))
+	for i, t := range transfers {
+		res[i] = RPCTokenTransfer{
+			Token:       t.Token,
+			From:        t.From,
+			To:          t.To,
+			Value:       (*hexutil.Big)(t.Value),
+			BlockNumber: hexutil.Uint64(t.Block),
+			TxHash:      t.TxHash,
+			LogIndex:    hexutil.Uint(t.LogIndex),
+		}
+	}
+	return res, nil
+}
+
+// GetLogs returns the logs emitted by the contract, starting with the block.
+func (s *PublicIndexerAPI) GetLogs(ctx context.Context, addr common.Address, fromBlock hexutil.Uint64, limit *hexutil.Uint) ([]*types.Log, error) {
+	max := maxLogsPerCall
+	if limit != nil && int(*limit) < max {
+		max = int(*limit)
+	}
+	logs, err := s.logs.Logs(addr, idx.Block(fromBlock), max)
+	if err != nil {
+		return nil, err
+	}
+	if logs == nil {
+		logs = []*types.Log{}
+	}
+	return logs, nil
+}
+
+// Progress returns the last indexed block of every index.
+func (s *PublicIndexerAPI) Progress(ctx context.Context) (map[string]hexutil.Uint64, error) {
+	res := make(map[string]hexutil.Uint64, len(s.ix.indexes))
+	for _, index := range s.ix.indexes {
+		n, err := s.ix.Progress(index.Name())
+		if err != nil {
+			return nil, err
+		}
+		res[index.Name()] = hexutil.Uint64(n)
+	}
+	return res, nil
+}
@@ -0,0 +1,88 @@
+package indexer
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/gossip"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// DatadirSource reads the blocks stored by the node (see gossip.Blocks) in its chaindata directory.
+//
+// The producer must open the databases read-only (see integration.DBProducer). A database
+// locked by the running node is opened from a checkpoint, which doesn't see the later blocks,
+// so Refresh reopens it once the indexer catches up.
+type DatadirSource struct {
+	producer kvdb.DBProducer
+	signer   types.Signer
+
+	mu     sync.Mutex
+	db     kvdb.Store
+	blocks *gossip.Blocks
+}
+
+// NewDatadirSource opens the blocks database of the node. chainID is needed to derive
+// the addresses of the contracts created by the indexed transactions.
+func NewDatadirSource(producer kvdb.DBProducer, chainID *big.Int) (*DatadirSource, error) {
+	s := &DatadirSource{
+		producer: producer,
+		signer:   types.LatestSignerForChainID(chainID),
+	}
+	return s, s.Refresh()
+}
+
+// Refresh reopens the blocks database, so the blocks stored since it was opened become available.
+func (s *DatadirSource) Refresh() error {
+	db, err := s.producer.OpenDB(gossip.BlocksDBName)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db != nil {
+		_ = s.db.Close()
+	}
+	s.db = db
+	s.blocks = gossip.NewBlocks(db)
+	return nil
+}
+
+// Close closes the blocks database.
+func (s *DatadirSource) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db.Close()
+}
+
+// LastBlock returns the last block stored by the node.
+func (s *DatadirSource) LastBlock() idx.Block {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.blocks.LastBlock()
+}
+
+// Block returns the stored block with its receipts.
+// The base fee isn't restored, as the rules of the block aren't known.
+func (s *DatadirSource) Block(n idx.Block) (*evmcore.EvmBlock, types.Receipts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	block, txs, receipts := s.blocks.Block(n, s.signer)
+	if block == nil {
+		return nil, nil, ErrBlockNotFound
+	}
+	var prev hash.Event
+	if n > 0 {
+		if p := s.blocks.GetBlock(n - 1); p != nil {
+			prev = p.Atropos
+		}
+	}
+	header := evmcore.ToEvmHeader(block, n, prev, opera.Rules{})
+	return evmcore.NewEvmBlock(header, txs), receipts, nil
+}
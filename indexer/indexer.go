@@ -0,0 +1,157 @@
+// Package indexer builds heavy secondary indexes (token transfers, logs) of the chain
+// in a separate process, so the validator process isn't slowed down by them.
+//
+// The indexer reads blocks from a read-only Source (the datadir of a node, see DatadirSource)
+// and writes the indexes into its own DB. Progress is stored per index, in the same DB,
+// so the indexer resumes where it stopped, and a newly added index catches up from the genesis.
+package indexer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/utils/throttle"
+)
+
+// ErrBlockNotFound is returned by a Source if the block isn't available.
+var ErrBlockNotFound = errors.New("block not found")
+
+// Source provides the indexed blocks. It's never written by the indexer.
+type Source interface {
+	// LastBlock returns the last block which is available for indexing.
+	LastBlock() idx.Block
+	// Block returns the block with its receipts.
+	Block(n idx.Block) (*evmcore.EvmBlock, types.Receipts, error)
+}
+
+// Refresher is implemented by the sources which have to be reopened to see the new blocks,
+// e.g. DatadirSource. Run refreshes them once the indexes catch up.
+type Refresher interface {
+	Refresh() error
+}
+
+// Index is an index which is built block by block.
+type Index interface {
+	// Name identifies the progress of the index, so it must never change.
+	Name() string
+	// IndexBlock adds the block to the index. It must be idempotent,
+	// as the block is indexed again if the process stops before the progress is saved.
+	IndexBlock(block *evmcore.EvmBlock, receipts types.Receipts) error
+}
+
+// Indexer feeds the blocks of the source to the indexes.
+type Indexer struct {
+	src      Source
+	progress kvdb.Store
+	indexes  []Index
+	throttle *throttle.Throttler
+}
+
+// New creates the indexer. progress is the DB (table) where the progress of the indexes is kept.
+func New(src Source, progress kvdb.Store, indexes ...Index) *Indexer {
+	return &Indexer{
+		src:      src,
+		progress: progress,
+		indexes:  indexes,
+		throttle: throttle.Unlimited(),
+	}
+}
+
+// SetThrottle limits the indexing, so it yields to the live block processing. Every block costs 1.
+func (ix *Indexer) SetThrottle(t *throttle.Throttler) {
+	ix.throttle = t
+}
+
+// Progress returns the last block indexed by the index, or 0 if none.
+func (ix *Indexer) Progress(name string) (idx.Block, error) {
+	b, err := ix.progress.Get([]byte(name))
+	if err != nil || b == nil {
+		return 0, err
+	}
+	return idx.BytesToBlock(b), nil
+}
+
+// IndexNext indexes up to `limit` available blocks by every index.
+// Returns the largest number of blocks indexed by an index, i.e. zero means all the indexes are up to date.
+func (ix *Indexer) IndexNext(ctx context.Context, limit int) (int, error) {
+	last := ix.src.LastBlock()
+	indexed := 0
+	for _, index := range ix.indexes {
+		num, err := ix.indexNext(ctx, index, last, limit)
+		if err != nil {
+			return 0, err
+		}
+		if num > indexed {
+			indexed = num
+		}
+	}
+	return indexed, nil
+}
+
+func (ix *Indexer) indexNext(ctx context.Context, index Index, last idx.Block, limit int) (int, error) {
+	done, err := ix.Progress(index.Name())
+	if err != nil {
+		return 0, err
+	}
+	num := 0
+	for n := done + 1; n <= last && num < limit; n++ {
+		release, err := ix.throttle.Acquire(ctx, 1)
+		if err != nil {
+			return num, err
+		}
+		err = ix.indexBlock(index, n)
+		release()
+		if err != nil {
+			return num, err
+		}
+		if err := ix.progress.Put([]byte(index.Name()), n.Bytes()); err != nil {
+			return num, err
+		}
+		num++
+	}
+	return num, nil
+}
+
+func (ix *Indexer) indexBlock(index Index, n idx.Block) error {
+	block, receipts, err := ix.src.Block(n)
+	if err != nil {
+		return err
+	}
+	return index.IndexBlock(block, receipts)
+}
+
+// Run indexes the blocks until the context is cancelled.
+// Once the indexes catch up with the source, it polls the source for new blocks.
+func (ix *Indexer) Run(ctx context.Context, pollInterval time.Duration) error {
+	const batch = 1000
+	for {
+		num, err := ix.IndexNext(ctx, batch)
+		if err != nil {
+			return err
+		}
+		if num == batch {
+			log.Debug("Indexed blocks", "num", num)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+		if r, ok := ix.src.(Refresher); ok {
+			if err := r.Refresh(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package indexer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/table"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/rony4d/go-opera-asset/evmcore"
+)
+
+// fakeSource is a chain of blocks, each with a single ERC-20 transfer of `n` tokens
+// from the address 1 to the address n+1.
+type fakeSource struct {
+	last idx.Block
+}
+
+func (s *fakeSource) LastBlock() idx.Block {
+	return s.last
+}
+
+func (s *fakeSource) Block(n idx.Block) (*evmcore.EvmBlock, types.Receipts, error) {
+	if n == 0 || n > s.last {
+		return nil, nil, ErrBlockNotFound
+	}
+	block := &evmcore.EvmBlock{EvmHeader: evmcore.EvmHeader{Number: big.NewInt(int64(n))}}
+	transfer := &types.Log{
+		Address: common.HexToAddress("0x7070"),
+		Topics: []common.Hash{
+			transferTopic,
+			common.BigToHash(big.NewInt(1)),
+			common.BigToHash(big.NewInt(int64(n) + 1)),
+		},
+		Data:  common.BigToHash(big.NewInt(int64(n))).Bytes(),
+		Index: 0,
+	}
+	other := &types.Log{Topics: []common.Hash{{1}}, Index: 1}
+	return block, types.Receipts{{Logs: []*types.Log{transfer, other}}}, nil
+}
+
+func TestIndexer_Resume(t *testing.T) {
+	db := memorydb.New()
+	src := &fakeSource{last: 5}
+	transfers := NewTokenTransfers(table.New(db, []byte("t")))
+	logs := NewLogs(table.New(db, []byte("l")))
+
+	ix := New(src, table.New(db, []byte("p")), transfers, logs)
+	num, err := ix.IndexNext(context.Background(), 3)
+	if err != nil || num != 3 {
+		t.Fatalf("indexed %d blocks, err %v", num, err)
+	}
+
+	// a restarted indexer resumes from the stored progress
+	ix = New(src, table.New(db, []byte("p")), transfers, logs)
+	if num, err = ix.IndexNext(context.Background(), 10); err != nil || num != 2 {
+		t.Fatalf("indexed %d blocks after restart, err %v", num, err)
+	}
+	if num, err = ix.IndexNext(context.Background(), 10); err != nil || num != 0 {
+		t.Fatalf("indexed %d blocks when up to date, err %v", num, err)
+	}
+	src.last = 6
+	if num, err = ix.IndexNext(context.Background(), 10); err != nil || num != 1 {
+		t.Fatalf("indexed %d new blocks, err %v", num, err)
+	}
+	if p, _ := ix.Progress(transfers.Name()); p != 6 {
+		t.Fatalf("progress is %d, want 6", p)
+	}
+
+	sender := common.BigToAddress(big.NewInt(1))
+	sent, err := transfers.Transfers(sender, 2, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 5 || sent[0].Block != 2 || sent[0].Value.Uint64() != 2 {
+		t.Fatalf("unexpected transfers of the sender %+v", sent)
+	}
+	received, err := transfers.Transfers(common.BigToAddress(big.NewInt(5)), 0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 1 || received[0].Block != 4 || received[0].From != sender {
+		t.Fatalf("unexpected transfers of the recipient %+v", received)
+	}
+	if limited, _ := transfers.Transfers(sender, 0, 2); len(limited) != 2 {
+		t.Fatalf("limit is ignored, got %d transfers", len(limited))
+	}
+
+	emitted, err := logs.Logs(common.HexToAddress("0x7070"), 5, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(emitted) != 2 || emitted[0].BlockNumber != 5 || emitted[1].BlockNumber != 6 || emitted[1].Topics[0] != transferTopic {
+		t.Fatalf("unexpected logs of the token %+v", emitted)
+	}
+}
@@ -0,0 +1,93 @@
+package indexer
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/inter/keys"
+)
+
+// indexedLog is the stored log. Unlike the RLP encoding of types.Log, it keeps the position of the log.
+type indexedLog struct {
+	Address   common.Address
+	Topics    []common.Hash
+	Data      []byte
+	Block     idx.Block
+	BlockHash common.Hash
+	TxHash    common.Hash
+	TxIndex   uint32
+	Index     uint32
+}
+
+// Logs indexes the logs by the address of the contract which emitted them.
+//
+// Keys are address (20 bytes) | block (8 bytes) | log index (4 bytes), so the logs of
+// a contract are iterated in the chain order.
+type Logs struct {
+	db kvdb.Store
+}
+
+// NewLogs wraps the DB (table) of the index.
+func NewLogs(db kvdb.Store) *Logs {
+	return &Logs{db}
+}
+
+// Name of the index.
+func (li *Logs) Name() string {
+	return "logs"
+}
+
+// IndexBlock adds the logs of the block.
+func (li *Logs) IndexBlock(block *evmcore.EvmBlock, receipts types.Receipts) error {
+	n := idx.Block(block.Number.Uint64())
+	for _, r := range receipts {
+		for _, l := range r.Logs {
+			b, err := rlp.EncodeToBytes(&indexedLog{
+				Address:   l.Address,
+				Topics:    l.Topics,
+				Data:      l.Data,
+				Block:     n,
+				BlockHash: block.Hash,
+				TxHash:    l.TxHash,
+				TxIndex:   uint32(l.TxIndex),
+				Index:     uint32(l.Index),
+			})
+			if err != nil {
+				return err
+			}
+			if err := li.db.Put(keys.AddressBlockPosition(l.Address, n, uint32(l.Index)), b); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Logs returns up to `limit` logs emitted by the contract, starting with the block `from`.
+func (li *Logs) Logs(addr common.Address, from idx.Block, limit int) ([]*types.Log, error) {
+	it := li.db.NewIterator(addr.Bytes(), keys.Block(from))
+	defer it.Release()
+
+	var res []*types.Log
+	for len(res) < limit && it.Next() {
+		var l indexedLog
+		if err := rlp.DecodeBytes(it.Value(), &l); err != nil {
+			return nil, err
+		}
+		res = append(res, &types.Log{
+			Address:     l.Address,
+			Topics:      l.Topics,
+			Data:        l.Data,
+			BlockNumber: uint64(l.Block),
+			BlockHash:   l.BlockHash,
+			TxHash:      l.TxHash,
+			TxIndex:     uint(l.TxIndex),
+			Index:       uint(l.Index),
+		})
+	}
+	return res, it.Error()
+}
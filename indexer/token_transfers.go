@@ -0,0 +1,113 @@
+package indexer
+
+import (
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/inter/keys"
+)
+
+// transferTopic is the topic of the Transfer event, which is the same for ERC-20 and ERC-721 tokens.
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// TokenTransfer is a transfer of ERC-20 tokens or of an ERC-721 token.
+// Value is the amount of ERC-20 tokens, or the ID of the ERC-721 token.
+type TokenTransfer struct {
+	Token    common.Address
+	From     common.Address
+	To       common.Address
+	Value    *big.Int
+	Block    idx.Block
+	TxHash   common.Hash
+	LogIndex uint32
+}
+
+// TokenTransfers indexes token transfers by the sender and the recipient addresses.
+//
+// Keys are address (20 bytes) | block (8 bytes) | log index (4 bytes), so the transfers of
+// an address are iterated in the chain order.
+type TokenTransfers struct {
+	db kvdb.Store
+}
+
+// NewTokenTransfers wraps the DB (table) of the index.
+func NewTokenTransfers(db kvdb.Store) *TokenTransfers {
+	return &TokenTransfers{db}
+}
+
+// Name of the index.
+func (tt *TokenTransfers) Name() string {
+	return "token_transfers"
+}
+
+// IndexBlock adds the token transfers of the block.
+func (tt *TokenTransfers) IndexBlock(block *evmcore.EvmBlock, receipts types.Receipts) error {
+	n := idx.Block(block.Number.Uint64())
+	for _, r := range receipts {
+		for _, l := range r.Logs {
+			t, ok := parseTransfer(l)
+			if !ok {
+				continue
+			}
+			t.Block = n
+			b, err := rlp.EncodeToBytes(&t)
+			if err != nil {
+				return err
+			}
+			if err := tt.db.Put(keys.AddressBlockPosition(t.From, n, t.LogIndex), b); err != nil {
+				return err
+			}
+			if t.To != t.From {
+				if err := tt.db.Put(keys.AddressBlockPosition(t.To, n, t.LogIndex), b); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Transfers returns up to `limit` transfers sent or received by the address, starting with the block `from`.
+func (tt *TokenTransfers) Transfers(addr common.Address, from idx.Block, limit int) ([]TokenTransfer, error) {
+	it := tt.db.NewIterator(addr.Bytes(), keys.Block(from))
+	defer it.Release()
+
+	var res []TokenTransfer
+	for len(res) < limit && it.Next() {
+		var t TokenTransfer
+		if err := rlp.DecodeBytes(it.Value(), &t); err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, it.Error()
+}
+
+func parseTransfer(l *types.Log) (TokenTransfer, bool) {
+	if len(l.Topics) < 3 || l.Topics[0] != transferTopic {
+		return TokenTransfer{}, false
+	}
+	t := TokenTransfer{
+		Token:    l.Address,
+		From:     common.BytesToAddress(l.Topics[1].Bytes()),
+		To:       common.BytesToAddress(l.Topics[2].Bytes()),
+		TxHash:   l.TxHash,
+		LogIndex: uint32(l.Index),
+	}
+	switch {
+	case len(l.Topics) == 4: // ERC-721, the token ID is indexed
+		t.Value = l.Topics[3].Big()
+	case len(l.Topics) == 3 && len(l.Data) == 32: // ERC-20
+		t.Value = new(big.Int).SetBytes(l.Data)
+	default:
+		return TokenTransfer{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,259 @@
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	lachesisleveldb "github.com/Fantom-foundation/lachesis-base/kvdb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// db.go opens the databases of the node.
+//
+// Overview:
+//   - DBProducer opens (and creates) the databases for the node which owns the datadir.
+//   - In the read-only mode, the databases are never created, locked exclusively or modified,
+//     so a datadir may be inspected (RPC queries, exports) while another process owns it,
+//     or for forensics of a corrupted node without the recovery rewriting it.
+//   - A database locked by a running node is opened from a checkpoint: the table files are
+//     immutable and hard-linked, the rest is copied into a temporary directory. The checkpoint
+//     is as of the moment it's taken, it doesn't see the later writes of the node.
+
+// ErrNoDatabase is returned when a database doesn't exist in the read-only mode.
+var ErrNoDatabase = errors.New("database doesn't exist")
+
+// DBProducer returns the producer of the databases in chaindataDir.
+// cacheFdLimit returns the cache size (in bytes) and the open files limit of the database.
+func DBProducer(chaindataDir string, cacheFdLimit func(string) (int, int), readonly bool) kvdb.IterableDBProducer {
+	if readonly {
+		return &readOnlyProducer{
+			datadir:      chaindataDir,
+			cacheFdLimit: cacheFdLimit,
+		}
+	}
+	return lachesisleveldb.NewProducer(chaindataDir, cacheFdLimit)
+}
+
+type readOnlyProducer struct {
+	datadir      string
+	cacheFdLimit func(string) (int, int)
+}
+
+// Names of existing databases.
+func (p *readOnlyProducer) Names() []string {
+	files, err := ioutil.ReadDir(p.datadir)
+	if err != nil {
+		return []string{}
+	}
+	var names []string
+	for _, f := range files {
+		if f.IsDir() {
+			names = append(names, f.Name())
+		}
+	}
+	return names
+}
+
+// OpenDB opens the existing database read-only.
+func (p *readOnlyProducer) OpenDB(name string) (kvdb.Store, error) {
+	path := filepath.Join(p.datadir, name)
+	if _, err := os.Stat(filepath.Join(path, "CURRENT")); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoDatabase, path)
+	}
+	cache, handles := p.cacheFdLimit(name)
+	o := &opt.Options{
+		ReadOnly:               true,
+		ErrorIfMissing:         true,
+		OpenFilesCacheCapacity: handles,
+		BlockCacheCapacity:     cache,
+	}
+
+	db, err := leveldb.OpenFile(path, o)
+	if err == nil {
+		return &readOnlyDB{db: db}, nil
+	}
+	if !isLocked(err) {
+		return nil, err
+	}
+	// the node holding the database doesn't let anyone else in, so take a checkpoint
+	checkpoint, err := ioutil.TempDir("", "opera-readonly-"+name)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkpointDB(path, checkpoint); err != nil {
+		_ = os.RemoveAll(checkpoint)
+		return nil, fmt.Errorf("database %s is locked, and its checkpoint failed: %w", path, err)
+	}
+	db, err = leveldb.OpenFile(checkpoint, o)
+	if err != nil {
+		_ = os.RemoveAll(checkpoint)
+		return nil, err
+	}
+	return &readOnlyDB{db: db, checkpoint: checkpoint}, nil
+}
+
+// isLocked returns true if the error means the database is locked by another process.
+func isLocked(err error) bool {
+	return errors.Is(err, syscall.EWOULDBLOCK) || errors.Is(err, syscall.EAGAIN)
+}
+
+// checkpointDB copies the database files, hard-linking the immutable table files.
+func checkpointDB(src, dst string) error {
+	files, err := ioutil.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || name == "LOCK" || name == "LOG" || strings.HasPrefix(name, "LOG.") {
+			continue
+		}
+		if ext := filepath.Ext(name); ext == ".ldb" || ext == ".sst" {
+			if err := os.Link(filepath.Join(src, name), filepath.Join(dst, name)); err == nil {
+				continue
+			}
+		}
+		if err := copyFile(filepath.Join(src, name), filepath.Join(dst, name)); err != nil {
+			if os.IsNotExist(err) {
+				continue // deleted by a compaction in the meantime
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// readOnlyDB is the database opened read-only, which rejects all the mutations.
+type readOnlyDB struct {
+	db         *leveldb.DB
+	checkpoint string // temporary checkpoint directory, empty if the database is opened in place
+}
+
+// Has retrieves if a key is present in the key-value store.
+func (db *readOnlyDB) Has(key []byte) (bool, error) {
+	return db.db.Has(key, nil)
+}
+
+// Get retrieves the given key if it's present in the key-value store.
+func (db *readOnlyDB) Get(key []byte) ([]byte, error) {
+	dat, err := db.db.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+	return dat, err
+}
+
+// NewIterator creates a binary-alphabetical iterator over the keys with the prefix, starting at start.
+func (db *readOnlyDB) NewIterator(prefix []byte, start []byte) kvdb.Iterator {
+	r := util.BytesPrefix(prefix)
+	r.Start = append(r.Start, start...)
+	return db.db.NewIterator(r, nil)
+}
+
+// GetSnapshot returns the snapshot of the database.
+func (db *readOnlyDB) GetSnapshot() (kvdb.Snapshot, error) {
+	snap, err := db.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &readOnlySnapshot{snap}, nil
+}
+
+// Stat returns a particular internal stat of the database.
+func (db *readOnlyDB) Stat(property string) (string, error) {
+	return db.db.GetProperty("leveldb." + property)
+}
+
+// Put is a mutation, so it isn't supported.
+func (db *readOnlyDB) Put(key []byte, value []byte) error {
+	return kvdb.ErrUnsupportedOp
+}
+
+// Delete is a mutation, so it isn't supported.
+func (db *readOnlyDB) Delete(key []byte) error {
+	return kvdb.ErrUnsupportedOp
+}
+
+// NewBatch returns the batch which rejects all the writes.
+func (db *readOnlyDB) NewBatch() kvdb.Batch {
+	return &readOnlyBatch{}
+}
+
+// Compact is a mutation, so it isn't supported.
+func (db *readOnlyDB) Compact(start []byte, limit []byte) error {
+	return kvdb.ErrUnsupportedOp
+}
+
+// Close closes the database and removes its checkpoint.
+func (db *readOnlyDB) Close() error {
+	err := db.db.Close()
+	if db.checkpoint != "" {
+		_ = os.RemoveAll(db.checkpoint)
+	}
+	return err
+}
+
+// Drop does nothing, the database is never removed in the read-only mode.
+func (db *readOnlyDB) Drop() {}
+
+type readOnlySnapshot struct {
+	snap *leveldb.Snapshot
+}
+
+func (s *readOnlySnapshot) Has(key []byte) (bool, error) {
+	return s.snap.Has(key, nil)
+}
+
+func (s *readOnlySnapshot) Get(key []byte) ([]byte, error) {
+	dat, err := s.snap.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+	return dat, err
+}
+
+func (s *readOnlySnapshot) NewIterator(prefix []byte, start []byte) kvdb.Iterator {
+	r := util.BytesPrefix(prefix)
+	r.Start = append(r.Start, start...)
+	return s.snap.NewIterator(r, nil)
+}
+
+func (s *readOnlySnapshot) Release() {
+	s.snap.Release()
+}
+
+// readOnlyBatch fails every write.
+type readOnlyBatch struct{}
+
+func (b *readOnlyBatch) Put(key []byte, value []byte) error { return kvdb.ErrUnsupportedOp }
+func (b *readOnlyBatch) Delete(key []byte) error            { return kvdb.ErrUnsupportedOp }
+func (b *readOnlyBatch) ValueSize() int                     { return 0 }
+func (b *readOnlyBatch) Write() error                       { return kvdb.ErrUnsupportedOp }
+func (b *readOnlyBatch) Reset()                             {}
+func (b *readOnlyBatch) Replay(w kvdb.Writer) error         { return nil }
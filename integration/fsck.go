@@ -0,0 +1,229 @@
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/inter/ibr"
+)
+
+// fsck.go cross-validates the node databases.
+//
+// Overview:
+//   The gossip store (events), the block store, the tx index and the LLR records are written
+//   by separate flushes, so a crash between them may leave them inconsistent. Fsck checks that:
+//     - every block up to the last one is stored, with a receipt per transaction;
+//     - the events of every block (including its Atropos) are in the gossip store;
+//     - every tx index entry points at the position of the transaction in its block,
+//       and every transaction of the blocks is indexed;
+//     - the block record hashes finalized by the LLR votes match the stored blocks.
+//   Only the derived indexes (the tx index) are repaired, by rebuilding them from the blocks.
+//   The other issues can't be repaired locally, the node must be resynced.
+//
+//   The node marks its chaindata dirty while the databases are open (see MarkDirty), so a
+//   crash leaves the marker behind, and FsckIfDirty checks the stores on the next start.
+
+// DirtyMarker is the file in the chaindata directory which exists while the databases are open.
+const DirtyMarker = "DIRTY"
+
+// ErrInconsistentStores is returned by FsckIfDirty if the stores have issues which can't be repaired.
+var ErrInconsistentStores = errors.New("node databases are inconsistent, resync is required")
+
+// FsckSource is the read access to the stores checked by Fsck.
+type FsckSource interface {
+	// LastBlock returns the last stored block.
+	LastBlock() idx.Block
+	// Block returns the stored block with its executed transactions and their receipts,
+	// in the block order, or nil if the block is missing.
+	Block(n idx.Block) (*inter.Block, types.Transactions, types.Receipts)
+	// HasEvent returns true if the event is in the gossip store.
+	HasEvent(id hash.Event) bool
+	// LlrBlockResult returns the hash of the block record finalized by the LLR votes,
+	// false if the block isn't finalized by LLR.
+	LlrBlockResult(n idx.Block) (hash.Hash, bool)
+}
+
+// TxPosition is the tx index entry, the position of a transaction in the chain.
+type TxPosition struct {
+	Block       idx.Block
+	BlockOffset uint32
+}
+
+// TxIndex is the index of the transaction positions. It's derived from the blocks,
+// so Fsck may rebuild it.
+type TxIndex interface {
+	GetTxPosition(tx common.Hash) *TxPosition
+	SetTxPosition(tx common.Hash, pos TxPosition)
+	DeleteTxPosition(tx common.Hash)
+	// ForEachTxPosition iterates all the entries until fn returns false.
+	ForEachTxPosition(fn func(tx common.Hash, pos TxPosition) bool)
+}
+
+// FsckIssueKind is the kind of an inconsistency.
+type FsckIssueKind string
+
+const (
+	FsckMissingBlock    FsckIssueKind = "missing-block"
+	FsckMissingReceipts FsckIssueKind = "missing-receipts"
+	FsckMissingEvent    FsckIssueKind = "missing-event"
+	FsckTxIndex         FsckIssueKind = "tx-index"
+	FsckLlrMismatch     FsckIssueKind = "llr-mismatch"
+)
+
+// FsckIssue is an inconsistency found by Fsck.
+type FsckIssue struct {
+	Kind     FsckIssueKind
+	Block    idx.Block
+	Detail   string
+	Repaired bool
+}
+
+// FsckReport is the outcome of Fsck.
+type FsckReport struct {
+	Blocks idx.Block // number of checked blocks
+	Issues []FsckIssue
+}
+
+// Unrepaired returns the issues which remain after the repair.
+func (r *FsckReport) Unrepaired() []FsckIssue {
+	var res []FsckIssue
+	for _, issue := range r.Issues {
+		if !issue.Repaired {
+			res = append(res, issue)
+		}
+	}
+	return res
+}
+
+func (r *FsckReport) add(kind FsckIssueKind, n idx.Block, repaired bool, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, FsckIssue{
+		Kind:     kind,
+		Block:    n,
+		Detail:   fmt.Sprintf(format, args...),
+		Repaired: repaired,
+	})
+}
+
+// Fsck cross-validates the stores. If repair is true, the tx index is fixed to match the blocks.
+func Fsck(src FsckSource, txIndex TxIndex, repair bool) *FsckReport {
+	report := &FsckReport{}
+	last := src.LastBlock()
+	stored := make(map[common.Hash]TxPosition)
+	for n := idx.Block(1); n <= last; n++ {
+		report.Blocks++
+		block, txs, receipts := src.Block(n)
+		if block == nil {
+			report.add(FsckMissingBlock, n, false, "block %d is missing", n)
+			continue
+		}
+		if len(receipts) != len(txs) {
+			report.add(FsckMissingReceipts, n, false, "%d receipts of %d transactions", len(receipts), len(txs))
+		}
+		if !src.HasEvent(block.Atropos) {
+			report.add(FsckMissingEvent, n, false, "Atropos %s is missing", block.Atropos)
+		}
+		for _, e := range block.Events {
+			if e != block.Atropos && !src.HasEvent(e) {
+				report.add(FsckMissingEvent, n, false, "event %s is missing", e)
+			}
+		}
+
+		for i, tx := range txs {
+			want := TxPosition{Block: n, BlockOffset: uint32(i)}
+			stored[tx.Hash()] = want
+			if got := txIndex.GetTxPosition(tx.Hash()); got == nil || *got != want {
+				if repair {
+					txIndex.SetTxPosition(tx.Hash(), want)
+				}
+				report.add(FsckTxIndex, n, repair, "tx %s is indexed at %v, want %v", tx.Hash().Hex(), got, want)
+			}
+		}
+
+		if llrHash, ok := src.LlrBlockResult(n); ok && len(receipts) == len(txs) {
+			if recordHash := blockRecordHash(block, txs, receipts); recordHash != llrHash {
+				report.add(FsckLlrMismatch, n, false, "block record %s doesn't match the LLR-finalized %s", recordHash, llrHash)
+			}
+		}
+	}
+
+	// the entries of transactions which aren't in the blocks
+	var dangling []common.Hash
+	txIndex.ForEachTxPosition(func(tx common.Hash, pos TxPosition) bool {
+		if _, ok := stored[tx]; !ok {
+			dangling = append(dangling, tx)
+			report.add(FsckTxIndex, pos.Block, repair, "tx %s is indexed at %v, but it isn't in the blocks", tx.Hex(), pos)
+		}
+		return true
+	})
+	if repair {
+		for _, tx := range dangling {
+			txIndex.DeleteTxPosition(tx)
+		}
+	}
+	return report
+}
+
+// blockRecordHash returns the hash of the block record the LLR votes are cast for.
+func blockRecordHash(block *inter.Block, txs types.Transactions, receipts types.Receipts) hash.Hash {
+	forStorage := make([]*types.ReceiptForStorage, len(receipts))
+	for i, r := range receipts {
+		forStorage[i] = (*types.ReceiptForStorage)(r)
+	}
+	return ibr.LlrFullBlockRecord{
+		Atropos:  block.Atropos,
+		Root:     block.Root,
+		Txs:      txs,
+		Receipts: forStorage,
+		Time:     block.Time,
+		GasUsed:  block.GasUsed,
+	}.Hash()
+}
+
+// MarkDirty marks the chaindata as open, the marker stays if the node crashes.
+func MarkDirty(chaindataDir string) error {
+	return ioutil.WriteFile(filepath.Join(chaindataDir, DirtyMarker), nil, 0600)
+}
+
+// MarkClean removes the marker on the clean shutdown, after the databases are flushed and closed.
+func MarkClean(chaindataDir string) error {
+	err := os.Remove(filepath.Join(chaindataDir, DirtyMarker))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// IsDirty returns true if the node didn't shut down cleanly.
+func IsDirty(chaindataDir string) bool {
+	_, err := os.Stat(filepath.Join(chaindataDir, DirtyMarker))
+	return err == nil
+}
+
+// FsckIfDirty checks and repairs the stores if the node didn't shut down cleanly.
+// It returns nil report if the shutdown was clean, and ErrInconsistentStores if there are
+// issues which can't be repaired.
+func FsckIfDirty(chaindataDir string, src FsckSource, txIndex TxIndex) (*FsckReport, error) {
+	if !IsDirty(chaindataDir) {
+		return nil, nil
+	}
+	log.Warn("Dirty shutdown detected, checking the databases consistency")
+	report := Fsck(src, txIndex, true)
+	for _, issue := range report.Issues {
+		log.Warn("Database inconsistency", "kind", issue.Kind, "block", issue.Block, "repaired", issue.Repaired, "detail", issue.Detail)
+	}
+	if unrepaired := report.Unrepaired(); len(unrepaired) != 0 {
+		return report, fmt.Errorf("%w: %d issue(s), the first one at block %d: %s", ErrInconsistentStores, len(unrepaired), unrepaired[0].Block, unrepaired[0].Detail)
+	}
+	log.Info("Databases are consistent", "blocks", report.Blocks, "repaired", len(report.Issues))
+	return report, nil
+}
@@ -0,0 +1,109 @@
+package integration
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// precompiles.go lets app-chains plug custom precompiled contracts into the EVM.
+//
+// Overview:
+//   A precompile is registered with its address and the block it's activated at. The EVM
+//   config of a block (see Precompiles.VMConfig) carries the built-in Opera precompiles
+//   plus the registered ones which are active at the block, so evmcore executes them the
+//   same way as the EVM writer, without any changes to evmcore.
+//
+//   The precompiles are a part of the consensus: all the nodes of the network must register
+//   the same precompiles with the same activation blocks, otherwise they produce different
+//   states. A precompile must be activated at a future block, never retroactively.
+//
+//   The addresses of the Ethereum precompiles (up to 0xffff, including the ones Ethereum may
+//   add later) and of the Opera system contracts (the 0xd100 prefix) are reserved.
+
+var (
+	// ErrReservedAddress is returned if a precompile is registered at a reserved address.
+	ErrReservedAddress = errors.New("address is reserved")
+	// ErrDuplicatePrecompile is returned if a precompile is already registered at the address.
+	ErrDuplicatePrecompile = errors.New("precompile is already registered at the address")
+	// ErrNilPrecompile is returned if a precompile has no contract.
+	ErrNilPrecompile = errors.New("precompile has no contract")
+)
+
+// ethPrecompilesEnd is the first address after the range reserved for the Ethereum precompiles.
+var ethPrecompilesEnd = common.BigToAddress(new(big.Int).Lsh(big.NewInt(1), 16))
+
+// operaSystemPrefix is the prefix of the addresses of the Opera system contracts.
+var operaSystemPrefix = []byte{0xd1, 0x00}
+
+// Precompile is a custom precompiled contract.
+type Precompile struct {
+	Name     string
+	Address  common.Address
+	Contract vm.PrecompiledStateContract
+	// Activation is the first block where the precompile is callable
+	Activation idx.Block
+}
+
+// IsReservedAddress returns true if the address is reserved for the Ethereum precompiles
+// or for the Opera system contracts.
+func IsReservedAddress(addr common.Address) bool {
+	return bytes.Compare(addr.Bytes(), ethPrecompilesEnd.Bytes()) < 0 || bytes.HasPrefix(addr.Bytes(), operaSystemPrefix)
+}
+
+// Precompiles is the registry of the custom precompiles. It must be filled before the node starts.
+type Precompiles struct {
+	list []Precompile // sorted by the address
+}
+
+// NewPrecompiles creates an empty registry.
+func NewPrecompiles() *Precompiles {
+	return &Precompiles{}
+}
+
+// Register adds the precompile to the registry.
+func (pp *Precompiles) Register(p Precompile) error {
+	if p.Contract == nil {
+		return fmt.Errorf("%s: %w", p.Name, ErrNilPrecompile)
+	}
+	if IsReservedAddress(p.Address) {
+		return fmt.Errorf("%s at %s: %w", p.Name, p.Address.Hex(), ErrReservedAddress)
+	}
+	i := sort.Search(len(pp.list), func(i int) bool {
+		return bytes.Compare(pp.list[i].Address.Bytes(), p.Address.Bytes()) >= 0
+	})
+	if i < len(pp.list) && pp.list[i].Address == p.Address {
+		return fmt.Errorf("%s at %s: %w (%s)", p.Name, p.Address.Hex(), ErrDuplicatePrecompile, pp.list[i].Name)
+	}
+	pp.list = append(pp.list, Precompile{})
+	copy(pp.list[i+1:], pp.list[i:])
+	pp.list[i] = p
+	return nil
+}
+
+// List returns the registered precompiles in the order of their addresses.
+func (pp *Precompiles) List() []Precompile {
+	return append([]Precompile(nil), pp.list...)
+}
+
+// VMConfig returns the EVM config of the block: the base config with the registered
+// precompiles which are active at the block. The base config isn't modified.
+func (pp *Precompiles) VMConfig(base vm.Config, block idx.Block) vm.Config {
+	cfg := base
+	cfg.StatePrecompiles = make(map[common.Address]vm.PrecompiledStateContract, len(base.StatePrecompiles)+len(pp.list))
+	for addr, c := range base.StatePrecompiles {
+		cfg.StatePrecompiles[addr] = c
+	}
+	for _, p := range pp.list {
+		if block >= p.Activation {
+			cfg.StatePrecompiles[p.Address] = p.Contract
+		}
+	}
+	return cfg
+}
@@ -1,6 +1,10 @@
 package integration
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/rony4d/go-opera-asset/utils/throttle"
+)
 
 // Package integration provides configuration presets and assembly helpers for
 // building the Opera node runtime. Presets bundle common settings (cache sizes,
@@ -27,6 +31,11 @@ type PresetConfig struct {
 	EnableMetrics  bool   // whether to expose Prometheus-style metrics endpoints
 	EnableTracing  bool   // whether to enable distributed tracing (Jaeger, etc.)
 	EnableLightKDF bool   // use faster (weaker) key derivation for keystore passwords
+
+	// background tasks yield to the live block processing within these limits
+	SyncThrottle       throttle.Config // historical sync, rate in blocks per second
+	IndexThrottle      throttle.Config // index building, rate in blocks per second
+	CompactionThrottle throttle.Config // DB compaction, rate in MB per second
 }
 
 func DefaultPreset() PresetConfig {
@@ -39,6 +48,10 @@ func DefaultPreset() PresetConfig {
 		EnableMetrics:  false,   // metrics disabled by default to reduce overhead
 		EnableTracing:  false,   // tracing disabled by default (adds latency)
 		EnableLightKDF: false,   // strong key derivation for production security
+		// no rate limits, only a few parallel background workers
+		SyncThrottle:       throttle.Config{MaxConcurrency: 4},
+		IndexThrottle:      throttle.Config{MaxConcurrency: 2},
+		CompactionThrottle: throttle.Config{MaxConcurrency: 1},
 	}
 }
 
@@ -63,6 +76,8 @@ func LitePreset() PresetConfig {
 	cfg.DBPreset = "lite"     // use minimal DB schema optimized for small datasets
 	cfg.EnableMetrics = true  // enable metrics to help diagnose issues during development
 	cfg.EnableLightKDF = true // faster key derivation speeds up account unlock during testing
+	// constrained environments can't afford to compact at full disk speed
+	cfg.CompactionThrottle = throttle.Config{MaxConcurrency: 1, Rate: 16}
 	return cfg
 }
 
@@ -88,6 +103,10 @@ func FullPreset() PresetConfig {
 	cfg.EnableMetrics = true   // expose metrics for Prometheus/Grafana dashboards
 	cfg.EnableTracing = true   // enable distributed tracing for production debugging
 	cfg.EnableLightKDF = false // strong key derivation: critical for validator key security
+	// a validator must not miss emission deadlines while it catches up
+	cfg.SyncThrottle = throttle.Config{MaxConcurrency: 2, Rate: 500}
+	cfg.IndexThrottle = throttle.Config{MaxConcurrency: 1, Rate: 200}
+	cfg.CompactionThrottle = throttle.Config{MaxConcurrency: 1, Rate: 32}
 	return cfg
 }
 
@@ -113,6 +132,10 @@ func ArchivePreset() PresetConfig {
 	cfg.EnableMetrics = true   // metrics help monitor long-running archival sync jobs
 	cfg.EnableTracing = true   // tracing aids debugging complex historical queries
 	cfg.EnableLightKDF = false // maintain strong security even for archival nodes
+	// explorers need the history and the indexes as soon as possible
+	cfg.SyncThrottle = throttle.Config{MaxConcurrency: 8}
+	cfg.IndexThrottle = throttle.Config{MaxConcurrency: 4}
+	cfg.CompactionThrottle = throttle.Config{MaxConcurrency: 2}
 	return cfg
 }
 
@@ -166,6 +189,10 @@ func ApplyPreset(target *PresetConfig, preset PresetConfig) {
 	target.EnableMetrics = preset.EnableMetrics
 	target.EnableTracing = preset.EnableTracing
 	target.EnableLightKDF = preset.EnableLightKDF
+	// throttles are always applied, zero limits mean no throttling
+	target.SyncThrottle = preset.SyncThrottle
+	target.IndexThrottle = preset.IndexThrottle
+	target.CompactionThrottle = preset.CompactionThrottle
 	if preset.Name != "" {
 		target.Name = preset.Name
 	}
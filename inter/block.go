@@ -106,6 +106,14 @@ type Block struct {
 	// a commitment to the entire state, allowing efficient state verification
 	// without storing the full state data.
 	Root hash.Hash
+
+	// ReceiptHash is the Merkle root hash of the receipt trie of the block.
+	// It's computed by the block processor along with Root. Blocks stored
+	// before the field was introduced have a zero ReceiptHash.
+	ReceiptHash hash.Hash `rlp:"optional"`
+
+	// Bloom is the bloom filter of the logs of all the block receipts.
+	Bloom types.Bloom `rlp:"optional"`
 }
 
 // EstimateSize returns an approximate size estimate of the block in bytes.
@@ -121,6 +129,8 @@ type Block struct {
 //   - Txs hashes: len(Txs) * 32 bytes
 //   - Atropos hash: 1 * 32 bytes
 //   - Root hash: 1 * 32 bytes
+//   - ReceiptHash: 1 * 32 bytes
+//   - Bloom: 256 bytes
 //   - SkippedTxs indexes: len(SkippedTxs) * 4 bytes (each uint32 is 4 bytes)
 //   - GasUsed: 8 bytes (uint64)
 //   - Time: 8 bytes (Timestamp is uint64 internally)
@@ -129,16 +139,16 @@ type Block struct {
 // exactly due to RLP encoding overhead, but it's accurate enough for
 // memory allocation and network planning purposes.
 func (b *Block) EstimateSize() int {
-	// Calculate hash storage: Events + InternalTxs + Txs + Atropos + Root
+	// Calculate hash storage: Events + InternalTxs + Txs + Atropos + Root + ReceiptHash
 	// Each hash is 32 bytes
-	hashCount := len(b.Events) + len(b.InternalTxs) + len(b.Txs) + 1 + 1
+	hashCount := len(b.Events) + len(b.InternalTxs) + len(b.Txs) + 1 + 1 + 1
 	hashBytes := hashCount * 32
 
 	// Calculate SkippedTxs storage: each uint32 index is 4 bytes
 	skippedBytes := len(b.SkippedTxs) * 4
 
-	// Calculate fixed-size fields: GasUsed (8 bytes) + Time (8 bytes)
-	fixedBytes := 8 + 8
+	// Calculate fixed-size fields: GasUsed (8 bytes) + Time (8 bytes) + Bloom
+	fixedBytes := 8 + 8 + types.BloomByteLength
 
 	return hashBytes + skippedBytes + fixedBytes
 }
@@ -0,0 +1,67 @@
+package inter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// legacyBlock is the encoding of the blocks stored before ReceiptHash and Bloom were introduced.
+type legacyBlock struct {
+	Time        Timestamp
+	Atropos     hash.Event
+	Events      hash.Events
+	Txs         []common.Hash
+	InternalTxs []common.Hash
+	SkippedTxs  []uint32
+	GasUsed     uint64
+	Root        hash.Hash
+}
+
+func TestBlock_OptionalFields(t *testing.T) {
+	legacy := legacyBlock{
+		Time:       FromUnix(1600000000),
+		Atropos:    hash.Event{1},
+		Events:     hash.Events{{2}, {3}},
+		Txs:        []common.Hash{{4}},
+		SkippedTxs: []uint32{1},
+		GasUsed:    21000,
+		Root:       hash.Hash{5},
+	}
+	legacyBytes, err := rlp.EncodeToBytes(&legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a stored legacy block decodes with the zero receipts root and bloom
+	var b Block
+	if err := rlp.DecodeBytes(legacyBytes, &b); err != nil {
+		t.Fatalf("failed to decode legacy block: %v", err)
+	}
+	if b.ReceiptHash != (hash.Hash{}) || b.Bloom != (types.Bloom{}) || b.Root != legacy.Root || b.GasUsed != legacy.GasUsed {
+		t.Errorf("unexpected decoded legacy block %+v", b)
+	}
+	// and encodes back into the same bytes
+	if again, err := rlp.EncodeToBytes(&b); err != nil || string(again) != string(legacyBytes) {
+		t.Errorf("legacy block encoding changed: %x != %x (%v)", again, legacyBytes, err)
+	}
+
+	// the executed block round-trips its receipts root and bloom
+	b.ReceiptHash = hash.Hash{6}
+	b.Bloom[0] = 7
+	full, err := rlp.EncodeToBytes(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Block
+	if err := rlp.DecodeBytes(full, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, b) {
+		t.Errorf("decoded block %+v, want %+v", decoded, b)
+	}
+}
@@ -0,0 +1,119 @@
+package inter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/dag"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+)
+
+// DAG invariants which hold for every valid set of events, regardless of the consensus state.
+// They are cheap to check without a DAG index, so they're used to validate events files
+// before replaying them and in tests which generate events.
+var (
+	ErrLamportNotAboveParents = errors.New("lamport isn't greater than parents' lamports")
+	ErrFrameBelowParents      = errors.New("frame is less than a parent's frame")
+	ErrSelfParentCreator      = errors.New("self-parent has a different creator")
+	ErrSeqNotMonotonic        = errors.New("seq isn't next to the self-parent's seq")
+)
+
+// CheckDagInvariants checks that the events are consistent with each other:
+//   - lamport is greater than lamports of all the parents
+//   - frame is not less than frames of all the parents
+//   - seq is the self-parent's seq + 1, and the self-parent has the same creator
+//
+// The events may be in any order. Parents which aren't in the set are skipped,
+// so a set may start at any point of an epoch.
+// Returns the first violation found, annotated with the offending event ID.
+func CheckDagInvariants(events []dag.Event) error {
+	c := NewDagChecker()
+	for _, e := range events {
+		if err := c.Add(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dagHeader is the part of an event which the DAG invariants depend on.
+type dagHeader struct {
+	id      hash.Event
+	creator idx.ValidatorID
+	seq     idx.Event
+	frame   idx.Frame
+	lamport idx.Lamport
+}
+
+// dagChild is an event which waits for its parent to be added.
+type dagChild struct {
+	dagHeader
+	selfParent bool
+}
+
+// DagChecker checks the DAG invariants (see CheckDagInvariants) of a stream of events.
+// It keeps only a small header of every added event, not the events themselves,
+// so it's used to check events files which don't fit into memory.
+type DagChecker struct {
+	seen    map[hash.Event]dagHeader
+	waiting map[hash.Event][]dagChild // by the ID of the parent which isn't added yet
+}
+
+// NewDagChecker creates an empty DagChecker.
+func NewDagChecker() *DagChecker {
+	return &DagChecker{
+		seen:    make(map[hash.Event]dagHeader),
+		waiting: make(map[hash.Event][]dagChild),
+	}
+}
+
+// Add checks the event against its parents which are already added, and the already added
+// events against it as their parent. The events may be added in any order.
+// Returns the first violation found, annotated with the offending event ID.
+func (c *DagChecker) Add(e dag.Event) error {
+	h := dagHeader{
+		id:      e.ID(),
+		creator: e.Creator(),
+		seq:     e.Seq(),
+		frame:   e.Frame(),
+		lamport: e.Lamport(),
+	}
+	for _, pid := range e.Parents() {
+		child := dagChild{h, e.IsSelfParent(pid)}
+		p, ok := c.seen[pid]
+		if !ok {
+			c.waiting[pid] = append(c.waiting[pid], child)
+			continue
+		}
+		if err := checkParentInvariants(child, p); err != nil {
+			return fmt.Errorf("event %s: %w", h.id.String(), err)
+		}
+	}
+	for _, child := range c.waiting[h.id] {
+		if err := checkParentInvariants(child, h); err != nil {
+			return fmt.Errorf("event %s: %w", child.id.String(), err)
+		}
+	}
+	delete(c.waiting, h.id)
+	c.seen[h.id] = h
+	return nil
+}
+
+func checkParentInvariants(e dagChild, p dagHeader) error {
+	if e.lamport <= p.lamport {
+		return ErrLamportNotAboveParents
+	}
+	if e.frame < p.frame {
+		return ErrFrameBelowParents
+	}
+	if e.selfParent {
+		if p.creator != e.creator {
+			return ErrSelfParentCreator
+		}
+		if e.seq != p.seq+1 {
+			return ErrSeqNotMonotonic
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package inter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/dag"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/stretchr/testify/require"
+)
+
+// buildDagEvent builds an event of the epoch 1 on top of the parents (the self-parent goes first).
+func buildDagEvent(creator idx.ValidatorID, seq idx.Event, lamport idx.Lamport, frame idx.Frame, parents ...dag.Event) *EventPayload {
+	e := MutableEventPayload{}
+	e.SetVersion(1)
+	e.SetEpoch(1)
+	e.SetCreator(creator)
+	e.SetSeq(seq)
+	e.SetLamport(lamport)
+	e.SetFrame(frame)
+	ids := hash.Events{}
+	for _, p := range parents {
+		ids.Add(p.ID())
+	}
+	e.SetParents(ids)
+	return e.Build()
+}
+
+func TestCheckDagInvariants(t *testing.T) {
+	a1 := buildDagEvent(1, 1, 1, 1)
+	b1 := buildDagEvent(2, 1, 1, 1)
+	a2 := buildDagEvent(1, 2, 2, 1, a1, b1)
+	b2 := buildDagEvent(2, 2, 3, 2, b1, a2)
+
+	a2f := buildDagEvent(1, 2, 2, 2, a1, b1) // a fork of a2 with a higher frame
+
+	valid := []dag.Event{b2, a1, a2, b1, a2f}
+	require.NoError(t, CheckDagInvariants(valid))
+	// partial sets are fine, unknown parents are skipped
+	require.NoError(t, CheckDagInvariants([]dag.Event{b2, a2}))
+	require.NoError(t, CheckDagInvariants(nil))
+
+	for name, tc := range map[string]struct {
+		e   dag.Event
+		err error
+	}{
+		"same lamport as parent": {buildDagEvent(2, 2, 2, 2, b1, a2), ErrLamportNotAboveParents},
+		"frame below parent":     {buildDagEvent(2, 2, 3, 1, b1, a2f), ErrFrameBelowParents},
+		"seq gap":                {buildDagEvent(2, 3, 3, 2, b1, a2), ErrSeqNotMonotonic},
+		"foreign self-parent":    {buildDagEvent(2, 2, 3, 2, a2, b1), ErrSelfParentCreator},
+	} {
+		t.Run(name, func(t *testing.T) {
+			events := append([]dag.Event{tc.e}, valid...)
+			err := CheckDagInvariants(events)
+			require.True(t, errors.Is(err, tc.err), "got %v", err)
+		})
+	}
+}
+
+func TestDagChecker_childBeforeParent(t *testing.T) {
+	a1 := buildDagEvent(1, 1, 3, 1)
+	a2 := buildDagEvent(1, 2, 2, 1, a1) // lamport isn't above the self-parent's one
+
+	c := NewDagChecker()
+	require.NoError(t, c.Add(a2))
+	err := c.Add(a1)
+	require.True(t, errors.Is(err, ErrLamportNotAboveParents), "got %v", err)
+	require.Contains(t, err.Error(), a2.ID().String(), "the child must be blamed")
+}
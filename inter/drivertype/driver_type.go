@@ -18,6 +18,12 @@ var (
 	// 1 << 7 means the 8th bit is set (binary 10000000, decimal 128).
 	DoublesignBit = uint64(1 << 7)
 
+	// OfflineBit is a bitmask flag used to mark a validator which stayed silent for longer
+	// than the offline threshold of the network rules. Unlike double-signing, it's not
+	// slashable: the validator may re-activate itself once it's back online.
+	// 1 << 3 means the 4th bit is set (binary 00001000, decimal 8).
+	OfflineBit = uint64(1 << 3)
+
 	// OkStatus represents the clean state of a validator with no adverse status bits set.
 	OkStatus = uint64(0)
 )
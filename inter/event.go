@@ -47,7 +47,6 @@ package inter
 import (
 	"crypto/sha256"
 
-	"github.com/Fantom-foundation/lachesis-base/common/bigendian"
 	"github.com/Fantom-foundation/lachesis-base/hash"
 	"github.com/Fantom-foundation/lachesis-base/inter/dag"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
@@ -229,7 +228,8 @@ type EventPayload struct {
 	payloadData
 
 	// cache
-	_size int // Size in bytes
+	_size     int       // Size in bytes
+	_txsCache *txsCache // Memoized tx hashes and senders, shared by copies
 }
 
 // MutableEventPayload is a builder struct used to construct a new EventPayload.
@@ -424,6 +424,7 @@ func (e *MutableEventPayload) build(locatorHash hash.Hash, baseHash hash.Hash, s
 		},
 		payloadData: e.payloadData,
 		_size:       size,
+		_txsCache:   &txsCache{},
 	}
 }
 
@@ -442,16 +443,11 @@ func (e *MutableEventPayload) Build() *EventPayload {
 
 // HashToSign computes the unique identifier that is signed by the validator.
 // It combines the BaseHash (parents, etc.) with epoch, sequence, and payload hash.
+// It's the SHA-256 of the fixed locator encoding (see locator_encoding.go), computed without allocations.
 func (l EventLocator) HashToSign() hash.Hash {
-	return hash.Of(
-		l.BaseHash.Bytes(),
-		bigendian.Uint16ToBytes(l.NetForkID),
-		l.Epoch.Bytes(),
-		l.Seq.Bytes(),
-		l.Lamport.Bytes(),
-		l.Creator.Bytes(),
-		l.PayloadHash.Bytes(),
-	)
+	var buf [EventLocatorSize]byte
+	l.EncodeTo(&buf)
+	return sha256.Sum256(buf[:])
 }
 
 // ID returns a shortened identifier (EventID) based on the full HashToSign.
@@ -0,0 +1,39 @@
+package inter
+
+import (
+	"errors"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/dag"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+)
+
+// Event IDs embed the epoch (bytes 0-3) and the lamport time (bytes 4-7) of the event.
+// The stores rely on this prefix to iterate events of an epoch and to find the lamport of a parent
+// without loading it, so a crafted parent ID with a wrong prefix would corrupt the epoch-scoped indexes.
+var (
+	ErrWrongParentEpoch   = errors.New("parent ID has a different epoch")
+	ErrWrongParentLamport = errors.New("parent ID has an inconsistent lamport")
+)
+
+// ValidateParentID checks that the prefix of a parent ID is consistent with the child event.
+// The parent must be from the same epoch and must have a lamport in the range [1, lamport).
+func ValidateParentID(epoch idx.Epoch, lamport idx.Lamport, parent hash.Event) error {
+	if parent.Epoch() != epoch {
+		return ErrWrongParentEpoch
+	}
+	if parent.Lamport() == 0 || parent.Lamport() >= lamport {
+		return ErrWrongParentLamport
+	}
+	return nil
+}
+
+// ValidateParentIDs checks the prefixes of all the event parent IDs.
+func ValidateParentIDs(e dag.Event) error {
+	for _, p := range e.Parents() {
+		if err := ValidateParentID(e.Epoch(), e.Lamport(), p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package inter
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeParentID(epoch idx.Epoch, lamport idx.Lamport) hash.Event {
+	parent := MutableEventPayload{}
+	parent.SetVersion(1)
+	parent.SetEpoch(epoch)
+	parent.SetLamport(lamport)
+	return parent.Build().ID()
+}
+
+func TestValidateParentIDs(t *testing.T) {
+	require.NoError(t, ValidateParentIDs(FakeEvent(1, 0, 0, false)))
+
+	for name, tc := range map[string]struct {
+		parent hash.Event
+		err    error
+	}{
+		"ok":            {fakeParentID(10, 99), nil},
+		"first":         {fakeParentID(10, 1), nil},
+		"other epoch":   {fakeParentID(11, 99), ErrWrongParentEpoch},
+		"zero lamport":  {fakeParentID(10, 0), ErrWrongParentLamport},
+		"same lamport":  {fakeParentID(10, 100), ErrWrongParentLamport},
+		"later lamport": {fakeParentID(10, 101), ErrWrongParentLamport},
+	} {
+		t.Run(name, func(t *testing.T) {
+			e := MutableEventPayload{}
+			e.SetVersion(1)
+			e.SetEpoch(10)
+			e.SetLamport(100)
+			e.SetParents(hash.Events{fakeParentID(10, 50), tc.parent})
+			require.Equal(t, tc.err, ValidateParentIDs(e.Build()))
+		})
+	}
+}
+
+func TestEventSerialization_ParentEpoch(t *testing.T) {
+	e := MutableEventPayload{}
+	e.SetVersion(1)
+	e.SetEpoch(10)
+	e.SetLamport(100)
+	e.SetPayloadHash(EmptyPayloadHash(1))
+	e.SetParents(hash.Events{fakeParentID(11, 50)})
+
+	_, err := e.Build().MarshalBinary()
+	require.Equal(t, ErrSerMalformedEvent, err)
+}
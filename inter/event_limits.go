@@ -0,0 +1,50 @@
+package inter
+
+import (
+	"errors"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+)
+
+var (
+	ErrTooManyParents = errors.New("event has too many parents")
+	ErrTooLargeExtra  = errors.New("event extra data is too large")
+	ErrTooManyTxs     = errors.New("event has too many transactions")
+	ErrTooLargeEvent  = errors.New("event is too large")
+)
+
+// EventLimits are the protocol limits of the event structure.
+// They're derived from the network rules, see opera.DagRules.EventLimits.
+type EventLimits struct {
+	MaxParents   idx.Event
+	MaxExtraData uint32
+	MaxTxs       uint32 // zero means no limit, only the rules of the releases before the limit have it
+	MaxSize      int    // limit of the serialized event, including the payload
+}
+
+// CheckLimits returns an error if the event exceeds the limits.
+func (e *EventPayload) CheckLimits(limits EventLimits) error {
+	if idx.Event(len(e.Parents())) > limits.MaxParents {
+		return ErrTooManyParents
+	}
+	if uint32(len(e.Extra())) > limits.MaxExtraData {
+		return ErrTooLargeExtra
+	}
+	if limits.MaxTxs != 0 && uint32(len(e.Txs())) > limits.MaxTxs {
+		return ErrTooManyTxs
+	}
+	if e.Size() > limits.MaxSize {
+		return ErrTooLargeEvent
+	}
+	return nil
+}
+
+// BuildWithLimits finalizes the event like Build, but rejects it if the protocol limits are exceeded.
+// Emitter uses it to not sign (and not broadcast) an event which other nodes would reject anyway.
+func (e *MutableEventPayload) BuildWithLimits(limits EventLimits) (*EventPayload, error) {
+	built := e.Build()
+	if err := built.CheckLimits(limits); err != nil {
+		return nil, err
+	}
+	return built, nil
+}
@@ -0,0 +1,57 @@
+package inter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildWithLimits(t *testing.T) {
+	limits := EventLimits{
+		MaxParents:   2,
+		MaxExtraData: 8,
+		MaxTxs:       1,
+		MaxSize:      1024,
+	}
+	newEvent := func(parents int, extra int) *MutableEventPayload {
+		e := &MutableEventPayload{}
+		e.SetVersion(1)
+		e.SetEpoch(1)
+		e.SetLamport(100)
+		ids := hash.Events{}
+		for i := 0; i < parents; i++ {
+			ids.Add(fakeParentID(1, 50))
+		}
+		e.SetParents(ids)
+		e.SetExtra(bytes.Repeat([]byte{1}, extra))
+		e.SetPayloadHash(EmptyPayloadHash(1))
+		return e
+	}
+
+	built, err := newEvent(2, 8).BuildWithLimits(limits)
+	require.NoError(t, err)
+	require.Equal(t, newEvent(2, 8).Build().ID(), built.ID())
+
+	_, err = newEvent(3, 0).BuildWithLimits(limits)
+	require.Equal(t, ErrTooManyParents, err)
+
+	_, err = newEvent(0, 9).BuildWithLimits(limits)
+	require.Equal(t, ErrTooLargeExtra, err)
+
+	withTxs := newEvent(0, 0)
+	withTxs.SetTxs(types.Transactions{types.NewTx(&types.LegacyTx{Nonce: 1}), types.NewTx(&types.LegacyTx{Nonce: 2})})
+	withTxs.SetPayloadHash(CalcPayloadHash(withTxs))
+	_, err = withTxs.BuildWithLimits(limits)
+	require.Equal(t, ErrTooManyTxs, err)
+	noTxsLimit := limits
+	noTxsLimit.MaxTxs = 0
+	_, err = withTxs.BuildWithLimits(noTxsLimit)
+	require.NoError(t, err)
+
+	limits.MaxExtraData = 2048
+	_, err = newEvent(0, 1024).BuildWithLimits(limits)
+	require.Equal(t, ErrTooLargeEvent, err)
+}
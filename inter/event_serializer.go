@@ -112,6 +112,9 @@ func (e *Event) MarshalCSER(w *cser.Writer) error {
 		if e.Lamport() < p.Lamport() {
 			return ErrSerMalformedEvent // Child cannot be older than parent
 		}
+		if e.Epoch() != p.Epoch() {
+			return ErrSerMalformedEvent // Parent epoch isn't serialized, it'd be replaced on decoding
+		}
 		// Optimization: Store parent lamport as difference (varint friendly)
 		w.U32(uint32(e.Lamport() - p.Lamport()))
 		// Store parent hash suffix (assuming prefix is known or full hash used depending on impl)
@@ -193,6 +196,9 @@ func eventUnmarshalCSER(r *cser.Reader, e *MutableEventPayload) (err error) {
 	parents := make(hash.Events, 0, parentsNum)
 	for i := uint32(0); i < parentsNum; i++ {
 		lamportDiff := r.U32()
+		if lamportDiff > lamport {
+			return ErrSerMalformedEvent // Parent lamport would underflow
+		}
 		h := [24]byte{}
 		r.FixedBytes(h[:]) // Reads the suffix
 
@@ -483,6 +489,29 @@ func (e *EventPayload) UnmarshalBinary(raw []byte) (err error) {
 	return nil
 }
 
+// UnmarshalEventHeader decodes only the header of a serialized EventPayload.
+//
+// The header is written first into both CSER streams, so it can be read without
+// touching the signature and the body (txs, MPs, votes). The event ID depends only
+// on the header (the body is committed to via the payload hash), so the returned
+// Event has a valid ID. It's used to cheaply filter out already known events
+// before the full payload is decoded.
+//
+// Note: the body isn't validated, i.e. a successful result doesn't mean that the
+// whole payload is well-formed.
+func UnmarshalEventHeader(raw []byte) (*Event, error) {
+	mutE := MutableEventPayload{}
+	err := cser.UnmarshalBinaryPrefixAdapter(raw, func(r *cser.Reader) error {
+		return eventUnmarshalCSER(r, &mutE)
+	})
+	if err != nil {
+		return nil, err
+	}
+	eventSer, _ := mutE.immutable().Event.MarshalBinary()
+	locatorHash, baseHash := calcEventHashes(eventSer, &mutE)
+	return &mutE.build(locatorHash, baseHash, len(raw)).Event, nil
+}
+
 // EncodeRLP implements rlp.Encoder interface.
 func (e *EventPayload) EncodeRLP(w io.Writer) error {
 	bytes, err := e.MarshalBinary()
@@ -241,6 +241,31 @@ func TestEventPayloadSerialization_Corrupted(t *testing.T) {
 	}
 }
 
+// TestUnmarshalEventHeader verifies that the header-only decoder yields the same
+// event ID as the full payload decoder, without requiring the body to be consumed.
+func TestUnmarshalEventHeader(t *testing.T) {
+	cases := map[string]EventPayload{
+		"empty_v0": emptyEvent(0),
+		"empty_v1": emptyEvent(1),
+		"random":   *FakeEvent(12, 1, 1, true),
+	}
+
+	for name, original := range cases {
+		t.Run(name, func(t *testing.T) {
+			bin, err := original.MarshalBinary()
+			require.NoError(t, err)
+
+			var full EventPayload
+			require.NoError(t, full.UnmarshalBinary(bin))
+
+			header, err := UnmarshalEventHeader(bin)
+			require.NoError(t, err)
+			assert.Equal(t, full.ID(), header.ID())
+			assert.Equal(t, full.PayloadHash(), header.PayloadHash())
+		})
+	}
+}
+
 // TestEventRPCMarshaling verifies the JSON RPC marshaling logic for Events and EventPayloads.
 // It ensures that fields are correctly mapped to their JSON representation and back.
 func TestEventRPCMarshaling(t *testing.T) {
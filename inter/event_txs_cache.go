@@ -0,0 +1,83 @@
+package inter
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// txsCache memoizes values derived from the txs of an EventPayload.
+//
+// The same payload is processed many times (event checks, block building, RPC),
+// and each pass would otherwise redo keccak for the tx hashes and ECDSA recovery
+// for the senders. EventPayload is immutable, so the cache never gets stale and
+// may be shared by all the copies of the payload.
+type txsCache struct {
+	hashesOnce sync.Once
+	hashes     []common.Hash
+
+	sendersMu sync.Mutex
+	signer    types.Signer // signer the senders were recovered with
+	senders   []common.Address
+}
+
+// TxHashes returns the hashes of the payload's txs.
+// They're computed on first call only. The returned slice must not be modified.
+func (e *EventPayload) TxHashes() []common.Hash {
+	if e._txsCache == nil {
+		return calcTxHashes(e.txs)
+	}
+	c := e._txsCache
+	c.hashesOnce.Do(func() {
+		c.hashes = calcTxHashes(e.txs)
+	})
+	return c.hashes
+}
+
+// TxSenders returns the senders of the payload's txs, recovered with the signer.
+// They're recovered on first call only. The result is cached for a single signer,
+// calls with a different signer aren't cached. The returned slice must not be modified.
+func (e *EventPayload) TxSenders(signer types.Signer) ([]common.Address, error) {
+	if e._txsCache == nil {
+		return recoverTxSenders(signer, e.txs)
+	}
+	c := e._txsCache
+	c.sendersMu.Lock()
+	defer c.sendersMu.Unlock()
+
+	if c.signer != nil {
+		if c.signer.Equal(signer) {
+			return c.senders, nil
+		}
+		return recoverTxSenders(signer, e.txs)
+	}
+	senders, err := recoverTxSenders(signer, e.txs)
+	if err != nil {
+		// errors aren't cached, the payload may be checked against another signer
+		return nil, err
+	}
+	c.signer = signer
+	c.senders = senders
+	return senders, nil
+}
+
+func calcTxHashes(txs types.Transactions) []common.Hash {
+	hashes := make([]common.Hash, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash()
+	}
+	return hashes
+}
+
+func recoverTxSenders(signer types.Signer, txs types.Transactions) ([]common.Address, error) {
+	senders := make([]common.Address, len(txs))
+	for i, tx := range txs {
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			return nil, err
+		}
+		senders[i] = sender
+	}
+	return senders, nil
+}
@@ -0,0 +1,95 @@
+package inter
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// signedTxsEvent creates an event with txsNum properly signed txs.
+func signedTxsEvent(t testing.TB, txsNum int) (*EventPayload, types.Signer, common.Address) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := types.LatestSignerForChainID(big.NewInt(0xfa3))
+
+	txs := make(types.Transactions, txsNum)
+	for i := range txs {
+		to := common.Address{byte(i)}
+		txs[i], err = types.SignTx(types.NewTransaction(uint64(i), to, big.NewInt(1), 21000, big.NewInt(1), nil), signer, key)
+		require.NoError(t, err)
+	}
+
+	me := MutableEventPayload{}
+	me.SetVersion(1)
+	me.SetEpoch(1)
+	me.SetParents(hash.Events{})
+	me.SetExtra([]byte{})
+	me.SetTxs(txs)
+	me.SetPayloadHash(CalcPayloadHash(&me))
+	return me.Build(), signer, crypto.PubkeyToAddress(key.PublicKey)
+}
+
+// TestEventPayload_TxsCache verifies that memoized tx hashes and senders match
+// freshly computed ones, including for copies of the payload.
+func TestEventPayload_TxsCache(t *testing.T) {
+	e, signer, from := signedTxsEvent(t, 10)
+
+	hashes := e.TxHashes()
+	require.Len(t, hashes, 10)
+	for i, tx := range e.Txs() {
+		require.Equal(t, tx.Hash(), hashes[i])
+	}
+
+	senders, err := e.TxSenders(signer)
+	require.NoError(t, err)
+	require.Len(t, senders, 10)
+	for _, sender := range senders {
+		require.Equal(t, from, sender)
+	}
+
+	// copies share the cache
+	cp := *e
+	cpSenders, err := cp.TxSenders(signer)
+	require.NoError(t, err)
+	require.Same(t, &senders[0], &cpSenders[0])
+
+	// a different signer isn't served from the cache
+	_, err = e.TxSenders(types.LatestSignerForChainID(big.NewInt(1)))
+	require.Error(t, err)
+
+	// decoded payload gets its own cache
+	bin, err := e.MarshalBinary()
+	require.NoError(t, err)
+	var decoded EventPayload
+	require.NoError(t, decoded.UnmarshalBinary(bin))
+	require.Equal(t, hashes, decoded.TxHashes())
+}
+
+// BenchmarkEventPayload_TxSenders compares recovering the senders on every pass
+// with the memoized senders of the payload.
+func BenchmarkEventPayload_TxSenders(b *testing.B) {
+	e, signer, _ := signedTxsEvent(b, 100)
+
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, tx := range e.Txs() {
+				// signer.Sender bypasses the per-tx cache
+				if _, err := signer.Sender(tx); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := e.TxSenders(signer); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
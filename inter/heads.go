@@ -0,0 +1,256 @@
+package inter
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/dag"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+)
+
+// heads.go tracks the DAG heads and selects the parents of a new event among them.
+//
+// Overview:
+//   The heads are the known events which aren't parents of any other known event. Adding
+//   an event removes its parents from the heads, and RemoveCovered removes the heads which
+//   the caller knows to be observed by other events (e.g. by the vector clock).
+//
+//   SelectParents takes the self-parent first, then up to max-1 heads of other creators,
+//   at most one per creator (a creator with several heads is a cheater, its highest head is
+//   taken). The other parents are chosen by a pluggable ParentStrategy: random, stake-weighted
+//   or latency-aware. The strategies are deterministic for the same input and random source.
+
+// EventsDiff returns the events of a which aren't in b, in the order of a.
+func EventsDiff(a, b hash.Events) hash.Events {
+	exclude := b.Set()
+	res := make(hash.Events, 0, len(a))
+	for _, id := range a {
+		if !exclude.Contains(id) {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+// EventsMerge returns the events of a followed by the events of b which aren't in a,
+// without duplicates.
+func EventsMerge(a, b hash.Events) hash.Events {
+	seen := make(hash.EventsSet, len(a)+len(b))
+	res := make(hash.Events, 0, len(a)+len(b))
+	for _, ids := range []hash.Events{a, b} {
+		for _, id := range ids {
+			if !seen.Contains(id) {
+				seen.Add(id)
+				res = append(res, id)
+			}
+		}
+	}
+	return res
+}
+
+// Heads is the set of the DAG heads. It isn't safe for concurrent use.
+type Heads struct {
+	byID map[hash.Event]dag.Event
+}
+
+// NewHeads creates an empty set of heads.
+func NewHeads() *Heads {
+	return &Heads{
+		byID: make(map[hash.Event]dag.Event),
+	}
+}
+
+// AddHead adds the event to the heads and removes its parents from them.
+func (h *Heads) AddHead(e dag.Event) {
+	for _, p := range e.Parents() {
+		delete(h.byID, p)
+	}
+	h.byID[e.ID()] = e
+}
+
+// RemoveCovered removes the heads for which covered returns true,
+// and returns the number of the removed heads.
+func (h *Heads) RemoveCovered(covered func(head dag.Event) bool) int {
+	removed := 0
+	for id, e := range h.byID {
+		if covered(e) {
+			delete(h.byID, id)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Contains returns true if the event is a head.
+func (h *Heads) Contains(id hash.Event) bool {
+	_, ok := h.byID[id]
+	return ok
+}
+
+// Len returns the number of the heads.
+func (h *Heads) Len() int {
+	return len(h.byID)
+}
+
+// Slice returns the heads sorted by their IDs.
+func (h *Heads) Slice() []dag.Event {
+	res := make([]dag.Event, 0, len(h.byID))
+	for _, e := range h.byID {
+		res = append(res, e)
+	}
+	sortEventsByID(res)
+	return res
+}
+
+// IDs returns the IDs of the heads sorted.
+func (h *Heads) IDs() hash.Events {
+	heads := h.Slice()
+	ids := make(hash.Events, len(heads))
+	for i, e := range heads {
+		ids[i] = e.ID()
+	}
+	return ids
+}
+
+func sortEventsByID(ee []dag.Event) {
+	sort.Slice(ee, func(i, j int) bool {
+		return idLess(ee[i].ID(), ee[j].ID())
+	})
+}
+
+func idLess(a, b hash.Event) bool {
+	return bytes.Compare(a[:], b[:]) < 0
+}
+
+// ParentStrategy chooses the parents of a new event among the candidate heads.
+type ParentStrategy interface {
+	// Choose returns up to n of the candidates, in the order of the preference.
+	// The candidates are sorted by their IDs and have distinct creators.
+	Choose(candidates []dag.Event, n int) []dag.Event
+}
+
+// SelectParents returns the parents of a new event of the creator: its self-parent
+// (if any) first, then up to max-1 heads chosen by the strategy.
+func (h *Heads) SelectParents(strategy ParentStrategy, creator idx.ValidatorID, selfParent *hash.Event, max int) hash.Events {
+	parents := make(hash.Events, 0, max)
+	if selfParent != nil && max > 0 {
+		parents = append(parents, *selfParent)
+	}
+	// the highest head per creator, the creator's own heads are represented by the self-parent
+	best := make(map[idx.ValidatorID]dag.Event)
+	for _, e := range h.byID {
+		if e.Creator() == creator {
+			continue
+		}
+		if b, ok := best[e.Creator()]; !ok || e.Lamport() > b.Lamport() ||
+			(e.Lamport() == b.Lamport() && idLess(e.ID(), b.ID())) {
+			best[e.Creator()] = e
+		}
+	}
+	candidates := make([]dag.Event, 0, len(best))
+	for _, e := range best {
+		candidates = append(candidates, e)
+	}
+	sortEventsByID(candidates)
+
+	if n := max - len(parents); n > 0 {
+		for _, e := range strategy.Choose(candidates, n) {
+			parents = append(parents, e.ID())
+		}
+	}
+	return parents
+}
+
+// RandomStrategy chooses uniformly random heads.
+type RandomStrategy struct {
+	Rand *rand.Rand
+}
+
+// Choose implements ParentStrategy.
+func (s RandomStrategy) Choose(candidates []dag.Event, n int) []dag.Event {
+	res := append([]dag.Event(nil), candidates...)
+	s.Rand.Shuffle(len(res), func(i, j int) {
+		res[i], res[j] = res[j], res[i]
+	})
+	if n < len(res) {
+		res = res[:n]
+	}
+	return res
+}
+
+// StakeWeightedStrategy chooses random heads with the probability proportional to the stake
+// of their creators, so the new event observes a quorum sooner.
+type StakeWeightedStrategy struct {
+	Validators *pos.Validators
+	Rand       *rand.Rand
+}
+
+// Choose implements ParentStrategy.
+func (s StakeWeightedStrategy) Choose(candidates []dag.Event, n int) []dag.Event {
+	left := append([]dag.Event(nil), candidates...)
+	weights := make([]uint64, len(left))
+	var total uint64
+	for i, e := range left {
+		weights[i] = uint64(s.Validators.Get(e.Creator()))
+		total += weights[i]
+	}
+
+	res := make([]dag.Event, 0, n)
+	for len(res) < n && len(left) > 0 {
+		chosen := len(left) - 1
+		if total > 0 {
+			r := uint64(s.Rand.Int63n(int64(total)))
+			for i, w := range weights {
+				if r < w {
+					chosen = i
+					break
+				}
+				r -= w
+			}
+		}
+		res = append(res, left[chosen])
+		total -= weights[chosen]
+		left = append(left[:chosen], left[chosen+1:]...)
+		weights = append(weights[:chosen], weights[chosen+1:]...)
+	}
+	return res
+}
+
+// LatencyAwareStrategy chooses the heads of the creators with the lowest latency first,
+// e.g. by the announce-to-delivery latency of their events, so the new event references
+// the heads most of the network already has.
+type LatencyAwareStrategy struct {
+	// Latency returns the latency of the creator, or a negative value if it's unknown
+	Latency func(creator idx.ValidatorID) time.Duration
+}
+
+// Choose implements ParentStrategy. The creators of unknown latency go last.
+func (s LatencyAwareStrategy) Choose(candidates []dag.Event, n int) []dag.Event {
+	type scored struct {
+		e       dag.Event
+		latency time.Duration
+	}
+	ss := make([]scored, len(candidates))
+	for i, e := range candidates {
+		ss[i] = scored{e, s.Latency(e.Creator())}
+	}
+	sort.SliceStable(ss, func(i, j int) bool {
+		a, b := ss[i].latency, ss[j].latency
+		if (a < 0) != (b < 0) {
+			return b < 0
+		}
+		return a < b
+	})
+	if n > len(ss) {
+		n = len(ss)
+	}
+	res := make([]dag.Event, n)
+	for i := range res {
+		res[i] = ss[i].e
+	}
+	return res
+}
@@ -0,0 +1,227 @@
+package inter
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/dag"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventsDiffMerge(t *testing.T) {
+	a := buildDagEvent(1, 1, 1, 1).ID()
+	b := buildDagEvent(2, 1, 1, 1).ID()
+	c := buildDagEvent(3, 1, 1, 1).ID()
+
+	require.Equal(t, hash.Events{a, c}, EventsDiff(hash.Events{a, b, c}, hash.Events{b}))
+	require.Equal(t, hash.Events{}, EventsDiff(hash.Events{a}, hash.Events{a, b}))
+	require.Equal(t, hash.Events{c, a, b}, EventsMerge(hash.Events{c, a, c}, hash.Events{a, b}))
+	require.Equal(t, hash.Events{}, EventsMerge(nil, nil))
+}
+
+func TestHeadsAddRemove(t *testing.T) {
+	a1 := buildDagEvent(1, 1, 1, 1)
+	b1 := buildDagEvent(2, 1, 1, 1)
+	c1 := buildDagEvent(3, 1, 1, 1)
+	a2 := buildDagEvent(1, 2, 2, 1, a1, b1)
+
+	heads := NewHeads()
+	for _, e := range []dag.Event{a1, b1, c1} {
+		heads.AddHead(e)
+	}
+	require.Equal(t, 3, heads.Len())
+
+	heads.AddHead(a2)
+	require.Equal(t, 2, heads.Len())
+	require.True(t, heads.Contains(a2.ID()))
+	require.True(t, heads.Contains(c1.ID()))
+	require.False(t, heads.Contains(a1.ID()))
+	require.False(t, heads.Contains(b1.ID()))
+
+	removed := heads.RemoveCovered(func(e dag.Event) bool {
+		return e.Creator() == 3
+	})
+	require.Equal(t, 1, removed)
+	require.Equal(t, hash.Events{a2.ID()}, heads.IDs())
+}
+
+// buildHeads returns n heads of distinct creators 1..n, the event of the creator 1 is the self-parent.
+func buildHeads(n int) (*Heads, *pos.Validators) {
+	heads := NewHeads()
+	b := pos.NewBuilder()
+	for i := 1; i <= n; i++ {
+		heads.AddHead(buildDagEvent(idx.ValidatorID(i), 1, 1, 1))
+		b.Set(idx.ValidatorID(i), pos.Weight(i))
+	}
+	return heads, b.Build()
+}
+
+func creatorOf(heads *Heads, id hash.Event) idx.ValidatorID {
+	for _, e := range heads.Slice() {
+		if e.ID() == id {
+			return e.Creator()
+		}
+	}
+	return 0
+}
+
+func TestSelectParents(t *testing.T) {
+	heads, validators := buildHeads(10)
+	self := heads.Slice()[0]
+	for _, e := range heads.Slice() {
+		if e.Creator() == 1 {
+			self = e
+		}
+	}
+	selfID := self.ID()
+
+	strategies := map[string]ParentStrategy{
+		"random":         RandomStrategy{Rand: rand.New(rand.NewSource(1))},
+		"stake-weighted": StakeWeightedStrategy{Validators: validators, Rand: rand.New(rand.NewSource(1))},
+		"latency-aware": LatencyAwareStrategy{Latency: func(creator idx.ValidatorID) time.Duration {
+			return time.Duration(creator) * time.Millisecond
+		}},
+	}
+	for name, strategy := range strategies {
+		parents := heads.SelectParents(strategy, 1, &selfID, 4)
+		require.Len(t, parents, 4, name)
+		require.Equal(t, selfID, parents[0], name)
+		require.Len(t, parents.Set(), 4, name)
+
+		// all the heads fit
+		parents = heads.SelectParents(strategy, 1, &selfID, 100)
+		require.Len(t, parents, 10, name)
+
+		// without a self-parent, the creator's own head isn't chosen
+		parents = heads.SelectParents(strategy, 1, nil, 100)
+		require.Len(t, parents, 9, name)
+		require.NotContains(t, parents, selfID, name)
+
+		require.Empty(t, heads.SelectParents(strategy, 1, &selfID, 0), name)
+	}
+}
+
+func TestSelectParentsOneHeadPerCreator(t *testing.T) {
+	b1 := buildDagEvent(2, 1, 1, 1)
+	b1fork := buildDagEvent(2, 1, 3, 1)
+	heads := NewHeads()
+	heads.AddHead(b1)
+	heads.AddHead(b1fork)
+	require.Equal(t, 2, heads.Len())
+
+	parents := heads.SelectParents(RandomStrategy{Rand: rand.New(rand.NewSource(1))}, 1, nil, 10)
+	require.Equal(t, hash.Events{b1fork.ID()}, parents)
+}
+
+func TestLatencyAwareStrategy(t *testing.T) {
+	heads, _ := buildHeads(5)
+	latency := map[idx.ValidatorID]time.Duration{
+		2: 30 * time.Millisecond,
+		3: 10 * time.Millisecond,
+		4: -1, // unknown
+		5: 20 * time.Millisecond,
+	}
+	strategy := LatencyAwareStrategy{Latency: func(creator idx.ValidatorID) time.Duration {
+		return latency[creator]
+	}}
+	parents := heads.SelectParents(strategy, 1, nil, 4)
+	creators := make([]idx.ValidatorID, len(parents))
+	for i, id := range parents {
+		creators[i] = creatorOf(heads, id)
+	}
+	require.Equal(t, []idx.ValidatorID{3, 5, 2, 4}, creators)
+}
+
+func TestStakeWeightedStrategy(t *testing.T) {
+	heads := NewHeads()
+	b := pos.NewBuilder()
+	b.Set(1, 1)
+	b.Set(2, 1000)
+	heads.AddHead(buildDagEvent(1, 1, 1, 1))
+	heads.AddHead(buildDagEvent(2, 1, 1, 1))
+	strategy := StakeWeightedStrategy{Validators: b.Build(), Rand: rand.New(rand.NewSource(1))}
+
+	// the heavy validator is chosen first almost always
+	heavy := 0
+	for i := 0; i < 1000; i++ {
+		parents := heads.SelectParents(strategy, 0, nil, 1)
+		require.Len(t, parents, 1)
+		if creatorOf(heads, parents[0]) == 2 {
+			heavy++
+		}
+	}
+	require.Greater(t, heavy, 950)
+
+	// the sampling is without replacement
+	require.Len(t, heads.SelectParents(strategy, 0, nil, 2).Set(), 2)
+}
+
+func TestStrategiesDeterministic(t *testing.T) {
+	heads, validators := buildHeads(50)
+	for i := 0; i < 2; i++ {
+		r1 := heads.SelectParents(RandomStrategy{Rand: rand.New(rand.NewSource(42))}, 1, nil, 10)
+		r2 := heads.SelectParents(RandomStrategy{Rand: rand.New(rand.NewSource(42))}, 1, nil, 10)
+		require.Equal(t, r1, r2)
+		s1 := heads.SelectParents(StakeWeightedStrategy{validators, rand.New(rand.NewSource(42))}, 1, nil, 10)
+		s2 := heads.SelectParents(StakeWeightedStrategy{validators, rand.New(rand.NewSource(42))}, 1, nil, 10)
+		require.Equal(t, s1, s2)
+	}
+}
+
+func BenchmarkHeadsAddHead(b *testing.B) {
+	const n = 128
+	events := make([]dag.Event, n)
+	for i := range events {
+		events[i] = buildDagEvent(idx.ValidatorID(i+1), 1, 1, 1)
+	}
+	next := make([]dag.Event, n)
+	for i := range next {
+		next[i] = buildDagEvent(idx.ValidatorID(i+1), 2, 2, 1, events[i], events[(i+1)%n])
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heads := NewHeads()
+		for _, e := range events {
+			heads.AddHead(e)
+		}
+		for _, e := range next {
+			heads.AddHead(e)
+		}
+	}
+}
+
+func BenchmarkSelectParents(b *testing.B) {
+	for _, n := range []int{128, 512} {
+		heads, validators := buildHeads(n)
+		strategies := map[string]ParentStrategy{
+			"random":         RandomStrategy{Rand: rand.New(rand.NewSource(1))},
+			"stake-weighted": StakeWeightedStrategy{Validators: validators, Rand: rand.New(rand.NewSource(1))},
+			"latency-aware": LatencyAwareStrategy{Latency: func(creator idx.ValidatorID) time.Duration {
+				return time.Duration(creator%17) * time.Millisecond
+			}},
+		}
+		for name, strategy := range strategies {
+			b.Run(fmt.Sprintf("%s/%d", name, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					heads.SelectParents(strategy, 1, nil, 10)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkEventsMerge(b *testing.B) {
+	heads, _ := buildHeads(128)
+	ids := heads.IDs()
+	x, y := ids[:96], ids[32:]
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		EventsMerge(x, y)
+		EventsDiff(x, y)
+	}
+}
@@ -0,0 +1,116 @@
+package iblockproc
+
+import (
+	"math"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// block_limits.go caps the number and the size of the block transactions.
+//
+// Overview:
+//   A block takes the transactions of all the events confirmed by its Atropos, so a burst of
+//   events may produce a mega-block which stalls the execution and the RPC consumers, even
+//   if it's within the gas limit. Blocks.MaxBlockTxs and Blocks.MaxBlockSize cap the number
+//   and the total size of the block transactions.
+//
+//   The transactions of a block are the overflow of the previous blocks followed by the
+//   transactions of the confirmed events, in the confirmation order. The longest prefix within
+//   the limits goes into the block, the rest is kept in BlockState.Overflow for the next block.
+//   The split depends only on the rules and the confirmed events, so all the nodes assemble
+//   the same blocks. A block takes at least one transaction, so a transaction above
+//   MaxBlockSize doesn't stall the chain, and a block is created while the overflow isn't
+//   empty, even if no events are confirmed.
+//
+//   The overflow is capped by the transactions of MaxOverflowBlocks full blocks, so a sustained
+//   burst can't grow the state without a bound. The transactions above the cap, i.e. the latest
+//   confirmed ones, are dropped: they're never executed and their nonces aren't used, so they may
+//   be submitted again.
+//
+//   The overflow survives the epoch sealing (see SealEpoch): it leads the first block of the next
+//   epoch, and is split and capped by the rules of the next epoch. The fees of the transactions
+//   originated by the validators which left aren't attributed (see AttributeFees).
+
+// MaxOverflowBlocks is the cap of the overflow, in the full blocks.
+const MaxOverflowBlocks = 64
+
+// OverflowTx is a transaction carried over into the next block, with the creator of the event
+// which carried it, so the fees are attributed as if it wasn't moved (see AttributeFees).
+type OverflowTx struct {
+	Tx         *types.Transaction
+	Originator idx.ValidatorID
+}
+
+// HasOverflow returns true if some transactions are waiting for the next block.
+func (bs BlockState) HasOverflow() bool {
+	return len(bs.Overflow) != 0
+}
+
+// AssembleBlockTxs returns the transactions of the next block and their originators, and keeps
+// the transactions above the rules limits in the overflow for the block after it, dropping
+// the transactions above the overflow cap. It must be called once per block, with the events
+// confirmed by the block.
+func (bs *BlockState) AssembleBlockTxs(rules opera.BlocksRules, events []inter.EventPayloadI) (types.Transactions, []idx.ValidatorID) {
+	queue := bs.Overflow
+	for _, e := range events {
+		for _, tx := range e.Txs() {
+			queue = append(queue, OverflowTx{Tx: tx, Originator: e.Creator()})
+		}
+	}
+	cut := blockTxsCut(rules, queue)
+
+	txs := make(types.Transactions, cut)
+	originators := make([]idx.ValidatorID, cut)
+	for i, otx := range queue[:cut] {
+		txs[i] = otx.Tx
+		originators[i] = otx.Originator
+	}
+	overflow := queue[cut:]
+	overflow = overflow[:overflowCut(rules, overflow)]
+	bs.Overflow = nil
+	if len(overflow) != 0 {
+		bs.Overflow = make([]OverflowTx, len(overflow))
+		copy(bs.Overflow, overflow)
+	}
+	return txs, originators
+}
+
+// blockTxsCut returns the number of the queued transactions which fit into a block.
+func blockTxsCut(rules opera.BlocksRules, queue []OverflowTx) int {
+	size := uint64(0)
+	for i, otx := range queue {
+		if rules.MaxBlockTxs != 0 && uint64(i) >= uint64(rules.MaxBlockTxs) {
+			return i
+		}
+		size += uint64(otx.Tx.Size())
+		if rules.MaxBlockSize != 0 && size > rules.MaxBlockSize && i != 0 {
+			return i
+		}
+	}
+	return len(queue)
+}
+
+// overflowCut returns the number of the overflow transactions within the cap of MaxOverflowBlocks
+// full blocks.
+func overflowCut(rules opera.BlocksRules, overflow []OverflowTx) int {
+	maxTxs := uint64(rules.MaxBlockTxs) * MaxOverflowBlocks
+	maxSize := rules.MaxBlockSize * MaxOverflowBlocks
+	if rules.MaxBlockSize > math.MaxUint64/MaxOverflowBlocks {
+		maxSize = 0 // the cap isn't reachable
+	}
+	size := uint64(0)
+	for i, otx := range overflow {
+		if maxTxs != 0 && uint64(i) >= maxTxs {
+			return i
+		}
+		size += uint64(otx.Tx.Size())
+		if maxSize != 0 && size > maxSize {
+			return i
+		}
+	}
+	return len(overflow)
+}
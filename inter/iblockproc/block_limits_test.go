@@ -0,0 +1,166 @@
+package iblockproc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/inter/drivertype"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+func limitsEvent(creator idx.ValidatorID, first uint64, n int, dataSize int) inter.EventPayloadI {
+	txs := make(types.Transactions, n)
+	for i := range txs {
+		txs[i] = types.NewTx(&types.LegacyTx{Nonce: first + uint64(i), GasPrice: big.NewInt(1), Gas: 21000, Data: make([]byte, dataSize)})
+	}
+	me := &inter.MutableEventPayload{}
+	me.SetCreator(creator)
+	me.SetTxs(txs)
+	return me.Build()
+}
+
+func TestAssembleBlockTxsCount(t *testing.T) {
+	rules := opera.BlocksRules{MaxBlockTxs: 3}
+	var bs BlockState
+
+	txs, originators := bs.AssembleBlockTxs(rules, []inter.EventPayloadI{limitsEvent(1, 0, 2, 0), limitsEvent(2, 2, 3, 0)})
+	if len(txs) != 3 || txs[2].Nonce() != 2 || originators[1] != 1 || originators[2] != 2 {
+		t.Fatalf("unexpected first block %d txs, originators %v", len(txs), originators)
+	}
+	if !bs.HasOverflow() || len(bs.Overflow) != 2 {
+		t.Fatalf("overflow isn't kept: %d txs", len(bs.Overflow))
+	}
+
+	// the overflow leads the next block, ahead of its own events
+	txs, originators = bs.AssembleBlockTxs(rules, []inter.EventPayloadI{limitsEvent(3, 5, 2, 0)})
+	if len(txs) != 3 || txs[0].Nonce() != 3 || txs[2].Nonce() != 5 || originators[0] != 2 || originators[2] != 3 {
+		t.Fatalf("unexpected second block %d txs, originators %v", len(txs), originators)
+	}
+	// a block is assembled from the overflow alone
+	txs, _ = bs.AssembleBlockTxs(rules, nil)
+	if len(txs) != 1 || txs[0].Nonce() != 6 || bs.HasOverflow() {
+		t.Fatalf("overflow isn't drained: %d txs, %d left", len(txs), len(bs.Overflow))
+	}
+
+	// no limits
+	txs, _ = bs.AssembleBlockTxs(opera.BlocksRules{}, []inter.EventPayloadI{limitsEvent(1, 0, 100, 0)})
+	if len(txs) != 100 || bs.HasOverflow() {
+		t.Fatalf("transactions are split without limits: %d txs", len(txs))
+	}
+}
+
+func TestAssembleBlockTxsSize(t *testing.T) {
+	e := limitsEvent(1, 0, 5, 1000)
+	txSize := uint64(e.Txs()[0].Size())
+	var bs BlockState
+
+	txs, _ := bs.AssembleBlockTxs(opera.BlocksRules{MaxBlockSize: 2*txSize + 1}, []inter.EventPayloadI{e})
+	if len(txs) != 2 || len(bs.Overflow) != 3 {
+		t.Fatalf("block size isn't limited: %d txs, %d left", len(txs), len(bs.Overflow))
+	}
+	// a transaction larger than the limit still makes a block
+	txs, _ = bs.AssembleBlockTxs(opera.BlocksRules{MaxBlockSize: txSize / 2}, nil)
+	if len(txs) != 1 || len(bs.Overflow) != 2 {
+		t.Fatalf("large transaction stalls the blocks: %d txs, %d left", len(txs), len(bs.Overflow))
+	}
+}
+
+func TestAssembleBlockTxsOverflowCap(t *testing.T) {
+	var bs BlockState
+	rules := opera.BlocksRules{MaxBlockTxs: 2}
+	txs, _ := bs.AssembleBlockTxs(rules, []inter.EventPayloadI{limitsEvent(1, 0, 2+2*MaxOverflowBlocks+5, 0)})
+	if len(txs) != 2 || len(bs.Overflow) != 2*MaxOverflowBlocks {
+		t.Fatalf("overflow isn't capped by count: %d txs, %d left", len(txs), len(bs.Overflow))
+	}
+	// the latest transactions are dropped
+	if last := bs.Overflow[len(bs.Overflow)-1].Tx.Nonce(); last != 2*MaxOverflowBlocks+1 {
+		t.Fatalf("unexpected last overflow transaction %d", last)
+	}
+	// the capped overflow still grows by the next events once it's drained
+	txs, _ = bs.AssembleBlockTxs(rules, []inter.EventPayloadI{limitsEvent(1, 1000, 1, 0)})
+	if len(txs) != 2 || txs[0].Nonce() != 2 || len(bs.Overflow) != 2*MaxOverflowBlocks-1 {
+		t.Fatalf("unexpected block after the cap: %d txs, %d left", len(txs), len(bs.Overflow))
+	}
+
+	e := limitsEvent(1, 0, MaxOverflowBlocks+10, 100)
+	txSize := uint64(e.Txs()[0].Size())
+	bs = BlockState{}
+	bs.AssembleBlockTxs(opera.BlocksRules{MaxBlockSize: txSize}, []inter.EventPayloadI{e})
+	if len(bs.Overflow) != MaxOverflowBlocks {
+		t.Fatalf("overflow isn't capped by size: %d left", len(bs.Overflow))
+	}
+}
+
+func TestSealEpochOverflow(t *testing.T) {
+	b := pos.NewBuilder()
+	b.Set(1, 10)
+	b.Set(2, 20)
+	rules := opera.FakeNetRules()
+	rules.Blocks.MaxBlockTxs = 2
+	es := EpochState{Epoch: 5, Validators: b.Build(), Rules: rules}
+	es.ValidatorStates = make([]ValidatorEpochState, es.Validators.Len())
+	nextRules := rules.Copy()
+	nextRules.Blocks.MaxBlockTxs = 3
+	bs := BlockState{
+		ValidatorStates:       []ValidatorBlockState{{Originated: new(big.Int)}, {Originated: new(big.Int)}},
+		NextValidatorProfiles: ValidatorProfiles{1: drivertype.Validator{Weight: big.NewInt(10)}},
+		DirtyRules:            &nextRules,
+	}
+	bs.AssembleBlockTxs(es.Rules.Blocks, []inter.EventPayloadI{limitsEvent(1, 0, 3, 0), limitsEvent(2, 3, 5, 0)})
+	if len(bs.Overflow) != 6 {
+		t.Fatalf("unexpected overflow %d", len(bs.Overflow))
+	}
+
+	// the overflow leads the first block of the next epoch, within the rules of the next epoch
+	nbs, nes := SealEpoch(bs, es, BlockCtx{Idx: 10, Time: 100})
+	if len(nbs.Overflow) != 6 || nbs.Overflow[0].Tx.Nonce() != 2 {
+		t.Fatalf("overflow isn't carried into the next epoch: %d txs", len(nbs.Overflow))
+	}
+	txs, originators := nbs.AssembleBlockTxs(nes.Rules.Blocks, nil)
+	if len(txs) != 3 || txs[0].Nonce() != 2 || originators[0] != 1 || originators[1] != 2 {
+		t.Fatalf("unexpected first block of the next epoch: %d txs, originators %v", len(txs), originators)
+	}
+	// the fees of the transactions originated by the left validator aren't attributed
+	receipts := make(types.Receipts, len(txs))
+	for i := range receipts {
+		receipts[i] = &types.Receipt{GasUsed: 21000}
+	}
+	fees := nbs.AttributeFees(txs, originators, nil, receipts, nil, nes.Validators)
+	if len(fees.Validators) != 1 || fees.Validators[0].ID != 1 || fees.Get(1).Uint64() != 21000 {
+		t.Fatalf("unexpected fees %+v", fees)
+	}
+}
+
+func TestBlockStateOverflow(t *testing.T) {
+	var bs BlockState
+	empty := bs.Hash()
+	bs.AssembleBlockTxs(opera.BlocksRules{MaxBlockTxs: 1}, []inter.EventPayloadI{limitsEvent(4, 0, 3, 10)})
+	if bs.Hash() == empty {
+		t.Fatal("overflow isn't a part of the state hash")
+	}
+
+	// the copy doesn't share the overflow
+	cp := bs.Copy()
+	cp.Overflow[0].Originator = 5
+	if bs.Overflow[0].Originator != 4 {
+		t.Fatal("copy shares the overflow")
+	}
+
+	raw, err := rlp.EncodeToBytes(&bs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded BlockState
+	if err := rlp.DecodeBytes(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Overflow) != 2 || decoded.Overflow[0].Originator != 4 || decoded.Overflow[0].Tx.Hash() != bs.Overflow[0].Tx.Hash() {
+		t.Fatalf("overflow isn't persisted: %+v", decoded.Overflow)
+	}
+}
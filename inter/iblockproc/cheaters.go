@@ -0,0 +1,57 @@
+package iblockproc
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/lachesis"
+
+	"github.com/rony4d/go-opera-asset/inter/drivertype"
+	"github.com/rony4d/go-opera-asset/opera/contracts/driver/drivercall"
+)
+
+// cheaters.go applies the penalties of the confirmed cheaters.
+//
+// Cheaters are accumulated in EpochCheaters, and CheatersWritten counts how many of them
+// have already been penalized by the NodeDriver calls. Both are part of BlockState, so they're
+// persisted atomically with the rest of the block results: if the node crashes, the block
+// is re-processed from the previous BlockState and produces exactly the same calls, and if
+// the block gets re-processed on top of its own results, the cheaters aren't penalized twice.
+
+// AddCheaters appends the cheaters which aren't known yet.
+// Adding the same cheaters again is a no-op.
+func (bs *BlockState) AddCheaters(cheaters lachesis.Cheaters) {
+	known := make(map[uint32]bool, len(bs.EpochCheaters))
+	for _, id := range bs.EpochCheaters {
+		known[uint32(id)] = true
+	}
+	for _, id := range cheaters {
+		if known[uint32(id)] {
+			continue
+		}
+		known[uint32(id)] = true
+		bs.EpochCheaters = append(bs.EpochCheaters, id)
+	}
+}
+
+// UnwrittenCheaters returns the cheaters which haven't been penalized yet.
+func (bs BlockState) UnwrittenCheaters() lachesis.Cheaters {
+	if int(bs.CheatersWritten) >= len(bs.EpochCheaters) {
+		return nil
+	}
+	return bs.EpochCheaters[bs.CheatersWritten:]
+}
+
+// WriteCheaters returns NodeDriver calldata which deactivates the unwritten cheaters,
+// and marks them as written. Each cheater is returned exactly once per epoch.
+func (bs *BlockState) WriteCheaters() [][]byte {
+	var calls [][]byte
+	for _, id := range bs.UnwrittenCheaters() {
+		calls = append(calls, drivercall.DeactivateValidator(id, drivertype.DoublesignBit))
+	}
+	bs.CheatersWritten = uint32(len(bs.EpochCheaters))
+	return calls
+}
+
+// ResetCheaters clears the cheaters at the epoch sealing, as the penalties are already applied.
+func (bs *BlockState) ResetCheaters() {
+	bs.EpochCheaters = lachesis.Cheaters{}
+	bs.CheatersWritten = 0
+}
@@ -0,0 +1,83 @@
+package iblockproc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/lachesis"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/rony4d/go-opera-asset/inter/drivertype"
+	"github.com/rony4d/go-opera-asset/opera/contracts/driver/drivercall"
+)
+
+// processBlock emulates the cheaters handling of the block processor.
+func processBlock(bs BlockState, cheaters lachesis.Cheaters) (BlockState, [][]byte) {
+	bs = bs.Copy()
+	bs.AddCheaters(cheaters)
+	return bs, bs.WriteCheaters()
+}
+
+func TestWriteCheaters(t *testing.T) {
+	bs := BlockState{}
+
+	bs, calls := processBlock(bs, lachesis.Cheaters{3, 1, 3})
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(calls))
+	}
+	if !bytes.Equal(calls[0], drivercall.DeactivateValidator(3, drivertype.DoublesignBit)) ||
+		!bytes.Equal(calls[1], drivercall.DeactivateValidator(1, drivertype.DoublesignBit)) {
+		t.Fatal("unexpected calls")
+	}
+
+	// the same cheaters are confirmed again along with a new one
+	bs, calls = processBlock(bs, lachesis.Cheaters{1, 2})
+	if len(calls) != 1 || !bytes.Equal(calls[0], drivercall.DeactivateValidator(2, drivertype.DoublesignBit)) {
+		t.Fatalf("expected only the new cheater to be written, got %d calls", len(calls))
+	}
+	if bs.CheatersWritten != 3 || len(bs.UnwrittenCheaters()) != 0 {
+		t.Fatalf("unexpected bookkeeping: written=%d, cheaters=%v", bs.CheatersWritten, bs.EpochCheaters)
+	}
+
+	bs.ResetCheaters()
+	if len(bs.EpochCheaters) != 0 || bs.CheatersWritten != 0 {
+		t.Fatal("cheaters aren't reset")
+	}
+}
+
+func TestWriteCheaters_replayAfterCrash(t *testing.T) {
+	var (
+		prev     = BlockState{EpochCheaters: lachesis.Cheaters{5}, CheatersWritten: 1}
+		cheaters = lachesis.Cheaters{5, 7}
+	)
+
+	// the state before the block is persisted, the block is processed, then the node crashes
+	persisted, err := rlp.EncodeToBytes(&prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, calls := processBlock(prev, cheaters)
+	if len(calls) != 1 || !bytes.Equal(calls[0], drivercall.DeactivateValidator(idx.ValidatorID(7), drivertype.DoublesignBit)) {
+		t.Fatal("unexpected calls")
+	}
+
+	// unclean shutdown: the block is re-processed from the persisted state
+	var restored BlockState
+	if err := rlp.DecodeBytes(persisted, &restored); err != nil {
+		t.Fatal(err)
+	}
+	replayed, replayedCalls := processBlock(restored, cheaters)
+	if len(replayedCalls) != 1 || !bytes.Equal(replayedCalls[0], calls[0]) {
+		t.Fatal("replay produced different calls")
+	}
+	if replayed.Hash() != after.Hash() {
+		t.Fatal("replay produced a different state")
+	}
+
+	// the results of the block were persisted, but the block gets re-processed on top of them
+	_, doubleCalls := processBlock(after, cheaters)
+	if len(doubleCalls) != 0 {
+		t.Fatalf("cheaters are penalized twice: %d calls", len(doubleCalls))
+	}
+}
@@ -87,6 +87,10 @@ type BlockState struct {
 
 	// AdvanceEpochs indicates if/how many epochs should be advanced.
 	AdvanceEpochs idx.Epoch
+
+	// Overflow is the transactions of the confirmed events which didn't fit into the last block,
+	// they lead the next block (see AssembleBlockTxs). It's capped by MaxOverflowBlocks full blocks.
+	Overflow []OverflowTx `rlp:"optional"`
 }
 
 // Copy creates a deep copy of the BlockState to ensure thread safety and prevent side effects
@@ -108,6 +112,10 @@ func (bs BlockState) Copy() BlockState {
 		rules := bs.DirtyRules.Copy()
 		cp.DirtyRules = &rules
 	}
+	if bs.Overflow != nil {
+		cp.Overflow = make([]OverflowTx, len(bs.Overflow))
+		copy(cp.Overflow, bs.Overflow)
+	}
 	return cp
 }
 
@@ -142,6 +150,10 @@ type EpochStateV1 struct {
 	ValidatorProfiles ValidatorProfiles
 
 	Rules opera.Rules
+
+	// PrevEpochGas is the total gas used by the previous epoch.
+	// It drives the epoch time limit in the gas-adaptive epochs mode.
+	PrevEpochGas uint64 `rlp:"optional"`
 }
 
 // EpochState is the current alias for EpochStateV1.
@@ -158,6 +170,12 @@ func (es EpochState) Duration() inter.Timestamp {
 	return es.EpochStart - es.PrevEpochStart
 }
 
+// MaxEpochDuration returns the time limit of the epoch.
+// It's constant unless the rules enable the gas-adaptive epochs mode.
+func (es EpochState) MaxEpochDuration() inter.Timestamp {
+	return es.Rules.Epochs.EpochDurationLimit(es.PrevEpochGas)
+}
+
 // Hash calculates the hash of the EpochState.
 // It handles backward compatibility: if the "London" upgrade is not active,
 // it hashes the state using the V0 structure (legacy format) to ensure hash consistency across upgrades.
@@ -0,0 +1,130 @@
+package iblockproc
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/lachesis"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// EpochSummary is the record of a sealed epoch.
+// It isn't used by the consensus, it's kept for tracking the network health over time.
+type EpochSummary struct {
+	Epoch      idx.Epoch
+	Start      inter.Timestamp
+	End        inter.Timestamp
+	FirstBlock idx.Block
+	LastBlock  idx.Block
+	Gas        uint64
+	Validators []ValidatorSummary
+	Cheaters   lachesis.Cheaters
+	RulesHash  hash.Hash
+}
+
+// ValidatorSummary is the activity of a validator during the epoch.
+type ValidatorSummary struct {
+	ID     idx.ValidatorID
+	Events uint32
+	Uptime inter.Timestamp
+}
+
+// EpochCounters are the statistics of the current epoch: the number of confirmed events of
+// every validator. They aren't used by the consensus, so they are kept apart from BlockState,
+// which is hashed, and are stored along with the epoch summaries.
+type EpochCounters struct {
+	Epoch  idx.Epoch
+	Events []uint32 // indexed by the validator index in EpochState.Validators
+}
+
+// OnEventConfirmed counts the confirmed event of its creator.
+// The counters of the previous epoch are dropped once an event of a new epoch is confirmed.
+func (c *EpochCounters) OnEventConfirmed(e inter.EventI, es *EpochState) {
+	if !es.Validators.Exists(e.Creator()) {
+		return
+	}
+	if c.Epoch != es.Epoch {
+		c.Epoch = es.Epoch
+		c.Events = nil
+	}
+	if n := int(es.Validators.Len()); len(c.Events) < n {
+		c.Events = append(c.Events, make([]uint32, n-len(c.Events))...)
+	}
+	c.Events[es.Validators.GetIdx(e.Creator())]++
+}
+
+// EventsOf returns the number of the validator's events confirmed in the epoch.
+func (c EpochCounters) EventsOf(id idx.ValidatorID, es *EpochState) uint32 {
+	if c.Epoch != es.Epoch || !es.Validators.Exists(id) {
+		return 0
+	}
+	i := int(es.Validators.GetIdx(id))
+	if i >= len(c.Events) {
+		return 0
+	}
+	return c.Events[i]
+}
+
+// NewEpochSummary creates the summary of the epoch being sealed, i.e. it must be called
+// with the states and the counters as of the epoch's last block.
+// firstBlock is the first block of the epoch, i.e. the block after the last block of the previous epoch.
+func NewEpochSummary(bs BlockState, es EpochState, counters EpochCounters, firstBlock idx.Block) EpochSummary {
+	s := EpochSummary{
+		Epoch:      es.Epoch,
+		Start:      es.EpochStart,
+		End:        bs.LastBlock.Time,
+		FirstBlock: firstBlock,
+		LastBlock:  bs.LastBlock.Idx,
+		Gas:        bs.EpochGas,
+		Cheaters:   append(lachesis.Cheaters{}, bs.EpochCheaters...),
+		RulesHash:  RulesHash(es.Rules),
+	}
+	for i, id := range es.Validators.SortedIDs() {
+		if i >= len(bs.ValidatorStates) {
+			break
+		}
+		vs := bs.ValidatorStates[i]
+		s.Validators = append(s.Validators, ValidatorSummary{
+			ID:     id,
+			Events: counters.EventsOf(id, &es),
+			Uptime: vs.Uptime,
+		})
+	}
+	return s
+}
+
+// RulesHash returns the hash of the rules, as in the epoch summaries.
+func RulesHash(rules opera.Rules) hash.Hash {
+	b, err := rlp.EncodeToBytes(rules)
+	if err != nil {
+		panic("can't hash rules: " + err.Error())
+	}
+	return hash.Of(b)
+}
+
+// Duration returns the time length of the epoch.
+func (s EpochSummary) Duration() inter.Timestamp {
+	if s.End < s.Start {
+		return 0
+	}
+	return s.End - s.Start
+}
+
+// Blocks returns the number of blocks in the epoch.
+func (s EpochSummary) Blocks() idx.Block {
+	if s.LastBlock < s.FirstBlock {
+		return 0
+	}
+	return s.LastBlock - s.FirstBlock + 1
+}
+
+// Events returns the total number of events confirmed in the epoch.
+func (s EpochSummary) Events() uint64 {
+	total := uint64(0)
+	for _, v := range s.Validators {
+		total += uint64(v.Events)
+	}
+	return total
+}
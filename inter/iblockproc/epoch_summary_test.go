@@ -0,0 +1,73 @@
+package iblockproc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+	"github.com/Fantom-foundation/lachesis-base/lachesis"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+func TestNewEpochSummary(t *testing.T) {
+	b := pos.NewBuilder()
+	b.Set(1, 10)
+	b.Set(2, 20)
+	validators := b.Build()
+
+	es := EpochState{
+		Epoch:      5,
+		EpochStart: 1000,
+		Validators: validators,
+		Rules:      opera.FakeNetRules(),
+	}
+	bs := BlockState{
+		LastBlock:       BlockCtx{Idx: 109, Time: 4000},
+		EpochGas:        777,
+		EpochCheaters:   lachesis.Cheaters{2},
+		ValidatorStates: make([]ValidatorBlockState, validators.Len()),
+	}
+	for i := range bs.ValidatorStates {
+		bs.ValidatorStates[i].Originated = new(big.Int)
+	}
+
+	// the counters of the previous epoch are dropped
+	counters := EpochCounters{Epoch: 4, Events: []uint32{7, 7}}
+	block := BlockCtx{Idx: 100, Time: 2000}
+	for i, creator := range []idx.ValidatorID{1, 2, 1} {
+		me := &inter.MutableEventPayload{}
+		me.SetCreator(creator)
+		me.SetMedianTime(inter.Timestamp(2000 + i))
+		e := me.Build()
+		bs.OnEventConfirmed(e, validators, block)
+		counters.OnEventConfirmed(e, &es)
+	}
+
+	s := NewEpochSummary(bs, es, counters, 100)
+	if s.Epoch != 5 || s.Duration() != 3000 || s.Blocks() != 10 || s.Gas != 777 {
+		t.Fatalf("unexpected summary %+v", s)
+	}
+	if s.Events() != 3 || len(s.Validators) != 2 {
+		t.Fatalf("unexpected validators %+v", s.Validators)
+	}
+	for _, v := range s.Validators {
+		if (v.ID == 1 && v.Events != 2) || (v.ID == 2 && v.Events != 1) {
+			t.Fatalf("unexpected events of validator %d: %d", v.ID, v.Events)
+		}
+	}
+	if counters.EventsOf(3, &es) != 0 || counters.EventsOf(1, &EpochState{Epoch: 6, Validators: validators}) != 0 {
+		t.Fatal("unexpected events of an unknown validator or epoch")
+	}
+	if len(s.Cheaters) != 1 || s.Cheaters[0] != 2 {
+		t.Fatalf("unexpected cheaters %v", s.Cheaters)
+	}
+
+	other := es
+	other.Rules = opera.MainNetRules()
+	if s.RulesHash == NewEpochSummary(bs, other, counters, 100).RulesHash {
+		t.Fatal("rules hash doesn't depend on the rules")
+	}
+}
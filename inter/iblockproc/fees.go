@@ -0,0 +1,136 @@
+package iblockproc
+
+import (
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera/contracts/driver/drivercall"
+)
+
+// fees.go attributes the transaction fees to the validators which originated the transactions.
+//
+// The transactions of a block are the transactions of its events, flattened in the confirmation
+// order (see inter.Block.SkippedTxs), after the overflow of the previous blocks (see
+// AssembleBlockTxs). The fee of every executed transaction is credited to the
+// creator of the event which carried it: to ValidatorBlockState.Originated, which is accumulated
+// over the epoch and passed to NodeDriver.sealEpoch for the rewards, and to the BlockFees record
+// of the block. Skipped transactions pay no fees, so they aren't credited.
+
+// ValidatorFee is the fee originated by a validator.
+type ValidatorFee struct {
+	ID  idx.ValidatorID
+	Fee *big.Int
+}
+
+// BlockFees is the record of the fees originated by the validators in a block,
+// in the order of the epoch validators. Validators with no fees are omitted.
+type BlockFees struct {
+	Validators []ValidatorFee
+}
+
+// Get returns the fee originated by the validator in the block.
+func (f BlockFees) Get(id idx.ValidatorID) *big.Int {
+	for _, v := range f.Validators {
+		if v.ID == id {
+			return new(big.Int).Set(v.Fee)
+		}
+	}
+	return new(big.Int)
+}
+
+// TxOriginators returns the creator of the event carrying each transaction of the block,
+// in the order of the flattened block transactions.
+func TxOriginators(events []inter.EventPayloadI) []idx.ValidatorID {
+	var res []idx.ValidatorID
+	for _, e := range events {
+		for range e.Txs() {
+			res = append(res, e.Creator())
+		}
+	}
+	return res
+}
+
+// TxFee returns the fee paid by the executed transaction.
+// baseFee is the base fee of the block, nil before the London upgrade.
+func TxFee(tx *types.Transaction, gasUsed uint64, baseFee *big.Int) *big.Int {
+	price := tx.GasPrice()
+	if baseFee != nil {
+		price = new(big.Int).Add(baseFee, tx.EffectiveGasTipValue(baseFee))
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), price)
+}
+
+// AttributeFees credits the fees of the block transactions to their originators and returns
+// the fees record of the block. txs and originators are all the flattened block transactions,
+// skipped are the indexes of the skipped ones, and receipts are of the executed ones, in order.
+// It must be called once per block, after the block is executed.
+func (bs *BlockState) AttributeFees(txs types.Transactions, originators []idx.ValidatorID, skipped []uint32,
+	receipts types.Receipts, baseFee *big.Int, validators *pos.Validators) BlockFees {
+	fees := make(map[idx.ValidatorID]*big.Int)
+	isSkipped := make(map[uint32]bool, len(skipped))
+	for _, s := range skipped {
+		isSkipped[s] = true
+	}
+	r := 0
+	for i, tx := range txs {
+		if isSkipped[uint32(i)] {
+			continue
+		}
+		if r >= len(receipts) || i >= len(originators) {
+			break
+		}
+		receipt := receipts[r]
+		r++
+		creator := originators[i]
+		if !validators.Exists(creator) {
+			continue
+		}
+		fee := TxFee(tx, receipt.GasUsed, baseFee)
+		if fees[creator] == nil {
+			fees[creator] = new(big.Int)
+		}
+		fees[creator].Add(fees[creator], fee)
+	}
+
+	var res BlockFees
+	for _, id := range validators.SortedIDs() {
+		fee := fees[id]
+		if fee == nil {
+			continue
+		}
+		vs := bs.GetValidatorState(id, validators)
+		if vs.Originated == nil {
+			vs.Originated = new(big.Int)
+		}
+		vs.Originated = new(big.Int).Add(vs.Originated, fee)
+		res.Validators = append(res.Validators, ValidatorFee{ID: id, Fee: fee})
+	}
+	return res
+}
+
+// SealEpochMetrics returns the performance of the epoch validators as of the epoch's last block,
+// in the order of the epoch validators, i.e. the input of drivercall.SealEpoch.
+func (bs BlockState) SealEpochMetrics(es *EpochState) []drivercall.ValidatorEpochMetric {
+	ids := es.Validators.SortedIDs()
+	metrics := make([]drivercall.ValidatorEpochMetric, len(ids))
+	for i := range ids {
+		if i >= len(bs.ValidatorStates) {
+			metrics[i].OriginatedTxFee = new(big.Int)
+			continue
+		}
+		vs := bs.ValidatorStates[i]
+		metrics[i] = drivercall.ValidatorEpochMetric{
+			Missed:          vs.MissedBlocks(bs.LastBlock),
+			Uptime:          vs.Uptime,
+			OriginatedTxFee: new(big.Int),
+		}
+		if vs.Originated != nil {
+			metrics[i].OriginatedTxFee.Set(vs.Originated)
+		}
+	}
+	return metrics
+}
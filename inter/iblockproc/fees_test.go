@@ -0,0 +1,79 @@
+package iblockproc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+func TestAttributeFees(t *testing.T) {
+	b := pos.NewBuilder()
+	b.Set(1, 10)
+	b.Set(2, 20)
+	validators := b.Build()
+	es := &EpochState{Validators: validators}
+	bs := BlockState{
+		LastBlock:       BlockCtx{Idx: 10, Time: 100},
+		ValidatorStates: make([]ValidatorBlockState, validators.Len()),
+	}
+
+	legacy := func(price int64) *types.Transaction {
+		return types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(price), Gas: 21000})
+	}
+	dynamic := types.NewTx(&types.DynamicFeeTx{GasFeeCap: big.NewInt(10), GasTipCap: big.NewInt(1), Gas: 21000})
+	var events []inter.EventPayloadI
+	for _, e := range []struct {
+		creator idx.ValidatorID
+		txs     types.Transactions
+	}{
+		{1, types.Transactions{legacy(5), legacy(6)}},
+		{3, types.Transactions{legacy(7)}}, // not a validator of the epoch
+		{2, types.Transactions{dynamic}},
+	} {
+		me := &inter.MutableEventPayload{}
+		me.SetCreator(e.creator)
+		me.SetTxs(e.txs)
+		events = append(events, me.Build())
+	}
+	var txs types.Transactions
+	for _, e := range events {
+		txs = append(txs, e.Txs()...)
+	}
+	originators := TxOriginators(events)
+	if len(originators) != 4 || originators[2] != 3 {
+		t.Fatalf("unexpected originators %v", originators)
+	}
+
+	// the second transaction is skipped, so there are receipts of 3 executed ones
+	receipts := types.Receipts{{GasUsed: 100}, {GasUsed: 200}, {GasUsed: 300}}
+	baseFee := big.NewInt(4)
+	fees := bs.AttributeFees(txs, originators, []uint32{1}, receipts, baseFee, validators)
+
+	// legacy tx: the whole gas price is paid, dynamic tx: base fee + tip
+	if fees.Get(1).Cmp(big.NewInt(100*5)) != 0 || fees.Get(2).Cmp(big.NewInt(300*(4+1))) != 0 || fees.Get(3).Sign() != 0 {
+		t.Fatalf("unexpected block fees %+v", fees)
+	}
+	// fees are accumulated over the blocks of the epoch
+	bs.AttributeFees(txs[:1], originators[:1], nil, receipts[:1], baseFee, validators)
+	metrics := bs.SealEpochMetrics(es)
+	for i, id := range validators.SortedIDs() {
+		want := map[idx.ValidatorID]int64{1: 1000, 2: 1500}[id]
+		if metrics[i].OriginatedTxFee.Cmp(big.NewInt(want)) != 0 {
+			t.Fatalf("validator %d originated %s, want %d", id, metrics[i].OriginatedTxFee, want)
+		}
+		if metrics[i].Missed.BlocksNum != 10 {
+			t.Fatalf("unexpected missed blocks %+v", metrics[i].Missed)
+		}
+	}
+
+	// the metrics are a copy
+	metrics[0].OriginatedTxFee.SetInt64(0)
+	if bs.ValidatorStates[0].Originated.Sign() == 0 {
+		t.Fatal("metrics share the state")
+	}
+}
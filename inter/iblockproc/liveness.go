@@ -0,0 +1,100 @@
+package iblockproc
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/inter/drivertype"
+	"github.com/rony4d/go-opera-asset/opera"
+	"github.com/rony4d/go-opera-asset/opera/contracts/driver/drivercall"
+)
+
+// liveness.go tracks which validators are online.
+//
+// Every confirmed event is a proof of liveness of its creator: it moves the creator's
+// LastOnlineTime forward (and accumulates Uptime) and marks the block it was confirmed in.
+// A validator is considered offline once it both missed more than BlockMissedSlack blocks
+// and stayed silent for longer than OfflinePeriod. The thresholds are part of the network
+// rules (opera.EconomyRules), so all the nodes make the same decision at the same block.
+
+// OnEventConfirmed updates the liveness of the event creator.
+// It must be called for every event confirmed by the block, in the confirmation order.
+func (bs *BlockState) OnEventConfirmed(e inter.EventI, validators *pos.Validators, block BlockCtx) {
+	if !validators.Exists(e.Creator()) {
+		return
+	}
+	vs := bs.GetValidatorState(e.Creator(), validators)
+	if e.MedianTime() > vs.LastOnlineTime {
+		// the very first event doesn't prove the validator was online before it
+		if vs.LastOnlineTime != 0 {
+			vs.Uptime += e.MedianTime() - vs.LastOnlineTime
+		}
+		vs.LastOnlineTime = e.MedianTime()
+	}
+	vs.LastBlock = block.Idx
+}
+
+// MissedBlocks returns how many blocks the validator missed and for how long it's been silent,
+// as of the given block.
+func (vs ValidatorBlockState) MissedBlocks(block BlockCtx) opera.BlocksMissed {
+	missed := opera.BlocksMissed{}
+	if block.Idx > vs.LastBlock {
+		missed.BlocksNum = block.Idx - vs.LastBlock
+	}
+	if block.Time > vs.LastOnlineTime {
+		missed.Period = block.Time - vs.LastOnlineTime
+	}
+	return missed
+}
+
+// IsOffline returns true if the validator exceeded both the missed blocks and the silence thresholds.
+func (vs ValidatorBlockState) IsOffline(block BlockCtx, rules opera.EconomyRules) bool {
+	if rules.OfflinePeriod == 0 {
+		return false
+	}
+	missed := vs.MissedBlocks(block)
+	return missed.BlocksNum > rules.BlockMissedSlack && missed.Period > rules.OfflinePeriod
+}
+
+// OfflineValidators returns all the validators which are offline as of the last block.
+func (bs BlockState) OfflineValidators(es *EpochState) []idx.ValidatorID {
+	return bs.offlineValidators(es, nil)
+}
+
+// NewOfflineValidators returns the validators which went offline after the prev block,
+// i.e. which are offline as of the last block, but weren't as of prev.
+// It's used to deactivate each offline validator exactly once.
+func (bs BlockState) NewOfflineValidators(es *EpochState, prev BlockCtx) []idx.ValidatorID {
+	return bs.offlineValidators(es, &prev)
+}
+
+func (bs BlockState) offlineValidators(es *EpochState, prev *BlockCtx) []idx.ValidatorID {
+	// the rule changes take effect only at the next epoch, see SealEpoch
+	rules := es.Rules.Economy
+	var offline []idx.ValidatorID
+	for i, id := range es.Validators.SortedIDs() {
+		if i >= len(bs.ValidatorStates) {
+			break
+		}
+		vs := bs.ValidatorStates[i]
+		if !vs.IsOffline(bs.LastBlock, rules) {
+			continue
+		}
+		if prev != nil && vs.IsOffline(*prev, rules) {
+			continue
+		}
+		offline = append(offline, id)
+	}
+	return offline
+}
+
+// OfflineDeactivationCalls returns NodeDriver calldata which deactivates the validators
+// that went offline after the prev block.
+func (bs BlockState) OfflineDeactivationCalls(es *EpochState, prev BlockCtx) [][]byte {
+	var calls [][]byte
+	for _, id := range bs.NewOfflineValidators(es, prev) {
+		calls = append(calls, drivercall.DeactivateValidator(id, drivertype.OfflineBit))
+	}
+	return calls
+}
@@ -0,0 +1,112 @@
+package iblockproc
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/inter/drivertype"
+	"github.com/rony4d/go-opera-asset/opera"
+	"github.com/rony4d/go-opera-asset/opera/contracts/driver/drivercall"
+)
+
+func livenessStates() (BlockState, EpochState) {
+	b := pos.NewBuilder()
+	b.Set(1, 10)
+	b.Set(2, 20)
+	validators := b.Build()
+	rules := opera.FakeNetRules()
+	rules.Economy.BlockMissedSlack = 50
+	rules.Economy.OfflinePeriod = inter.Timestamp(10 * time.Minute)
+	bs := BlockState{ValidatorStates: make([]ValidatorBlockState, validators.Len())}
+	for i := range bs.ValidatorStates {
+		bs.ValidatorStates[i].Originated = new(big.Int)
+	}
+	return bs, EpochState{Validators: validators, Rules: rules}
+}
+
+func confirm(bs *BlockState, es EpochState, creator idx.ValidatorID, block BlockCtx) {
+	me := &inter.MutableEventPayload{}
+	me.SetCreator(creator)
+	me.SetMedianTime(block.Time)
+	bs.OnEventConfirmed(me.Build(), es.Validators, block)
+}
+
+func TestOfflineValidators(t *testing.T) {
+	bs, es := livenessStates()
+	start := inter.Timestamp(1000 * time.Second)
+	at := func(block idx.Block, after time.Duration) BlockCtx {
+		return BlockCtx{Idx: block, Time: start + inter.Timestamp(after)}
+	}
+	confirm(&bs, es, 1, at(10, 0))
+	confirm(&bs, es, 2, at(10, 0))
+	confirm(&bs, es, 1, at(11, time.Minute))
+
+	vs := bs.GetValidatorState(1, es.Validators)
+	if vs.Uptime != inter.Timestamp(time.Minute) || vs.LastBlock != 11 || vs.LastOnlineTime != at(11, time.Minute).Time {
+		t.Fatalf("unexpected liveness %+v", vs)
+	}
+
+	// offline only once both the missed blocks and the silence thresholds are exceeded
+	vs = bs.GetValidatorState(2, es.Validators)
+	for _, c := range []struct {
+		block   BlockCtx
+		offline bool
+	}{
+		{at(100, 5*time.Minute), false},
+		{at(20, 20*time.Minute), false},
+		{at(60, 20*time.Minute), false},
+		{at(61, 20*time.Minute), true},
+	} {
+		if got := vs.IsOffline(c.block, es.Rules.Economy); got != c.offline {
+			t.Fatalf("block %d at %v: offline %v, want %v", c.block.Idx, c.block.Time, got, c.offline)
+		}
+	}
+	noDetection := es.Rules.Economy
+	noDetection.OfflinePeriod = 0
+	if vs.IsOffline(at(1000, 24*time.Hour), noDetection) {
+		t.Fatal("offline detection isn't disabled")
+	}
+
+	// validator 1 keeps emitting, validator 2 goes offline
+	confirm(&bs, es, 1, at(100, 20*time.Minute))
+	bs.LastBlock = at(100, 20*time.Minute)
+	if offline := bs.OfflineValidators(&es); len(offline) != 1 || offline[0] != 2 {
+		t.Fatalf("unexpected offline validators %v", offline)
+	}
+	// the pending rule changes don't apply until the epoch is sealed
+	dirty := es.Rules.Copy()
+	dirty.Economy.OfflinePeriod = 0
+	bs.DirtyRules = &dirty
+	if offline := bs.OfflineValidators(&es); len(offline) != 1 || offline[0] != 2 {
+		t.Fatalf("dirty rules are applied mid-epoch: %v", offline)
+	}
+	bs.DirtyRules = nil
+	prev := at(30, 15*time.Minute)
+	if offline := bs.NewOfflineValidators(&es, prev); len(offline) != 1 || offline[0] != 2 {
+		t.Fatalf("unexpected new offline validators %v", offline)
+	}
+	calls := bs.OfflineDeactivationCalls(&es, prev)
+	if len(calls) != 1 || !bytes.Equal(calls[0], drivercall.DeactivateValidator(2, drivertype.OfflineBit)) {
+		t.Fatalf("unexpected deactivation calls %x", calls)
+	}
+	// a validator is deactivated only once
+	if calls := bs.OfflineDeactivationCalls(&es, at(99, 19*time.Minute)); len(calls) != 0 {
+		t.Fatalf("validator is deactivated again: %x", calls)
+	}
+
+	// an event of the validator brings it back online
+	confirm(&bs, es, 2, at(101, 21*time.Minute))
+	bs.LastBlock = at(101, 21*time.Minute)
+	if offline := bs.OfflineValidators(&es); len(offline) != 0 {
+		t.Fatalf("validator is still offline: %v", offline)
+	}
+	if vs := bs.GetValidatorState(2, es.Validators); vs.MissedBlocks(bs.LastBlock) != (opera.BlocksMissed{}) {
+		t.Fatalf("missed blocks aren't reset: %+v", vs.MissedBlocks(bs.LastBlock))
+	}
+}
@@ -0,0 +1,190 @@
+package iblockproc
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// pipeline.go overlaps the stages of the processing of consecutive blocks.
+//
+// Overview:
+//   The blocks are processed sequentially: every stage (sender recovery, state prefetch,
+//   execution, receipts encoding, index writes, ...) of a block depends on the previous
+//   blocks. But a stage depends only on the same and earlier stages of the previous blocks,
+//   so different stages of consecutive blocks may run at the same time: the senders of the
+//   next block are recovered while the receipts of the current block are written.
+//
+//   Every stage runs in its own goroutine and takes the blocks in the submission order, so
+//   each stage sees the blocks exactly as in the serial processing. The stages are connected
+//   by bounded queues, which limit the number of blocks in flight: a slow stage blocks the
+//   earlier stages instead of accumulating blocks in memory.
+//
+//   Once a stage fails, none of the stages runs for the failed block and the blocks after
+//   it, while the blocks before it are processed completely. The earlier stages of the
+//   following blocks may have already run though, so their side effects must be either
+//   discardable (caches, prefetching) or idempotent.
+//
+//   PipelineConfig.Serial is the safety switch: every block passes through all the stages
+//   in the caller's goroutine before the next block is taken.
+
+// ErrPipelineClosed is returned when a block is submitted into a closed pipeline.
+var ErrPipelineClosed = errors.New("block processing pipeline is closed")
+
+// PipelineConfig is the config of the block processing pipeline.
+type PipelineConfig struct {
+	// Serial disables the overlapping of the stages.
+	Serial bool
+	// QueueSize is the number of blocks buffered in front of every stage.
+	QueueSize int
+}
+
+// DefaultPipelineConfig returns the default config of the block processing pipeline.
+func DefaultPipelineConfig() PipelineConfig {
+	return PipelineConfig{
+		QueueSize: 4,
+	}
+}
+
+// Stage is a step of the block processing.
+// Fn is called for every block in the submission order, and never concurrently with itself.
+type Stage struct {
+	Name string
+	Fn   func(block interface{}) error
+}
+
+type pipelineJob struct {
+	seq   uint64
+	block interface{}
+}
+
+// Pipeline processes the blocks by the stages, overlapping the stages of consecutive blocks.
+type Pipeline struct {
+	stages []Stage
+	queues []chan pipelineJob // queues[i] feeds stages[i], nil in the serial mode
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	next   uint64
+	err    error
+	failed uint64 // seq of the failed block, valid if err isn't nil
+	closed bool
+}
+
+// NewPipeline creates the pipeline of the stages, in the order of processing.
+func NewPipeline(cfg PipelineConfig, stages ...Stage) *Pipeline {
+	p := &Pipeline{
+		stages: stages,
+	}
+	if cfg.Serial || len(stages) == 0 {
+		return p
+	}
+	queueSize := cfg.QueueSize
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	p.queues = make([]chan pipelineJob, len(stages))
+	for i := range p.queues {
+		p.queues[i] = make(chan pipelineJob, queueSize)
+	}
+	p.wg.Add(len(stages))
+	for i := range stages {
+		go p.loop(i)
+	}
+	return p
+}
+
+// Submit queues the block for the processing. It blocks while the first stage is busy and
+// its queue is full. Returns the error of an earlier block, if any stage failed.
+// The blocks must be submitted from a single goroutine, in the order of the blocks.
+func (p *Pipeline) Submit(block interface{}) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrPipelineClosed
+	}
+	if p.err != nil {
+		err := p.err
+		p.mu.Unlock()
+		return err
+	}
+	job := pipelineJob{seq: p.next, block: block}
+	p.next++
+	p.mu.Unlock()
+
+	if p.queues == nil {
+		for i := range p.stages {
+			p.run(i, job)
+		}
+		return p.Err()
+	}
+	p.queues[0] <- job
+	return p.Err()
+}
+
+// Close waits until all the submitted blocks are processed and stops the stages.
+// Returns the error of the first failed block.
+func (p *Pipeline) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return p.Err()
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	if p.queues != nil {
+		close(p.queues[0])
+		p.wg.Wait()
+	}
+	return p.Err()
+}
+
+// Err returns the error of the first failed block, nil if no stage failed so far.
+func (p *Pipeline) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.err
+}
+
+func (p *Pipeline) loop(i int) {
+	defer p.wg.Done()
+	for job := range p.queues[i] {
+		p.run(i, job)
+		if i+1 < len(p.queues) {
+			p.queues[i+1] <- job
+		}
+	}
+	if i+1 < len(p.queues) {
+		close(p.queues[i+1])
+	}
+}
+
+// run processes the block by the stage, unless the block or an earlier one has failed.
+func (p *Pipeline) run(i int, job pipelineJob) {
+	if p.aborted(job.seq) {
+		return
+	}
+	if err := p.stages[i].Fn(job.block); err != nil {
+		p.fail(job.seq, fmt.Errorf("block processing stage %s: %w", p.stages[i].Name, err))
+	}
+}
+
+func (p *Pipeline) aborted(seq uint64) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.err != nil && seq >= p.failed
+}
+
+func (p *Pipeline) fail(seq uint64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// a later stage may fail on an earlier block after an earlier stage failed on a later block
+	if p.err == nil || seq < p.failed {
+		p.err = err
+		p.failed = seq
+	}
+}
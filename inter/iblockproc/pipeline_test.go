@@ -0,0 +1,295 @@
+package iblockproc
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// stageLog records the blocks processed by every stage.
+type stageLog struct {
+	mu     sync.Mutex
+	blocks [][]int
+	steps  map[int]int // the number of the stages passed by a block
+}
+
+func newStageLog(stages int) *stageLog {
+	return &stageLog{blocks: make([][]int, stages), steps: map[int]int{}}
+}
+
+func (l *stageLog) stages(n int, fail func(stage, block int) error) []Stage {
+	res := make([]Stage, n)
+	for i := range res {
+		i := i
+		res[i] = Stage{Name: string(rune('a' + i)), Fn: func(b interface{}) error {
+			block := b.(int)
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if l.steps[block] != i {
+				return errors.New("stage runs before the previous stages")
+			}
+			l.steps[block]++
+			l.blocks[i] = append(l.blocks[i], block)
+			if fail != nil {
+				return fail(i, block)
+			}
+			return nil
+		}}
+	}
+	return res
+}
+
+func TestPipelineOrder(t *testing.T) {
+	for _, cfg := range []PipelineConfig{DefaultPipelineConfig(), {QueueSize: 0}, {Serial: true}} {
+		log := newStageLog(4)
+		p := NewPipeline(cfg, log.stages(4, nil)...)
+		for b := 0; b < 100; b++ {
+			if err := p.Submit(b); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := p.Close(); err != nil {
+			t.Fatalf("%+v: %v", cfg, err)
+		}
+		for i, blocks := range log.blocks {
+			if len(blocks) != 100 {
+				t.Fatalf("%+v: stage %d processed %d blocks", cfg, i, len(blocks))
+			}
+			for b, got := range blocks {
+				if got != b {
+					t.Fatalf("%+v: stage %d processed block %d out of order", cfg, i, got)
+				}
+			}
+		}
+		if err := p.Submit(100); err != ErrPipelineClosed {
+			t.Fatalf("closed pipeline accepted a block: %v", err)
+		}
+	}
+}
+
+func TestPipelineFailure(t *testing.T) {
+	errBroken := errors.New("broken block")
+	for _, cfg := range []PipelineConfig{DefaultPipelineConfig(), {Serial: true}} {
+		log := newStageLog(3)
+		p := NewPipeline(cfg, log.stages(3, func(stage, block int) error {
+			if stage == 1 && block == 5 {
+				return errBroken
+			}
+			return nil
+		})...)
+		for b := 0; b < 20; b++ {
+			if err := p.Submit(b); err != nil {
+				if !errors.Is(err, errBroken) {
+					t.Fatalf("unexpected error %v", err)
+				}
+				break
+			}
+		}
+		if err := p.Close(); !errors.Is(err, errBroken) {
+			t.Fatalf("%+v: failure isn't reported: %v", cfg, err)
+		}
+		if got := log.blocks[1]; len(got) != 6 {
+			t.Fatalf("%+v: failed stage processed blocks %v", cfg, got)
+		}
+		if got := log.blocks[2]; len(got) != 5 {
+			t.Fatalf("%+v: blocks before the failed one aren't processed completely: %v", cfg, got)
+		}
+	}
+}
+
+func TestSerialPipelineIsSynchronous(t *testing.T) {
+	log := newStageLog(3)
+	p := NewPipeline(PipelineConfig{Serial: true}, log.stages(3, nil)...)
+	if err := p.Submit(0); err != nil {
+		t.Fatal(err)
+	}
+	if log.steps[0] != 3 {
+		t.Fatalf("block isn't processed by Submit: %d stages passed", log.steps[0])
+	}
+}
+
+// replayBlock is a block of the replayed epoch, filled by the stages.
+type replayBlock struct {
+	number   uint64
+	txs      types.Transactions
+	senders  []common.Address
+	nonces   []uint64
+	receipts [][]byte
+}
+
+// replayEpoch generates an epoch resembling the mainnet load: mostly transfers and
+// contract calls of a few hundred bytes, some large deployments, of both tx types.
+func replayEpoch(b testing.TB, blocks, txsPerBlock int) []*replayBlock {
+	b.Helper()
+	r := rand.New(rand.NewSource(1))
+	signer := types.NewLondonSigner(big.NewInt(250))
+	keys := make([]*ecdsa.PrivateKey, 50)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			b.Fatal(err)
+		}
+		keys[i] = key
+	}
+	to := common.Address{1}
+	res := make([]*replayBlock, blocks)
+	nonce := uint64(0)
+	for i := range res {
+		block := &replayBlock{number: uint64(i + 1)}
+		for j := 0; j < txsPerBlock; j++ {
+			data := make([]byte, r.Intn(300))
+			if r.Intn(50) == 0 {
+				data = make([]byte, 10000+r.Intn(10000))
+			}
+			r.Read(data)
+			var inner types.TxData = &types.LegacyTx{Nonce: nonce, GasPrice: big.NewInt(1e9), Gas: 100000, To: &to, Data: data}
+			if r.Intn(2) == 0 {
+				inner = &types.DynamicFeeTx{ChainID: big.NewInt(250), Nonce: nonce, GasFeeCap: big.NewInt(2e9), GasTipCap: big.NewInt(1e9), Gas: 100000, To: &to, Data: data}
+			}
+			nonce++
+			tx, err := types.SignNewTx(keys[r.Intn(len(keys))], signer, inner)
+			if err != nil {
+				b.Fatal(err)
+			}
+			block.txs = append(block.txs, tx)
+		}
+		res[i] = block
+	}
+	return res
+}
+
+// replayStages are the stages of the block processing over in-memory databases.
+func replayStages() []Stage {
+	signer := types.NewLondonSigner(big.NewInt(250))
+	state := memorydb.New()
+	index := memorydb.New()
+	return []Stage{
+		{Name: "senders", Fn: func(v interface{}) error {
+			block := v.(*replayBlock)
+			block.senders = make([]common.Address, len(block.txs))
+			for i, tx := range block.txs {
+				sender, err := types.Sender(signer, tx)
+				if err != nil {
+					return err
+				}
+				block.senders[i] = sender
+			}
+			return nil
+		}},
+		{Name: "prefetch", Fn: func(v interface{}) error {
+			block := v.(*replayBlock)
+			block.nonces = make([]uint64, len(block.txs))
+			for i, sender := range block.senders {
+				if raw, err := state.Get(sender.Bytes()); err == nil {
+					block.nonces[i] = new(big.Int).SetBytes(raw).Uint64()
+				}
+			}
+			return nil
+		}},
+		{Name: "execute", Fn: func(v interface{}) error {
+			block := v.(*replayBlock)
+			for i, sender := range block.senders {
+				if err := state.Put(sender.Bytes(), new(big.Int).SetUint64(block.nonces[i]+1).Bytes()); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		{Name: "receipts", Fn: func(v interface{}) error {
+			block := v.(*replayBlock)
+			block.receipts = make([][]byte, len(block.txs))
+			for i, tx := range block.txs {
+				receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: uint64(i+1) * 21000, TxHash: tx.Hash()}
+				raw, err := rlp.EncodeToBytes((*types.ReceiptForStorage)(receipt))
+				if err != nil {
+					return err
+				}
+				block.receipts[i] = raw
+			}
+			return nil
+		}},
+		{Name: "index", Fn: func(v interface{}) error {
+			block := v.(*replayBlock)
+			batch := index.NewBatch()
+			for i, tx := range block.txs {
+				if err := batch.Put(tx.Hash().Bytes(), block.receipts[i]); err != nil {
+					return err
+				}
+			}
+			return batch.Write()
+		}},
+	}
+}
+
+func TestPipelineReplay(t *testing.T) {
+	for _, cfg := range []PipelineConfig{DefaultPipelineConfig(), {Serial: true}} {
+		epoch := replayEpoch(t, 10, 20)
+		p := NewPipeline(cfg, replayStages()...)
+		for _, block := range epoch {
+			if err := p.Submit(block); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := p.Close(); err != nil {
+			t.Fatal(err)
+		}
+		for _, block := range epoch {
+			if len(block.receipts) != len(block.txs) {
+				t.Fatalf("%+v: block %d isn't processed", cfg, block.number)
+			}
+		}
+	}
+}
+
+// cloneEpoch decodes the transactions anew, so the senders cached by the previous run
+// are recovered again.
+func cloneEpoch(b *testing.B, epoch []*replayBlock) []*replayBlock {
+	res := make([]*replayBlock, len(epoch))
+	for i, block := range epoch {
+		raw, err := rlp.EncodeToBytes(block.txs)
+		if err != nil {
+			b.Fatal(err)
+		}
+		res[i] = &replayBlock{number: block.number}
+		if err := rlp.DecodeBytes(raw, &res[i].txs); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return res
+}
+
+func benchmarkPipeline(b *testing.B, cfg PipelineConfig) {
+	replayed := replayEpoch(b, 50, 100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		epoch := cloneEpoch(b, replayed)
+		b.StartTimer()
+		p := NewPipeline(cfg, replayStages()...)
+		for _, block := range epoch {
+			if err := p.Submit(block); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if err := p.Close(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPipelineSerial(b *testing.B) {
+	benchmarkPipeline(b, PipelineConfig{Serial: true})
+}
+
+func BenchmarkPipelineConcurrent(b *testing.B) {
+	benchmarkPipeline(b, DefaultPipelineConfig())
+}
@@ -11,7 +11,6 @@ import (
 	"math/big"
 
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
-	"github.com/Fantom-foundation/lachesis-base/inter/pos"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/rony4d/go-opera-asset/inter/drivertype"
 )
@@ -42,12 +41,8 @@ func (vv ValidatorProfiles) Copy() ValidatorProfiles {
 // This is crucial for deterministic serialization because Go maps iteration order is random.
 // To have a consistent hash or RLP encoding, we must convert the map to a sorted list first.
 func (vv ValidatorProfiles) SortedArray() []drivertype.ValidatorAndID {
-	// Use the builder to construct a validator set representation that helps with sorting
-	builder := pos.NewBigBuilder()
-	for id, profile := range vv {
-		builder.Set(id, profile.Weight)
-	}
-	validators := builder.Build()
+	// Use the validator set representation that helps with sorting
+	validators := vv.Validators()
 
 	// Get IDs in a deterministic sorted order
 	sortedIds := validators.SortedIDs()
@@ -0,0 +1,83 @@
+package iblockproc
+
+import (
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/lachesis"
+)
+
+// ShouldSealEpoch returns true if the epoch must be sealed after the given block.
+// The decision depends only on the block time and the epoch gas, never on the local clock,
+// so it's deterministic across nodes (and in tests which drive block times from a manual clock).
+func (es EpochState) ShouldSealEpoch(bs BlockState, block BlockCtx) bool {
+	if bs.EpochGas >= es.Rules.Epochs.MaxEpochGas {
+		return true
+	}
+	return block.Time >= es.EpochStart+es.MaxEpochDuration()
+}
+
+// SealEpoch returns the states the next epoch starts with, once the epoch is sealed after
+// the given block. The next validators are NextValidatorProfiles, the validators which stay
+// inherit their block states, and the dirty data of the sealed epoch becomes active.
+// The overflow transactions are carried into the next epoch.
+// In the gas-adaptive epochs mode, the epoch gas of the sealed epoch is kept as PrevEpochGas,
+// it drives the time limit of the next epoch.
+func SealEpoch(bs BlockState, es EpochState, block BlockCtx) (BlockState, EpochState) {
+	bs, es = bs.Copy(), es.Copy()
+
+	oldValidators := es.Validators
+	newValidators := bs.NextValidatorProfiles.Validators()
+	es.ValidatorProfiles = bs.NextValidatorProfiles.Copy()
+
+	newValidatorEpochStates := make([]ValidatorEpochState, newValidators.Len())
+	newValidatorBlockStates := make([]ValidatorBlockState, newValidators.Len())
+	for newValIdx := idx.Validator(0); newValIdx < newValidators.Len(); newValIdx++ {
+		// default values
+		newValidatorBlockStates[newValIdx] = ValidatorBlockState{
+			Originated: new(big.Int),
+		}
+		// inherit validator's state from the previous epoch, if any
+		valID := newValidators.GetID(newValIdx)
+		if !oldValidators.Exists(valID) {
+			// new validator
+			newValidatorBlockStates[newValIdx].LastBlock = block.Idx
+			newValidatorBlockStates[newValIdx].LastOnlineTime = block.Time
+			continue
+		}
+		oldValIdx := oldValidators.GetIdx(valID)
+		newValidatorBlockStates[newValIdx] = bs.ValidatorStates[oldValIdx]
+		newValidatorBlockStates[newValIdx].DirtyGasRefund = 0
+		newValidatorBlockStates[newValIdx].Uptime = 0
+		newValidatorEpochStates[newValIdx].GasRefund = bs.ValidatorStates[oldValIdx].DirtyGasRefund
+		newValidatorEpochStates[newValIdx].PrevEpochEvent = bs.ValidatorStates[oldValIdx].LastEvent
+	}
+	es.ValidatorStates = newValidatorEpochStates
+	bs.ValidatorStates = newValidatorBlockStates
+	es.Validators = newValidators
+
+	// dirty data becomes active
+	es.PrevEpochStart = es.EpochStart
+	es.EpochStart = block.Time
+	if bs.DirtyRules != nil {
+		es.Rules = bs.DirtyRules.Copy()
+		bs.DirtyRules = nil
+	}
+	es.EpochStateRoot = bs.FinalizedStateRoot
+
+	// kept only if it's used, so the epoch state of the other networks is hashed as before
+	es.PrevEpochGas = 0
+	if es.Rules.Epochs.AutoTuned() {
+		es.PrevEpochGas = bs.EpochGas
+	}
+	bs.EpochGas = 0
+	bs.EpochCheaters = lachesis.Cheaters{}
+	bs.CheatersWritten = 0
+	// the overflow is kept, it leads the first block of the next epoch (see AssembleBlockTxs)
+	es.Epoch++
+	if bs.AdvanceEpochs > 0 {
+		es.Epoch += bs.AdvanceEpochs
+		bs.AdvanceEpochs = 0
+	}
+	return bs, es
+}
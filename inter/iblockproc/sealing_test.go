@@ -0,0 +1,82 @@
+package iblockproc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+
+	"github.com/rony4d/go-opera-asset/inter/drivertype"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+func TestSealEpoch(t *testing.T) {
+	rules := opera.FakeNetRules()
+	rules.Epochs.MaxEpochGas = 1000
+	rules.Epochs.MaxEpochDuration = 100
+	rules.Epochs.MinEpochDuration = 10
+
+	b := pos.NewBuilder()
+	b.Set(1, 10)
+	b.Set(2, 20)
+	es := EpochState{Epoch: 5, EpochStart: 1000, Validators: b.Build(), Rules: rules}
+	es.ValidatorStates = make([]ValidatorEpochState, es.Validators.Len())
+	bs := BlockState{
+		EpochGas:        1000,
+		ValidatorStates: make([]ValidatorBlockState, es.Validators.Len()),
+		NextValidatorProfiles: ValidatorProfiles{
+			1: drivertype.Validator{Weight: big.NewInt(10)},
+			3: drivertype.Validator{Weight: big.NewInt(30)},
+		},
+	}
+	for i := range bs.ValidatorStates {
+		bs.ValidatorStates[i] = ValidatorBlockState{Originated: big.NewInt(1), Uptime: 7, DirtyGasRefund: 9, LastBlock: 50}
+	}
+	// the fully loaded epoch is sealed by gas
+	block := BlockCtx{Idx: 60, Time: 1050}
+	if !es.ShouldSealEpoch(bs, block) {
+		t.Fatal("loaded epoch isn't sealed")
+	}
+
+	nbs, nes := SealEpoch(bs, es, block)
+	if nes.Epoch != 6 || nes.EpochStart != 1050 || nes.PrevEpochStart != 1000 || nbs.EpochGas != 0 {
+		t.Fatalf("unexpected next epoch %+v", nes)
+	}
+	// the next epoch is shortened after the fully loaded one
+	if nes.PrevEpochGas != 1000 || nes.MaxEpochDuration() != 10 {
+		t.Fatalf("epoch gas isn't carried over: %d, limit %d", nes.PrevEpochGas, nes.MaxEpochDuration())
+	}
+	if !nes.ShouldSealEpoch(nbs, BlockCtx{Idx: 61, Time: 1060}) || nes.ShouldSealEpoch(nbs, BlockCtx{Idx: 61, Time: 1059}) {
+		t.Fatal("shortened epoch isn't sealed in time")
+	}
+
+	// the validator 1 stays with its state, the validator 3 joins, the validator 2 leaves
+	if nes.Validators.Len() != 2 || !nes.Validators.Exists(3) || nes.Validators.Exists(2) {
+		t.Fatalf("unexpected next validators %v", nes.Validators)
+	}
+	stays := nbs.GetValidatorState(1, nes.Validators)
+	if stays.LastBlock != 50 || stays.Uptime != 0 || stays.DirtyGasRefund != 0 || nes.GetValidatorState(1, nes.Validators).GasRefund != 9 {
+		t.Fatalf("unexpected state of the staying validator %+v", stays)
+	}
+	joins := nbs.GetValidatorState(3, nes.Validators)
+	if joins.LastBlock != block.Idx || joins.LastOnlineTime != block.Time {
+		t.Fatalf("unexpected state of the new validator %+v", joins)
+	}
+	// the sealed states aren't modified
+	if es.Epoch != 5 || bs.EpochGas != 1000 || bs.ValidatorStates[0].Uptime != 7 {
+		t.Fatal("sealing modified the sealed epoch states")
+	}
+
+	// an idle epoch gets the max time limit back
+	_, nes = SealEpoch(nbs, nes, BlockCtx{Idx: idx.Block(70), Time: 1100})
+	if nes.PrevEpochGas != 0 || nes.MaxEpochDuration() != 100 {
+		t.Fatalf("idle epoch isn't reflected: %d, limit %d", nes.PrevEpochGas, nes.MaxEpochDuration())
+	}
+
+	// the epoch gas isn't kept unless the gas-adaptive epochs mode is enabled
+	es.Rules.Epochs.MinEpochDuration = 0
+	if _, nes = SealEpoch(bs, es, block); nes.PrevEpochGas != 0 {
+		t.Fatalf("epoch gas is kept without the gas-adaptive epochs mode: %d", nes.PrevEpochGas)
+	}
+}
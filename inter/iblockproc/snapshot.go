@@ -0,0 +1,178 @@
+// Package iblockproc defines the structures and logic for processing inter-block state.
+// This file (snapshot.go) defines StateSnapshot, the exported block and epoch states of a
+// validator's node. A validator moving to a new machine exports the states of its old node,
+// signs them with its validator key and imports them on the new node, which then resumes
+// exactly where the old one stopped instead of syncing the whole chain again.
+
+package iblockproc
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/rony4d/go-opera-asset/inter/validatorpk"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// SnapshotVersion is the version of the StateSnapshot format.
+const SnapshotVersion = 1
+
+// snapshotMagic starts every encoded snapshot, so a wrong file is rejected before decoding.
+var snapshotMagic = []byte("opera-epochstate")
+
+var (
+	// ErrSnapshotHashMismatch is returned if the states don't match the hashes they were exported with.
+	ErrSnapshotHashMismatch = errors.New("state hash mismatch")
+	// ErrSnapshotSignature is returned if the snapshot isn't signed by the key of its validator.
+	ErrSnapshotSignature = errors.New("invalid snapshot signature")
+	// ErrSnapshotFormat is returned if the data isn't a snapshot of a supported version.
+	ErrSnapshotFormat = errors.New("not an epoch state snapshot")
+)
+
+// StateSnapshot is the block and epoch states of a node as of the last block of BlockState,
+// signed by a validator of the epoch.
+type StateSnapshot struct {
+	Version uint8
+
+	BlockState BlockState
+	EpochState EpochState
+
+	// BlockStateHash and EpochStateHash are the hashes of the states when they were exported.
+	// They guard against the states being decoded differently by another node version.
+	BlockStateHash hash.Hash
+	EpochStateHash hash.Hash
+
+	// Upgrades and DirtyUpgrades are the upgrades of EpochState.Rules and BlockState.DirtyRules,
+	// which aren't a part of the RLP encoding of the rules
+	Upgrades      opera.Upgrades
+	DirtyUpgrades opera.Upgrades
+
+	Validator idx.ValidatorID
+	Signature []byte // secp256k1 signature of SigningHash
+}
+
+// NewStateSnapshot creates the unsigned snapshot of the states.
+func NewStateSnapshot(bs BlockState, es EpochState) *StateSnapshot {
+	s := &StateSnapshot{
+		Version:        SnapshotVersion,
+		BlockState:     bs,
+		EpochState:     es,
+		BlockStateHash: bs.Hash(),
+		EpochStateHash: es.Hash(),
+		Upgrades:       es.Rules.Upgrades,
+	}
+	if bs.DirtyRules != nil {
+		s.DirtyUpgrades = bs.DirtyRules.Upgrades
+	}
+	return s
+}
+
+// SigningHash returns the hash the validator signs. It covers the network, the epoch,
+// the block and the states, so the signature can't be reused for other states.
+func (s *StateSnapshot) SigningHash() hash.Hash {
+	b, err := rlp.EncodeToBytes([]interface{}{
+		snapshotMagic,
+		s.Version,
+		s.EpochState.Rules.NetworkID,
+		s.EpochState.Epoch,
+		s.BlockState.LastBlock.Idx,
+		s.BlockStateHash,
+		s.EpochStateHash,
+		s.Validator,
+	})
+	if err != nil {
+		panic("can't hash: " + err.Error())
+	}
+	return hash.BytesToHash(crypto.Keccak256(b))
+}
+
+// Sign signs the snapshot by the key of the validator.
+func (s *StateSnapshot) Sign(validator idx.ValidatorID, key *ecdsa.PrivateKey) error {
+	s.Validator = validator
+	if err := s.checkSigner(&key.PublicKey); err != nil {
+		return err
+	}
+	h := s.SigningHash()
+	sig, err := crypto.Sign(h.Bytes(), key)
+	if err != nil {
+		return err
+	}
+	s.Signature = sig
+	return nil
+}
+
+// checkSigner checks that the key belongs to the validator of the snapshot.
+func (s *StateSnapshot) checkSigner(pub *ecdsa.PublicKey) error {
+	profile, ok := s.EpochState.ValidatorProfiles[s.Validator]
+	if !ok {
+		return fmt.Errorf("%w: %d isn't a validator of epoch %d", ErrSnapshotSignature, s.Validator, s.EpochState.Epoch)
+	}
+	if profile.PubKey.Type != validatorpk.Types.Secp256k1 || !bytes.Equal(profile.PubKey.Raw, crypto.FromECDSAPub(pub)) {
+		return fmt.Errorf("%w: the key doesn't belong to validator %d", ErrSnapshotSignature, s.Validator)
+	}
+	return nil
+}
+
+// Verify checks that the states match their hashes, are consistent with each other,
+// and that the snapshot is signed by its validator.
+func (s *StateSnapshot) Verify() error {
+	if s.Version != SnapshotVersion {
+		return fmt.Errorf("%w: version %d", ErrSnapshotFormat, s.Version)
+	}
+	if h := s.BlockState.Hash(); h != s.BlockStateHash {
+		return fmt.Errorf("%w: block state %s, expected %s", ErrSnapshotHashMismatch, h.String(), s.BlockStateHash.String())
+	}
+	if h := s.EpochState.Hash(); h != s.EpochStateHash {
+		return fmt.Errorf("%w: epoch state %s, expected %s", ErrSnapshotHashMismatch, h.String(), s.EpochStateHash.String())
+	}
+	es, bs := s.EpochState, s.BlockState
+	if es.Validators == nil || len(es.ValidatorStates) != int(es.Validators.Len()) || len(bs.ValidatorStates) != int(es.Validators.Len()) {
+		return errors.New("validator states don't match the validators of the epoch")
+	}
+	if len(s.Signature) != crypto.SignatureLength {
+		return fmt.Errorf("%w: snapshot isn't signed", ErrSnapshotSignature)
+	}
+	h := s.SigningHash()
+	pub, err := crypto.SigToPub(h.Bytes(), s.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSnapshotSignature, err)
+	}
+	return s.checkSigner(pub)
+}
+
+// WriteStateSnapshot encodes the snapshot.
+func WriteStateSnapshot(w io.Writer, s *StateSnapshot) error {
+	if _, err := w.Write(snapshotMagic); err != nil {
+		return err
+	}
+	return rlp.Encode(w, s)
+}
+
+// ReadStateSnapshot decodes the snapshot. It doesn't verify it.
+func ReadStateSnapshot(r io.Reader) (*StateSnapshot, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(raw, snapshotMagic) {
+		return nil, ErrSnapshotFormat
+	}
+	s := new(StateSnapshot)
+	if err := rlp.DecodeBytes(raw[len(snapshotMagic):], s); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSnapshotFormat, err)
+	}
+	s.EpochState.Rules.Upgrades = s.Upgrades
+	if s.BlockState.DirtyRules != nil {
+		s.BlockState.DirtyRules.Upgrades = s.DirtyUpgrades
+	}
+	return s, nil
+}
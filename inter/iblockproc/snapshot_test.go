@@ -0,0 +1,93 @@
+package iblockproc
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rony4d/go-opera-asset/inter/drivertype"
+	"github.com/rony4d/go-opera-asset/inter/validatorpk"
+)
+
+// snapshotStates returns the golden states where the validator 2 has the key.
+func snapshotStates(t *testing.T) (BlockState, EpochState, *ecdsa.PrivateKey) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	es := goldenEpochState(true)
+	es.ValidatorProfiles[2] = drivertype.Validator{
+		Weight: big.NewInt(2000),
+		PubKey: validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: crypto.FromECDSAPub(&key.PublicKey)},
+	}
+	return goldenBlockState(), es, key
+}
+
+func TestStateSnapshotRoundTrip(t *testing.T) {
+	bs, es, key := snapshotStates(t)
+	// the upgrades aren't a part of the RLP encoding of the rules, but they affect the hashes
+	rules := goldenRules(false)
+	rules.Upgrades.Berlin = true
+	bs.DirtyRules = &rules
+	s := NewStateSnapshot(bs, es)
+	require.NoError(t, s.Sign(2, key))
+	require.NoError(t, s.Verify())
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteStateSnapshot(&buf, s))
+	decoded, err := ReadStateSnapshot(&buf)
+	require.NoError(t, err)
+	require.NoError(t, decoded.Verify())
+	require.Equal(t, bs.Hash(), decoded.BlockState.Hash())
+	require.Equal(t, es.Hash(), decoded.EpochState.Hash())
+	require.Equal(t, idx.ValidatorID(2), decoded.Validator)
+	require.Equal(t, es.Rules.Upgrades, decoded.EpochState.Rules.Upgrades)
+	require.Equal(t, rules.Upgrades, decoded.BlockState.DirtyRules.Upgrades)
+
+	_, err = ReadStateSnapshot(bytes.NewReader([]byte("garbage")))
+	require.ErrorIs(t, err, ErrSnapshotFormat)
+}
+
+func TestStateSnapshotVerify(t *testing.T) {
+	bs, es, key := snapshotStates(t)
+
+	// the key of another validator
+	require.ErrorIs(t, NewStateSnapshot(bs, es).Sign(1, key), ErrSnapshotSignature)
+	require.ErrorIs(t, NewStateSnapshot(bs, es).Sign(7, key), ErrSnapshotSignature)
+
+	signed := func() *StateSnapshot {
+		s := NewStateSnapshot(bs.Copy(), es.Copy())
+		require.NoError(t, s.Sign(2, key))
+		return s
+	}
+
+	s := signed()
+	s.BlockState.EpochGas++
+	require.ErrorIs(t, s.Verify(), ErrSnapshotHashMismatch)
+
+	s = signed()
+	s.EpochState.PrevEpochGas++
+	require.ErrorIs(t, s.Verify(), ErrSnapshotHashMismatch)
+
+	// the states are replaced along with their hashes
+	s = signed()
+	s.BlockState.EpochGas++
+	s.BlockStateHash = s.BlockState.Hash()
+	require.ErrorIs(t, s.Verify(), ErrSnapshotSignature)
+
+	// the signature is moved to another validator
+	s = signed()
+	s.Validator = 1
+	require.ErrorIs(t, s.Verify(), ErrSnapshotSignature)
+
+	s = signed()
+	s.Signature = nil
+	require.ErrorIs(t, s.Verify(), ErrSnapshotSignature)
+
+	s = signed()
+	s.Version++
+	require.ErrorIs(t, s.Verify(), ErrSnapshotFormat)
+}
@@ -0,0 +1,298 @@
+package iblockproc
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+	"github.com/Fantom-foundation/lachesis-base/lachesis"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/inter/drivertype"
+	"github.com/rony4d/go-opera-asset/inter/validatorpk"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// The state hashes are a part of the consensus: every node must produce the same hash of the
+// same state, or the network splits. The golden hashes were captured once and must never change.
+// A change which changes the hashes (e.g. a new state version activated by an upgrade) adds new
+// golden states, and captures their hashes with -update-state-hashes, which never overwrites
+// the existing ones.
+var updateStateHashes = flag.Bool("update-state-hashes", false, "capture the hashes of the new golden states")
+
+const stateHashesFile = "state_hashes.json"
+
+// The baseline states were encoded and hashed by the code which predates the optional fields
+// of the states and of the rules. They're never regenerated: the current code must decode them,
+// encode them back to the same bytes and produce the same hashes, and the current default
+// states and mainnet/testnet rules must encode the same way as the baseline ones (except for
+// Dag.MaxTxsPerEvent, which the baseline networks didn't have).
+const baselineStatesFile = "baseline_states.json"
+
+type baselineStates struct {
+	States map[string]struct {
+		RLP  hexutil.Bytes `json:"rlp"`
+		Hash hash.Hash     `json:"hash"`
+	} `json:"states"`
+	Rules map[string]hexutil.Bytes `json:"rules"`
+}
+
+// goldenHash derives a hash of the golden states from the seed.
+func goldenHash(seed string) hash.Hash {
+	return hash.BytesToHash(crypto.Keccak256([]byte(seed)))
+}
+
+// goldenValidators are the validators of the golden states.
+func goldenValidators() *pos.Validators {
+	b := pos.NewBuilder()
+	b.Set(1, 1000)
+	b.Set(2, 2000)
+	b.Set(5, 500)
+	return b.Build()
+}
+
+// goldenRules returns the baseline mainnet rules, rather than the current defaults,
+// so the golden states don't change if the defaults do.
+func goldenRules(london bool) opera.Rules {
+	raw, err := ioutil.ReadFile(filepath.Join("testdata", baselineStatesFile))
+	if err != nil {
+		panic(err)
+	}
+	var baseline baselineStates
+	if err := json.Unmarshal(raw, &baseline); err != nil {
+		panic(err)
+	}
+	var rules opera.Rules
+	if err := rlp.DecodeBytes(baseline.Rules["main"], &rules); err != nil {
+		panic(err)
+	}
+	rules.Upgrades = opera.Upgrades{Berlin: london, London: london}
+	return rules
+}
+
+func goldenProfiles(validators *pos.Validators) ValidatorProfiles {
+	profiles := make(ValidatorProfiles)
+	for i, id := range validators.SortedIDs() {
+		raw := make([]byte, 65)
+		for j := range raw {
+			raw[j] = byte(i*7 + j)
+		}
+		profiles[id] = drivertype.Validator{
+			Weight: new(big.Int).SetUint64(uint64(validators.Get(id))),
+			PubKey: validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: raw},
+		}
+	}
+	return profiles
+}
+
+func goldenBlockState() BlockState {
+	validators := goldenValidators()
+	bs := BlockState{
+		LastBlock: BlockCtx{
+			Idx:     1234,
+			Time:    inter.FromUnix(1600000000),
+			Atropos: hash.Event(goldenHash("atropos")),
+		},
+		FinalizedStateRoot:    goldenHash("state root"),
+		EpochGas:              987654321,
+		ValidatorStates:       make([]ValidatorBlockState, validators.Len()),
+		NextValidatorProfiles: goldenProfiles(validators),
+	}
+	for i := range bs.ValidatorStates {
+		bs.ValidatorStates[i] = ValidatorBlockState{
+			LastEvent: EventInfo{
+				ID:           hash.Event(goldenHash(fmt.Sprintf("event %d", i))),
+				GasPowerLeft: inter.GasPowerLeft{Gas: [inter.GasPowerConfigs]uint64{uint64(i) * 100, uint64(i) * 1000}},
+				Time:         inter.FromUnix(1600000000 - int64(i)),
+			},
+			Uptime:           inter.Timestamp(i) * 1e9,
+			LastOnlineTime:   inter.FromUnix(1600000000 - int64(i)),
+			LastGasPowerLeft: inter.GasPowerLeft{Gas: [inter.GasPowerConfigs]uint64{uint64(i), uint64(i) * 10}},
+			LastBlock:        idx.Block(1234 - i),
+			DirtyGasRefund:   uint64(i) * 21000,
+			Originated:       new(big.Int).Mul(big.NewInt(int64(i+1)), big.NewInt(1e18)),
+		}
+	}
+	return bs
+}
+
+func goldenEpochState(london bool) EpochState {
+	validators := goldenValidators()
+	es := EpochState{
+		Epoch:             42,
+		EpochStart:        inter.FromUnix(1600000000),
+		PrevEpochStart:    inter.FromUnix(1599999000),
+		EpochStateRoot:    goldenHash("epoch state root"),
+		Validators:        validators,
+		ValidatorStates:   make([]ValidatorEpochState, validators.Len()),
+		ValidatorProfiles: goldenProfiles(validators),
+		Rules:             goldenRules(london),
+	}
+	for i := range es.ValidatorStates {
+		es.ValidatorStates[i] = ValidatorEpochState{
+			GasRefund: uint64(i) * 50000,
+			PrevEpochEvent: EventInfo{
+				ID:   hash.Event(goldenHash(fmt.Sprintf("prev epoch event %d", i))),
+				Time: inter.FromUnix(1599999999 - int64(i)),
+			},
+		}
+	}
+	return es
+}
+
+// goldenStates returns the representative states, by the name of their golden hash.
+func goldenStates() map[string]func() hash.Hash {
+	return map[string]func() hash.Hash{
+		"block_state_empty": func() hash.Hash {
+			return BlockState{}.Hash()
+		},
+		"block_state": func() hash.Hash {
+			return goldenBlockState().Hash()
+		},
+		"block_state_cheaters": func() hash.Hash {
+			bs := goldenBlockState()
+			bs.EpochCheaters = lachesis.Cheaters{5, 2}
+			bs.CheatersWritten = 1
+			return bs.Hash()
+		},
+		"block_state_dirty_rules": func() hash.Hash {
+			bs := goldenBlockState()
+			rules := goldenRules(false)
+			rules.Economy.MinGasPrice = big.NewInt(2e9)
+			bs.DirtyRules = &rules
+			bs.AdvanceEpochs = 1
+			return bs.Hash()
+		},
+		"block_state_dirty_rules_london": func() hash.Hash {
+			bs := goldenBlockState()
+			rules := goldenRules(true)
+			rules.Economy.MinGasPrice = big.NewInt(2e9)
+			bs.DirtyRules = &rules
+			bs.AdvanceEpochs = 1
+			return bs.Hash()
+		},
+		"block_state_dirty_rules_txs_limit": func() hash.Hash {
+			bs := goldenBlockState()
+			rules := goldenRules(true)
+			rules.Dag.MaxTxsPerEvent = 256
+			bs.DirtyRules = &rules
+			return bs.Hash()
+		},
+		"epoch_state_pre_london": func() hash.Hash {
+			return goldenEpochState(false).Hash()
+		},
+		"epoch_state_london": func() hash.Hash {
+			return goldenEpochState(true).Hash()
+		},
+		"epoch_state_london_prev_epoch_gas": func() hash.Hash {
+			es := goldenEpochState(true)
+			es.PrevEpochGas = 123456789
+			return es.Hash()
+		},
+	}
+}
+
+// TestStateHashesGolden verifies that the hashes of the representative states match the
+// captured golden hashes, so a refactoring can't change them silently.
+func TestStateHashesGolden(t *testing.T) {
+	path := filepath.Join("testdata", stateHashesFile)
+	got := make(map[string]hash.Hash)
+	for name, h := range goldenStates() {
+		got[name] = h()
+	}
+
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	var want map[string]hash.Hash
+	require.NoError(t, json.Unmarshal(raw, &want))
+	for name := range want {
+		_, ok := got[name]
+		require.True(t, ok, "golden state %s is removed", name)
+	}
+	for name, h := range got {
+		if _, ok := want[name]; !ok && *updateStateHashes {
+			want[name] = h
+			continue
+		}
+		require.Contains(t, want, name, "golden state %s has no hash, capture it with -update-state-hashes", name)
+		require.Equal(t, want[name], h, "hash of %s has changed, the network would split", name)
+	}
+
+	if *updateStateHashes {
+		raw, err := json.MarshalIndent(want, "", "\t")
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(path, append(raw, '\n'), 0644))
+	}
+}
+
+// baselineState decodes the baseline state by its name, returns the decoded state and its hash.
+func baselineState(t *testing.T, name string, b []byte) (interface{}, hash.Hash) {
+	if strings.HasPrefix(name, "epoch_state") {
+		var es EpochState
+		require.NoError(t, rlp.DecodeBytes(b, &es), name)
+		return &es, es.Hash()
+	}
+	var bs BlockState
+	require.NoError(t, rlp.DecodeBytes(b, &bs), name)
+	return &bs, bs.Hash()
+}
+
+// TestStateHashesBaseline verifies that the states encoded before the optional fields were
+// introduced keep their encoding and hashes.
+func TestStateHashesBaseline(t *testing.T) {
+	raw, err := ioutil.ReadFile(filepath.Join("testdata", baselineStatesFile))
+	require.NoError(t, err)
+	var baseline baselineStates
+	require.NoError(t, json.Unmarshal(raw, &baseline))
+
+	golden := goldenStates()
+	for name, v := range baseline.States {
+		state, h := baselineState(t, name, v.RLP)
+		require.Equal(t, v.Hash, h, "hash of the baseline %s has changed", name)
+		b, err := rlp.EncodeToBytes(state)
+		require.NoError(t, err)
+		require.Equal(t, []byte(v.RLP), b, "encoding of the baseline %s has changed", name)
+		if goldenHash, ok := golden[name]; ok {
+			require.Equal(t, v.Hash, goldenHash(), "golden %s doesn't match the baseline one", name)
+		}
+	}
+
+	for name, rules := range map[string]opera.Rules{"main": opera.MainNetRules(), "test": opera.TestNetRules()} {
+		// the txs per event limit is the only rule the baseline networks didn't have
+		require.NotZero(t, rules.Dag.MaxTxsPerEvent, name)
+		rules.Dag.MaxTxsPerEvent = 0
+		b, err := rlp.EncodeToBytes(&rules)
+		require.NoError(t, err)
+		require.Equal(t, []byte(baseline.Rules[name]), b, "%s rules differ from the baseline ones", name)
+	}
+}
+
+// TestStateHashesInvariants verifies the properties the golden hashes rely on.
+func TestStateHashesInvariants(t *testing.T) {
+	// the hash doesn't depend on the order of the validator profiles map
+	bs := goldenBlockState()
+	require.Equal(t, bs.Hash(), bs.Copy().Hash())
+
+	// the pre-London epoch state is hashed in the legacy format, which ignores the time of the previous epoch event
+	es := goldenEpochState(false)
+	before := es.Hash()
+	es.ValidatorStates[0].PrevEpochEvent.Time++
+	require.Equal(t, before, es.Hash())
+
+	es = goldenEpochState(true)
+	before = es.Hash()
+	es.ValidatorStates[0].PrevEpochEvent.Time++
+	require.NotEqual(t, before, es.Hash())
+}
@@ -0,0 +1,112 @@
+package iblockproc
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+)
+
+var (
+	// ErrUnknownValidator is returned if a stake change refers to a validator which isn't
+	// in the next epoch's validator set, as stake may be delegated only to an existing validator.
+	ErrUnknownValidator = errors.New("not a validator of the next epoch")
+	// ErrStakeUnderflow is returned if more stake is withdrawn than the validator has.
+	ErrStakeUnderflow = errors.New("unstaked amount exceeds the validator stake")
+)
+
+// StakeChange is a pending change of a validator's stake: a delegation if Delta is positive,
+// an undelegation if it's negative.
+type StakeChange struct {
+	ValidatorID idx.ValidatorID
+	Delta       *big.Int
+}
+
+// ValidatorProjection is the effect of the stake changes on a validator in the next epoch.
+// Stake is the validator's stake as of the last block, and Weight is its consensus weight
+// in the next epoch if no stake changes. A validator which leaves the set has zero NextWeight.
+type ValidatorProjection struct {
+	ID         idx.ValidatorID
+	Stake      *big.Int
+	NextStake  *big.Int
+	Weight     pos.Weight
+	NextWeight pos.Weight
+}
+
+// Validators returns the validator set of the profiles. It's the construction the epoch sealer
+// uses for the next epoch's validators: the stakes are downscaled by the same power of two,
+// so that the total weight fits into 31 bits, and the zero stakes are dropped.
+func (vv ValidatorProfiles) Validators() *pos.Validators {
+	builder := pos.NewBigBuilder()
+	for id, profile := range vv {
+		builder.Set(id, profile.Weight)
+	}
+	return builder.Build()
+}
+
+// ApplyStakeChanges returns a copy of the profiles with the stake changes applied, in order.
+// A validator whose whole stake is withdrawn leaves the set.
+func (vv ValidatorProfiles) ApplyStakeChanges(changes []StakeChange) (ValidatorProfiles, error) {
+	res := vv.Copy()
+	for _, c := range changes {
+		profile, ok := res[c.ValidatorID]
+		if !ok {
+			return nil, ErrUnknownValidator
+		}
+		profile.Weight.Add(profile.Weight, c.Delta)
+		if profile.Weight.Sign() < 0 {
+			return nil, ErrStakeUnderflow
+		}
+	}
+	for id, profile := range res {
+		if profile.Weight.Sign() == 0 {
+			delete(res, id)
+		}
+	}
+	return res, nil
+}
+
+// SimulateNextValidators returns the validator set the next epoch would start with if the
+// stake changes were applied before the epoch seals, along with the effect on every validator.
+// Note that a change of a single stake may change the weights of all the validators, as the
+// stakes are downscaled by a ratio which depends on the total stake.
+// The projections are ordered as the next validators, followed by the leaving validators by ID.
+func (bs BlockState) SimulateNextValidators(changes []StakeChange) ([]ValidatorProjection, *pos.Validators, error) {
+	next, err := bs.NextValidatorProfiles.ApplyStakeChanges(changes)
+	if err != nil {
+		return nil, nil, err
+	}
+	current := bs.NextValidatorProfiles.Validators()
+	validators := next.Validators()
+
+	res := make([]ValidatorProjection, 0, len(bs.NextValidatorProfiles))
+	for _, id := range validators.SortedIDs() {
+		res = append(res, ValidatorProjection{
+			ID:         id,
+			Stake:      new(big.Int).Set(bs.NextValidatorProfiles[id].Weight),
+			NextStake:  new(big.Int).Set(next[id].Weight),
+			Weight:     current.Get(id),
+			NextWeight: validators.Get(id),
+		})
+	}
+	var leaving []idx.ValidatorID
+	for id := range bs.NextValidatorProfiles {
+		if !validators.Exists(id) {
+			leaving = append(leaving, id)
+		}
+	}
+	sort.Slice(leaving, func(i, j int) bool {
+		return leaving[i] < leaving[j]
+	})
+	for _, id := range leaving {
+		res = append(res, ValidatorProjection{
+			ID:        id,
+			Stake:     new(big.Int).Set(bs.NextValidatorProfiles[id].Weight),
+			NextStake: new(big.Int),
+			Weight:    current.Get(id),
+		})
+	}
+	return res, validators, nil
+}
@@ -0,0 +1,78 @@
+package iblockproc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+
+	"github.com/rony4d/go-opera-asset/inter/drivertype"
+)
+
+func stakes(weights map[idx.ValidatorID]int64) ValidatorProfiles {
+	res := make(ValidatorProfiles, len(weights))
+	for id, w := range weights {
+		res[id] = drivertype.Validator{Weight: new(big.Int).Mul(big.NewInt(w), big.NewInt(1e18))}
+	}
+	return res
+}
+
+func tokens(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18))
+}
+
+func TestSimulateNextValidators(t *testing.T) {
+	bs := BlockState{NextValidatorProfiles: stakes(map[idx.ValidatorID]int64{1: 1000, 2: 2000, 3: 500})}
+
+	projections, validators, err := bs.SimulateNextValidators([]StakeChange{
+		{ValidatorID: 1, Delta: tokens(3000)},
+		{ValidatorID: 3, Delta: tokens(-500)},
+		{ValidatorID: 2, Delta: tokens(-100)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the same set the sealer would build from the changed profiles
+	want := stakes(map[idx.ValidatorID]int64{1: 4000, 2: 1900}).Validators()
+	if validators.String() != want.String() {
+		t.Fatalf("validators %v, want %v", validators, want)
+	}
+	if len(projections) != 3 {
+		t.Fatalf("unexpected projections %+v", projections)
+	}
+	current := bs.NextValidatorProfiles.Validators()
+	for i, exp := range []struct {
+		id         idx.ValidatorID
+		stake      int64
+		nextStake  int64
+		nextWeight pos.Weight
+	}{
+		{1, 1000, 4000, want.Get(1)},
+		{2, 2000, 1900, want.Get(2)},
+		{3, 500, 0, 0},
+	} {
+		p := projections[i]
+		if p.ID != exp.id || p.Stake.Cmp(tokens(exp.stake)) != 0 || p.NextStake.Cmp(tokens(exp.nextStake)) != 0 ||
+			p.Weight != current.Get(exp.id) || p.NextWeight != exp.nextWeight {
+			t.Fatalf("projection %d: %+v", i, p)
+		}
+	}
+	// the block state isn't affected
+	if bs.NextValidatorProfiles[3].Weight.Cmp(tokens(500)) != 0 {
+		t.Fatal("simulation modified the block state")
+	}
+
+	// no changes yield the set the epoch would be sealed with
+	_, validators, err = bs.SimulateNextValidators(nil)
+	if err != nil || validators.String() != current.String() {
+		t.Fatalf("validators %v, want %v (%v)", validators, current, err)
+	}
+
+	if _, _, err := bs.SimulateNextValidators([]StakeChange{{ValidatorID: 4, Delta: tokens(1)}}); err != ErrUnknownValidator {
+		t.Fatalf("delegation to an unknown validator: %v", err)
+	}
+	if _, _, err := bs.SimulateNextValidators([]StakeChange{{ValidatorID: 3, Delta: tokens(-501)}}); err != ErrStakeUnderflow {
+		t.Fatalf("unstaking above the stake: %v", err)
+	}
+}
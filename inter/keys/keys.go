@@ -0,0 +1,137 @@
+// Package keys is the single key schema of the stores (gossip store, LLR store, indexes).
+//
+// All the numbers are encoded big-endian with a fixed width, so the byte order of the keys
+// is the numeric order, and a key built from a prefix of the fields is a prefix of the full key:
+//
+//	epoch || lamport || event hash tail   (= hash.Event, so events of an epoch are iterated in lamport order)
+//	epoch || validator ID
+//	block || position
+//	address || block || position
+//
+// Range scans iterate a prefix built from the leading fields, e.g. Epoch(e) iterates the
+// events of the epoch e, and EpochLamport(e, l) starts from the events of the lamport l.
+package keys
+
+import (
+	"errors"
+
+	"github.com/Fantom-foundation/lachesis-base/common/bigendian"
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Sizes of the encoded fields.
+const (
+	EpochSize     = 4
+	LamportSize   = 4
+	ValidatorSize = 4
+	BlockSize     = 8
+	PositionSize  = 4
+	EventSize     = 32
+	AddressSize   = common.AddressLength
+)
+
+// ErrKeyLength is returned if a key can't be parsed because of its length.
+var ErrKeyLength = errors.New("wrong key length")
+
+func concat(size int, parts ...[]byte) []byte {
+	key := make([]byte, 0, size)
+	for _, p := range parts {
+		key = append(key, p...)
+	}
+	return key
+}
+
+// Epoch returns the key of the epoch, which is also the prefix of the epoch's events.
+func Epoch(epoch idx.Epoch) []byte {
+	return epoch.Bytes()
+}
+
+// ParseEpoch is the reverse of Epoch.
+func ParseEpoch(key []byte) (idx.Epoch, error) {
+	if len(key) != EpochSize {
+		return 0, ErrKeyLength
+	}
+	return idx.BytesToEpoch(key), nil
+}
+
+// EpochLamport returns the prefix of the events of the epoch with the lamport.
+func EpochLamport(epoch idx.Epoch, lamport idx.Lamport) []byte {
+	return concat(EpochSize+LamportSize, epoch.Bytes(), lamport.Bytes())
+}
+
+// ParseEpochLamport is the reverse of EpochLamport.
+func ParseEpochLamport(key []byte) (idx.Epoch, idx.Lamport, error) {
+	if len(key) != EpochSize+LamportSize {
+		return 0, 0, ErrKeyLength
+	}
+	return idx.BytesToEpoch(key[:EpochSize]), idx.BytesToLamport(key[EpochSize:]), nil
+}
+
+// Event returns the key of the event. The event ID already starts with its epoch and lamport.
+func Event(id hash.Event) []byte {
+	return id.Bytes()
+}
+
+// ParseEvent is the reverse of Event.
+func ParseEvent(key []byte) (hash.Event, error) {
+	if len(key) != EventSize {
+		return hash.Event{}, ErrKeyLength
+	}
+	return hash.BytesToEvent(key), nil
+}
+
+// EpochValidator returns the key of a validator's record of the epoch (e.g. an LLR vote).
+func EpochValidator(epoch idx.Epoch, validator idx.ValidatorID) []byte {
+	return concat(EpochSize+ValidatorSize, epoch.Bytes(), validator.Bytes())
+}
+
+// ParseEpochValidator is the reverse of EpochValidator.
+func ParseEpochValidator(key []byte) (idx.Epoch, idx.ValidatorID, error) {
+	if len(key) != EpochSize+ValidatorSize {
+		return 0, 0, ErrKeyLength
+	}
+	return idx.BytesToEpoch(key[:EpochSize]), idx.BytesToValidatorID(key[EpochSize:]), nil
+}
+
+// Block returns the key of the block, which is also the prefix of the block's positions.
+func Block(n idx.Block) []byte {
+	return n.Bytes()
+}
+
+// ParseBlock is the reverse of Block.
+func ParseBlock(key []byte) (idx.Block, error) {
+	if len(key) != BlockSize {
+		return 0, ErrKeyLength
+	}
+	return idx.BytesToBlock(key), nil
+}
+
+// BlockPosition returns the key of an item (transaction, receipt, log) at the position in the block.
+func BlockPosition(n idx.Block, pos uint32) []byte {
+	return concat(BlockSize+PositionSize, n.Bytes(), bigendian.Uint32ToBytes(pos))
+}
+
+// ParseBlockPosition is the reverse of BlockPosition.
+func ParseBlockPosition(key []byte) (idx.Block, uint32, error) {
+	if len(key) != BlockSize+PositionSize {
+		return 0, 0, ErrKeyLength
+	}
+	return idx.BytesToBlock(key[:BlockSize]), bigendian.BytesToUint32(key[BlockSize:]), nil
+}
+
+// AddressBlockPosition returns the key of an item of the address at the position in the block.
+// The address comes first, so the items of an address are iterated in the chain order.
+func AddressBlockPosition(addr common.Address, n idx.Block, pos uint32) []byte {
+	return concat(AddressSize+BlockSize+PositionSize, addr.Bytes(), n.Bytes(), bigendian.Uint32ToBytes(pos))
+}
+
+// ParseAddressBlockPosition is the reverse of AddressBlockPosition.
+func ParseAddressBlockPosition(key []byte) (common.Address, idx.Block, uint32, error) {
+	if len(key) != AddressSize+BlockSize+PositionSize {
+		return common.Address{}, 0, 0, ErrKeyLength
+	}
+	n, pos, err := ParseBlockPosition(key[AddressSize:])
+	return common.BytesToAddress(key[:AddressSize]), n, pos, err
+}
@@ -0,0 +1,173 @@
+package keys
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/dag"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	u32s = []uint32{0, 1, 0xff, 0x100, math.MaxUint32 - 1, math.MaxUint32}
+	u64s = []uint64{0, 1, 0xff, 0x100, math.MaxUint32, math.MaxUint32 + 1, math.MaxUint64 - 1, math.MaxUint64}
+)
+
+// checkOrder verifies that the keys built from the sorted values are sorted bytewise.
+func checkOrder(t *testing.T, name string, keys [][]byte) {
+	t.Helper()
+	for i := 1; i < len(keys); i++ {
+		if bytes.Compare(keys[i-1], keys[i]) >= 0 {
+			t.Fatalf("%s: key %x isn't below %x", name, keys[i-1], keys[i])
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	var ordered [][]byte
+	for _, e := range u32s {
+		key := Epoch(idx.Epoch(e))
+		if got, err := ParseEpoch(key); err != nil || got != idx.Epoch(e) {
+			t.Fatalf("epoch %d: got %d, %v", e, got, err)
+		}
+		ordered = append(ordered, key)
+	}
+	checkOrder(t, "epoch", ordered)
+
+	ordered = nil
+	for _, e := range u32s {
+		for _, l := range u32s {
+			key := EpochLamport(idx.Epoch(e), idx.Lamport(l))
+			gotE, gotL, err := ParseEpochLamport(key)
+			if err != nil || gotE != idx.Epoch(e) || gotL != idx.Lamport(l) {
+				t.Fatalf("epoch %d lamport %d: got %d %d, %v", e, l, gotE, gotL, err)
+			}
+			if !bytes.HasPrefix(key, Epoch(idx.Epoch(e))) {
+				t.Fatalf("epoch %d isn't a prefix of %x", e, key)
+			}
+			id := dag.MutableBaseEvent{}
+			id.SetEpoch(idx.Epoch(e))
+			id.SetLamport(idx.Lamport(l))
+			id.SetID([24]byte{byte(e), byte(l)})
+			event := Event(id.ID())
+			if got, err := ParseEvent(event); err != nil || got != id.ID() {
+				t.Fatalf("event %s: got %s, %v", id.ID(), got, err)
+			}
+			if !bytes.HasPrefix(event, key) {
+				t.Fatalf("%x isn't a prefix of the event %x", key, event)
+			}
+			ordered = append(ordered, key)
+		}
+	}
+	checkOrder(t, "epoch-lamport", ordered)
+
+	ordered = nil
+	for _, e := range u32s {
+		for _, v := range u32s {
+			key := EpochValidator(idx.Epoch(e), idx.ValidatorID(v))
+			gotE, gotV, err := ParseEpochValidator(key)
+			if err != nil || gotE != idx.Epoch(e) || gotV != idx.ValidatorID(v) {
+				t.Fatalf("epoch %d validator %d: got %d %d, %v", e, v, gotE, gotV, err)
+			}
+			ordered = append(ordered, key)
+		}
+	}
+	checkOrder(t, "epoch-validator", ordered)
+
+	ordered = nil
+	for _, n := range u64s {
+		if got, err := ParseBlock(Block(idx.Block(n))); err != nil || got != idx.Block(n) {
+			t.Fatalf("block %d: got %d, %v", n, got, err)
+		}
+		for _, pos := range u32s {
+			key := BlockPosition(idx.Block(n), pos)
+			gotN, gotPos, err := ParseBlockPosition(key)
+			if err != nil || gotN != idx.Block(n) || gotPos != pos {
+				t.Fatalf("block %d position %d: got %d %d, %v", n, pos, gotN, gotPos, err)
+			}
+			if !bytes.HasPrefix(key, Block(idx.Block(n))) {
+				t.Fatalf("block %d isn't a prefix of %x", n, key)
+			}
+			ordered = append(ordered, key)
+		}
+	}
+	checkOrder(t, "block-position", ordered)
+
+	ordered = nil
+	for _, a := range []common.Address{{}, common.HexToAddress("0x01"), common.HexToAddress("0xffffffffffffffffffffffffffffffffffffffff")} {
+		for _, n := range u64s {
+			for _, pos := range u32s {
+				key := AddressBlockPosition(a, idx.Block(n), pos)
+				gotA, gotN, gotPos, err := ParseAddressBlockPosition(key)
+				if err != nil || gotA != a || gotN != idx.Block(n) || gotPos != pos {
+					t.Fatalf("address %s block %d position %d: got %s %d %d, %v", a.Hex(), n, pos, gotA.Hex(), gotN, gotPos, err)
+				}
+				ordered = append(ordered, key)
+			}
+		}
+	}
+	checkOrder(t, "address-block-position", ordered)
+}
+
+func TestWrongLength(t *testing.T) {
+	for _, key := range [][]byte{nil, make([]byte, 3), make([]byte, 13), make([]byte, 40)} {
+		if _, err := ParseEpoch(key); err != ErrKeyLength {
+			t.Fatalf("epoch key %x: %v", key, err)
+		}
+		if _, _, err := ParseEpochLamport(key); err != ErrKeyLength {
+			t.Fatalf("epoch-lamport key %x: %v", key, err)
+		}
+		if _, _, err := ParseEpochValidator(key); err != ErrKeyLength {
+			t.Fatalf("epoch-validator key %x: %v", key, err)
+		}
+		if _, err := ParseEvent(key); err != ErrKeyLength {
+			t.Fatalf("event key %x: %v", key, err)
+		}
+		if _, err := ParseBlock(key); err != ErrKeyLength {
+			t.Fatalf("block key %x: %v", key, err)
+		}
+		if _, _, err := ParseBlockPosition(key); err != ErrKeyLength {
+			t.Fatalf("block-position key %x: %v", key, err)
+		}
+		if _, _, _, err := ParseAddressBlockPosition(key); err != ErrKeyLength {
+			t.Fatalf("address-block-position key %x: %v", key, err)
+		}
+	}
+}
+
+// TestRangeScan verifies that the events of an epoch are iterated in the lamport order,
+// starting from the requested lamport and without the events of the adjacent epochs.
+func TestRangeScan(t *testing.T) {
+	db := memorydb.New()
+	for _, e := range []idx.Epoch{1, 2, 3} {
+		for l := idx.Lamport(300); l > 0; l -= 50 {
+			id := dag.MutableBaseEvent{}
+			id.SetEpoch(e)
+			id.SetLamport(l)
+			id.SetID([24]byte{byte(l)})
+			if err := db.Put(Event(id.ID()), []byte{1}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	it := db.NewIterator(Epoch(2), EpochLamport(2, 200)[EpochSize:])
+	defer it.Release()
+	var got []idx.Lamport
+	for it.Next() {
+		id, err := ParseEvent(it.Key())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id.Epoch() != 2 {
+			t.Fatalf("event %s of another epoch", id)
+		}
+		got = append(got, id.Lamport())
+	}
+	if len(got) != 3 || got[0] != 200 || got[1] != 250 || got[2] != 300 {
+		t.Fatalf("unexpected lamports %v", got)
+	}
+}
@@ -0,0 +1,98 @@
+package inter
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+)
+
+// The fixed-size encoding of the event locators.
+//
+// The locators are encoded and hashed on every LLR vote, so the encoding is written into
+// stack arrays, without reflection, RLP or heap allocations. The locator encoding is:
+//   BaseHash (32) | NetForkID (2) | Epoch (4) | Seq (4) | Lamport (4) | Creator (4) | PayloadHash (32),
+// all the integers are big-endian. It's exactly the preimage of EventLocator.HashToSign,
+// so the event IDs don't depend on which of the encodings is used.
+// The signed locator encoding is the locator encoding followed by the signature (64).
+
+const (
+	// EventLocatorSize is the size of the fixed encoding of EventLocator.
+	EventLocatorSize = 2*32 + 2 + 4*4
+	// SignedEventLocatorSize is the size of the fixed encoding of SignedEventLocator.
+	SignedEventLocatorSize = EventLocatorSize + SigSize
+)
+
+// ErrLocatorSize is returned when decoding a locator of a wrong size.
+var ErrLocatorSize = errors.New("wrong size of the locator encoding")
+
+// EncodeTo writes the fixed encoding of the locator into buf.
+func (l *EventLocator) EncodeTo(buf *[EventLocatorSize]byte) {
+	l.put(buf[:])
+}
+
+// put writes the fixed encoding of the locator into the first EventLocatorSize bytes of buf.
+func (l *EventLocator) put(buf []byte) {
+	copy(buf[0:32], l.BaseHash[:])
+	binary.BigEndian.PutUint16(buf[32:34], l.NetForkID)
+	binary.BigEndian.PutUint32(buf[34:38], uint32(l.Epoch))
+	binary.BigEndian.PutUint32(buf[38:42], uint32(l.Seq))
+	binary.BigEndian.PutUint32(buf[42:46], uint32(l.Lamport))
+	binary.BigEndian.PutUint32(buf[46:50], uint32(l.Creator))
+	copy(buf[50:82], l.PayloadHash[:])
+}
+
+// MarshalFixed returns the fixed encoding of the locator.
+func (l EventLocator) MarshalFixed() (buf [EventLocatorSize]byte) {
+	l.EncodeTo(&buf)
+	return buf
+}
+
+// UnmarshalFixed decodes the fixed encoding of the locator.
+func (l *EventLocator) UnmarshalFixed(b []byte) error {
+	if len(b) != EventLocatorSize {
+		return ErrLocatorSize
+	}
+	copy(l.BaseHash[:], b[0:32])
+	l.NetForkID = binary.BigEndian.Uint16(b[32:34])
+	l.Epoch = idx.Epoch(binary.BigEndian.Uint32(b[34:38]))
+	l.Seq = idx.Event(binary.BigEndian.Uint32(b[38:42]))
+	l.Lamport = idx.Lamport(binary.BigEndian.Uint32(b[42:46]))
+	l.Creator = idx.ValidatorID(binary.BigEndian.Uint32(b[46:50]))
+	copy(l.PayloadHash[:], b[50:82])
+	return nil
+}
+
+// EncodeTo writes the fixed encoding of the signed locator into buf.
+func (r *SignedEventLocator) EncodeTo(buf *[SignedEventLocatorSize]byte) {
+	r.Locator.put(buf[:EventLocatorSize])
+	copy(buf[EventLocatorSize:], r.Sig[:])
+}
+
+// MarshalFixed returns the fixed encoding of the signed locator.
+func (r SignedEventLocator) MarshalFixed() (buf [SignedEventLocatorSize]byte) {
+	r.EncodeTo(&buf)
+	return buf
+}
+
+// UnmarshalFixed decodes the fixed encoding of the signed locator.
+func (r *SignedEventLocator) UnmarshalFixed(b []byte) error {
+	if len(b) != SignedEventLocatorSize {
+		return ErrLocatorSize
+	}
+	if err := r.Locator.UnmarshalFixed(b[:EventLocatorSize]); err != nil {
+		return err
+	}
+	copy(r.Sig[:], b[EventLocatorSize:])
+	return nil
+}
+
+// Hash returns the SHA-256 of the fixed encoding of the signed locator,
+// e.g. to deduplicate the LLR votes.
+func (r SignedEventLocator) Hash() hash.Hash {
+	var buf [SignedEventLocatorSize]byte
+	r.EncodeTo(&buf)
+	return sha256.Sum256(buf[:])
+}
@@ -0,0 +1,126 @@
+package inter
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/common/bigendian"
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+func testSignedLocator() SignedEventLocator {
+	var r SignedEventLocator
+	r.Locator = EventLocator{
+		BaseHash:    hash.Of([]byte("base")),
+		NetForkID:   0x0102,
+		Epoch:       7,
+		Seq:         3,
+		Lamport:     42,
+		Creator:     0x01020304,
+		PayloadHash: hash.Of([]byte("payload")),
+	}
+	for i := range r.Sig {
+		r.Sig[i] = byte(i)
+	}
+	return r
+}
+
+// hashToSignReference is HashToSign as it's computed from the separate fields.
+func hashToSignReference(l EventLocator) hash.Hash {
+	return hash.Of(
+		l.BaseHash.Bytes(),
+		bigendian.Uint16ToBytes(l.NetForkID),
+		l.Epoch.Bytes(),
+		l.Seq.Bytes(),
+		l.Lamport.Bytes(),
+		l.Creator.Bytes(),
+		l.PayloadHash.Bytes(),
+	)
+}
+
+func TestLocatorFixedEncoding(t *testing.T) {
+	r := testSignedLocator()
+
+	// the event IDs must not change
+	require.Equal(t, hashToSignReference(r.Locator), r.Locator.HashToSign())
+
+	enc := r.MarshalFixed()
+	var decoded SignedEventLocator
+	require.NoError(t, decoded.UnmarshalFixed(enc[:]))
+	require.Equal(t, r, decoded)
+	require.Equal(t, hash.Of(enc[:]), r.Hash())
+
+	require.Equal(t, ErrLocatorSize, decoded.UnmarshalFixed(enc[:SignedEventLocatorSize-1]))
+	require.Equal(t, ErrLocatorSize, decoded.Locator.UnmarshalFixed(enc[:]))
+}
+
+func TestLocatorFixedEncoding_NoAllocs(t *testing.T) {
+	r := testSignedLocator()
+	var sink hash.Hash
+	allocs := testing.AllocsPerRun(100, func() {
+		sink = r.Locator.HashToSign()
+		sink = r.Hash()
+		enc := r.MarshalFixed()
+		sink[0] = enc[0]
+	})
+	require.Zero(t, allocs)
+	_ = sink
+}
+
+// --- Benchmarks ---
+
+func BenchmarkSignedEventLocator_EncodeRLP(b *testing.B) {
+	r := testSignedLocator()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := rlp.EncodeToBytes(&r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSignedEventLocator_EncodeFixed(b *testing.B) {
+	r := testSignedLocator()
+	var buf [SignedEventLocatorSize]byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.EncodeTo(&buf)
+	}
+}
+
+func BenchmarkSignedEventLocator_HashRLP(b *testing.B) {
+	r := testSignedLocator()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		enc, err := rlp.EncodeToBytes(&r)
+		if err != nil {
+			b.Fatal(err)
+		}
+		_ = hash.Of(enc)
+	}
+}
+
+func BenchmarkSignedEventLocator_HashFixed(b *testing.B) {
+	r := testSignedLocator()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = r.Hash()
+	}
+}
+
+func BenchmarkEventLocator_HashToSignReference(b *testing.B) {
+	l := testSignedLocator().Locator
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = hashToSignReference(l)
+	}
+}
+
+func BenchmarkEventLocator_HashToSign(b *testing.B) {
+	l := testSignedLocator().Locator
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = l.HashToSign()
+	}
+}
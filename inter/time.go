@@ -32,6 +32,12 @@ func (t Timestamp) Unix() int64 {
 	return int64(t) / int64(time.Second)
 }
 
+// UnixMilli returns t as a Unix time in milliseconds, rounded down.
+// Rounding down keeps it consistent with Unix: UnixMilli() / 1000 == Unix().
+func (t Timestamp) UnixMilli() int64 {
+	return int64(t) / int64(time.Millisecond)
+}
+
 func (t Timestamp) Time() time.Time {
 	return time.Unix(int64(t)/int64(time.Second), int64(t)%int64(time.Second))
 }
@@ -0,0 +1,29 @@
+package inter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimestamp_UnixMilli(t *testing.T) {
+	for _, tt := range []struct {
+		t    Timestamp
+		ms   int64
+		unix int64
+	}{
+		{0, 0, 0},
+		{Timestamp(999 * time.Microsecond), 0, 0},
+		{Timestamp(time.Millisecond), 1, 0},
+		{Timestamp(999*time.Millisecond + 999999), 999, 0},
+		{Timestamp(time.Second), 1000, 1},
+		{FromUnix(1600000000) + Timestamp(1500*time.Microsecond), 1600000000001, 1600000000},
+		{FromUnix(1600000000) + Timestamp(time.Second-1), 1600000000999, 1600000000},
+	} {
+		if ms := tt.t.UnixMilli(); ms != tt.ms {
+			t.Errorf("%d.UnixMilli() = %d, want %d", tt.t, ms, tt.ms)
+		}
+		if unix := tt.t.Unix(); unix != tt.unix || tt.t.UnixMilli()/1000 != unix {
+			t.Errorf("%d.Unix() = %d is inconsistent with UnixMilli() = %d", tt.t, unix, tt.t.UnixMilli())
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Package blocktime implements a precompiled contract exposing the block time in milliseconds.
+//
+// Overview:
+//
+//	The TIMESTAMP opcode returns the block time in seconds, as Ethereum does, while the Opera
+//	block time (inter.Timestamp) has the nanosecond precision. App-chains which need a finer
+//	time (e.g. order books, auctions) call BlockTime.timestampMs() instead.
+//
+//	The contract is registered only once opera.Upgrades.MillisecondTime is enabled; before it,
+//	ContractAddress is an ordinary empty account, and calls to it return no data.
+//
+// Rounding:
+//
+//	The time is rounded down to milliseconds (see inter.Timestamp.UnixMilli), never to the
+//	nearest one, so timestampMs() / 1000 == block.timestamp in every block, and the result
+//	depends only on the block time agreed by the consensus.
+package blocktime
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+var (
+	// ContractAddress is the precompiled contract address for BlockTime.
+	// Address: 0xd100b10c00000000000000000000000000000000
+	ContractAddress = common.HexToAddress("0xd100b10c00000000000000000000000000000000")
+
+	// ContractABI is the JSON ABI definition for the BlockTime contract:
+	//   - timestampMs() returns (uint256): the block time in milliseconds since the Unix epoch
+	// The method isn't declared view, as the EVM dispatches the state precompiles only by CALL,
+	// while Solidity calls the view methods by STATICCALL.
+	ContractABI string = "[{\"constant\":false,\"inputs\":[],\"name\":\"timestampMs\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"
+)
+
+// TimestampMsGas is the gas cost of timestampMs(), the same as of the TIMESTAMP opcode.
+const TimestampMsGas = vm.GasQuickStep
+
+var timestampMsMethodID []byte // timestampMs()
+
+func init() {
+	abi, err := abi.JSON(strings.NewReader(ContractABI))
+	if err != nil {
+		panic(err)
+	}
+	method, exist := abi.Methods["timestampMs"]
+	if !exist {
+		panic("unknown BlockTime method")
+	}
+	timestampMsMethodID = make([]byte, len(method.ID))
+	copy(timestampMsMethodID, method.ID)
+}
+
+// PreCompiledContract implements the vm.PrecompiledStateContract interface.
+// It's created per block, as the block context carries the time only in seconds.
+type PreCompiledContract struct {
+	Time inter.Timestamp // block time
+}
+
+// Run returns the block time in milliseconds, ABI-encoded as uint256.
+func (c PreCompiledContract) Run(_ vm.StateDB, _ vm.BlockContext, _ vm.TxContext, _ common.Address, input []byte, suppliedGas uint64) ([]byte, uint64, error) {
+	if len(input) != 4 || !bytes.Equal(input, timestampMsMethodID) {
+		return nil, 0, vm.ErrExecutionReverted
+	}
+	if suppliedGas < TimestampMsGas {
+		return nil, 0, vm.ErrOutOfGas
+	}
+	ms := new(big.Int).SetInt64(c.Time.UnixMilli())
+	return common.BigToHash(ms).Bytes(), suppliedGas - TimestampMsGas, nil
+}
@@ -27,6 +27,13 @@
 //   - The contract address is hardcoded and must match across all nodes
 //   - Only authorized backend contracts can call certain functions
 //   - Validator operations are validated through the consensus layer
+//
+// Bindings:
+//   driver_bindings.go is generated from NodeDriver.abi (opera-sfc repository) by abigen,
+//   see the go:generate directive below. Don't edit it manually, the calls made by the node
+//   itself are built by the drivercall package on top of the generated ABI.
+
+//go:generate go run github.com/ethereum/go-ethereum/cmd/abigen --abi NodeDriver.abi --pkg driver --type NodeDriver --out driver_bindings.go
 
 package driver
 
@@ -0,0 +1,84 @@
+// Package drivercall builds calldata of the NodeDriver contract methods which are
+// called by the node itself (i.e. by internal transactions), not by users.
+//
+// The calls are packed with the ABI of the generated NodeDriver bindings, so the
+// arguments are type-checked against the contract instead of being laid out by hand.
+package drivercall
+
+import (
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+	"github.com/rony4d/go-opera-asset/opera/contracts/driver"
+)
+
+// sAbi is the parsed ABI of the NodeDriver contract.
+var sAbi *abi.ABI
+
+func init() {
+	var err error
+	sAbi, err = driver.NodeDriverMetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// pack encodes the call. The arguments are statically typed by the helpers,
+// so an error means that the helper doesn't match the ABI.
+func pack(method string, args ...interface{}) []byte {
+	data, err := sAbi.Pack(method, args...)
+	if err != nil {
+		panic("drivercall: " + method + ": " + err.Error())
+	}
+	return data
+}
+
+// DeactivateValidator returns calldata of NodeDriver.deactivateValidator(validatorID, status).
+//
+// Parameters:
+//   - validatorID: The validator to deactivate
+//   - status: Status bits to set, e.g. drivertype.OfflineBit or drivertype.DoublesignBit
+func DeactivateValidator(validatorID idx.ValidatorID, status uint64) []byte {
+	return pack("deactivateValidator", new(big.Int).SetUint64(uint64(validatorID)), new(big.Int).SetUint64(status))
+}
+
+// SealEpochValidators returns calldata of NodeDriver.sealEpochValidators(nextValidatorIDs),
+// which sets the validators of the next epoch.
+func SealEpochValidators(nextValidatorIDs []idx.ValidatorID) []byte {
+	ids := make([]*big.Int, len(nextValidatorIDs))
+	for i, id := range nextValidatorIDs {
+		ids[i] = new(big.Int).SetUint64(uint64(id))
+	}
+	return pack("sealEpochValidators", ids)
+}
+
+// ValidatorEpochMetric is the performance of a validator in the sealed epoch.
+type ValidatorEpochMetric struct {
+	Missed          opera.BlocksMissed
+	Uptime          inter.Timestamp
+	OriginatedTxFee *big.Int
+}
+
+// SealEpoch returns calldata of NodeDriver.sealEpoch(offlineTimes, offlineBlocks, uptimes, originatedTxsFee),
+// which distributes the rewards of the sealed epoch.
+// The metrics must be in the order of the epoch validators.
+func SealEpoch(metrics []ValidatorEpochMetric) []byte {
+	offlineTimes := make([]*big.Int, len(metrics))
+	offlineBlocks := make([]*big.Int, len(metrics))
+	uptimes := make([]*big.Int, len(metrics))
+	originatedTxsFee := make([]*big.Int, len(metrics))
+	for i, m := range metrics {
+		offlineTimes[i] = new(big.Int).SetUint64(uint64(m.Missed.Period.Unix()))
+		offlineBlocks[i] = new(big.Int).SetUint64(uint64(m.Missed.BlocksNum))
+		uptimes[i] = new(big.Int).SetUint64(uint64(m.Uptime.Unix()))
+		originatedTxsFee[i] = m.OriginatedTxFee
+		if originatedTxsFee[i] == nil {
+			originatedTxsFee[i] = new(big.Int)
+		}
+	}
+	return pack("sealEpoch", offlineTimes, offlineBlocks, uptimes, originatedTxsFee)
+}
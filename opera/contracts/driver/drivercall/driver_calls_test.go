@@ -0,0 +1,61 @@
+package drivercall
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+func TestDeactivateValidator(t *testing.T) {
+	// the layout of the previous hand-packed encoding
+	want := []byte{0x1e, 0x70, 0x2f, 0x83}
+	want = append(want, common.BigToHash(big.NewInt(3)).Bytes()...)
+	want = append(want, common.BigToHash(big.NewInt(1<<7)).Bytes()...)
+
+	if got := DeactivateValidator(3, 1<<7); !bytes.Equal(got, want) {
+		t.Fatalf("unexpected calldata %x, want %x", got, want)
+	}
+}
+
+func TestSealEpoch(t *testing.T) {
+	metrics := []ValidatorEpochMetric{
+		{
+			Missed:          opera.BlocksMissed{BlocksNum: 5, Period: inter.Timestamp(10 * time.Second)},
+			Uptime:          inter.Timestamp(60 * time.Second),
+			OriginatedTxFee: big.NewInt(1000),
+		},
+		{Uptime: inter.Timestamp(70 * time.Second)},
+	}
+	data := SealEpoch(metrics)
+
+	method, err := sAbi.MethodById(data[:4])
+	if err != nil || method.Name != "sealEpoch" {
+		t.Fatalf("unexpected method %v, err %v", method, err)
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	offlineTimes, offlineBlocks, uptimes, fees := args[0].([]*big.Int), args[1].([]*big.Int), args[2].([]*big.Int), args[3].([]*big.Int)
+	if offlineTimes[0].Uint64() != 10 || offlineBlocks[0].Uint64() != 5 || uptimes[0].Uint64() != 60 || fees[0].Uint64() != 1000 {
+		t.Fatalf("unexpected arguments of the first validator %v %v %v %v", offlineTimes, offlineBlocks, uptimes, fees)
+	}
+	if uptimes[1].Uint64() != 70 || fees[1].Sign() != 0 {
+		t.Fatalf("unexpected arguments of the second validator %v %v", uptimes, fees)
+	}
+
+	data = SealEpochValidators([]idx.ValidatorID{1, 4})
+	if method, err = sAbi.MethodById(data[:4]); err != nil || method.Name != "sealEpochValidators" {
+		t.Fatalf("unexpected method %v, err %v", method, err)
+	}
+	if args, err = method.Inputs.Unpack(data[4:]); err != nil || args[0].([]*big.Int)[1].Uint64() != 4 {
+		t.Fatalf("unexpected arguments %v, err %v", args, err)
+	}
+}
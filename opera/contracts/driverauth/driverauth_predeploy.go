@@ -0,0 +1,23 @@
+// Package driverauth provides the address and the generated Go bindings of the
+// predeployed NodeDriverAuth contract.
+//
+// Overview:
+//   NodeDriverAuth is the access control layer in front of the NodeDriver contract.
+//   The node calls NodeDriver, which forwards the consensus calls (epoch sealing,
+//   validator deactivation) to the SFC through NodeDriverAuth, while the governance
+//   calls (rules and code upgrades) go the other way and are restricted to the owner.
+//
+// Bindings:
+//   driverauth_bindings.go is generated from NodeDriverAuth.abi (opera-sfc repository)
+//   by abigen, see the go:generate directive below. Don't edit it manually.
+
+//go:generate go run github.com/ethereum/go-ethereum/cmd/abigen --abi NodeDriverAuth.abi --pkg driverauth --type NodeDriverAuth --out driverauth_bindings.go
+
+package driverauth
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ContractAddress is the predeployed address of the NodeDriverAuth contract.
+var ContractAddress = common.HexToAddress("0xd100ae0100000000000000000000000000000000")
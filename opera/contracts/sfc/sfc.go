@@ -0,0 +1,49 @@
+// Package sfc builds calldata of the SFC (Special Fee Contract) methods which are called
+// by validators, i.e. by ordinary transactions of their auth accounts.
+//
+// Overview:
+//   The SFC is deployed by the genesis at ContractAddress, and it forwards the validator
+//   changes to the NodeDriver (through NodeDriverAuth), which emits the events the block
+//   processor applies at the epoch boundary.
+//
+//   Only the methods used by the node's commands are described by ContractABI.
+package sfc
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/rony4d/go-opera-asset/inter/validatorpk"
+)
+
+var (
+	// ContractAddress is the address of the SFC contract.
+	ContractAddress = common.HexToAddress("0xfc00face00000000000000000000000000000000")
+
+	// ContractABI is the JSON ABI definition of the used SFC methods:
+	//   - updateValidatorPubkey(bytes pubkey): change the key of the sender's validator since the next epoch
+	ContractABI string = "[{\"constant\":false,\"inputs\":[{\"internalType\":\"bytes\",\"name\":\"pubkey\",\"type\":\"bytes\"}],\"name\":\"updateValidatorPubkey\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"
+)
+
+// sAbi is the parsed ABI of the SFC contract.
+var sAbi abi.ABI
+
+func init() {
+	var err error
+	sAbi, err = abi.JSON(strings.NewReader(ContractABI))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// UpdateValidatorPubkey returns calldata of SFC.updateValidatorPubkey(pubkey).
+// The key takes effect since the epoch following the one the transaction is executed in.
+func UpdateValidatorPubkey(pubkey validatorpk.PubKey) []byte {
+	data, err := sAbi.Pack("updateValidatorPubkey", pubkey.Bytes())
+	if err != nil {
+		panic("sfc: updateValidatorPubkey: " + err.Error())
+	}
+	return data
+}
@@ -0,0 +1,22 @@
+package sfc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/rony4d/go-opera-asset/inter/validatorpk"
+)
+
+func TestUpdateValidatorPubkey(t *testing.T) {
+	pubkey := validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: []byte{1, 2, 3}}
+	data := UpdateValidatorPubkey(pubkey)
+	if !bytes.Equal(data[:4], crypto.Keccak256([]byte("updateValidatorPubkey(bytes)"))[:4]) {
+		t.Fatalf("unexpected selector %x", data[:4])
+	}
+	args, err := sAbi.Methods["updateValidatorPubkey"].Inputs.Unpack(data[4:])
+	if err != nil || !bytes.Equal(args[0].([]byte), pubkey.Bytes()) {
+		t.Fatalf("unexpected arguments %v, err %v", args, err)
+	}
+}
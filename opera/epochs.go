@@ -0,0 +1,43 @@
+package opera
+
+import (
+	"math/big"
+
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+// AutoTuned returns true if the gas-adaptive epochs mode is enabled.
+func (r EpochsRules) AutoTuned() bool {
+	return r.MinEpochDuration != 0
+}
+
+// EpochDurationLimit returns the time limit of the next epoch.
+//
+// If the gas-adaptive mode is off, it's always MaxEpochDuration. Otherwise the limit
+// is interpolated linearly from the gas utilization of the previous epoch:
+//   - idle previous epoch (no gas used) -> MaxEpochDuration
+//   - fully loaded previous epoch (MaxEpochGas used) -> MinEpochDuration
+//
+// Busy chains get short epochs (fresh validator set and rewards more often), while quiet
+// app-chains don't pay for frequent epoch sealing. The limit depends only on the rules and
+// the sealed previous epoch, so all the validators compute the same value.
+//
+// Parameters:
+//   - prevEpochGas: Total gas used by the previous epoch
+//
+// Returns:
+//   - inter.Timestamp: Time limit of the next epoch
+func (r EpochsRules) EpochDurationLimit(prevEpochGas uint64) inter.Timestamp {
+	if !r.AutoTuned() || r.MinEpochDuration >= r.MaxEpochDuration || r.MaxEpochGas == 0 {
+		return r.MaxEpochDuration
+	}
+	if prevEpochGas >= r.MaxEpochGas {
+		return r.MinEpochDuration
+	}
+	// reduction = (Max - Min) * prevEpochGas / MaxEpochGas
+	// big.Int is used as the product may overflow uint64
+	reduction := new(big.Int).SetUint64(uint64(r.MaxEpochDuration - r.MinEpochDuration))
+	reduction.Mul(reduction, new(big.Int).SetUint64(prevEpochGas))
+	reduction.Div(reduction, new(big.Int).SetUint64(r.MaxEpochGas))
+	return r.MaxEpochDuration - inter.Timestamp(reduction.Uint64())
+}
@@ -0,0 +1,40 @@
+package opera
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+// TestEpochDurationLimit verifies the gas-adaptive epoch time limit.
+func TestEpochDurationLimit(t *testing.T) {
+	fixed := DefaultEpochsRules()
+	if fixed.AutoTuned() {
+		t.Fatal("default epochs rules must not be auto-tuned")
+	}
+	if got := fixed.EpochDurationLimit(fixed.MaxEpochGas); got != fixed.MaxEpochDuration {
+		t.Errorf("fixed limit = %v, want %v", got, fixed.MaxEpochDuration)
+	}
+
+	tuned := DefaultEpochsRules()
+	tuned.MinEpochDuration = inter.Timestamp(10 * time.Minute)
+
+	tests := []struct {
+		name string
+		gas  uint64
+		want inter.Timestamp
+	}{
+		{"idle", 0, tuned.MaxEpochDuration},
+		{"full load", tuned.MaxEpochGas, tuned.MinEpochDuration},
+		{"overload", tuned.MaxEpochGas * 2, tuned.MinEpochDuration},
+		{"half load", tuned.MaxEpochGas / 2, (tuned.MaxEpochDuration + tuned.MinEpochDuration) / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tuned.EpochDurationLimit(tt.gas); got != tt.want {
+				t.Errorf("EpochDurationLimit(%d) = %v, want %v", tt.gas, got, tt.want)
+			}
+		})
+	}
+}
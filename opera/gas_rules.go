@@ -0,0 +1,125 @@
+package opera
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// gas_rules.go implements the versioned RLP encoding of GasRules.
+//
+// Overview:
+//   - Version 1 is encoded as a plain RLP list of GasRulesRLPV1, exactly as before the versioning,
+//     so the rules (and the states hashing them) persisted by older nodes stay valid.
+//   - Later versions are encoded as an RLP string holding the version byte followed by the
+//     RLP list of the version's struct, in the same way as typed transactions (EIP-2718).
+//     A list and a string never collide, so the decoder tells the versions apart by the kind.
+//   - Decoding accepts any known version. Encoding uses the latest version once its fields are
+//     in use, which the validation of the rules allows only after Upgrades.GasV2, i.e. the
+//     encoding changes exactly at the upgrade and not when a node is updated.
+
+// Versions of the gas rules encoding.
+const (
+	GasRulesV1 uint8 = 1
+	GasRulesV2 uint8 = 2
+
+	// LatestGasRulesVersion is the version of GasRules
+	LatestGasRulesVersion = GasRulesV2
+)
+
+// ErrUnknownGasRulesVersion is returned when decoding the gas rules of an unsupported version.
+var ErrUnknownGasRulesVersion = errors.New("unknown gas rules version")
+
+// V1 returns the fields of the gas rules which are known to version 1.
+func (g GasRules) V1() GasRulesRLPV1 {
+	return GasRulesRLPV1{
+		MaxEventGas:          g.MaxEventGas,
+		EventGas:             g.EventGas,
+		ParentGas:            g.ParentGas,
+		ExtraDataGas:         g.ExtraDataGas,
+		BlockVotesBaseGas:    g.BlockVotesBaseGas,
+		BlockVoteGas:         g.BlockVoteGas,
+		EpochVoteGas:         g.EpochVoteGas,
+		MisbehaviourProofGas: g.MisbehaviourProofGas,
+	}
+}
+
+// GasRulesFromV1 converts the gas rules of version 1, leaving the later fields zero.
+func GasRulesFromV1(v1 GasRulesRLPV1) GasRules {
+	return GasRules{
+		MaxEventGas:          v1.MaxEventGas,
+		EventGas:             v1.EventGas,
+		ParentGas:            v1.ParentGas,
+		ExtraDataGas:         v1.ExtraDataGas,
+		BlockVotesBaseGas:    v1.BlockVotesBaseGas,
+		BlockVoteGas:         v1.BlockVoteGas,
+		EpochVoteGas:         v1.EpochVoteGas,
+		MisbehaviourProofGas: v1.MisbehaviourProofGas,
+	}
+}
+
+// Version returns the lowest version which can hold the gas rules, i.e. the version they are encoded with.
+func (g GasRules) Version() uint8 {
+	if g.TxDataGas != 0 || g.VoteAggregationDiscount != 0 {
+		return GasRulesV2
+	}
+	return GasRulesV1
+}
+
+// GasRulesVersion returns the latest version of the gas rules enabled by the upgrades.
+func (u Upgrades) GasRulesVersion() uint8 {
+	if u.GasV2 {
+		return GasRulesV2
+	}
+	return GasRulesV1
+}
+
+// EncodeRLP implements the rlp.Encoder interface.
+func (g GasRules) EncodeRLP(w io.Writer) error {
+	version := g.Version()
+	if version == GasRulesV1 {
+		return rlp.Encode(w, g.V1())
+	}
+	body, err := rlp.EncodeToBytes(GasRulesRLPV2(g))
+	if err != nil {
+		return err
+	}
+	return rlp.Encode(w, append([]byte{version}, body...))
+}
+
+// DecodeRLP implements the rlp.Decoder interface.
+func (g *GasRules) DecodeRLP(s *rlp.Stream) error {
+	kind, _, err := s.Kind()
+	if err != nil {
+		return err
+	}
+	if kind == rlp.List {
+		var v1 GasRulesRLPV1
+		if err := s.Decode(&v1); err != nil {
+			return err
+		}
+		*g = GasRulesFromV1(v1)
+		return nil
+	}
+
+	raw, err := s.Bytes()
+	if err != nil {
+		return err
+	}
+	if len(raw) == 0 {
+		return fmt.Errorf("%w: empty", ErrUnknownGasRulesVersion)
+	}
+	switch raw[0] {
+	case GasRulesV2:
+		var v2 GasRulesRLPV2
+		if err := rlp.DecodeBytes(raw[1:], &v2); err != nil {
+			return err
+		}
+		*g = GasRules(v2)
+		return nil
+	default:
+		return fmt.Errorf("%w: %d", ErrUnknownGasRulesVersion, raw[0])
+	}
+}
@@ -0,0 +1,123 @@
+package opera
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// TestGasRulesRLP_V1Compatible verifies that the gas rules without the V2 fields are encoded
+// exactly as GasRulesRLPV1, so the persisted rules keep their hashes.
+func TestGasRulesRLP_V1Compatible(t *testing.T) {
+	gas := DefaultGasRules()
+	if gas.Version() != GasRulesV1 {
+		t.Fatalf("Version() = %d, want %d", gas.Version(), GasRulesV1)
+	}
+	got, err := rlp.EncodeToBytes(gas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := rlp.EncodeToBytes(gas.V1())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("encoding = %x, want %x", got, want)
+	}
+
+	var decoded GasRules
+	if err := rlp.DecodeBytes(want, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != gas {
+		t.Errorf("decoded %+v, want %+v", decoded, gas)
+	}
+}
+
+// TestGasRulesRLP_V2 verifies the round trip of the gas rules using the V2 fields.
+func TestGasRulesRLP_V2(t *testing.T) {
+	gas := DefaultGasRules()
+	gas.TxDataGas = 16
+	gas.VoteAggregationDiscount = 50
+	if gas.Version() != GasRulesV2 {
+		t.Fatalf("Version() = %d, want %d", gas.Version(), GasRulesV2)
+	}
+	b, err := rlp.EncodeToBytes(gas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kind, content, _, err := rlp.Split(b); err != nil || kind != rlp.String || content[0] != GasRulesV2 {
+		t.Fatalf("V2 isn't encoded as a typed envelope: %x", b)
+	}
+	var decoded GasRules
+	if err := rlp.DecodeBytes(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != gas {
+		t.Errorf("decoded %+v, want %+v", decoded, gas)
+	}
+}
+
+// TestGasRulesRLP_MixedVersions verifies that the rules of both versions decode side by side,
+// e.g. the rules of the epochs before and after the upgrade.
+func TestGasRulesRLP_MixedVersions(t *testing.T) {
+	before := FakeNetRules()
+	after := FakeNetRules()
+	after.Upgrades.GasV2 = true
+	after.Economy.Gas.TxDataGas = 16
+	if err := after.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := rlp.EncodeToBytes([]Rules{before, after})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []Rules
+	if err := rlp.DecodeBytes(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("decoded %d rules, want 2", len(decoded))
+	}
+	if decoded[0].Economy.Gas != before.Economy.Gas {
+		t.Errorf("V1 gas rules = %+v, want %+v", decoded[0].Economy.Gas, before.Economy.Gas)
+	}
+	if decoded[1].Economy.Gas != after.Economy.Gas {
+		t.Errorf("V2 gas rules = %+v, want %+v", decoded[1].Economy.Gas, after.Economy.Gas)
+	}
+	// the following fields are decoded correctly after both encodings
+	if decoded[0].Economy.MinGasPrice.Cmp(before.Economy.MinGasPrice) != 0 || decoded[1].Economy.OfflinePeriod != after.Economy.OfflinePeriod {
+		t.Error("fields following the gas rules are decoded wrong")
+	}
+}
+
+// TestGasRulesRLP_UnknownVersion verifies that a future version is rejected rather than misread.
+func TestGasRulesRLP_UnknownVersion(t *testing.T) {
+	body, err := rlp.EncodeToBytes(GasRulesRLPV2{EventGas: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, payload := range [][]byte{append([]byte{LatestGasRulesVersion + 1}, body...), {}} {
+		b, err := rlp.EncodeToBytes(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded GasRules
+		if err := rlp.DecodeBytes(b, &decoded); !errors.Is(err, ErrUnknownGasRulesVersion) {
+			t.Errorf("DecodeBytes(%x) = %v, want %v", b, err, ErrUnknownGasRulesVersion)
+		}
+	}
+}
+
+// TestUpgrades_GasRulesVersion verifies the gas rules version enabled by the upgrades.
+func TestUpgrades_GasRulesVersion(t *testing.T) {
+	if v := (Upgrades{Berlin: true, London: true, Llr: true}).GasRulesVersion(); v != GasRulesV1 {
+		t.Errorf("GasRulesVersion() = %d, want %d", v, GasRulesV1)
+	}
+	if v := (Upgrades{GasV2: true}).GasRulesVersion(); v != LatestGasRulesVersion {
+		t.Errorf("GasRulesVersion() = %d, want %d", v, LatestGasRulesVersion)
+	}
+}
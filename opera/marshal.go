@@ -0,0 +1,118 @@
+package opera
+
+import (
+	"errors"
+	"io"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// marshal.go implements the RLP encoding of the rules, the same as upstream go-opera.
+//
+// The rules without upgrades are encoded as RulesRLP. Once any upgrade is enabled, the rules
+// are prefixed with the type byte 1 and followed by the upgrades bitmap (see the *Bit flags),
+// so the upgrades are a part of the rules hash, and survive storing the rules.
+//
+// Compatibility: the rules without upgrades are encoded byte-identically to the previous
+// releases. The rules with any upgrade enabled are NOT: the upgrades weren't encoded before,
+// so the encoding and the rules hash of such a network change. It's a consensus change,
+// all the nodes of such a network must upgrade together.
+
+// rulesTypeUpgrades is the type of the rules encoded with the upgrades bitmap.
+const rulesTypeUpgrades = 1
+
+// ErrUnknownRulesType is returned if the encoded rules have an unknown type prefix.
+var ErrUnknownRulesType = errors.New("unknown rules type")
+
+// EncodeRLP implements the rlp.Encoder interface.
+func (r Rules) EncodeRLP(w io.Writer) error {
+	if r.Upgrades != (Upgrades{}) {
+		if _, err := w.Write([]byte{rulesTypeUpgrades}); err != nil {
+			return err
+		}
+	}
+	body := RulesRLP(r)
+	if err := rlp.Encode(w, &body); err != nil {
+		return err
+	}
+	if r.Upgrades != (Upgrades{}) {
+		return rlp.Encode(w, &r.Upgrades)
+	}
+	return nil
+}
+
+// DecodeRLP implements the rlp.Decoder interface.
+func (r *Rules) DecodeRLP(s *rlp.Stream) error {
+	kind, _, err := s.Kind()
+	if err != nil {
+		return err
+	}
+	rType := uint8(0)
+	if kind == rlp.Byte {
+		b, err := s.Bytes()
+		if err != nil {
+			return err
+		}
+		if len(b) != 1 || b[0] != rulesTypeUpgrades {
+			return ErrUnknownRulesType
+		}
+		rType = b[0]
+	}
+	var body RulesRLP
+	if err := s.Decode(&body); err != nil {
+		return err
+	}
+	*r = Rules(body)
+	if rType == rulesTypeUpgrades {
+		return s.Decode(&r.Upgrades)
+	}
+	return nil
+}
+
+// upgradesRLP is the RLP encoding of Upgrades.
+type upgradesRLP struct {
+	V uint64
+}
+
+// EncodeRLP implements the rlp.Encoder interface.
+func (u Upgrades) EncodeRLP(w io.Writer) error {
+	bitmap := upgradesRLP{}
+	if u.Berlin {
+		bitmap.V |= berlinBit
+	}
+	if u.London {
+		bitmap.V |= londonBit
+	}
+	if u.Llr {
+		bitmap.V |= llrBit
+	}
+	if u.Paused {
+		bitmap.V |= pausedBit
+	}
+	if u.GasV2 {
+		bitmap.V |= gasV2Bit
+	}
+	if u.Cancun {
+		bitmap.V |= cancunBit
+	}
+	if u.MillisecondTime {
+		bitmap.V |= msTimeBit
+	}
+	return rlp.Encode(w, &bitmap)
+}
+
+// DecodeRLP implements the rlp.Decoder interface.
+func (u *Upgrades) DecodeRLP(s *rlp.Stream) error {
+	bitmap := upgradesRLP{}
+	if err := s.Decode(&bitmap); err != nil {
+		return err
+	}
+	u.Berlin = bitmap.V&berlinBit != 0
+	u.London = bitmap.V&londonBit != 0
+	u.Llr = bitmap.V&llrBit != 0
+	u.Paused = bitmap.V&pausedBit != 0
+	u.GasV2 = bitmap.V&gasV2Bit != 0
+	u.Cancun = bitmap.V&cancunBit != 0
+	u.MillisecondTime = bitmap.V&msTimeBit != 0
+	return nil
+}
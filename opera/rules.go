@@ -46,6 +46,10 @@ const (
 	berlinBit = 1 << 0 // Berlin upgrade flag
 	londonBit = 1 << 1 // London upgrade flag
 	llrBit    = 1 << 2 // LLR (Low Latency Records) upgrade flag
+	pausedBit = 1 << 3 // Emergency network pause flag
+	gasV2Bit  = 1 << 4 // GasRulesRLPV2 fields flag
+	cancunBit = 1 << 5 // Cancun (blob gas accounting) upgrade flag
+	msTimeBit = 1 << 6 // Millisecond block time precompile flag
 )
 
 // DefaultVMConfig provides the default EVM configuration with precompiled contracts.
@@ -58,7 +62,8 @@ var DefaultVMConfig = vm.Config{
 
 // RulesRLP (RLP stands for Recursive Length Prefix. It's Ethereum's serialization format) is the RLP-serializable version of Rules.
 // It contains all network configuration parameters that need to be persisted
-// or transmitted over the network. The Upgrades field is excluded from RLP encoding.
+// or transmitted over the network. The Upgrades field is excluded from its RLP encoding,
+// Rules encodes it separately as a bitmap, see marshal.go.
 type RulesRLP struct {
 	Name      string // Network name identifier (e.g., "main", "test", "fake")
 	NetworkID uint64 // Chain ID for transaction signing and network identification
@@ -75,7 +80,7 @@ type RulesRLP struct {
 	// Economy options - Gas pricing and economic parameters
 	Economy EconomyRules
 
-	// Upgrades - Protocol upgrade flags (not RLP-encoded)
+	// Upgrades - Protocol upgrade flags (encoded by Rules, see marshal.go)
 	Upgrades Upgrades `rlp:"-"`
 }
 
@@ -142,8 +147,32 @@ type GasRulesRLPV1 struct {
 	MisbehaviourProofGas uint64
 }
 
-// GasRules is the current version of gas rules (aliased to V1)
-type GasRules GasRulesRLPV1
+// GasRulesRLPV2 defines gas costs of version 2. It extends version 1 with the fields
+// which take effect only once Upgrades.GasV2 is enabled.
+type GasRulesRLPV2 struct {
+	MaxEventGas          uint64
+	EventGas             uint64
+	ParentGas            uint64
+	ExtraDataGas         uint64
+	BlockVotesBaseGas    uint64
+	BlockVoteGas         uint64
+	EpochVoteGas         uint64
+	MisbehaviourProofGas uint64
+
+	// Post-GasV2 fields
+
+	// TxDataGas is the gas cost per byte of transactions data in an event
+	// Large transactions cost more gas power to include, regardless of their execution gas
+	TxDataGas uint64
+
+	// VoteAggregationDiscount is the discount (in percents) of BlockVoteGas and EpochVoteGas
+	// for votes aggregated into a single signature
+	VoteAggregationDiscount uint64
+}
+
+// GasRules is the current version of gas rules (aliased to the latest version, V2).
+// See gas_rules.go for how the versions are encoded.
+type GasRules GasRulesRLPV2
 
 // EpochsRules defines the rules for epoch management.
 // Epochs are time-based periods that group events together for finalization.
@@ -155,6 +184,11 @@ type EpochsRules struct {
 	// MaxEpochDuration is the maximum time an epoch can last
 	// Epochs are finalized when either gas limit or time limit is reached
 	MaxEpochDuration inter.Timestamp
+
+	// MinEpochDuration enables the gas-adaptive epochs mode if non-zero.
+	// In this mode the epoch time limit varies between MinEpochDuration (under full load)
+	// and MaxEpochDuration (when idle), see EpochDurationLimit
+	MinEpochDuration inter.Timestamp `rlp:"optional"`
 }
 
 // DagRules defines the rules for the Lachesis DAG (Directed Acyclic Graph).
@@ -172,6 +206,22 @@ type DagRules struct {
 	// MaxExtraData is the maximum size (in bytes) of extra data in an event
 	// Extra data beyond this limit is rejected
 	MaxExtraData uint32
+
+	// MaxTxsPerEvent is the maximum number of transactions in an event
+	// Very large events propagate slowly, so the payload is split across more events.
+	// Validate rejects zero; the rules stored by the releases before the limit decode
+	// with zero, which isn't enforced as a limit
+	MaxTxsPerEvent uint32 `rlp:"optional"`
+}
+
+// EventLimits returns the limits of the event structure under the rules.
+func (r DagRules) EventLimits() inter.EventLimits {
+	return inter.EventLimits{
+		MaxParents:   r.MaxParents,
+		MaxExtraData: r.MaxExtraData,
+		MaxTxs:       r.MaxTxsPerEvent,
+		MaxSize:      inter.ProtocolMaxMsgSize,
+	}
 }
 
 // BlocksMissed tracks information about blocks missed by a validator.
@@ -202,6 +252,11 @@ type EconomyRules struct {
 	// LongGasPower is the gas power allocation for long-term operations
 	// Used for sustained validator operations over longer periods
 	LongGasPower GasPowerRules
+
+	// OfflinePeriod is how long a validator may stay silent before it's considered offline.
+	// A validator is offline only if it also missed more than BlockMissedSlack blocks.
+	// Zero disables the offline detection.
+	OfflinePeriod inter.Timestamp `rlp:"optional"`
 }
 
 // BlocksRules contains rules for block production and validation.
@@ -214,6 +269,22 @@ type BlocksRules struct {
 	// MaxEmptyBlockSkipPeriod is the maximum time validators can skip empty blocks
 	// Validators must produce blocks even if empty, unless within this period
 	MaxEmptyBlockSkipPeriod inter.Timestamp
+
+	// MaxBlobGasPerBlock is the limit of blob gas (EIP-4844) per block
+	// It takes effect only once Upgrades.Cancun is enabled
+	MaxBlobGasPerBlock uint64 `rlp:"optional"`
+
+	// TargetBlobGasPerBlock is the blob gas per block which keeps the blob gas price stable
+	// Blocks above the target raise the price of the following blocks, blocks below it lower it
+	TargetBlobGasPerBlock uint64 `rlp:"optional"`
+
+	// MaxBlockTxs is the limit of transactions per block, zero means no limit
+	// The transactions of the confirmed events above the limit are moved into the next block
+	MaxBlockTxs uint32 `rlp:"optional"`
+
+	// MaxBlockSize is the limit of the total size of the block transactions, zero means no limit
+	// The transactions above the limit are moved into the next block, like above MaxBlockTxs
+	MaxBlockSize uint64 `rlp:"optional"`
 }
 
 // Upgrades tracks which protocol upgrades are enabled for a network.
@@ -222,6 +293,20 @@ type Upgrades struct {
 	Berlin bool // Berlin upgrade (EIP-2565, EIP-2929, EIP-2718, EIP-2930)
 	London bool // London upgrade (EIP-1559, EIP-3198, EIP-3529, EIP-3541)
 	Llr    bool // LLR (Low Latency Records) upgrade - Opera-specific feature
+	// Paused is the emergency brake, switched on and off by governance.
+	// While it's set, validators keep emitting events (so they aren't considered offline),
+	// but the events carry no transactions, i.e. the chain produces only empty blocks.
+	Paused bool
+	// GasV2 enables the fields of GasRulesRLPV2
+	GasV2 bool
+	// Cancun enables the blob gas accounting (EIP-4844): blob transactions are priced
+	// by the blob gas price and limited by Blocks.MaxBlobGasPerBlock.
+	// Before it, blob transactions are rejected as a not supported type.
+	Cancun bool
+	// MillisecondTime exposes the block time in milliseconds to the contracts via the
+	// blocktime precompile, for app-chains which need a finer time than the TIMESTAMP
+	// opcode. TIMESTAMP stays in seconds either way, for the Ethereum compatibility.
+	MillisecondTime bool
 }
 
 // UpgradeHeight specifies at which block height an upgrade becomes active.
@@ -235,23 +320,45 @@ type UpgradeHeight struct {
 // This is used for transaction signing and EVM execution compatibility.
 //
 // Parameters:
-//   - hh: Array of upgrade heights, ordered by block height
+//   - hh: the upgrade heights of the network, in the order of the heights.
+//     If empty, the upgrades of the rules are considered enabled since the genesis.
 //
 // Returns:
 //   - *ethparams.ChainConfig: Ethereum-compatible chain configuration
 //
-// The function processes upgrade heights sequentially and sets BerlinBlock
-// and LondonBlock based on the first occurrence of each upgrade flag.
+// Every fork of the config is set explicitly, so the EVM behaves exactly as the
+// rules declare, independently of the geth defaults:
+//   - the pre-Berlin forks (Homestead ... Muir Glacier) are enabled since the genesis,
+//     as Opera started with them;
+//   - Berlin and London are enabled at the height where the upgrade flag first appears,
+//     and disabled if a later height clears the flag. Each flag is mapped on its own,
+//     London without Berlin is rejected by Rules.Validate instead;
+//   - the DAO fork, Catalyst and the PoW/PoA engines are never enabled.
 func (r Rules) EvmChainConfig(hh []UpgradeHeight) *ethparams.ChainConfig {
-	// Start with all Ethereum protocol changes as base
-	cfg := *ethparams.AllEthashProtocolChanges
-
-	// Set the chain ID from network ID
-	cfg.ChainID = new(big.Int).SetUint64(r.NetworkID)
-
-	// Initialize upgrade blocks as nil (not activated)
-	cfg.BerlinBlock = nil
-	cfg.LondonBlock = nil
+	if len(hh) == 0 {
+		hh = []UpgradeHeight{{Upgrades: r.Upgrades, Height: 0}}
+	}
+	genesis := func() *big.Int {
+		return new(big.Int)
+	}
+	cfg := ethparams.ChainConfig{
+		ChainID: new(big.Int).SetUint64(r.NetworkID),
+
+		HomesteadBlock:      genesis(),
+		DAOForkBlock:        nil,
+		DAOForkSupport:      false,
+		EIP150Block:         genesis(),
+		EIP155Block:         genesis(),
+		EIP158Block:         genesis(),
+		ByzantiumBlock:      genesis(),
+		ConstantinopleBlock: genesis(),
+		PetersburgBlock:     genesis(),
+		IstanbulBlock:       genesis(),
+		MuirGlacierBlock:    genesis(),
+		BerlinBlock:         nil,
+		LondonBlock:         nil,
+		CatalystBlock:       nil,
+	}
 
 	// Process each upgrade height in order
 	for i, h := range hh {
@@ -328,7 +435,7 @@ func FakeNetRules() Rules {
 	return Rules{
 		Name:      "fake",
 		NetworkID: FakeNetworkID,
-		Dag:       DefaultDagRules(),
+		Dag:       FakeNetDagRules(),    // Generous txs limit
 		Epochs:    FakeNetEpochsRules(), // Accelerated epochs
 		Economy:   FakeEconomyRules(),   // Accelerated gas power
 		Blocks: BlocksRules{
@@ -352,6 +459,8 @@ func DefaultEconomyRules() EconomyRules {
 		MinGasPrice:      big.NewInt(1e9), // 1 Gwei minimum gas price
 		ShortGasPower:    DefaultShortGasPowerRules(),
 		LongGasPower:     DefaulLongGasPowerRules(),
+		// OfflinePeriod is left zero: the existing networks enable the offline detection
+		// with a rules update, as it changes the rules encoding and hash
 	}
 }
 
@@ -362,6 +471,7 @@ func FakeEconomyRules() EconomyRules {
 	// Override with accelerated gas power rules (1000x faster)
 	cfg.ShortGasPower = FakeShortGasPowerRules()
 	cfg.LongGasPower = FakeLongGasPowerRules()
+	cfg.OfflinePeriod = inter.Timestamp(10 * time.Minute) // Detect offline validators within an epoch
 	return cfg
 }
 
@@ -372,9 +482,18 @@ func DefaultDagRules() DagRules {
 		MaxParents:     10,  // Events can reference up to 10 parent events
 		MaxFreeParents: 3,   // First 3 parents are free, rest cost gas
 		MaxExtraData:   128, // Maximum 128 bytes of extra data per event
+		MaxTxsPerEvent: 256, // Maximum 256 transactions per event
 	}
 }
 
+// FakeNetDagRules returns the DAG configuration for fake networks.
+// The txs limit is generous, so load tests aren't throttled by it.
+func FakeNetDagRules() DagRules {
+	cfg := DefaultDagRules()
+	cfg.MaxTxsPerEvent = 10000
+	return cfg
+}
+
 // DefaultEpochsRules returns the mainnet epoch configuration.
 // Epochs finalize when either gas limit or time limit is reached.
 func DefaultEpochsRules() EpochsRules {
@@ -1,12 +1,14 @@
 package opera
 
 import (
+	"bytes"
 	"encoding/json"
 	"math/big"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/rony4d/go-opera-asset/inter"
 	"github.com/rony4d/go-opera-asset/opera/contracts/evmwriter"
 )
@@ -47,6 +49,141 @@ func TestUpgradeBits(t *testing.T) {
 	if llrBit != 1<<2 {
 		t.Errorf("llrBit = %d, want %d", llrBit, 1<<2)
 	}
+	if pausedBit != 1<<3 {
+		t.Errorf("pausedBit = %d, want %d", pausedBit, 1<<3)
+	}
+	if gasV2Bit != 1<<4 {
+		t.Errorf("gasV2Bit = %d, want %d", gasV2Bit, 1<<4)
+	}
+	if cancunBit != 1<<5 {
+		t.Errorf("cancunBit = %d, want %d", cancunBit, 1<<5)
+	}
+	if msTimeBit != 1<<6 {
+		t.Errorf("msTimeBit = %d, want %d", msTimeBit, 1<<6)
+	}
+}
+
+// TestRules_RLPWithoutUpgrades verifies that rules without upgrades keep the baseline encoding,
+// so the hashes of existing networks don't change.
+func TestRules_RLPWithoutUpgrades(t *testing.T) {
+	rules := MainNetRules()
+	rules.Upgrades = Upgrades{}
+
+	got, err := rlp.EncodeToBytes(rules)
+	if err != nil {
+		t.Fatalf("EncodeToBytes failed: %v", err)
+	}
+	want, err := rlp.EncodeToBytes(RulesRLP(rules))
+	if err != nil {
+		t.Fatalf("EncodeToBytes failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("encoding = %x, want baseline %x", got, want)
+	}
+
+	var decoded Rules
+	if err := rlp.DecodeBytes(got, &decoded); err != nil {
+		t.Fatalf("DecodeBytes failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, rules) {
+		t.Errorf("decoded = %+v, want %+v", decoded, rules)
+	}
+}
+
+// TestRules_RLPUpgrades verifies that every upgrade flag survives the RLP round-trip
+// and changes the encoding of the rules.
+func TestRules_RLPUpgrades(t *testing.T) {
+	base := FakeNetRules()
+	base.Upgrades = Upgrades{}
+	baseEnc, err := rlp.EncodeToBytes(base)
+	if err != nil {
+		t.Fatalf("EncodeToBytes failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		upgrades Upgrades
+		bitmap   uint64
+	}{
+		{"Berlin", Upgrades{Berlin: true}, berlinBit},
+		{"London", Upgrades{London: true}, londonBit},
+		{"Llr", Upgrades{Llr: true}, llrBit},
+		{"Paused", Upgrades{Paused: true}, pausedBit},
+		{"GasV2", Upgrades{GasV2: true}, gasV2Bit},
+		{"Cancun", Upgrades{Cancun: true}, cancunBit},
+		{"MillisecondTime", Upgrades{MillisecondTime: true}, msTimeBit},
+		{"All", Upgrades{Berlin: true, London: true, Llr: true, Paused: true, GasV2: true, Cancun: true, MillisecondTime: true},
+			berlinBit | londonBit | llrBit | pausedBit | gasV2Bit | cancunBit | msTimeBit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bitmap, err := rlp.EncodeToBytes(tt.upgrades)
+			if err != nil {
+				t.Fatalf("EncodeToBytes failed: %v", err)
+			}
+			wantBitmap, _ := rlp.EncodeToBytes(upgradesRLP{tt.bitmap})
+			if !bytes.Equal(bitmap, wantBitmap) {
+				t.Errorf("bitmap = %x, want %x", bitmap, wantBitmap)
+			}
+
+			rules := base.Copy()
+			rules.Upgrades = tt.upgrades
+			enc, err := rlp.EncodeToBytes(rules)
+			if err != nil {
+				t.Fatalf("EncodeToBytes failed: %v", err)
+			}
+			if bytes.Equal(enc, baseEnc) {
+				t.Error("upgrade didn't change the encoding")
+			}
+			var decoded Rules
+			if err := rlp.DecodeBytes(enc, &decoded); err != nil {
+				t.Fatalf("DecodeBytes failed: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, rules) {
+				t.Errorf("decoded = %+v, want %+v", decoded, rules)
+			}
+		})
+	}
+}
+
+// TestRules_RLPNested verifies that rules with upgrades round-trip inside another structure.
+func TestRules_RLPNested(t *testing.T) {
+	type wrapper struct {
+		Before uint64
+		Rules  Rules
+		After  string
+	}
+	rules := FakeNetRules()
+	rules.Upgrades.Paused = true
+	in := wrapper{Before: 7, Rules: rules, After: "tail"}
+
+	enc, err := rlp.EncodeToBytes(&in)
+	if err != nil {
+		t.Fatalf("EncodeToBytes failed: %v", err)
+	}
+	var out wrapper
+	if err := rlp.DecodeBytes(enc, &out); err != nil {
+		t.Fatalf("DecodeBytes failed: %v", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("decoded = %+v, want %+v", out, in)
+	}
+}
+
+// TestRules_RLPUnknownType verifies that an unknown type prefix is rejected.
+func TestRules_RLPUnknownType(t *testing.T) {
+	rules := FakeNetRules()
+	rules.Upgrades = Upgrades{Berlin: true}
+	enc, err := rlp.EncodeToBytes(rules)
+	if err != nil {
+		t.Fatalf("EncodeToBytes failed: %v", err)
+	}
+	enc[0] = rulesTypeUpgrades + 1
+
+	var decoded Rules
+	if err := rlp.DecodeBytes(enc, &decoded); err != ErrUnknownRulesType {
+		t.Errorf("DecodeBytes error = %v, want %v", err, ErrUnknownRulesType)
+	}
 }
 
 // TestDefaultVMConfig verifies that the default VM config includes the EVM writer precompile.
@@ -171,6 +308,20 @@ func TestDefaultDagRules(t *testing.T) {
 	if rules.MaxExtraData != 128 {
 		t.Errorf("MaxExtraData = %d, want %d", rules.MaxExtraData, 128)
 	}
+	if rules.MaxTxsPerEvent != 256 {
+		t.Errorf("MaxTxsPerEvent = %d, want %d", rules.MaxTxsPerEvent, 256)
+	}
+	if FakeNetDagRules().MaxTxsPerEvent <= rules.MaxTxsPerEvent {
+		t.Error("FakeNet should have a more generous MaxTxsPerEvent than MainNet")
+	}
+
+	limits := rules.EventLimits()
+	if limits.MaxParents != rules.MaxParents || limits.MaxExtraData != rules.MaxExtraData || limits.MaxTxs != rules.MaxTxsPerEvent {
+		t.Errorf("EventLimits() = %+v, doesn't match the rules", limits)
+	}
+	if limits.MaxSize != inter.ProtocolMaxMsgSize {
+		t.Errorf("EventLimits().MaxSize = %d, want %d", limits.MaxSize, inter.ProtocolMaxMsgSize)
+	}
 }
 
 // TestDefaultEpochsRules verifies the mainnet epoch configuration.
@@ -363,6 +514,11 @@ func TestDefaultEconomyRules(t *testing.T) {
 	if rules.LongGasPower.AllocPerSec == 0 {
 		t.Error("LongGasPower should be set")
 	}
+
+	// Verify offline detection is left to a rules update, so the mainnet rules hash is unchanged
+	if rules.OfflinePeriod != 0 {
+		t.Errorf("OfflinePeriod = %v, want 0", rules.OfflinePeriod)
+	}
 }
 
 // TestFakeEconomyRules verifies that fake network economy uses accelerated gas power.
@@ -400,6 +556,11 @@ func TestFakeEconomyRules(t *testing.T) {
 		t.Errorf("LongGasPower.AllocPerSec = %d, want %d",
 			rules.LongGasPower.AllocPerSec, expectedLongAlloc)
 	}
+
+	// OfflinePeriod should be shorter than an epoch
+	if rules.OfflinePeriod == 0 || rules.OfflinePeriod >= FakeNetEpochsRules().MaxEpochDuration+1 {
+		t.Errorf("OfflinePeriod = %v, want non-zero and within an epoch", rules.OfflinePeriod)
+	}
 }
 
 // TestRulesCopy verifies that Copy() creates a deep copy, especially for pointer types.
@@ -471,6 +632,102 @@ func TestEvmChainConfig(t *testing.T) {
 	if cfg.LondonBlock != nil {
 		t.Error("LondonBlock should be nil when no upgrades specified")
 	}
+
+	// Pre-Berlin forks are always enabled since the genesis
+	for name, block := range map[string]*big.Int{
+		"Homestead":      cfg.HomesteadBlock,
+		"EIP150":         cfg.EIP150Block,
+		"EIP155":         cfg.EIP155Block,
+		"EIP158":         cfg.EIP158Block,
+		"Byzantium":      cfg.ByzantiumBlock,
+		"Constantinople": cfg.ConstantinopleBlock,
+		"Petersburg":     cfg.PetersburgBlock,
+		"Istanbul":       cfg.IstanbulBlock,
+		"MuirGlacier":    cfg.MuirGlacierBlock,
+	} {
+		if block == nil || block.Sign() != 0 {
+			t.Errorf("%sBlock = %v, want 0", name, block)
+		}
+	}
+
+	// Forks which Opera never had
+	if cfg.DAOForkBlock != nil || cfg.DAOForkSupport {
+		t.Error("DAO fork should be disabled")
+	}
+	if cfg.CatalystBlock != nil {
+		t.Error("CatalystBlock should be nil")
+	}
+	if cfg.Ethash != nil || cfg.Clique != nil {
+		t.Error("consensus engine configs should be nil")
+	}
+}
+
+// TestEvmChainConfig_UpgradeCombinations verifies that the EVM rules at a block match
+// the declared upgrades exactly, for every combination of the upgrades.
+func TestEvmChainConfig_UpgradeCombinations(t *testing.T) {
+	tests := []struct {
+		name       string
+		upgrades   Upgrades
+		wantBerlin bool
+		wantLondon bool
+	}{
+		{"none", Upgrades{}, false, false},
+		{"Berlin", Upgrades{Berlin: true}, true, false},
+		{"Berlin+London", Upgrades{Berlin: true, London: true}, true, true},
+		{"Berlin+London+Llr", Upgrades{Berlin: true, London: true, Llr: true}, true, true},
+		{"Llr", Upgrades{Llr: true}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := FakeNetRules()
+			rules.Upgrades = tt.upgrades
+
+			// without heights, the declared upgrades apply since the genesis
+			for _, hh := range [][]UpgradeHeight{nil, {{Upgrades: tt.upgrades, Height: 0}}} {
+				evm := rules.EvmChainConfig(hh).Rules(big.NewInt(100))
+				if !evm.IsHomestead || !evm.IsEIP150 || !evm.IsEIP155 || !evm.IsEIP158 ||
+					!evm.IsByzantium || !evm.IsConstantinople || !evm.IsPetersburg || !evm.IsIstanbul {
+					t.Errorf("pre-Berlin forks should be enabled: %+v", evm)
+				}
+				if evm.IsBerlin != tt.wantBerlin {
+					t.Errorf("IsBerlin = %v, want %v", evm.IsBerlin, tt.wantBerlin)
+				}
+				if evm.IsLondon != tt.wantLondon {
+					t.Errorf("IsLondon = %v, want %v", evm.IsLondon, tt.wantLondon)
+				}
+			}
+		})
+	}
+}
+
+// TestEvmChainConfig_ForkTransitions verifies the EVM rules before and after the upgrade heights.
+func TestEvmChainConfig_ForkTransitions(t *testing.T) {
+	rules := FakeNetRules()
+	cfg := rules.EvmChainConfig([]UpgradeHeight{
+		{Upgrades: Upgrades{}, Height: 1},
+		{Upgrades: Upgrades{Berlin: true}, Height: 100},
+		{Upgrades: Upgrades{Berlin: true, London: true}, Height: 200},
+	})
+
+	tests := []struct {
+		block      int64
+		wantBerlin bool
+		wantLondon bool
+	}{
+		{0, false, false},
+		{99, false, false},
+		{100, true, false},
+		{199, true, false},
+		{200, true, true},
+	}
+	for _, tt := range tests {
+		evm := cfg.Rules(big.NewInt(tt.block))
+		if evm.IsBerlin != tt.wantBerlin || evm.IsLondon != tt.wantLondon {
+			t.Errorf("block %d: Berlin=%v London=%v, want Berlin=%v London=%v",
+				tt.block, evm.IsBerlin, evm.IsLondon, tt.wantBerlin, tt.wantLondon)
+		}
+	}
 }
 
 // TestEvmChainConfig_WithUpgrades verifies EvmChainConfig with upgrade heights.
@@ -643,7 +900,7 @@ func TestRulesRLPStructure(t *testing.T) {
 	}
 }
 
-// TestGasRulesTypeAlias verifies that GasRules is correctly aliased to GasRulesRLPV1.
+// TestGasRulesTypeAlias verifies that GasRules holds all the fields of GasRulesRLPV1.
 func TestGasRulesTypeAlias(t *testing.T) {
 	// GasRules should have all fields from GasRulesRLPV1
 	rules := DefaultGasRules()
@@ -0,0 +1,149 @@
+package opera
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+// templates.go provides vetted rules for Opera-based app-chains.
+//
+// Overview:
+//   FakeNetRules are tuned for tests (e.g. 1000x gas power), so they must not be copied into
+//   a production genesis. The templates start from the mainnet parameters, enable all the
+//   upgrades a new chain may start with, and tune them for one of the typical workloads:
+//     - TemplateHighThroughput: large blocks and events, gas-adaptive epochs;
+//     - TemplateLowLatency: small events, which propagate faster, frequent blocks and
+//       the millisecond block time;
+//     - TemplateStorageCheap: low gas price and the blob data (EIP-4844) enabled.
+//   The chain-specific values are set by TemplateKnobs. The resulting rules are validated,
+//   so a knob can't turn a template into rules which can't work.
+
+// Names of the rules templates.
+const (
+	TemplateHighThroughput = "high-throughput"
+	TemplateLowLatency     = "low-latency"
+	TemplateStorageCheap   = "storage-cheap"
+)
+
+// ErrUnknownTemplate is returned by TemplateRules for an unknown template name.
+var ErrUnknownTemplate = errors.New("unknown rules template")
+
+// templateBlobGas is the blob gas of a single blob (EIP-4844).
+const templateBlobGas = 1 << 17
+
+// TemplateKnobs are the parameters of a chain built from a template.
+// The zero values keep the template defaults, except NetworkID, which is required.
+type TemplateKnobs struct {
+	Name             string          // rules name, the template name if empty
+	NetworkID        uint64          // chain ID of the app-chain
+	MaxBlockGas      uint64          // Blocks.MaxBlockGas
+	MaxBlockTxs      uint32          // Blocks.MaxBlockTxs
+	MaxBlockSize     uint64          // Blocks.MaxBlockSize
+	MinGasPrice      *big.Int        // Economy.MinGasPrice
+	MaxEpochDuration inter.Timestamp // Epochs.MaxEpochDuration
+}
+
+// RulesTemplates returns the names of the known templates.
+func RulesTemplates() []string {
+	return []string{TemplateHighThroughput, TemplateLowLatency, TemplateStorageCheap}
+}
+
+// TemplateRules returns the rules of the template with the knobs applied.
+//
+// Parameters:
+//   - name: One of the template names (see RulesTemplates)
+//   - knobs: The chain-specific parameters
+//
+// Returns:
+//   - Rules: The validated rules
+//   - error: ErrUnknownTemplate, or the validation error of the knobs
+func TemplateRules(name string, knobs TemplateKnobs) (Rules, error) {
+	var rules Rules
+	switch name {
+	case TemplateHighThroughput:
+		rules = highThroughputRules()
+	case TemplateLowLatency:
+		rules = lowLatencyRules()
+	case TemplateStorageCheap:
+		rules = storageCheapRules()
+	default:
+		return Rules{}, fmt.Errorf("%w: %q (valid: %v)", ErrUnknownTemplate, name, RulesTemplates())
+	}
+
+	rules.Name = name
+	if knobs.Name != "" {
+		rules.Name = knobs.Name
+	}
+	rules.NetworkID = knobs.NetworkID
+	if knobs.MaxBlockGas != 0 {
+		rules.Blocks.MaxBlockGas = knobs.MaxBlockGas
+	}
+	if knobs.MaxBlockTxs != 0 {
+		rules.Blocks.MaxBlockTxs = knobs.MaxBlockTxs
+	}
+	if knobs.MaxBlockSize != 0 {
+		rules.Blocks.MaxBlockSize = knobs.MaxBlockSize
+	}
+	if knobs.MinGasPrice != nil {
+		rules.Economy.MinGasPrice = new(big.Int).Set(knobs.MinGasPrice)
+	}
+	if knobs.MaxEpochDuration != 0 {
+		rules.Epochs.MaxEpochDuration = knobs.MaxEpochDuration
+	}
+
+	if err := rules.Validate(); err != nil {
+		return Rules{}, fmt.Errorf("%s template: %w", name, err)
+	}
+	return rules, nil
+}
+
+// templateBaseRules returns the mainnet parameters with all the upgrades of a new chain enabled.
+func templateBaseRules() Rules {
+	rules := MainNetRules()
+	rules.Upgrades = Upgrades{
+		Berlin: true,
+		London: true,
+		Llr:    true,
+	}
+	return rules
+}
+
+func highThroughputRules() Rules {
+	rules := templateBaseRules()
+	rules.Dag.MaxTxsPerEvent = 1024
+	rules.Blocks.MaxBlockGas = 100000000                              // 100M gas per block
+	rules.Epochs.MaxEpochGas *= 5                                     // 7.5B gas per epoch
+	rules.Epochs.MinEpochDuration = inter.Timestamp(30 * time.Minute) // busy epochs are sealed faster
+	rules.Economy.ShortGasPower.AllocPerSec *= 5                      // 5x of the mainnet throughput
+	rules.Economy.LongGasPower.AllocPerSec *= 5
+	rules.Blocks.MaxBlockTxs = 20000 // keeps the blocks digestible by the RPC consumers
+	rules.Blocks.MaxBlockSize = 16 * 1024 * 1024
+	return rules
+}
+
+func lowLatencyRules() Rules {
+	rules := templateBaseRules()
+	rules.Dag.MaxTxsPerEvent = 64                                           // small events propagate faster
+	rules.Blocks.MaxEmptyBlockSkipPeriod = inter.Timestamp(1 * time.Second) // blocks keep coming when idle
+	rules.Epochs.MaxEpochDuration = inter.Timestamp(1 * time.Hour)
+	rules.Economy.ShortGasPower.StartupAllocPeriod = inter.Timestamp(1 * time.Second)
+	rules.Upgrades.MillisecondTime = true
+	rules.Blocks.MaxBlockTxs = 2000 // small blocks are executed faster
+	rules.Blocks.MaxBlockSize = 2 * 1024 * 1024
+	return rules
+}
+
+func storageCheapRules() Rules {
+	rules := templateBaseRules()
+	rules.Blocks.MaxBlockGas = 40000000 // fits the deployment of large contracts
+	rules.Blocks.MaxBlobGasPerBlock = 6 * templateBlobGas
+	rules.Blocks.TargetBlobGasPerBlock = 3 * templateBlobGas
+	rules.Economy.MinGasPrice = big.NewInt(1e8) // 0.1 Gwei
+	rules.Upgrades.Cancun = true
+	rules.Blocks.MaxBlockSize = 32 * 1024 * 1024 // fits the deployments of large contracts
+	return rules
+}
@@ -0,0 +1,62 @@
+package opera
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+// TestTemplateRules verifies that all the templates produce valid rules and apply the knobs.
+func TestTemplateRules(t *testing.T) {
+	for _, name := range RulesTemplates() {
+		rules, err := TemplateRules(name, TemplateKnobs{NetworkID: 0x1234})
+		if err != nil {
+			t.Fatalf("%s template: %v", name, err)
+		}
+		if rules.Name != name || rules.NetworkID != 0x1234 {
+			t.Fatalf("%s template: unexpected name %q or network ID %d", name, rules.Name, rules.NetworkID)
+		}
+		if !rules.Upgrades.London || !rules.Upgrades.Llr {
+			t.Fatalf("%s template: upgrades aren't enabled", name)
+		}
+	}
+
+	knobs := TemplateKnobs{
+		Name:             "appchain",
+		NetworkID:        0x1234,
+		MaxBlockGas:      30000000,
+		MaxBlockTxs:      500,
+		MinGasPrice:      big.NewInt(5),
+		MaxEpochDuration: inter.Timestamp(2 * time.Hour),
+	}
+	rules, err := TemplateRules(TemplateHighThroughput, knobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules.Name != "appchain" || rules.Blocks.MaxBlockGas != 30000000 || rules.Economy.MinGasPrice.Int64() != 5 ||
+		rules.Epochs.MaxEpochDuration != inter.Timestamp(2*time.Hour) || rules.Blocks.MaxBlockTxs != 500 {
+		t.Fatalf("knobs aren't applied: %s", rules)
+	}
+	knobs.MinGasPrice.SetInt64(6)
+	if rules.Economy.MinGasPrice.Int64() != 5 {
+		t.Fatal("rules share MinGasPrice with the knobs")
+	}
+}
+
+// TestTemplateRules_Invalid verifies that unknown templates and broken knobs are rejected.
+func TestTemplateRules_Invalid(t *testing.T) {
+	if _, err := TemplateRules("turbo", TemplateKnobs{NetworkID: 1}); !errors.Is(err, ErrUnknownTemplate) {
+		t.Fatalf("unknown template isn't rejected: %v", err)
+	}
+	if _, err := TemplateRules(TemplateLowLatency, TemplateKnobs{}); err == nil {
+		t.Fatal("zero network ID isn't rejected")
+	}
+	// the high-throughput epochs may not be shorter than its MinEpochDuration
+	knobs := TemplateKnobs{NetworkID: 1, MaxEpochDuration: inter.Timestamp(time.Minute)}
+	if _, err := TemplateRules(TemplateHighThroughput, knobs); err == nil {
+		t.Fatal("inconsistent epoch durations aren't rejected")
+	}
+}
@@ -0,0 +1,118 @@
+package opera
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by Rules.Validate.
+var (
+	ErrNoMinGasPrice = errors.New("MinGasPrice isn't set")
+)
+
+// MinMaxBlockSize is the lowest allowed Blocks.MaxBlockSize, the size of the largest
+// transaction accepted by the txpool, so a block always fits a single transaction.
+const MinMaxBlockSize = 4 * 32 * 1024
+
+// Validate performs static sanity checks of the rules.
+// It doesn't know anything about the chain state, so it only rejects rules
+// which can't possibly work (zero limits, inconsistent bounds, etc.).
+//
+// Returns:
+//   - error: The first found problem, nil if the rules look sane
+func (r Rules) Validate() error {
+	if r.NetworkID == 0 {
+		return errors.New("NetworkID must be non-zero")
+	}
+
+	// DAG
+	if r.Dag.MaxParents < 2 {
+		return fmt.Errorf("Dag.MaxParents=%d is too low, at least 2 parents are required", r.Dag.MaxParents)
+	}
+	if r.Dag.MaxFreeParents > r.Dag.MaxParents {
+		return fmt.Errorf("Dag.MaxFreeParents=%d exceeds Dag.MaxParents=%d", r.Dag.MaxFreeParents, r.Dag.MaxParents)
+	}
+	if r.Dag.MaxTxsPerEvent == 0 {
+		return errors.New("Dag.MaxTxsPerEvent must be non-zero")
+	}
+
+	// Epochs
+	if r.Epochs.MaxEpochGas == 0 {
+		return errors.New("Epochs.MaxEpochGas must be non-zero")
+	}
+	if r.Epochs.MaxEpochDuration == 0 {
+		return errors.New("Epochs.MaxEpochDuration must be non-zero")
+	}
+	if r.Epochs.MinEpochDuration > r.Epochs.MaxEpochDuration {
+		return fmt.Errorf("Epochs.MinEpochDuration=%d exceeds Epochs.MaxEpochDuration=%d", r.Epochs.MinEpochDuration, r.Epochs.MaxEpochDuration)
+	}
+	// the gas of the previous epoch, which drives the time limit, is hashed only by the London epoch state
+	if r.Epochs.MinEpochDuration != 0 && !r.Upgrades.London {
+		return errors.New("Epochs.MinEpochDuration requires the London upgrade")
+	}
+
+	// Blocks
+	if r.Blocks.MaxBlockGas == 0 {
+		return errors.New("Blocks.MaxBlockGas must be non-zero")
+	}
+	if r.Blocks.TargetBlobGasPerBlock > r.Blocks.MaxBlobGasPerBlock {
+		return fmt.Errorf("Blocks.TargetBlobGasPerBlock=%d exceeds Blocks.MaxBlobGasPerBlock=%d", r.Blocks.TargetBlobGasPerBlock, r.Blocks.MaxBlobGasPerBlock)
+	}
+	if r.Blocks.MaxBlockSize != 0 && r.Blocks.MaxBlockSize < MinMaxBlockSize {
+		return fmt.Errorf("Blocks.MaxBlockSize=%d is below %d", r.Blocks.MaxBlockSize, MinMaxBlockSize)
+	}
+
+	// Economy
+	if r.Economy.MinGasPrice == nil {
+		return ErrNoMinGasPrice
+	}
+	if r.Economy.MinGasPrice.Sign() < 0 {
+		return fmt.Errorf("Economy.MinGasPrice=%s is negative", r.Economy.MinGasPrice)
+	}
+	gas := r.Economy.Gas
+	if gas.MaxEventGas < gas.EventGas {
+		return fmt.Errorf("Economy.Gas.MaxEventGas=%d is lower than Economy.Gas.EventGas=%d", gas.MaxEventGas, gas.EventGas)
+	}
+	if gas.MaxEventGas > r.Epochs.MaxEpochGas {
+		return fmt.Errorf("Economy.Gas.MaxEventGas=%d exceeds Epochs.MaxEpochGas=%d", gas.MaxEventGas, r.Epochs.MaxEpochGas)
+	}
+	if err := validateGasPower("ShortGasPower", r.Economy.ShortGasPower, gas); err != nil {
+		return err
+	}
+	if err := validateGasPower("LongGasPower", r.Economy.LongGasPower, gas); err != nil {
+		return err
+	}
+	if gas.Version() > r.Upgrades.GasRulesVersion() {
+		return fmt.Errorf("Economy.Gas requires version %d, but the upgrades enable only version %d", gas.Version(), r.Upgrades.GasRulesVersion())
+	}
+	if gas.VoteAggregationDiscount > 100 {
+		return fmt.Errorf("Economy.Gas.VoteAggregationDiscount=%d exceeds 100%%", gas.VoteAggregationDiscount)
+	}
+
+	// Upgrades
+	if r.Upgrades.London && !r.Upgrades.Berlin {
+		return errors.New("Upgrades.London requires Upgrades.Berlin")
+	}
+	if r.Upgrades.Cancun && !r.Upgrades.London {
+		return errors.New("Upgrades.Cancun requires Upgrades.London")
+	}
+	if r.Upgrades.Cancun && r.Blocks.MaxBlobGasPerBlock == 0 {
+		return errors.New("Upgrades.Cancun requires non-zero Blocks.MaxBlobGasPerBlock")
+	}
+
+	return nil
+}
+
+// validateGasPower checks that a gas power window lets validators emit at least one event.
+func validateGasPower(name string, gp GasPowerRules, gas GasRules) error {
+	if gp.AllocPerSec == 0 {
+		return fmt.Errorf("Economy.%s.AllocPerSec must be non-zero", name)
+	}
+	if gp.MaxAllocPeriod == 0 {
+		return fmt.Errorf("Economy.%s.MaxAllocPeriod must be non-zero", name)
+	}
+	if gp.MinStartupGas < gas.EventGas {
+		return fmt.Errorf("Economy.%s.MinStartupGas=%d is lower than Economy.Gas.EventGas=%d", name, gp.MinStartupGas, gas.EventGas)
+	}
+	return nil
+}
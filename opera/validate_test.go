@@ -0,0 +1,69 @@
+package opera
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestRulesValidate_Presets verifies that all the built-in rules pass validation.
+func TestRulesValidate_Presets(t *testing.T) {
+	for name, rules := range map[string]Rules{
+		"main": MainNetRules(),
+		"test": TestNetRules(),
+		"fake": FakeNetRules(),
+	} {
+		if err := rules.Validate(); err != nil {
+			t.Errorf("%s rules are invalid: %v", name, err)
+		}
+	}
+}
+
+// TestRulesValidate_Broken verifies that obviously broken rules are rejected.
+func TestRulesValidate_Broken(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(r *Rules)
+	}{
+		{"zero network ID", func(r *Rules) { r.NetworkID = 0 }},
+		{"too few parents", func(r *Rules) { r.Dag.MaxParents = 1 }},
+		{"free parents above max", func(r *Rules) { r.Dag.MaxFreeParents = r.Dag.MaxParents + 1 }},
+		{"zero txs per event", func(r *Rules) { r.Dag.MaxTxsPerEvent = 0 }},
+		{"zero epoch gas", func(r *Rules) { r.Epochs.MaxEpochGas = 0 }},
+		{"zero epoch duration", func(r *Rules) { r.Epochs.MaxEpochDuration = 0 }},
+		{"min epoch duration above max", func(r *Rules) { r.Epochs.MinEpochDuration = r.Epochs.MaxEpochDuration + 1 }},
+		{"adaptive epochs without london", func(r *Rules) {
+			r.Upgrades = Upgrades{Berlin: true}
+			r.Epochs.MinEpochDuration = 1
+		}},
+		{"zero block gas", func(r *Rules) { r.Blocks.MaxBlockGas = 0 }},
+		{"nil min gas price", func(r *Rules) { r.Economy.MinGasPrice = nil }},
+		{"negative min gas price", func(r *Rules) { r.Economy.MinGasPrice = big.NewInt(-1) }},
+		{"event gas above max", func(r *Rules) { r.Economy.Gas.EventGas = r.Economy.Gas.MaxEventGas + 1 }},
+		{"zero gas power alloc", func(r *Rules) { r.Economy.ShortGasPower.AllocPerSec = 0 }},
+		{"low startup gas", func(r *Rules) { r.Economy.LongGasPower.MinStartupGas = 0 }},
+		{"london without berlin", func(r *Rules) { r.Upgrades.Berlin = false }},
+		{"blob gas target above max", func(r *Rules) { r.Blocks.TargetBlobGasPerBlock = 1 }},
+		{"block size below a tx", func(r *Rules) { r.Blocks.MaxBlockSize = MinMaxBlockSize - 1 }},
+		{"cancun without london", func(r *Rules) {
+			r.Upgrades.London = false
+			r.Upgrades.Cancun = true
+			r.Blocks.MaxBlobGasPerBlock = 1 << 17
+		}},
+		{"cancun without blob gas limit", func(r *Rules) { r.Upgrades.Cancun = true }},
+		{"gas v2 fields before upgrade", func(r *Rules) { r.Economy.Gas.TxDataGas = 16 }},
+		{"vote discount above 100%", func(r *Rules) {
+			r.Upgrades.GasV2 = true
+			r.Economy.Gas.VoteAggregationDiscount = 101
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := FakeNetRules()
+			tt.mutate(&rules)
+			if err := rules.Validate(); err == nil {
+				t.Errorf("Validate() = nil, want error")
+			}
+		})
+	}
+}
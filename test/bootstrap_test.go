@@ -0,0 +1,257 @@
+package test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rony4d/go-opera-asset/cmd/opera/launcher"
+)
+
+// makeArchive packs the files into a tar.gz archive.
+func makeArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// TestDownloadSnapshot verifies the hash check and the resumption of an interrupted download.
+func TestDownloadSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opera-bootstrap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	archive := makeArchive(t, map[string]string{
+		"chaindata/main":       "main db",
+		"chaindata/epoch-1/db": "epoch db",
+	})
+	sum := sha256.Sum256(archive)
+	hash := hex.EncodeToString(sum[:])
+
+	var ranges []string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "snapshot.tar.gz", time.Time{}, bytes.NewReader(archive))
+	}))
+	defer srv.Close()
+
+	dst := filepath.Join(dir, "snapshot.tar.gz")
+
+	// plain http is rejected
+	if err := launcher.DownloadSnapshot(context.Background(), srv.Client(), strings.Replace(srv.URL, "https", "http", 1), dst, hash); err == nil {
+		t.Fatal("http download isn't rejected")
+	}
+
+	// wrong hash: the partial file is dropped
+	wrong := strings.Repeat("00", sha256.Size)
+	err = launcher.DownloadSnapshot(context.Background(), srv.Client(), srv.URL, dst, wrong)
+	if !errors.Is(err, launcher.ErrSnapshotHashMismatch) {
+		t.Fatalf("expected hash mismatch, got %v", err)
+	}
+	if _, err := os.Stat(dst + ".part"); !os.IsNotExist(err) {
+		t.Fatal("corrupted partial download isn't removed")
+	}
+
+	// interrupted download is resumed
+	if err := ioutil.WriteFile(dst+".part", archive[:len(archive)/2], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ranges = nil
+	if err := launcher.DownloadSnapshot(context.Background(), srv.Client(), srv.URL, dst, "0x"+hash); err != nil {
+		t.Fatal(err)
+	}
+	if len(ranges) != 1 || ranges[0] == "" {
+		t.Fatalf("download isn't resumed, requested ranges: %q", ranges)
+	}
+
+	datadir := filepath.Join(dir, "node")
+	if err := launcher.ExtractSnapshot(dst, datadir, "chaindata"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadFile(filepath.Join(datadir, "chaindata", "epoch-1", "db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "epoch db" {
+		t.Fatalf("unexpected extracted data %q", got)
+	}
+}
+
+// TestExtractSnapshot_pathTraversal verifies that archive entries can't escape the datadir.
+func TestExtractSnapshot_pathTraversal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opera-bootstrap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "evil.tar.gz")
+	if err := ioutil.WriteFile(path, makeArchive(t, map[string]string{"../evil": "x"}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := launcher.ExtractSnapshot(path, filepath.Join(dir, "node"), "chaindata"); err == nil {
+		t.Fatal("path traversal isn't rejected")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "evil")); !os.IsNotExist(err) {
+		t.Fatal("file is written outside of the datadir")
+	}
+}
+
+// TestExtractSnapshot_interrupted verifies that an interrupted unpacking never leaves a partial database.
+func TestExtractSnapshot_interrupted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opera-bootstrap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	datadir := filepath.Join(dir, "node")
+	write := func(path, data string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func(path string) string {
+		t.Helper()
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	// the node was killed while unpacking: nothing is moved into the datadir
+	write(filepath.Join(datadir, "bootstrap.tmp", "chaindata", "half"), "partial")
+	if err := launcher.FinishSnapshot(datadir, "chaindata"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(datadir, "chaindata")); !os.IsNotExist(err) {
+		t.Fatal("partially unpacked database is moved into the datadir")
+	}
+	// and the next unpacking starts over
+	archive := filepath.Join(dir, "snapshot.tar.gz")
+	if err := ioutil.WriteFile(archive, makeArchive(t, map[string]string{"chaindata/main": "main db", "chaindata/epoch-1/db": "epoch db"}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := launcher.ExtractSnapshot(archive, datadir, "chaindata"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(datadir, "chaindata", "half")); !os.IsNotExist(err) {
+		t.Fatal("leftovers of the interrupted unpacking are moved into the datadir")
+	}
+	if _, err := os.Stat(filepath.Join(datadir, "bootstrap.tmp")); !os.IsNotExist(err) {
+		t.Fatal("temporary dir isn't removed")
+	}
+	if got := read(filepath.Join(datadir, "chaindata", "main")); got != "main db" {
+		t.Fatalf("unexpected extracted data %q", got)
+	}
+
+	// the node was killed while moving the unpacked snapshot: the move is completed
+	write(filepath.Join(datadir, "bootstrap.tmp", "chaindata", "main"), "new main db")
+	write(filepath.Join(datadir, "bootstrap.tmp", ".unpacked"), "")
+	if err := launcher.FinishSnapshot(datadir, "chaindata"); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(filepath.Join(datadir, "chaindata", "main")); got != "new main db" {
+		t.Fatalf("interrupted move isn't completed, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(datadir, "chaindata", "epoch-1")); !os.IsNotExist(err) {
+		t.Fatal("stale database entries aren't replaced")
+	}
+	if _, err := os.Stat(filepath.Join(datadir, "bootstrap.tmp")); !os.IsNotExist(err) {
+		t.Fatal("temporary dir isn't removed")
+	}
+}
+
+// TestExtractSnapshot_onlyDatabase verifies that a snapshot can't replace anything but the database,
+// e.g. the keystore or the node key.
+func TestExtractSnapshot_onlyDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opera-bootstrap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	datadir := filepath.Join(dir, "node")
+	keyfile := filepath.Join(datadir, "keystore", "key")
+	if err := os.MkdirAll(filepath.Dir(keyfile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyfile, []byte("key"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	checkKey := func() {
+		t.Helper()
+		if got, err := ioutil.ReadFile(keyfile); err != nil || string(got) != "key" {
+			t.Fatalf("keystore is replaced: %q, %v", got, err)
+		}
+	}
+
+	archive := filepath.Join(dir, "evil.tar.gz")
+	if err := ioutil.WriteFile(archive, makeArchive(t, map[string]string{"chaindata/main": "main db", "keystore/evil": "x"}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := launcher.ExtractSnapshot(archive, datadir, "chaindata"); err == nil {
+		t.Fatal("snapshot entry outside of the database isn't rejected")
+	}
+	checkKey()
+	if _, err := os.Stat(filepath.Join(datadir, "chaindata")); !os.IsNotExist(err) {
+		t.Fatal("rejected snapshot is moved into the datadir")
+	}
+
+	// an unpacked snapshot which has other entries is rejected before anything is replaced
+	for _, name := range []string{"chaindata/main", "keystore/evil", ".unpacked"} {
+		path := filepath.Join(datadir, "bootstrap.tmp", filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := launcher.FinishSnapshot(datadir, "chaindata"); err == nil {
+		t.Fatal("unpacked entry outside of the database isn't rejected")
+	}
+	checkKey()
+	if _, err := os.Stat(filepath.Join(datadir, "chaindata")); !os.IsNotExist(err) {
+		t.Fatal("database is replaced by the rejected snapshot")
+	}
+
+	// the database dir must be a dir of the datadir
+	if err := launcher.FinishSnapshot(datadir, ".."); err == nil {
+		t.Fatal("database dir outside of the datadir isn't rejected")
+	}
+}
@@ -0,0 +1,106 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/ethapi"
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// callBackend executes calls on top of its state.
+type callBackend struct {
+	ethapi.Backend
+	statedb *state.StateDB
+	header  evmcore.EvmHeader
+}
+
+func (b *callBackend) StateAndHeaderByNumberOrHash(ctx context.Context, _ rpc.BlockNumberOrHash) (*state.StateDB, *evmcore.EvmHeader, error) {
+	h := b.header
+	return b.statedb.Copy(), &h, nil
+}
+
+func (b *callBackend) GetEVM(ctx context.Context, msg types.Message, statedb *state.StateDB, header *evmcore.EvmHeader, vmConfig *vm.Config) (*vm.EVM, func() error, error) {
+	cfg := evmcore.NewEvmConfig(opera.FakeNetRules(), nil)
+	evm := vm.NewEVM(evmcore.NewEVMBlockContext(header, nil), core.NewEVMTxContext(msg), statedb, cfg.ChainConfig, *vmConfig)
+	return evm, func() error { return nil }, nil
+}
+
+// returnWord returns the code which returns the 32-byte word pushed by the op.
+func returnWord(op ...byte) hexutil.Bytes {
+	return append(op, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3) // MSTORE(0, word), RETURN(0, 32)
+}
+
+func TestCallOverrides(t *testing.T) {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract := common.Address{0xc}
+	statedb.SetCode(contract, returnWord(0x60, 0x01, 0x54)) // SLOAD(1)
+	statedb.SetState(contract, common.Hash{31: 1}, common.Hash{31: 0xa})
+	b := &callBackend{
+		statedb: statedb,
+		header:  evmcore.EvmHeader{Number: big.NewInt(10), Time: inter.FromUnix(1000), GasLimit: 1e7, BaseFee: big.NewInt(0)},
+	}
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	call := func(to common.Address, overrides *evmcore.StateOverride, blockOverrides *evmcore.BlockOverrides) (*big.Int, error) {
+		res, err := ethapi.DoCall(context.Background(), b, ethapi.TransactionArgs{To: &to}, latest, overrides, blockOverrides, time.Second, 1e7)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetBytes(res.Return()), res.Err
+	}
+
+	if got, err := call(contract, nil, nil); err != nil || got.Int64() != 0xa {
+		t.Fatalf("call without overrides returned %v, %v", got, err)
+	}
+	diff := map[common.Hash]common.Hash{{31: 1}: {31: 0xb}}
+	if got, err := call(contract, &evmcore.StateOverride{contract: {StateDiff: &diff}}, nil); err != nil || got.Int64() != 0xb {
+		t.Fatalf("call with stateDiff returned %v, %v", got, err)
+	}
+	empty := map[common.Hash]common.Hash{}
+	if got, err := call(contract, &evmcore.StateOverride{contract: {State: &empty}}, nil); err != nil || got.Sign() != 0 {
+		t.Fatalf("call with replaced state returned %v, %v", got, err)
+	}
+	if _, err := call(contract, &evmcore.StateOverride{contract: {State: &empty, StateDiff: &diff}}, nil); err == nil {
+		t.Fatal("state and stateDiff conflict isn't rejected")
+	}
+
+	// code and balance overrides of an account which doesn't exist
+	other := common.Address{0xd}
+	code := returnWord(0x30, 0x31) // BALANCE(ADDRESS)
+	balance := (*hexutil.Big)(big.NewInt(12345))
+	if got, err := call(other, &evmcore.StateOverride{other: {Code: &code, Balance: &balance}}, nil); err != nil || got.Int64() != 12345 {
+		t.Fatalf("call with code and balance overrides returned %v, %v", got, err)
+	}
+
+	// block overrides
+	number, tm := (*hexutil.Big)(big.NewInt(777)), hexutil.Uint64(5000)
+	code = returnWord(0x43) // NUMBER
+	if got, err := call(other, &evmcore.StateOverride{other: {Code: &code}}, &evmcore.BlockOverrides{Number: number}); err != nil || got.Int64() != 777 {
+		t.Fatalf("NUMBER with block override returned %v, %v", got, err)
+	}
+	code = returnWord(0x42) // TIMESTAMP
+	if got, err := call(other, &evmcore.StateOverride{other: {Code: &code}}, &evmcore.BlockOverrides{Time: &tm}); err != nil || got.Int64() != 5000 {
+		t.Fatalf("TIMESTAMP with block override returned %v, %v", got, err)
+	}
+
+	// the overrides never reach the state of the backend
+	if b.statedb.GetState(contract, common.Hash{31: 1}) != (common.Hash{31: 0xa}) || len(b.statedb.GetCode(other)) != 0 || b.header.Number.Int64() != 10 {
+		t.Fatal("overrides modified the chain state")
+	}
+}
@@ -0,0 +1,80 @@
+package test
+
+import (
+	"compress/gzip"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/rony4d/go-opera-asset/cmd/opera/launcher"
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+func dagEvent(creator idx.ValidatorID, seq idx.Event, lamport idx.Lamport, parents ...*inter.EventPayload) *inter.EventPayload {
+	e := inter.MutableEventPayload{}
+	e.SetVersion(1)
+	e.SetEpoch(1)
+	e.SetCreator(creator)
+	e.SetSeq(seq)
+	e.SetLamport(lamport)
+	e.SetFrame(1)
+	e.SetPayloadHash(inter.EmptyPayloadHash(1))
+	ids := hash.Events{}
+	for _, p := range parents {
+		ids.Add(p.ID())
+	}
+	e.SetParents(ids)
+	return e.Build()
+}
+
+func writeEventsFile(t *testing.T, path string, events ...*inter.EventPayload) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	defer gz.Close()
+	for _, e := range events {
+		if err := rlp.Encode(gz, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestCheckEventsFile verifies that `opera check dag` accepts a consistent events file
+// and pinpoints a broken one.
+func TestCheckEventsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opera-check-dag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	a1 := dagEvent(1, 1, 1)
+	b1 := dagEvent(2, 1, 1)
+	a2 := dagEvent(1, 2, 2, a1, b1)
+
+	good := filepath.Join(dir, "good.rlp.gz")
+	writeEventsFile(t, good, a1, b1, a2)
+	num, err := launcher.CheckEventsFile(good)
+	if err != nil {
+		t.Fatalf("consistent events are rejected: %v", err)
+	}
+	if num != 3 {
+		t.Fatalf("read %d events, want 3", num)
+	}
+
+	bad := filepath.Join(dir, "bad.rlp.gz")
+	writeEventsFile(t, bad, a1, b1, a2, dagEvent(2, 2, 2, b1, a2))
+	if _, err := launcher.CheckEventsFile(bad); !errors.Is(err, inter.ErrLamportNotAboveParents) {
+		t.Fatalf("expected %v, got %v", inter.ErrLamportNotAboveParents, err)
+	}
+}
@@ -1,6 +1,8 @@
 package test
 
 import (
+	"io/ioutil"
+	"os"
 	"strings"
 
 	"path/filepath"
@@ -10,6 +12,7 @@ import (
 
 	"github.com/rony4d/go-opera-asset/cmd/opera/launcher"
 	"github.com/rony4d/go-opera-asset/flags"
+	"github.com/rony4d/go-opera-asset/opera"
 )
 
 // helper to run makeAllConfigs with a synthetic CLI context.
@@ -49,6 +52,18 @@ func runConfigFromArgs(t *testing.T, args []string) launcher.Config {
 	return got
 }
 
+// statusOf returns the status of the named check, failing the test if it's missing.
+func statusOf(t *testing.T, report launcher.ConfigReport, name string) launcher.CheckStatus {
+	t.Helper()
+	for _, res := range report {
+		if res.Name == name {
+			return res.Status
+		}
+	}
+	t.Fatalf("check %q is missing from the report", name)
+	return launcher.CheckFail
+}
+
 // TestMakeAllConfigs_flagOverrides verifies that every command-line flag we declare
 // in the launcher correctly overrides the corresponding field in the aggregated
 // Config struct. The test iterates through representative flag combinations and
@@ -174,6 +189,28 @@ func TestMakeAllConfigs_flagOverrides(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Network selection",
+			args: []string{"--network", "testnet"},
+			want: func(t *testing.T, cfg launcher.Config) {
+				if cfg.Opera.NetworkName != "testnet" || cfg.Opera.NetworkID != 0xfa2 || cfg.Opera.FakeNet {
+					t.Fatalf("Network not applied: %#v", cfg.Opera)
+				}
+			},
+		},
+		{
+			name: "Background throttles",
+			args: []string{"--preset", "full", "--background.sync.concurrency", "1", "--background.compaction.rate", "8.5"},
+			want: func(t *testing.T, cfg launcher.Config) {
+				// the preset limits apply unless overridden by the flags
+				if cfg.Background.Sync.MaxConcurrency != 1 || cfg.Background.Sync.Rate != 500 {
+					t.Fatalf("Sync throttle = %+v", cfg.Background.Sync)
+				}
+				if cfg.Background.Index.Rate != 200 || cfg.Background.Compaction.Rate != 8.5 {
+					t.Fatalf("Background throttles = %+v", cfg.Background)
+				}
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -186,3 +223,58 @@ func TestMakeAllConfigs_flagOverrides(t *testing.T) {
 	}
 
 }
+
+// TestNetworkDataDir_legacyLayout verifies that a datadir which already holds the database
+// of the selected network keeps being used as is, while fresh datadirs and the other networks
+// get per-network subdirectories.
+func TestNetworkDataDir_legacyLayout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opera-datadir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mainnet := runConfigFromArgs(t, []string{"--datadir", dir, "--network", "mainnet"})
+	fakenet := runConfigFromArgs(t, []string{"--datadir", dir, "--network", "fakenet"})
+	if mainnet.NetworkDataDir() == fakenet.NetworkDataDir() {
+		t.Fatalf("networks share the data directory %q", mainnet.NetworkDataDir())
+	}
+	if mainnet.NetworkDataDir() != filepath.Join(dir, "mainnet") {
+		t.Fatalf("NetworkDataDir = %q", mainnet.NetworkDataDir())
+	}
+
+	// database created before the per-network layout, of an unknown network
+	if err := os.MkdirAll(filepath.Join(dir, mainnet.OperaStore.Path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	mainnet = runConfigFromArgs(t, []string{"--datadir", dir, "--network", "mainnet"})
+	if mainnet.NetworkDataDir() != filepath.Join(dir, "mainnet") {
+		t.Fatalf("database of an unknown network is used: %q", mainnet.NetworkDataDir())
+	}
+
+	// a legacy node without --network records its network on start
+	legacy := launcher.Config{}
+	legacy.Node.DataDir = dir
+	legacy.Opera.NetworkID = opera.MainNetworkID
+	if err := launcher.RecordNetworkID(legacy); err != nil {
+		t.Fatal(err)
+	}
+	mainnet = runConfigFromArgs(t, []string{"--datadir", dir, "--network", "mainnet"})
+	if mainnet.NetworkDataDir() != dir {
+		t.Fatalf("legacy datadir isn't used: %q", mainnet.NetworkDataDir())
+	}
+	if err := launcher.RecordNetworkID(mainnet); err != nil {
+		t.Fatal(err)
+	}
+	// but the other networks don't pick the mainnet data
+	fakenet = runConfigFromArgs(t, []string{"--datadir", dir, "--network", "fakenet"})
+	if fakenet.NetworkDataDir() != filepath.Join(dir, "fakenet") {
+		t.Fatalf("fakenet uses the mainnet datadir: %q", fakenet.NetworkDataDir())
+	}
+
+	// the recorded network is enforced
+	legacy.Opera.NetworkID = opera.TestNetworkID
+	if err := launcher.RecordNetworkID(legacy); err == nil || !strings.Contains(err.Error(), "network 250") {
+		t.Fatalf("network mismatch isn't detected: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/rony4d/go-opera-asset/cmd/opera/launcher"
+	"github.com/rony4d/go-opera-asset/ethapi"
+)
+
+// TestWriteEpochSummaries verifies the formats of `opera epochs export`.
+func TestWriteEpochSummaries(t *testing.T) {
+	summaries := []*ethapi.RPCEpochSummary{
+		{
+			Epoch: 1, Start: 100, End: 160, Duration: 60,
+			FirstBlock: 1, LastBlock: 10, Blocks: 10, Gas: 21000, Events: 3,
+			Validators: []ethapi.RPCValidatorSummary{{ID: 1, Events: 2}, {ID: 2, Events: 1}},
+			RulesHash:  common.HexToHash("0x01"),
+		},
+		{
+			Epoch: 2, Start: 160, End: 200, Duration: 40,
+			FirstBlock: 11, LastBlock: 11, Blocks: 1, Events: 1,
+			Validators: []ethapi.RPCValidatorSummary{{ID: 1, Events: 1}},
+			Cheaters:   []hexutil.Uint{2},
+		},
+	}
+
+	var csv bytes.Buffer
+	if err := launcher.WriteEpochSummaries(&csv, "csv", summaries); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and 2 rows, got %q", csv.String())
+	}
+	if !strings.HasPrefix(lines[0], "epoch,start,end,duration") {
+		t.Fatalf("unexpected header %q", lines[0])
+	}
+	if want := "1,100,160,60,1,10,10,21000,3,2,0," + common.HexToHash("0x01").Hex(); lines[1] != want {
+		t.Fatalf("unexpected row %q, want %q", lines[1], want)
+	}
+	if !strings.HasPrefix(lines[2], "2,160,200,40,11,11,1,0,1,1,1,") {
+		t.Fatalf("unexpected row %q", lines[2])
+	}
+
+	var js bytes.Buffer
+	if err := launcher.WriteEpochSummaries(&js, "json", summaries); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []*ethapi.RPCEpochSummary
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || decoded[1].Cheaters[0] != 2 || len(decoded[0].Validators) != 2 {
+		t.Fatalf("unexpected JSON export %s", js.String())
+	}
+
+	if err := launcher.WriteEpochSummaries(&js, "xml", summaries); err == nil {
+		t.Fatal("unknown format is accepted")
+	}
+}
@@ -0,0 +1,160 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/cmd/opera/launcher"
+	"github.com/rony4d/go-opera-asset/ethapi"
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/inter/drivertype"
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+	"github.com/rony4d/go-opera-asset/inter/validatorpk"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// epochStateBackend serves the states of the current epoch.
+type epochStateBackend struct {
+	ethapi.Backend
+	bs iblockproc.BlockState
+	es iblockproc.EpochState
+}
+
+func (b *epochStateBackend) GetEpochBlockState(ctx context.Context, epoch rpc.BlockNumber) (*iblockproc.BlockState, *iblockproc.EpochState, error) {
+	if epoch != rpc.LatestBlockNumber && idx.Epoch(epoch) != b.es.Epoch {
+		return nil, nil, nil
+	}
+	bs, es := b.bs.Copy(), b.es.Copy()
+	return &bs, &es, nil
+}
+
+// epochStates returns the states of 3 validators with the fake keys 1..3.
+func epochStates(rules opera.Rules) (iblockproc.BlockState, iblockproc.EpochState) {
+	builder := pos.NewBuilder()
+	profiles := make(iblockproc.ValidatorProfiles)
+	for id := idx.ValidatorID(1); id <= 3; id++ {
+		builder.Set(id, pos.Weight(id)*1000)
+		profiles[id] = drivertype.Validator{
+			Weight: big.NewInt(int64(id) * 1000),
+			PubKey: validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: crypto.FromECDSAPub(&evmcore.FakeKey(int(id)).PublicKey)},
+		}
+	}
+	validators := builder.Build()
+	bs := iblockproc.BlockState{
+		LastBlock:             iblockproc.BlockCtx{Idx: 1000, Time: inter.FromUnix(1600000000)},
+		EpochGas:              12345,
+		ValidatorStates:       make([]iblockproc.ValidatorBlockState, validators.Len()),
+		NextValidatorProfiles: profiles.Copy(),
+	}
+	for i := range bs.ValidatorStates {
+		bs.ValidatorStates[i].Originated = big.NewInt(int64(i))
+	}
+	es := iblockproc.EpochState{
+		Epoch:             7,
+		EpochStart:        inter.FromUnix(1599990000),
+		Validators:        validators,
+		ValidatorStates:   make([]iblockproc.ValidatorEpochState, validators.Len()),
+		ValidatorProfiles: profiles,
+		Rules:             rules,
+	}
+	return bs, es
+}
+
+func TestEpochStateExportImport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opera-epochstate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cfg := runConfigFromArgs(t, []string{"--datadir", filepath.Join(dir, "node")})
+
+	b := &epochStateBackend{}
+	b.bs, b.es = epochStates(launcher.NetworkRules(cfg.Opera))
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("debug", ethapi.NewPublicDebugAPI(b)); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+	ctx := context.Background()
+
+	if _, err := launcher.ExportEpochState(ctx, client, 0, 2, evmcore.FakeKey(1)); !errors.Is(err, iblockproc.ErrSnapshotSignature) {
+		t.Fatalf("snapshot is signed by the key of another validator: %v", err)
+	}
+	if _, err := launcher.ExportEpochState(ctx, client, 6, 2, evmcore.FakeKey(2)); err == nil {
+		t.Fatal("unknown epoch is exported")
+	}
+	s, err := launcher.ExportEpochState(ctx, client, 7, 2, evmcore.FakeKey(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exported bytes.Buffer
+	if err := iblockproc.WriteStateSnapshot(&exported, s); err != nil {
+		t.Fatal(err)
+	}
+
+	// the validator mode of another validator
+	other := cfg
+	other.Emitter.Enabled = true
+	other.Emitter.ValidatorID = 3
+	if _, err := launcher.ImportEpochState(other, bytes.NewReader(exported.Bytes())); err == nil {
+		t.Fatal("snapshot of another validator is imported")
+	}
+	// another network
+	other = cfg
+	other.Opera.NetworkID++
+	if _, err := launcher.ImportEpochState(other, bytes.NewReader(exported.Bytes())); err == nil {
+		t.Fatal("snapshot of another network is imported")
+	}
+	// a tampered snapshot
+	tampered := append([]byte{}, exported.Bytes()...)
+	tampered[len(tampered)-80] ^= 1
+	if _, err := launcher.ImportEpochState(cfg, bytes.NewReader(tampered)); err == nil {
+		t.Fatal("tampered snapshot is imported")
+	}
+
+	imported, err := launcher.ImportEpochState(cfg, bytes.NewReader(exported.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.BlockState.Hash() != b.bs.Hash() || imported.EpochState.Hash() != b.es.Hash() {
+		t.Fatal("imported states differ from the exported ones")
+	}
+	f, err := os.Open(filepath.Join(cfg.NetworkDataDir(), launcher.ImportedEpochStateFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	placed, err := iblockproc.ReadStateSnapshot(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := placed.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a node with a database doesn't take the snapshot over
+	chaindata := filepath.Join(cfg.NetworkDataDir(), cfg.OperaStore.Path)
+	if err := os.MkdirAll(chaindata, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(chaindata, "CURRENT"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := launcher.ImportEpochState(cfg, bytes.NewReader(exported.Bytes())); err == nil {
+		t.Fatal("snapshot is imported over an existing database")
+	}
+}
@@ -0,0 +1,194 @@
+package test
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/integration"
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/inter/ibr"
+)
+
+type fsckBlock struct {
+	block    *inter.Block
+	txs      types.Transactions
+	receipts types.Receipts
+}
+
+// fsckStores is an in-memory node with its tx index.
+type fsckStores struct {
+	last   idx.Block
+	blocks map[idx.Block]fsckBlock
+	events map[hash.Event]bool
+	llr    map[idx.Block]hash.Hash
+	index  map[common.Hash]integration.TxPosition
+}
+
+func (s *fsckStores) LastBlock() idx.Block { return s.last }
+
+func (s *fsckStores) Block(n idx.Block) (*inter.Block, types.Transactions, types.Receipts) {
+	b, ok := s.blocks[n]
+	if !ok {
+		return nil, nil, nil
+	}
+	return b.block, b.txs, b.receipts
+}
+
+func (s *fsckStores) HasEvent(id hash.Event) bool { return s.events[id] }
+
+func (s *fsckStores) LlrBlockResult(n idx.Block) (hash.Hash, bool) {
+	h, ok := s.llr[n]
+	return h, ok
+}
+
+func (s *fsckStores) GetTxPosition(tx common.Hash) *integration.TxPosition {
+	pos, ok := s.index[tx]
+	if !ok {
+		return nil
+	}
+	return &pos
+}
+
+func (s *fsckStores) SetTxPosition(tx common.Hash, pos integration.TxPosition) { s.index[tx] = pos }
+
+func (s *fsckStores) DeleteTxPosition(tx common.Hash) { delete(s.index, tx) }
+
+func (s *fsckStores) ForEachTxPosition(fn func(tx common.Hash, pos integration.TxPosition) bool) {
+	for tx, pos := range s.index {
+		if !fn(tx, pos) {
+			return
+		}
+	}
+}
+
+// consistentStores returns 3 blocks of 2 transactions each, finalized by LLR.
+func consistentStores(t *testing.T) *fsckStores {
+	s := &fsckStores{
+		last:   3,
+		blocks: make(map[idx.Block]fsckBlock),
+		events: make(map[hash.Event]bool),
+		llr:    make(map[idx.Block]hash.Hash),
+		index:  make(map[common.Hash]integration.TxPosition),
+	}
+	signer := types.NewEIP155Signer(big.NewInt(4003))
+	for n := idx.Block(1); n <= s.last; n++ {
+		atropos := hash.Event{byte(n), 1}
+		b := fsckBlock{block: &inter.Block{
+			Time:    inter.FromUnix(1600000000 + int64(n)),
+			Atropos: atropos,
+			Events:  hash.Events{atropos, {byte(n), 2}},
+			Root:    hash.Hash{byte(n)},
+			GasUsed: 42000,
+		}}
+		for _, e := range b.block.Events {
+			s.events[e] = true
+		}
+		for i := 0; i < 2; i++ {
+			tx, err := types.SignTx(types.NewTransaction(uint64(n)*2+uint64(i), common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil), signer, evmcore.FakeKey(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			b.txs = append(b.txs, tx)
+			b.receipts = append(b.receipts, &types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: uint64(i+1) * 21000, Logs: []*types.Log{}})
+			s.index[tx.Hash()] = integration.TxPosition{Block: n, BlockOffset: uint32(i)}
+		}
+		s.blocks[n] = b
+		receipts := make([]*types.ReceiptForStorage, len(b.receipts))
+		for i, r := range b.receipts {
+			receipts[i] = (*types.ReceiptForStorage)(r)
+		}
+		s.llr[n] = ibr.LlrFullBlockRecord{
+			Atropos: b.block.Atropos, Root: b.block.Root, Txs: b.txs, Receipts: receipts, Time: b.block.Time, GasUsed: b.block.GasUsed,
+		}.Hash()
+	}
+	return s
+}
+
+func issueKinds(issues []integration.FsckIssue) map[integration.FsckIssueKind]int {
+	kinds := make(map[integration.FsckIssueKind]int)
+	for _, issue := range issues {
+		kinds[issue.Kind]++
+	}
+	return kinds
+}
+
+func TestFsck(t *testing.T) {
+	s := consistentStores(t)
+	if report := integration.Fsck(s, s, false); len(report.Issues) != 0 || report.Blocks != 3 {
+		t.Fatalf("issues in consistent stores: %+v", report)
+	}
+
+	// derived index issues
+	moved := s.blocks[1].txs[0].Hash()
+	s.index[moved] = integration.TxPosition{Block: 2, BlockOffset: 5}
+	delete(s.index, s.blocks[2].txs[1].Hash())
+	s.index[common.Hash{0xff}] = integration.TxPosition{Block: 9}
+	// primary data issues
+	delete(s.events, s.blocks[2].block.Events[1])
+	s.blocks[3].block.Root = hash.Hash{0xee}
+
+	report := integration.Fsck(s, s, false)
+	kinds := issueKinds(report.Issues)
+	if kinds[integration.FsckTxIndex] != 3 || kinds[integration.FsckMissingEvent] != 1 || kinds[integration.FsckLlrMismatch] != 1 {
+		t.Fatalf("unexpected issues %+v", report.Issues)
+	}
+	if s.index[moved].Block != 2 {
+		t.Fatal("index is modified without the repair")
+	}
+
+	report = integration.Fsck(s, s, true)
+	if kinds := issueKinds(report.Unrepaired()); len(kinds) != 2 || kinds[integration.FsckTxIndex] != 0 {
+		t.Fatalf("unexpected unrepaired issues %+v", report.Unrepaired())
+	}
+	if report := integration.Fsck(s, s, false); issueKinds(report.Issues)[integration.FsckTxIndex] != 0 {
+		t.Fatalf("tx index isn't rebuilt: %+v", report.Issues)
+	}
+
+	// a missing block
+	delete(s.blocks, 2)
+	if report := integration.Fsck(s, s, true); issueKinds(report.Unrepaired())[integration.FsckMissingBlock] != 1 {
+		t.Fatalf("missing block isn't detected: %+v", report.Issues)
+	}
+}
+
+func TestFsckIfDirty(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opera-fsck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := consistentStores(t)
+	delete(s.index, s.blocks[1].txs[0].Hash())
+	if report, err := integration.FsckIfDirty(dir, s, s); report != nil || err != nil {
+		t.Fatalf("checked after a clean shutdown: %+v, %v", report, err)
+	}
+
+	if err := integration.MarkDirty(dir); err != nil {
+		t.Fatal(err)
+	}
+	report, err := integration.FsckIfDirty(dir, s, s)
+	if err != nil || len(report.Issues) != 1 || !report.Issues[0].Repaired {
+		t.Fatalf("index isn't repaired after a dirty shutdown: %+v, %v", report, err)
+	}
+	s.blocks[1].block.GasUsed++
+	if _, err := integration.FsckIfDirty(dir, s, s); !errors.Is(err, integration.ErrInconsistentStores) {
+		t.Fatalf("error = %v, want %v", err, integration.ErrInconsistentStores)
+	}
+
+	if err := integration.MarkClean(dir); err != nil || integration.IsDirty(dir) {
+		t.Fatalf("marker isn't removed, err %v", err)
+	}
+	if err := integration.MarkClean(dir); err != nil {
+		t.Fatal(err)
+	}
+}
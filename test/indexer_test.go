@@ -0,0 +1,149 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/leveldb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/cmd/opera/launcher"
+	"github.com/rony4d/go-opera-asset/gossip"
+	"github.com/rony4d/go-opera-asset/indexer"
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+// storeTransferBlock stores the block n with a single transaction, which emits an ERC-20 transfer
+// of n tokens from the address 1 to the address n+1.
+func storeTransferBlock(t *testing.T, blocks *gossip.Blocks, n idx.Block, chainID *big.Int) {
+	t.Helper()
+	key, _ := crypto.GenerateKey()
+	tx, err := types.SignTx(types.NewTransaction(0, common.Address{7}, big.NewInt(0), 100000, big.NewInt(1), nil),
+		types.LatestSignerForChainID(chainID), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfer := &types.Log{
+		Address: common.Address{7},
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")),
+			common.BigToHash(big.NewInt(1)),
+			common.BigToHash(big.NewInt(int64(n) + 1)),
+		},
+		Data: common.BigToHash(big.NewInt(int64(n))).Bytes(),
+	}
+	receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 50000, Logs: []*types.Log{transfer}}
+	blocks.Set(n, &inter.Block{Atropos: hash.Event{byte(n)}, GasUsed: 50000}, types.Transactions{tx}, types.Receipts{receipt})
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// TestRunIndexer verifies that `opera indexer` indexes the blocks of a datadir owned by
+// a running node, serves the indexes over its RPC, and follows the new blocks.
+func TestRunIndexer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opera-indexer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := runConfigFromArgs(t, []string{"--datadir", dir, "--network", "fakenet"})
+	cfg.Indexer.HTTPAddr = "127.0.0.1"
+	cfg.Indexer.HTTPPort = freePort(t)
+	chainID := new(big.Int).SetUint64(cfg.Opera.NetworkID)
+
+	// the node keeps its database open (and locked) all the time
+	chaindata := filepath.Join(cfg.NetworkDataDir(), cfg.OperaStore.Path)
+	nodeDB, err := leveldb.NewProducer(chaindata, noCache).OpenDB(gossip.BlocksDBName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nodeDB.Close()
+	blocks := gossip.NewBlocks(nodeDB)
+	storeTransferBlock(t, blocks, 1, chainID)
+	storeTransferBlock(t, blocks, 2, chainID)
+	if err := launcher.CheckIndexerConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- launcher.RunIndexer(ctx, cfg)
+	}()
+
+	var client *rpc.Client
+	waitProgress := func(want hexutil.Uint64) {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			progress := map[string]hexutil.Uint64{}
+			if client == nil {
+				client, _ = rpc.Dial(fmt.Sprintf("http://127.0.0.1:%d", cfg.Indexer.HTTPPort))
+			}
+			if client != nil {
+				_ = client.Call(&progress, "indexer_progress")
+			}
+			if progress["token_transfers"] == want && progress["logs"] == want {
+				return
+			}
+			select {
+			case err := <-done:
+				t.Fatalf("indexer stopped: %v", err)
+			default:
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("indexer progress %v, want %d", progress, want)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+	waitProgress(2)
+
+	var transfers []indexer.RPCTokenTransfer
+	if err := client.Call(&transfers, "indexer_getTokenTransfers", common.BigToAddress(big.NewInt(1)), hexutil.Uint64(0), nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(transfers) != 2 || transfers[1].BlockNumber != 2 || transfers[1].Value.ToInt().Uint64() != 2 {
+		t.Fatalf("unexpected transfers %+v", transfers)
+	}
+
+	// a block stored later is picked up once the indexer reopens the database
+	storeTransferBlock(t, blocks, 3, chainID)
+	waitProgress(3)
+
+	var logs []*types.Log
+	if err := client.Call(&logs, "indexer_getLogs", common.Address{7}, hexutil.Uint64(2), nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 2 || logs[0].BlockNumber != 2 || logs[1].BlockHash != (common.Hash{3}) || logs[1].TxHash == (common.Hash{}) {
+		t.Fatalf("unexpected logs %+v", logs)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("indexer stopped with %v", err)
+	}
+	client.Close()
+}
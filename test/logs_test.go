@@ -0,0 +1,256 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/ethapi"
+	"github.com/rony4d/go-opera-asset/evmcore"
+)
+
+var (
+	logsToken    = common.HexToAddress("0x1000")
+	logsOther    = common.HexToAddress("0x2000")
+	logsTransfer = common.HexToHash("0xaa")
+	logsApproval = common.HexToHash("0xbb")
+)
+
+// logsBackend serves the blocks 0..latest, every 10th block has a transfer log of the token,
+// every other block a log of another contract.
+// The blocks below legacyBelow have no recorded receipts root and bloom.
+type logsBackend struct {
+	ethapi.Backend
+	cfg          ethapi.LogsConfig
+	latest       idx.Block
+	earliest     idx.Block
+	legacyBelow  idx.Block
+	receiptReads int
+	slow         time.Duration
+}
+
+func (b *logsBackend) RPCLogsConfig() ethapi.LogsConfig {
+	return b.cfg
+}
+
+func (b *logsBackend) receipts(n idx.Block) types.Receipts {
+	log := &types.Log{Address: logsOther, Topics: []common.Hash{logsApproval}, BlockNumber: uint64(n)}
+	if n%10 == 0 {
+		log = &types.Log{Address: logsToken, Topics: []common.Hash{logsTransfer, common.BigToHash(big.NewInt(int64(n)))}, BlockNumber: uint64(n)}
+	} else if n%2 == 0 {
+		return types.Receipts{}
+	}
+	return types.Receipts{{Logs: []*types.Log{log}}}
+}
+
+func (b *logsBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmHeader, error) {
+	if number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber {
+		number = rpc.BlockNumber(b.latest)
+	}
+	if idx.Block(number) > b.latest {
+		return nil, nil
+	}
+	if b.slow != 0 {
+		time.Sleep(b.slow)
+	}
+	n := idx.Block(number)
+	header := &evmcore.EvmHeader{
+		Number: big.NewInt(int64(n)),
+		Hash:   common.BigToHash(big.NewInt(int64(n) + 1)),
+	}
+	if n >= b.legacyBelow {
+		header.SetReceipts(b.receipts(n))
+	}
+	return header, nil
+}
+
+func (b *logsBackend) BlockByHash(ctx context.Context, h common.Hash) (*evmcore.EvmBlock, error) {
+	n := h.Big().Int64() - 1
+	if n < 0 || idx.Block(n) > b.latest {
+		return nil, nil
+	}
+	return &evmcore.EvmBlock{EvmHeader: evmcore.EvmHeader{Number: big.NewInt(n), Hash: h}}, nil
+}
+
+func (b *logsBackend) GetReceipts(ctx context.Context, n idx.Block) (types.Receipts, error) {
+	b.receiptReads++
+	return b.receipts(n), nil
+}
+
+func (b *logsBackend) EarliestReceiptsBlock(ctx context.Context) (idx.Block, error) {
+	return b.earliest, nil
+}
+
+func blockNumber(n int64) *rpc.BlockNumber {
+	bn := rpc.BlockNumber(n)
+	return &bn
+}
+
+func TestGetLogs(t *testing.T) {
+	b := &logsBackend{latest: 100, cfg: ethapi.LogsConfig{MaxBlockRange: 1000, MaxResults: 100}}
+	api := ethapi.NewPublicLogsAPI(b)
+	ctx := context.Background()
+
+	logs, err := api.GetLogs(ctx, ethapi.FilterCriteria{
+		FromBlock: blockNumber(0),
+		Addresses: []common.Address{logsToken},
+		Topics:    [][]common.Hash{{logsTransfer}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 11 {
+		t.Fatalf("expected 11 transfers, got %d", len(logs))
+	}
+	for i, log := range logs {
+		if log.BlockNumber != uint64(i*10) {
+			t.Fatalf("unexpected log of block %d at position %d", log.BlockNumber, i)
+		}
+	}
+	// the bloom filters out the blocks without the token logs
+	if b.receiptReads > 20 {
+		t.Fatalf("bloom prefilter is ineffective: %d receipts read for 11 matching blocks", b.receiptReads)
+	}
+
+	// topic positions: any topic at the first position, a specific one at the second
+	logs, err = api.GetLogs(ctx, ethapi.FilterCriteria{
+		FromBlock: blockNumber(0),
+		Topics:    [][]common.Hash{{}, {common.BigToHash(big.NewInt(30))}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].BlockNumber != 30 {
+		t.Fatalf("unexpected logs %v", logs)
+	}
+
+	// a single block by hash
+	h := common.BigToHash(big.NewInt(21))
+	logs, err = api.GetLogs(ctx, ethapi.FilterCriteria{BlockHash: &h})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].BlockNumber != 20 {
+		t.Fatalf("unexpected logs of the block %v", logs)
+	}
+
+	// the range after the latest block is empty, not an error
+	logs, err = api.GetLogs(ctx, ethapi.FilterCriteria{FromBlock: blockNumber(200), ToBlock: blockNumber(300)})
+	if err != nil || len(logs) != 0 {
+		t.Fatalf("unexpected result after the latest block: %v, %v", logs, err)
+	}
+	if _, err := api.GetLogs(ctx, ethapi.FilterCriteria{FromBlock: blockNumber(50), ToBlock: blockNumber(40)}); err == nil {
+		t.Fatal("reversed range is accepted")
+	}
+}
+
+// TestGetLogs_noBloom verifies that the logs of the blocks stored without the bloom aren't
+// filtered out by the empty bloom.
+func TestGetLogs_noBloom(t *testing.T) {
+	b := &logsBackend{latest: 100, legacyBelow: 50, cfg: ethapi.LogsConfig{MaxBlockRange: 1000, MaxResults: 100}}
+	api := ethapi.NewPublicLogsAPI(b)
+
+	logs, err := api.GetLogs(context.Background(), ethapi.FilterCriteria{
+		FromBlock: blockNumber(0),
+		Addresses: []common.Address{logsToken},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 11 || logs[0].BlockNumber != 0 || logs[4].BlockNumber != 40 {
+		t.Fatalf("expected 11 transfers including the legacy blocks, got %v", logs)
+	}
+	// the legacy blocks are read in full, the rest are still prefiltered
+	if b.receiptReads < 50 || b.receiptReads > 50+20 {
+		t.Fatalf("%d receipts read, expected the 50 legacy blocks and the matching ones", b.receiptReads)
+	}
+}
+
+func TestGetLogsLimits(t *testing.T) {
+	ctx := context.Background()
+
+	b := &logsBackend{latest: 100, cfg: ethapi.LogsConfig{MaxBlockRange: 50}}
+	api := ethapi.NewPublicLogsAPI(b)
+	if _, err := api.GetLogs(ctx, ethapi.FilterCriteria{FromBlock: blockNumber(0)}); !errors.Is(err, ethapi.ErrLogsRangeTooWide) {
+		t.Fatalf("expected too wide range error, got %v", err)
+	}
+	if _, err := api.GetLogs(ctx, ethapi.FilterCriteria{FromBlock: blockNumber(51)}); err != nil {
+		t.Fatalf("range within the limit is rejected: %v", err)
+	}
+
+	b = &logsBackend{latest: 100, cfg: ethapi.LogsConfig{MaxResults: 10}}
+	api = ethapi.NewPublicLogsAPI(b)
+	if _, err := api.GetLogs(ctx, ethapi.FilterCriteria{FromBlock: blockNumber(0)}); !errors.Is(err, ethapi.ErrLogsTooManyResults) {
+		t.Fatalf("expected too many results error, got %v", err)
+	}
+	if _, err := api.GetLogs(ctx, ethapi.FilterCriteria{FromBlock: blockNumber(0), Addresses: []common.Address{logsToken}}); !errors.Is(err, ethapi.ErrLogsTooManyResults) {
+		t.Fatalf("11 transfers exceed the limit of 10, got %v", err)
+	}
+	if logs, err := api.GetLogs(ctx, ethapi.FilterCriteria{FromBlock: blockNumber(10), Addresses: []common.Address{logsToken}}); err != nil || len(logs) != 10 {
+		t.Fatalf("results within the limit are rejected: %d, %v", len(logs), err)
+	}
+
+	b = &logsBackend{latest: 100, earliest: 40}
+	api = ethapi.NewPublicLogsAPI(b)
+	if _, err := api.GetLogs(ctx, ethapi.FilterCriteria{FromBlock: blockNumber(30)}); !errors.Is(err, ethapi.ErrHistoryPruned) {
+		t.Fatalf("expected pruned history error, got %v", err)
+	}
+	if _, err := api.GetLogs(ctx, ethapi.FilterCriteria{FromBlock: blockNumber(40)}); err != nil {
+		t.Fatalf("available history is rejected: %v", err)
+	}
+
+	b = &logsBackend{latest: 100, cfg: ethapi.LogsConfig{Timeout: 20 * time.Millisecond}, slow: 5 * time.Millisecond}
+	api = ethapi.NewPublicLogsAPI(b)
+	if _, err := api.GetLogs(ctx, ethapi.FilterCriteria{FromBlock: blockNumber(0)}); !errors.Is(err, ethapi.ErrLogsTimeout) {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
+
+// TestGetLogsRPC verifies the JSON format of the query over RPC.
+func TestGetLogsRPC(t *testing.T) {
+	b := &logsBackend{latest: 100}
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("eth", ethapi.NewPublicLogsAPI(b)); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	var logs []*types.Log
+	// a single address and a null topic position
+	query := map[string]interface{}{
+		"fromBlock": "0x0",
+		"toBlock":   "latest",
+		"address":   logsToken,
+		"topics":    []interface{}{nil, []common.Hash{common.BigToHash(big.NewInt(30)), common.BigToHash(big.NewInt(40))}},
+	}
+	if err := client.Call(&logs, "eth_getLogs", query); err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 2 || logs[0].BlockNumber != 30 || logs[1].BlockNumber != 40 {
+		t.Fatalf("unexpected logs %v", logs)
+	}
+
+	// no matches is an empty list, not null
+	var raw interface{}
+	if err := client.Call(&raw, "eth_getLogs", map[string]interface{}{"address": logsOther, "fromBlock": "0x0", "toBlock": "0x0"}); err != nil {
+		t.Fatal(err)
+	}
+	if list, ok := raw.([]interface{}); !ok || len(list) != 0 {
+		t.Fatalf("expected an empty list, got %v", raw)
+	}
+
+	err := client.Call(&logs, "eth_getLogs", map[string]interface{}{"blockHash": common.Hash{}, "fromBlock": "0x0"})
+	if err == nil || !strings.Contains(err.Error(), "blockHash") {
+		t.Fatalf("block hash along with a range is accepted: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/cmd/opera/launcher"
+	"github.com/rony4d/go-opera-asset/gossip/emitter"
+)
+
+// TestMaintenanceIPC verifies that the maintenance mode is switched over the IPC socket of the node.
+func TestMaintenanceIPC(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ipc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cfg := runConfigFromArgs(t, []string{"--datadir", dir, "--network", "fakenet"})
+	if want := filepath.Join(cfg.NetworkDataDir(), "opera.ipc"); cfg.IPCEndpoint() != want {
+		t.Fatalf("IPC endpoint %q, want %q", cfg.IPCEndpoint(), want)
+	}
+
+	m := emitter.NewMaintenance()
+	stop, err := launcher.StartIPC(cfg, emitter.MaintenanceAPIs(m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	client, err := rpc.Dial(cfg.IPCEndpoint())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if err := client.CallContext(context.Background(), nil, "validator_startMaintenance"); err != nil {
+		t.Fatal(err)
+	}
+	var status map[string]interface{}
+	if err := client.CallContext(context.Background(), &status, "validator_maintenanceStatus"); err != nil {
+		t.Fatal(err)
+	}
+	if status["status"] != "maintenance" || m.StartEmission() {
+		t.Fatalf("maintenance mode isn't entered: %v", status)
+	}
+	if err := client.CallContext(context.Background(), nil, "validator_stopMaintenance"); err != nil {
+		t.Fatal(err)
+	}
+	if !m.StartEmission() {
+		t.Fatal("maintenance mode isn't left")
+	}
+
+	cfg.Node.RPC.EnableIPC = false
+	if cfg.IPCEndpoint() != "" {
+		t.Fatal("IPC endpoint of the disabled IPC")
+	}
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/integration"
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+	"github.com/rony4d/go-opera-asset/opera/contracts/evmwriter"
+)
+
+// echoPrecompile returns its input and counts the calls.
+type echoPrecompile struct {
+	calls int
+}
+
+func (p *echoPrecompile) Run(_ vm.StateDB, _ vm.BlockContext, _ vm.TxContext, _ common.Address, input []byte, suppliedGas uint64) ([]byte, uint64, error) {
+	p.calls++
+	return input, suppliedGas, nil
+}
+
+// TestPrecompilesRegistration verifies that custom precompiles are rejected at the reserved addresses.
+func TestPrecompilesRegistration(t *testing.T) {
+	pp := integration.NewPrecompiles()
+	custom := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	tests := []struct {
+		name string
+		p    integration.Precompile
+		want error
+	}{
+		{"custom", integration.Precompile{Name: "custom", Address: custom, Contract: &echoPrecompile{}}, nil},
+		{"duplicate", integration.Precompile{Name: "dup", Address: custom, Contract: &echoPrecompile{}}, integration.ErrDuplicatePrecompile},
+		{"nil contract", integration.Precompile{Name: "nil", Address: common.HexToAddress("0x2000")}, integration.ErrNilPrecompile},
+		{"ecrecover", integration.Precompile{Name: "ecrecover", Address: common.BytesToAddress([]byte{1}), Contract: &echoPrecompile{}}, integration.ErrReservedAddress},
+		{"future Ethereum precompile", integration.Precompile{Name: "eth", Address: common.HexToAddress("0xffff"), Contract: &echoPrecompile{}}, integration.ErrReservedAddress},
+		{"EVM writer", integration.Precompile{Name: "writer", Address: evmwriter.ContractAddress, Contract: &echoPrecompile{}}, integration.ErrReservedAddress},
+		{"after Ethereum range", integration.Precompile{Name: "low", Address: common.HexToAddress("0x10000"), Contract: &echoPrecompile{}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := pp.Register(tt.p)
+			if (tt.want == nil) != (err == nil) || (tt.want != nil && !errors.Is(err, tt.want)) {
+				t.Fatalf("Register() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if list := pp.List(); len(list) != 2 || list[0].Name != "low" || list[1].Name != "custom" {
+		t.Fatalf("unexpected registry %+v", list)
+	}
+}
+
+// TestPrecompilesActivation verifies that a custom precompile is executed by the block
+// processing only since its activation block, along with the built-in ones.
+func TestPrecompilesActivation(t *testing.T) {
+	addr := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	echo := &echoPrecompile{}
+	pp := integration.NewPrecompiles()
+	if err := pp.Register(integration.Precompile{Name: "echo", Address: addr, Contract: echo, Activation: 100}); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := opera.FakeNetRules()
+	key := evmcore.FakeKey(1)
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statedb.SetBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1e18))
+
+	signer := evmcore.NewEvmConfig(rules, nil).Signer
+	call := func(block idx.Block, nonce uint64) *core.ExecutionResult {
+		tx, err := types.SignNewTx(key, signer, &types.LegacyTx{
+			Nonce: nonce, GasPrice: big.NewInt(1), Gas: 100000, To: &addr, Data: []byte("ping"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		header := &evmcore.EvmHeader{
+			Number:   new(big.Int).SetUint64(uint64(block)),
+			Time:     inter.FromUnix(1600000000),
+			GasLimit: math.MaxUint64,
+			BaseFee:  big.NewInt(1),
+		}
+		vmConfig := pp.VMConfig(opera.DefaultVMConfig, block)
+		if vmConfig.StatePrecompiles[evmwriter.ContractAddress] == nil {
+			t.Fatal("built-in precompile is missing")
+		}
+		evm := evmcore.NewBlockEVM(evmcore.NewEvmConfig(rules, nil), vmConfig, header, statedb, func(uint64) common.Hash { return common.Hash{} })
+		res, err := evm.ApplyTransaction(tx, 0, new(core.GasPool).AddGas(header.GasLimit))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	if res := call(99, 0); echo.calls != 0 || len(res.ReturnData) != 0 {
+		t.Fatalf("precompile is called before the activation")
+	}
+	if res := call(100, 1); echo.calls != 1 || string(res.ReturnData) != "ping" {
+		t.Fatalf("precompile isn't called after the activation: %q", res.ReturnData)
+	}
+	if len(opera.DefaultVMConfig.StatePrecompiles) != 1 {
+		t.Fatal("the default VM config is modified")
+	}
+}
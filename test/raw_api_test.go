@@ -0,0 +1,107 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/ethapi"
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/inter"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// rawBackend serves the stored blocks 1..len(blocks).
+type rawBackend struct {
+	ethapi.Backend
+	blocks []*inter.Block
+}
+
+func (b *rawBackend) evmBlock(n idx.Block) *evmcore.EvmBlock {
+	if n == 0 || int(n) > len(b.blocks) {
+		return nil
+	}
+	return evmcore.NewEvmBlock(evmcore.ToEvmHeader(b.blocks[n-1], n, hash.Event{}, opera.FakeNetRules()), nil)
+}
+
+func (b *rawBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmBlock, error) {
+	if number == rpc.LatestBlockNumber {
+		number = rpc.BlockNumber(len(b.blocks))
+	}
+	return b.evmBlock(idx.Block(number)), nil
+}
+
+func (b *rawBackend) BlockByHash(ctx context.Context, h common.Hash) (*evmcore.EvmBlock, error) {
+	for i, block := range b.blocks {
+		if common.Hash(block.Atropos) == h {
+			return b.evmBlock(idx.Block(i + 1)), nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *rawBackend) GetBlock(ctx context.Context, n idx.Block) (*inter.Block, error) {
+	if n == 0 || int(n) > len(b.blocks) {
+		return nil, nil
+	}
+	return b.blocks[n-1], nil
+}
+
+func TestGetRawBlock(t *testing.T) {
+	b := &rawBackend{blocks: []*inter.Block{
+		// stored before the receipts root and bloom were introduced
+		{Time: inter.FromUnix(1600000000), Atropos: hash.Event{1}, Events: hash.Events{{1}}, GasUsed: 21000, Root: hash.Hash{2}},
+		{
+			Time:        inter.FromUnix(1600000001),
+			Atropos:     hash.Event{3},
+			Events:      hash.Events{{3}, {4}},
+			Txs:         []common.Hash{{5}},
+			SkippedTxs:  []uint32{1},
+			GasUsed:     42000,
+			Root:        hash.Hash{6},
+			ReceiptHash: hash.Hash{7},
+			Bloom:       types.Bloom{8},
+		},
+	}}
+	api := ethapi.NewPublicDebugAPI(b)
+	ctx := context.Background()
+
+	for i, stored := range b.blocks {
+		want, err := rlp.EncodeToBytes(stored)
+		if err != nil {
+			t.Fatal(err)
+		}
+		byNumber, err := api.GetRawBlock(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(i+1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(byNumber) != string(want) {
+			t.Errorf("block %d: GetRawBlock() = %x, want %x", i+1, byNumber, want)
+		}
+		byHash, err := api.GetRawBlock(ctx, rpc.BlockNumberOrHashWithHash(common.Hash(stored.Atropos), false))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(byHash) != string(want) {
+			t.Errorf("block %d by hash: GetRawBlock() = %x, want %x", i+1, byHash, want)
+		}
+
+		var decoded inter.Block
+		if err := rlp.DecodeBytes(byNumber, &decoded); err != nil {
+			t.Fatalf("block %d: raw block isn't an inter.Block: %v", i+1, err)
+		}
+		if decoded.Atropos != stored.Atropos || decoded.Root != stored.Root || decoded.ReceiptHash != stored.ReceiptHash || decoded.Bloom != stored.Bloom {
+			t.Errorf("block %d: decoded %+v, want %+v", i+1, decoded, *stored)
+		}
+	}
+
+	if _, err := api.GetRawBlock(ctx, rpc.BlockNumberOrHashWithNumber(3)); err == nil {
+		t.Error("unknown block: no error")
+	}
+}
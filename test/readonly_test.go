@@ -0,0 +1,163 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/leveldb"
+
+	"github.com/rony4d/go-opera-asset/cmd/opera/launcher"
+	"github.com/rony4d/go-opera-asset/integration"
+)
+
+// listFiles returns the names and sizes of all the files under dir.
+func listFiles(t *testing.T, dir string) map[string]int64 {
+	t.Helper()
+	files := make(map[string]int64)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			files[path] = info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func noCache(string) (int, int) {
+	return 0, 0
+}
+
+// checkReadOnlyDB verifies that the database has the data and rejects all the mutations.
+func checkReadOnlyDB(t *testing.T, db kvdb.Store) {
+	t.Helper()
+	if v, err := db.Get([]byte("key")); err != nil || !bytes.Equal(v, []byte("value")) {
+		t.Fatalf("Get() = %q, %v", v, err)
+	}
+	if v, err := db.Get([]byte("missing")); err != nil || v != nil {
+		t.Fatalf("Get(missing) = %q, %v", v, err)
+	}
+	it := db.NewIterator([]byte("k"), nil)
+	if !it.Next() || !bytes.Equal(it.Key(), []byte("key")) {
+		t.Fatal("iterator misses the key")
+	}
+	it.Release()
+
+	if err := db.Put([]byte("key"), []byte("other")); !errors.Is(err, kvdb.ErrUnsupportedOp) {
+		t.Fatalf("Put() = %v", err)
+	}
+	if err := db.Delete([]byte("key")); !errors.Is(err, kvdb.ErrUnsupportedOp) {
+		t.Fatalf("Delete() = %v", err)
+	}
+	batch := db.NewBatch()
+	_ = batch.Put([]byte("key"), []byte("other"))
+	if err := batch.Write(); !errors.Is(err, kvdb.ErrUnsupportedOp) {
+		t.Fatalf("batch Write() = %v", err)
+	}
+	if err := db.Compact(nil, nil); !errors.Is(err, kvdb.ErrUnsupportedOp) {
+		t.Fatalf("Compact() = %v", err)
+	}
+}
+
+func TestReadOnlyDBProducer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opera-readonly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writer, err := leveldb.New(filepath.Join(dir, "main"), 16*1024*1024, 16, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	producer := integration.DBProducer(dir, noCache, true)
+	if names := producer.Names(); !reflect.DeepEqual(names, []string{"main"}) {
+		t.Fatalf("Names() = %v", names)
+	}
+	if _, err := producer.OpenDB("missing"); !errors.Is(err, integration.ErrNoDatabase) {
+		t.Fatalf("missing database: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Fatal("missing database is created")
+	}
+
+	// the writer holds the database, so it's opened from a checkpoint
+	locked, err := producer.OpenDB("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReadOnlyDB(t, locked)
+	if err := locked.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the database isn't touched when opened in place
+	before := listFiles(t, dir)
+	db, err := producer.OpenDB("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReadOnlyDB(t, db)
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	db.Drop()
+	if after := listFiles(t, dir); !reflect.DeepEqual(before, after) {
+		var changed []string
+		for name, size := range after {
+			if before[name] != size {
+				changed = append(changed, name)
+			}
+		}
+		sort.Strings(changed)
+		t.Fatalf("read-only database is modified: %v", changed)
+	}
+}
+
+func TestReadOnlyConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opera-readonly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	datadir := filepath.Join(dir, "node")
+
+	cfg := runConfigFromArgs(t, []string{"--datadir", datadir, "--readonly", "--network", "fakenet",
+		"--faucet", "--bootstrap-url", "https://example.com/snapshot.tar.gz"})
+	if !cfg.Node.ReadOnly || cfg.Faucet.Enabled || cfg.Bootstrap.URL != "" || cfg.TxPool.Journal != "" {
+		t.Fatalf("writing features aren't disabled: %+v", cfg)
+	}
+	if _, err := os.Stat(datadir); !os.IsNotExist(err) {
+		t.Fatal("datadir is created in the read-only mode")
+	}
+	if _, err := launcher.ImportEpochState(cfg, bytes.NewReader(nil)); !errors.Is(err, launcher.ErrReadOnly) {
+		t.Fatalf("epoch state is imported in the read-only mode: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(cfg.NetworkDataDir(), cfg.OperaStore.Path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	report := launcher.CheckConfig(cfg)
+	if statusOf(t, report, "readonly") != launcher.CheckPass || statusOf(t, report, "datadir") != launcher.CheckPass {
+		t.Fatalf("read-only datadir isn't accepted: %+v", report)
+	}
+}
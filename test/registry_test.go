@@ -0,0 +1,200 @@
+package test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/rony4d/go-opera-asset/cmd/opera/launcher"
+	"github.com/rony4d/go-opera-asset/inter/iblockproc"
+)
+
+// TestRegistry verifies that only the documents signed with the pinned key are accepted,
+// that an older document doesn't roll the registry back, and how the registry is applied.
+func TestRegistry(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := runConfigFromArgs(t, []string{"--bootnodes", "enode://a@127.0.0.1:5050"})
+	rules := launcher.NetworkRules(cfg.Opera)
+	reg := launcher.NetworkRegistry{
+		NetworkID:    cfg.Opera.NetworkID,
+		Version:      2,
+		Bootnodes:    []string{"enode://a@127.0.0.1:5050", "enode://b@127.0.0.2:5050"},
+		RulesHash:    iblockproc.RulesHash(rules),
+		SnapshotURL:  "https://example.com/snapshot.tar.gz",
+		SnapshotHash: "ab",
+	}
+	doc, err := launcher.SignRegistry(priv, reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := doc
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(served)
+	}))
+	defer srv.Close()
+
+	r := launcher.NewRegistry(cfg.Opera.NetworkID, pub)
+	if updated, err := r.Refresh(context.Background(), srv.Client(), srv.URL); err != nil || !updated {
+		t.Fatalf("registry isn't accepted: %v", err)
+	}
+	got := r.Latest()
+	if got.Version != 2 || len(got.Bootnodes) != 2 || got.CheckRules(rules) != nil {
+		t.Fatalf("unexpected registry %+v", got)
+	}
+	rules.Blocks.MaxBlockGas++
+	if got.CheckRules(rules) == nil {
+		t.Fatal("rules mismatch isn't detected")
+	}
+
+	// a document with the same version or an older one is ignored
+	reg.Version = 1
+	reg.Bootnodes = nil
+	served, _ = launcher.SignRegistry(priv, reg)
+	if updated, err := r.Refresh(context.Background(), srv.Client(), srv.URL); err != nil || updated {
+		t.Fatalf("older registry is accepted: %v", err)
+	}
+	if len(r.Latest().Bootnodes) != 2 {
+		t.Fatal("registry is rolled back")
+	}
+
+	// a tampered document or a document signed with another key is rejected
+	served = []byte(strings.Replace(string(doc), `"version":2`, `"version":3`, 1))
+	if _, err := r.Refresh(context.Background(), srv.Client(), srv.URL); !errors.Is(err, launcher.ErrRegistrySignature) {
+		t.Fatalf("tampered registry: %v", err)
+	}
+	_, other, _ := ed25519.GenerateKey(nil)
+	reg.Version = 3
+	served, _ = launcher.SignRegistry(other, reg)
+	if _, err := r.Refresh(context.Background(), srv.Client(), srv.URL); !errors.Is(err, launcher.ErrRegistrySignature) {
+		t.Fatalf("registry of another key: %v", err)
+	}
+
+	// a registry of another network is rejected
+	reg.NetworkID++
+	served, _ = launcher.SignRegistry(priv, reg)
+	if _, err := r.Refresh(context.Background(), srv.Client(), srv.URL); !errors.Is(err, launcher.ErrRegistryNetwork) {
+		t.Fatalf("registry of another network: %v", err)
+	}
+
+	// the bootnodes are merged, and the explicitly configured snapshot is kept
+	launcher.ApplyRegistry(&cfg, got)
+	if len(cfg.Node.P2P.Bootnodes) != 2 || cfg.Bootstrap.URL != got.SnapshotURL {
+		t.Fatalf("registry isn't applied: %v, %+v", cfg.Node.P2P.Bootnodes, cfg.Bootstrap)
+	}
+	cfg.Bootstrap.URL = "https://example.com/other.tar.gz"
+	launcher.ApplyRegistry(&cfg, got)
+	if len(cfg.Node.P2P.Bootnodes) != 2 || cfg.Bootstrap.URL != "https://example.com/other.tar.gz" {
+		t.Fatalf("configured snapshot is overridden: %+v", cfg.Bootstrap)
+	}
+
+	// the key must be a valid ed25519 public key
+	cfg.Registry.URL = srv.URL
+	cfg.Registry.Key = hexutil.Encode(pub)
+	if err := launcher.CheckRegistryConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Registry.Key = hexutil.Encode(pub[:16])
+	if launcher.CheckRegistryConfig(cfg) == nil {
+		t.Fatal("short key is accepted")
+	}
+}
+
+// TestRegistryPersisted verifies that the last accepted registry survives a restart, so a replayed
+// older document isn't accepted after it, and that RunRegistry keeps refreshing the registry.
+func TestRegistryPersisted(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "opera-registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cfg := runConfigFromArgs(t, []string{"--datadir", dir, "--network", "fakenet"})
+	reg := launcher.NetworkRegistry{NetworkID: cfg.Opera.NetworkID, Version: 2, Bootnodes: []string{"enode://a@127.0.0.1:5050"}}
+	var mu sync.Mutex
+	served, _ := launcher.SignRegistry(priv, reg)
+	serve := func(doc []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		served = doc
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Write(served)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(dir, launcher.RegistryFile)
+	r, err := launcher.OpenRegistry(path, cfg.Opera.NetworkID, pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated, err := r.Refresh(context.Background(), srv.Client(), srv.URL); err != nil || !updated {
+		t.Fatalf("registry isn't accepted: %v", err)
+	}
+
+	// the restarted node rejects an older document
+	reg.Version = 1
+	reg.Bootnodes = nil
+	old, _ := launcher.SignRegistry(priv, reg)
+	serve(old)
+	r, err = launcher.OpenRegistry(path, cfg.Opera.NetworkID, pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Latest() == nil || r.Latest().Version != 2 {
+		t.Fatalf("accepted registry isn't loaded: %+v", r.Latest())
+	}
+	if updated, err := r.Refresh(context.Background(), srv.Client(), srv.URL); err != nil || updated {
+		t.Fatalf("older registry is accepted after a restart: %v", err)
+	}
+	if _, err := launcher.OpenRegistry(path, cfg.Opera.NetworkID+1, pub); !errors.Is(err, launcher.ErrRegistryNetwork) {
+		t.Fatalf("registry of another network is loaded: %v", err)
+	}
+
+	// RunRegistry picks up a newer document
+	cfg.Registry.URL = srv.URL
+	cfg.Registry.Interval = time.Millisecond
+	reg.Version = 3
+	newer, _ := launcher.SignRegistry(priv, reg)
+	serve(newer)
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan *launcher.NetworkRegistry, 1)
+	done := make(chan struct{})
+	go func() {
+		launcher.RunRegistry(ctx, cfg, r, func(reg *launcher.NetworkRegistry) {
+			updates <- reg
+		})
+		close(done)
+	}()
+	select {
+	case got := <-updates:
+		if got.Version != 3 {
+			t.Fatalf("unexpected update %+v", got)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("registry isn't refreshed")
+	}
+	cancel()
+	<-done
+	if r, err = launcher.OpenRegistry(path, cfg.Opera.NetworkID, pub); err != nil || r.Latest().Version != 3 {
+		t.Fatalf("refreshed registry isn't persisted: %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/rony4d/go-opera-asset/cmd/opera/launcher"
+)
+
+// TestAdjustToResources verifies that caches and DB handles are downscaled to the
+// system limits, and that the node refuses to start if even the minimums don't fit.
+func TestAdjustToResources(t *testing.T) {
+	base := func() launcher.Config {
+		var cfg launcher.Config
+		cfg.OperaStore.CacheMB = 3072
+		cfg.LachesisStore.CacheMB = 1024
+		cfg.OperaStore.Handles = 512
+		return cfg
+	}
+
+	tests := []struct {
+		name     string                    // descriptive name for the scenario
+		res      launcher.SystemResources  // detected system limits
+		wantErr  bool                      // whether the node must refuse to start
+		warnings int                       // number of adjusted values
+		check    func(cfg launcher.Config) // assertion helper examining the adjusted config
+	}{
+		{
+			name: "unknown limits",
+			res:  launcher.SystemResources{},
+			check: func(cfg launcher.Config) {
+				if cfg.OperaStore.CacheMB != 3072 || cfg.OperaStore.Handles != 512 {
+					t.Fatalf("config changed: %+v", cfg.OperaStore)
+				}
+			},
+		},
+		{
+			name: "enough resources",
+			res:  launcher.SystemResources{MemoryMB: 16384, FDLimit: 65536},
+			check: func(cfg launcher.Config) {
+				if cfg.OperaStore.CacheMB != 3072 || cfg.LachesisStore.CacheMB != 1024 || cfg.OperaStore.Handles != 512 {
+					t.Fatalf("config changed: %+v", cfg)
+				}
+			},
+		},
+		{
+			name:     "small host",
+			res:      launcher.SystemResources{MemoryMB: 4096, FDLimit: 256},
+			warnings: 2,
+			check: func(cfg launcher.Config) {
+				if total := cfg.OperaStore.CacheMB + cfg.LachesisStore.CacheMB; total != 2048 {
+					t.Fatalf("caches = %d MB, want 2048 MB", total)
+				}
+				if cfg.LachesisStore.CacheMB != 512 {
+					t.Fatalf("caches aren't scaled proportionally: %+v", cfg)
+				}
+				if cfg.OperaStore.Handles != 128 {
+					t.Fatalf("handles = %d, want 128", cfg.OperaStore.Handles)
+				}
+			},
+		},
+		{
+			name:    "not enough memory",
+			res:     launcher.SystemResources{MemoryMB: 256},
+			wantErr: true,
+		},
+		{
+			name:    "not enough file descriptors",
+			res:     launcher.SystemResources{FDLimit: 64},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base()
+			warnings, err := launcher.AdjustToResources(&cfg, tt.res)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AdjustToResources() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(warnings) != tt.warnings {
+				t.Fatalf("warnings = %q, want %d", warnings, tt.warnings)
+			}
+			tt.check(cfg)
+		})
+	}
+}
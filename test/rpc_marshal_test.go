@@ -0,0 +1,59 @@
+package test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"github.com/rony4d/go-opera-asset/ethapi"
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/inter"
+)
+
+func TestRPCMarshalBlock_Receipts(t *testing.T) {
+	receipts := types.Receipts{{
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 21000,
+		Logs:              []*types.Log{{Address: logsToken, Topics: []common.Hash{logsTransfer}}},
+	}}
+	receipts[0].Bloom = types.CreateBloom(receipts)
+	header := &evmcore.EvmHeader{
+		Number: big.NewInt(5),
+		Hash:   common.Hash{5},
+		Time:   inter.FromUnix(1600000000),
+	}
+	header.SetReceipts(receipts)
+
+	out, err := json.Marshal(ethapi.RPCMarshalBlock(evmcore.NewEvmBlock(header, nil), true, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields struct {
+		ReceiptsRoot common.Hash `json:"receiptsRoot"`
+		LogsBloom    types.Bloom `json:"logsBloom"`
+		Number       string      `json:"number"`
+		Transactions []common.Hash
+	}
+	if err := json.Unmarshal(out, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if want := types.DeriveSha(receipts, trie.NewStackTrie(nil)); fields.ReceiptsRoot != want {
+		t.Errorf("receiptsRoot = %s, want %s", fields.ReceiptsRoot.Hex(), want.Hex())
+	}
+	if !types.BloomLookup(fields.LogsBloom, logsToken) || !types.BloomLookup(fields.LogsBloom, logsTransfer) || types.BloomLookup(fields.LogsBloom, logsOther) {
+		t.Error("logsBloom doesn't match the logs")
+	}
+	if fields.Number != "0x5" || len(fields.Transactions) != 0 {
+		t.Errorf("number = %s, transactions = %v", fields.Number, fields.Transactions)
+	}
+
+	// a block without transactions commits to the empty receipts trie
+	header.SetReceipts(nil)
+	if got := ethapi.RPCMarshalHeader(header); got["receiptsRoot"] != types.EmptyRootHash || got["logsBloom"] != (types.Bloom{}) {
+		t.Errorf("empty block: receiptsRoot = %v, logsBloom = %v", got["receiptsRoot"], got["logsBloom"])
+	}
+}
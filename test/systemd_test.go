@@ -0,0 +1,212 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rony4d/go-opera-asset/cmd/opera/launcher"
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+// listenNotifySocket emulates the notify socket of systemd.
+func listenNotifySocket(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("no unix datagram sockets")
+	}
+	dir, err := ioutil.TempDir("", "opera-systemd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	messages := make(chan string, 100)
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+			messages <- string(buf[:n])
+		}
+	}()
+	return path, messages
+}
+
+func nextNotification(t *testing.T, messages <-chan string) string {
+	t.Helper()
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification")
+		return ""
+	}
+}
+
+func TestSystemdNotify(t *testing.T) {
+	socket, messages := listenNotifySocket(t)
+	n := launcher.NewSystemdNotifier(socket, 0)
+
+	if err := n.Ready(); err != nil {
+		t.Fatal(err)
+	}
+	if msg := nextNotification(t, messages); msg != "READY=1" {
+		t.Fatalf("unexpected notification %q", msg)
+	}
+	if err := n.SyncStatus(250, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if msg := nextNotification(t, messages); msg != "STATUS=Syncing, block 250 of 1000 (25.0%)" {
+		t.Fatalf("unexpected notification %q", msg)
+	}
+	if err := n.SyncStatus(1000, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if msg := nextNotification(t, messages); msg != "STATUS=Synced, block 1000" {
+		t.Fatalf("unexpected notification %q", msg)
+	}
+	// a multi-line status must not inject assignments
+	if err := n.Status("a\nREADY=1"); err != nil {
+		t.Fatal(err)
+	}
+	if msg := nextNotification(t, messages); msg != "STATUS=a READY=1" {
+		t.Fatalf("unexpected notification %q", msg)
+	}
+
+	// outside of systemd, nothing is sent
+	off := launcher.NewSystemdNotifier("", time.Second)
+	if off.Enabled() || off.WatchdogTimeout() != 0 {
+		t.Fatal("notifier without socket is enabled")
+	}
+	if err := off.Ready(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSystemdNotifierFromEnv(t *testing.T) {
+	for _, key := range []string{"NOTIFY_SOCKET", "WATCHDOG_USEC", "WATCHDOG_PID"} {
+		defer os.Setenv(key, os.Getenv(key))
+	}
+	os.Setenv("NOTIFY_SOCKET", "/run/systemd/notify")
+	os.Setenv("WATCHDOG_USEC", "30000000")
+	os.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+	n, err := launcher.SystemdNotifierFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !n.Enabled() || n.WatchdogTimeout() != 30*time.Second {
+		t.Fatalf("unexpected watchdog timeout %v", n.WatchdogTimeout())
+	}
+
+	// the watchdog of another process
+	os.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	if n, err = launcher.SystemdNotifierFromEnv(); err != nil || n.WatchdogTimeout() != 0 {
+		t.Fatalf("watchdog of another process is used: %v, %v", n.WatchdogTimeout(), err)
+	}
+
+	os.Setenv("WATCHDOG_PID", "")
+	os.Setenv("WATCHDOG_USEC", "soon")
+	if _, err = launcher.SystemdNotifierFromEnv(); err == nil {
+		t.Fatal("invalid WATCHDOG_USEC is accepted")
+	}
+}
+
+// TestSystemdWatchdog verifies that the keepalives stop while the node is unhealthy.
+func TestSystemdWatchdog(t *testing.T) {
+	socket, messages := listenNotifySocket(t)
+	n := launcher.NewSystemdNotifier(socket, 20*time.Millisecond)
+
+	var unhealthy int32
+	health := launcher.NewHealthChecker()
+	health.Add("blocks", func() error {
+		if atomic.LoadInt32(&unhealthy) != 0 {
+			return errors.New("hung")
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		n.RunWatchdog(ctx, health)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	for i := 0; i < 3; i++ {
+		if msg := nextNotification(t, messages); msg != "WATCHDOG=1" {
+			t.Fatalf("unexpected notification %q", msg)
+		}
+	}
+
+	atomic.StoreInt32(&unhealthy, 1)
+	// the keepalives sent before the failure may be still in flight
+	for {
+		msg := nextNotification(t, messages)
+		if msg == "WATCHDOG=1" {
+			continue
+		}
+		if !strings.HasPrefix(msg, "STATUS=Unhealthy: blocks: hung") {
+			t.Fatalf("unexpected notification %q", msg)
+		}
+		break
+	}
+	select {
+	case msg := <-messages:
+		t.Fatalf("notification %q is sent while the node is unhealthy", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	atomic.StoreInt32(&unhealthy, 0)
+	if msg := nextNotification(t, messages); msg != "WATCHDOG=1" {
+		t.Fatalf("keepalives aren't resumed: %q", msg)
+	}
+}
+
+func TestProgressCheck(t *testing.T) {
+	c := clock.NewManual(time.Unix(1600000000, 0))
+	var block uint64 = 10
+	check := launcher.ProgressCheck(func() uint64 { return block }, time.Minute, c)
+
+	c.Advance(time.Minute)
+	if err := check(); err != nil {
+		t.Fatalf("stall within the limit is reported: %v", err)
+	}
+	c.Advance(time.Second)
+	if err := check(); err == nil {
+		t.Fatal("stall isn't reported")
+	}
+	block++
+	if err := check(); err != nil {
+		t.Fatalf("progress isn't noticed: %v", err)
+	}
+
+	health := launcher.NewHealthChecker()
+	health.Add("ok", func() error { return nil })
+	health.Add("blocks", check)
+	c.Advance(2 * time.Minute)
+	if err := health.Check(); err == nil || !strings.HasPrefix(err.Error(), "blocks: ") {
+		t.Fatalf("unexpected health %v", err)
+	}
+}
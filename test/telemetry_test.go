@@ -0,0 +1,64 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/rony4d/go-opera-asset/cmd/opera/launcher"
+)
+
+// TestTelemetry verifies that the telemetry is off by default, and that the report
+// carries only the anonymous fields.
+func TestTelemetry(t *testing.T) {
+	cfg := runConfigFromArgs(t, nil)
+	if cfg.Telemetry.Enabled {
+		t.Fatal("telemetry is on by default")
+	}
+	cfg = runConfigFromArgs(t, []string{"--network", "mainnet", "--telemetry", "on", "--telemetry.endpoint", "https://example.com"})
+	if !cfg.Telemetry.Enabled || launcher.CheckTelemetryConfig(cfg) != nil {
+		t.Fatalf("telemetry isn't enabled: %+v", cfg.Telemetry)
+	}
+
+	var received map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(raw, &received); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	report := launcher.NewTelemetryReport(cfg, launcher.SyncStatusSynced)
+	if err := launcher.SendTelemetry(context.Background(), srv.Client(), srv.URL, report); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"version":    report.Version,
+		"network":    "mainnet",
+		"goVersion":  runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+		"syncStatus": "synced",
+	}
+	if len(received) != len(want) {
+		t.Fatalf("unexpected report fields %v", received)
+	}
+	for k, v := range want {
+		if received[k] != v {
+			t.Fatalf("%s = %v, want %v", k, received[k], v)
+		}
+	}
+
+	// custom network names aren't reported
+	cfg.Opera.NetworkID = 12345
+	cfg.Opera.FakeNet = false
+	cfg.Opera.NetworkName = "acme-private"
+	if n := launcher.NewTelemetryReport(cfg, launcher.SyncStatusUnknown).Network; n != "custom" {
+		t.Fatalf("network = %q, want custom", n)
+	}
+}
@@ -0,0 +1,66 @@
+package test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/opera"
+)
+
+// TestValidateTx verifies that eth_sendRawTransaction rejects transactions with geth's errors,
+// and that the errors are classified into the canonical skip reasons.
+func TestValidateTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	rules := opera.FakeNetRules()
+	to := common.HexToAddress("0x01")
+
+	sign := func(chainID uint64, inner types.TxData) *types.Transaction {
+		tx, err := types.SignNewTx(key, types.LatestSignerForChainID(new(big.Int).SetUint64(chainID)), inner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	legacy := func(gas uint64, gasPrice *big.Int) *types.LegacyTx {
+		return &types.LegacyTx{To: &to, Gas: gas, GasPrice: gasPrice, Value: big.NewInt(1)}
+	}
+	price := rules.Economy.MinGasPrice
+
+	for name, tc := range map[string]struct {
+		tx      *types.Transaction
+		baseFee *big.Int
+		err     error
+		reason  evmcore.SkipReason
+	}{
+		"valid":             {sign(rules.NetworkID, legacy(21000, price)), price, nil, evmcore.SkipNone},
+		"wrong chain":       {sign(opera.MainNetworkID, legacy(21000, price)), nil, types.ErrInvalidChainId, evmcore.SkipInvalidChainID},
+		"intrinsic gas":     {sign(rules.NetworkID, legacy(20000, price)), nil, core.ErrIntrinsicGas, evmcore.SkipIntrinsicGas},
+		"below min price":   {sign(rules.NetworkID, legacy(21000, big.NewInt(1))), nil, core.ErrUnderpriced, evmcore.SkipUnderpriced},
+		"below base fee":    {sign(rules.NetworkID, legacy(21000, price)), new(big.Int).Add(price, common.Big1), core.ErrFeeCapTooLow, evmcore.SkipFeeCapTooLow},
+		"above block gas":   {sign(rules.NetworkID, legacy(rules.Blocks.MaxBlockGas+1, price)), nil, core.ErrGasLimit, evmcore.SkipGasAboveBlockLimit},
+		"tip above fee cap": {sign(rules.NetworkID, &types.DynamicFeeTx{ChainID: new(big.Int).SetUint64(rules.NetworkID), To: &to, Gas: 21000, GasFeeCap: price, GasTipCap: new(big.Int).Add(price, common.Big1)}), nil, core.ErrTipAboveFeeCap, evmcore.SkipTipAboveFeeCap},
+		"oversized":         {sign(rules.NetworkID, &types.LegacyTx{To: &to, Gas: 10000000, GasPrice: price, Data: make([]byte, 128*1024)}), nil, core.ErrOversizedData, evmcore.SkipOversized},
+	} {
+		err := evmcore.ValidateTx(tc.tx, rules, tc.baseFee)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s: expected %v, got %v", name, tc.err, err)
+		}
+		if reason := evmcore.ClassifyTxError(err); reason != tc.reason {
+			t.Errorf("%s: expected reason %v, got %v", name, tc.reason, reason)
+		}
+	}
+
+	// typed transactions are accepted only after the corresponding upgrade
+	rules.Upgrades.London = false
+	dynamic := sign(rules.NetworkID, &types.DynamicFeeTx{ChainID: new(big.Int).SetUint64(rules.NetworkID), To: &to, Gas: 21000, GasFeeCap: price, GasTipCap: price})
+	if err := evmcore.ValidateTx(dynamic, rules, nil); err != types.ErrTxTypeNotSupported {
+		t.Fatalf("expected %v, got %v", types.ErrTxTypeNotSupported, err)
+	}
+}
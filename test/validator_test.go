@@ -0,0 +1,311 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/rony4d/go-opera-asset/cmd/opera/launcher"
+	"github.com/rony4d/go-opera-asset/ethapi"
+	"github.com/rony4d/go-opera-asset/evmcore"
+	"github.com/rony4d/go-opera-asset/gossip/emitter"
+	"github.com/rony4d/go-opera-asset/inter/validatorpk"
+	"github.com/rony4d/go-opera-asset/opera"
+	"github.com/rony4d/go-opera-asset/valkeystore"
+)
+
+func TestValidatorPubkeyRPC(t *testing.T) {
+	b := &epochStateBackend{}
+	b.bs, b.es = epochStates(opera.FakeNetRules())
+	newKey := validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: []byte{1, 2, 3}}
+	next := b.bs.NextValidatorProfiles[2]
+	next.PubKey = newKey
+	b.bs.NextValidatorProfiles[2] = next
+	delete(b.bs.NextValidatorProfiles, 3)
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("abft", ethapi.NewPublicAbftAPI(b)); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+	chain := launcher.NewRPCValidatorKeyChain(client, nil, time.Millisecond)
+
+	keys, err := chain.ValidatorPubkeys(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys.Epoch != 7 || keys.Current.String() != b.es.ValidatorProfiles[2].PubKey.String() || keys.Next.String() != newKey.String() {
+		t.Fatalf("unexpected keys %+v", keys)
+	}
+	if keys, err = chain.ValidatorPubkeys(context.Background(), 3); err != nil || !keys.Next.Empty() {
+		t.Fatalf("leaving validator has the next key %v, err %v", keys.Next, err)
+	}
+	if _, err := chain.ValidatorPubkeys(context.Background(), 4); err == nil {
+		t.Fatal("keys of an unknown validator")
+	}
+}
+
+// fakeKeyChain switches the epoch after the given number of polls since the key change.
+type fakeKeyChain struct {
+	epoch         idx.Epoch
+	current, next validatorpk.PubKey
+	pollsToSeal   int
+	submitted     int
+	waitErr       error
+}
+
+func (c *fakeKeyChain) ValidatorPubkeys(ctx context.Context, validator idx.ValidatorID) (launcher.ValidatorPubkeys, error) {
+	if c.submitted != 0 && c.pollsToSeal == 0 {
+		c.epoch++
+		c.current = c.next
+	}
+	c.pollsToSeal--
+	return launcher.ValidatorPubkeys{Epoch: c.epoch, Current: c.current, Next: c.next}, nil
+}
+
+func (c *fakeKeyChain) SubmitPubkeyChange(ctx context.Context, pubkey validatorpk.PubKey) (common.Hash, error) {
+	c.submitted++
+	c.next = pubkey
+	return common.Hash{1}, nil
+}
+
+func (c *fakeKeyChain) WaitMined(ctx context.Context, tx common.Hash) error {
+	err := c.waitErr
+	c.waitErr = nil
+	return err
+}
+
+// TestRotateValidatorKey verifies that the emitter signs with the new key since its activation
+// epoch, and that an interrupted rotation is resumed without another key or transaction.
+func TestRotateValidatorKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opera-rotate-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cfg := runConfigFromArgs(t, []string{"--datadir", dir, "--network", "fakenet"})
+	if err := os.MkdirAll(cfg.NetworkDataDir(), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	oldKey := validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: crypto.FromECDSAPub(&evmcore.FakeKey(2).PublicKey)}
+	ks := valkeystore.NewFileKeystore(filepath.Join(cfg.NetworkDataDir(), "keystore"), keystore.LightScryptN, keystore.LightScryptP)
+	if _, err := launcher.RotateValidatorKey(context.Background(), cfg, &fakeKeyChain{}, "pass", time.Millisecond); err == nil {
+		t.Fatal("rotated the key of no validator")
+	}
+	cfg.Emitter.ValidatorID = 2
+	cfg.Emitter.ValidatorKey = oldKey.String()
+	// the password can't be checked without the current key
+	if _, err := launcher.RotateValidatorKey(context.Background(), cfg, &fakeKeyChain{current: oldKey, next: oldKey}, "pass", time.Millisecond); err == nil {
+		t.Fatal("rotated the key which isn't in the keystore")
+	}
+	if err := ks.Add(oldKey, evmcore.FakeKey(2), "pass"); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Emitter.ValidatorKey = "0xc00102"
+	chain := &fakeKeyChain{epoch: 7, current: oldKey, next: oldKey, pollsToSeal: 3, waitErr: errors.New("interrupted")}
+	if _, err := launcher.RotateValidatorKey(context.Background(), cfg, chain, "pass", time.Millisecond); err == nil {
+		t.Fatal("rotated the key the emitter doesn't sign with")
+	}
+
+	cfg.Emitter.ValidatorKey = oldKey.String()
+	if _, err := launcher.RotateValidatorKey(context.Background(), cfg, &fakeKeyChain{current: oldKey, next: oldKey}, "wrong", time.Millisecond); !errors.Is(err, keystore.ErrDecrypt) {
+		t.Fatalf("rotated the key by a wrong password, err %v", err)
+	}
+	r, err := launcher.RotateValidatorKey(context.Background(), cfg, chain, "pass", time.Millisecond)
+	if err == nil || r.Stage != launcher.RotationSubmitted {
+		t.Fatalf("rotation isn't interrupted: %+v, err %v", r, err)
+	}
+	if report := launcher.CheckConfig(cfg); statusOf(t, report, "keyrotation") != launcher.CheckWarn {
+		t.Error("interrupted rotation isn't reported")
+	}
+	if _, err := os.Stat(filepath.Join(cfg.NetworkDataDir(), launcher.ActiveValidatorKeyFile)); !os.IsNotExist(err) {
+		t.Fatal("emitter is given the new key before the change is pending")
+	}
+
+	resumed, err := launcher.RotateValidatorKey(context.Background(), cfg, chain, "pass", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.NewPubkey != r.NewPubkey || chain.submitted != 1 || resumed.Stage != launcher.RotationSwitched || resumed.Epoch != 8 {
+		t.Fatalf("rotation isn't resumed: %+v, %d transactions", resumed, chain.submitted)
+	}
+	if chain.epoch != 8 || chain.current.String() != resumed.NewPubkey {
+		t.Fatalf("switched before the epoch boundary, epoch %d", chain.epoch)
+	}
+	newKey, _ := validatorpk.FromString(resumed.NewPubkey)
+	if resumed.KeyFile != ks.PathOf(newKey) {
+		t.Fatalf("new key is stored in %s", resumed.KeyFile)
+	}
+	if _, err := crypto.LoadECDSA(resumed.KeyFile); err == nil {
+		t.Fatal("new key is stored unencrypted")
+	}
+	key, err := ks.Get(newKey, "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the emitter of the running node signs with the new key since the activation epoch
+	signer, err := emitter.NewSigner(oldKey, evmcore.FakeKey(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := launcher.WatchActiveValidatorKey(cfg, signer, "pass", time.Millisecond)
+	deadline := time.Now().Add(10 * time.Second)
+	for signer.Latest().String() != resumed.NewPubkey {
+		if time.Now().After(deadline) {
+			t.Fatal("running emitter isn't given the new key")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	stop()
+	digest := crypto.Keccak256([]byte("event"))
+	if sig, err := signer.Sign(7, digest); err != nil || !crypto.VerifySignature(oldKey.Raw, digest, sig) {
+		t.Fatalf("running emitter doesn't sign the old epoch with the old key, err %v", err)
+	}
+	if sig, err := signer.Sign(8, digest); err != nil || !crypto.VerifySignature(crypto.FromECDSAPub(&key.PublicKey), digest, sig) {
+		t.Fatalf("running emitter doesn't sign with the new key, err %v", err)
+	}
+
+	// the emitter of the restarted node signs with the new key
+	restarted := runConfigFromArgs(t, []string{"--datadir", dir, "--network", "fakenet"})
+	if restarted.Emitter.ValidatorID != 2 || restarted.Emitter.ValidatorKey != resumed.NewPubkey {
+		t.Fatalf("emitter isn't switched: %+v", restarted.Emitter)
+	}
+	if statusOf(t, launcher.CheckConfig(restarted), "keyrotation") != launcher.CheckPass {
+		t.Error("finished rotation is reported")
+	}
+}
+
+// TestRotateValidatorKey_interrupted verifies that a rotation interrupted before the new key is
+// stored generates another key, and that the emitter gets the pending key before the epoch boundary.
+func TestRotateValidatorKey_interrupted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opera-rotate-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cfg := runConfigFromArgs(t, []string{"--datadir", dir, "--network", "fakenet"})
+	if err := os.MkdirAll(cfg.NetworkDataDir(), 0700); err != nil {
+		t.Fatal(err)
+	}
+	oldKey := validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: crypto.FromECDSAPub(&evmcore.FakeKey(2).PublicKey)}
+	ks := valkeystore.NewFileKeystore(filepath.Join(cfg.NetworkDataDir(), "keystore"), keystore.LightScryptN, keystore.LightScryptP)
+	if err := ks.Add(oldKey, evmcore.FakeKey(2), "pass"); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Emitter.ValidatorID = 2
+	cfg.Emitter.ValidatorKey = oldKey.String()
+
+	// the node was killed after the new public key was recorded, but before the key was stored
+	lost := validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: crypto.FromECDSAPub(&evmcore.FakeKey(3).PublicKey)}
+	path := filepath.Join(cfg.NetworkDataDir(), launcher.KeyRotationFile)
+	started := launcher.KeyRotation{Validator: 2, OldPubkey: oldKey.String(), NewPubkey: lost.String(), Stage: launcher.RotationStarted}
+	raw, _ := json.Marshal(started)
+	if err := ioutil.WriteFile(path, raw, 0600); err != nil {
+		t.Fatal(err)
+	}
+	// the epoch isn't sealed until the emitter gets the pending key
+	chain := &fakeKeyChain{epoch: 7, current: oldKey, next: oldKey, pollsToSeal: 1 << 30}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := launcher.RotateValidatorKey(ctx, cfg, chain, "pass", time.Millisecond)
+		done <- err
+	}()
+	deadline := time.Now().Add(10 * time.Second)
+	var active launcher.ActiveValidatorKey
+	for {
+		if raw, err := ioutil.ReadFile(filepath.Join(cfg.NetworkDataDir(), launcher.ActiveValidatorKeyFile)); err == nil && json.Unmarshal(raw, &active) == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("emitter isn't given the pending key")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("rotation isn't waiting for the epoch boundary, err %v", err)
+	}
+	if chain.submitted != 1 || active.Pubkey == lost.String() || active.Epoch != 8 || active.PrevPubkey != oldKey.String() {
+		t.Fatalf("unexpected pending key %+v, %d transactions", active, chain.submitted)
+	}
+	newKey, _ := validatorpk.FromString(active.Pubkey)
+	if !ks.Has(newKey) {
+		t.Fatal("pending key isn't in the keystore")
+	}
+
+	// the restarted emitter signs with the old key until the activation epoch
+	restarted := runConfigFromArgs(t, []string{"--datadir", dir, "--network", "fakenet"})
+	if restarted.Emitter.ValidatorKey != active.Pubkey {
+		t.Fatalf("emitter isn't switched: %+v", restarted.Emitter)
+	}
+	signer, err := emitter.NewSigner(oldKey, evmcore.FakeKey(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err := launcher.LoadActiveValidatorKey(restarted, signer, "pass"); err != nil || !loaded {
+		t.Fatalf("pending key isn't loaded, err %v", err)
+	}
+	if signer.PubKey(7).String() != oldKey.String() || signer.PubKey(8).String() != active.Pubkey {
+		t.Fatal("pending key isn't switched to at the epoch boundary")
+	}
+}
+
+func TestSimulateNextValidatorsRPC(t *testing.T) {
+	b := &epochStateBackend{}
+	b.bs, b.es = epochStates(opera.FakeNetRules())
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("abft", ethapi.NewPublicAbftAPI(b)); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	var res ethapi.RPCValidatorsSimulation
+	changes := []map[string]interface{}{
+		{"validatorId": "0x1", "amount": "0x1f4"},
+		{"validatorId": "0x3", "amount": "0xbb8", "unstake": true},
+	}
+	if err := client.CallContext(context.Background(), &res, "abft_simulateNextValidators", changes); err != nil {
+		t.Fatal(err)
+	}
+	if res.Epoch != 8 || res.TotalWeight != 6000 || res.NextTotalWeight != 3500 || len(res.Validators) != 3 {
+		t.Fatalf("unexpected simulation %+v", res)
+	}
+	for i, want := range []struct {
+		id                 uint
+		weight, nextWeight uint64
+	}{{2, 2000, 2000}, {1, 1000, 1500}, {3, 3000, 0}} {
+		v := res.Validators[i]
+		if uint(v.ID) != want.id || uint64(v.Weight) != want.weight || uint64(v.NextWeight) != want.nextWeight ||
+			v.NextStake.ToInt().Uint64() != want.nextWeight {
+			t.Fatalf("validator %d: %+v", i, v)
+		}
+	}
+	// the state isn't affected by the simulation
+	if b.bs.NextValidatorProfiles[3].Weight.Int64() != 3000 {
+		t.Fatal("simulation modified the state")
+	}
+
+	changes = []map[string]interface{}{{"validatorId": "0x2", "amount": "0x7d1", "unstake": true}}
+	if err := client.CallContext(context.Background(), &res, "abft_simulateNextValidators", changes); err == nil {
+		t.Fatal("unstaking above the stake isn't rejected")
+	}
+}
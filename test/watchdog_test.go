@@ -0,0 +1,93 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rony4d/go-opera-asset/cmd/opera/launcher"
+	"github.com/rony4d/go-opera-asset/gossip/emitter"
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+// TestMemoryWatchdog verifies that the watchdog restarts the node only above the threshold,
+// only at a safe point and only after the pending emissions are finished.
+func TestMemoryWatchdog(t *testing.T) {
+	const mb = 1024 * 1024
+	c := clock.NewManual(time.Unix(1600000000, 0))
+	m := emitter.NewMaintenance()
+	rss := uint64(500 * mb)
+	restarts := 0
+	var restartErr error
+	w := launcher.NewMemoryWatchdog(launcher.WatchdogConfig{MaxRSSMB: 1000, Interval: time.Minute, MinUptime: time.Hour},
+		m, func() uint64 { return rss }, func() error {
+			if enabled, inflight := m.Status(); !enabled || inflight != 0 {
+				t.Fatalf("restart with maintenance %v and %d pending emissions", enabled, inflight)
+			}
+			restarts++
+			return restartErr
+		}, c)
+
+	if w.Check() {
+		t.Fatal("armed below the threshold")
+	}
+	rss = 2000 * mb
+	if w.Check() {
+		t.Fatal("armed before the minimum uptime")
+	}
+	c.Advance(time.Hour)
+	if !w.Check() {
+		t.Fatal("not armed above the threshold")
+	}
+
+	// the restart waits for the pending emission
+	if !m.StartEmission() {
+		t.Fatal("emission isn't allowed")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := w.SafePoint(ctx, 2); err == nil || restarts != 0 {
+		t.Fatalf("restarted with a pending emission: %v", err)
+	}
+	if enabled, _ := m.Status(); enabled {
+		t.Fatal("maintenance mode isn't left after the failed restart")
+	}
+	m.DoneEmission()
+
+	// a failed restart is retried at the next epoch
+	restartErr = errors.New("exec failed")
+	if _, err := w.SafePoint(context.Background(), 3); err == nil || restarts != 1 {
+		t.Fatalf("restart failure isn't reported: %v", err)
+	}
+	restartErr = nil
+	if restarted, err := w.SafePoint(context.Background(), 4); !restarted || err != nil || restarts != 2 {
+		t.Fatalf("not restarted: %v", err)
+	}
+	if enabled, _ := m.Status(); enabled {
+		t.Fatal("maintenance mode isn't left after the restart")
+	}
+
+	// the restart is disarmed and the uptime starts over
+	if restarted, _ := w.SafePoint(context.Background(), 5); restarted {
+		t.Fatal("restarted twice")
+	}
+	if w.Check() {
+		t.Fatal("armed right after the restart")
+	}
+
+	// the maintenance mode entered by the operator is kept
+	c.Advance(time.Hour)
+	if !w.Check() {
+		t.Fatal("not armed above the threshold")
+	}
+	if err := m.Enter(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if restarted, err := w.SafePoint(context.Background(), 6); !restarted || err != nil {
+		t.Fatalf("not restarted: %v", err)
+	}
+	if enabled, _ := m.Status(); !enabled {
+		t.Fatal("operator's maintenance mode is left")
+	}
+}
@@ -0,0 +1,31 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManual(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	c := NewManual(start)
+
+	if !c.Now().Equal(start) {
+		t.Fatalf("Now() = %v, want %v", c.Now(), start)
+	}
+	if got := c.Advance(time.Minute); !got.Equal(start.Add(time.Minute)) {
+		t.Fatalf("Advance() = %v, want %v", got, start.Add(time.Minute))
+	}
+	c.Set(start)
+	if !c.Now().Equal(start) {
+		t.Fatalf("Now() after Set = %v, want %v", c.Now(), start)
+	}
+}
+
+func TestReal(t *testing.T) {
+	var c Clock = Real{}
+	before := time.Now()
+	now := c.Now()
+	if now.Before(before) {
+		t.Fatalf("Real clock is behind time.Now(): %v < %v", now, before)
+	}
+}
@@ -140,6 +140,32 @@ func UnmarshalBinaryAdapter(raw []byte, unmarshalCser func(reader *Reader) error
 	return nil
 }
 
+// UnmarshalBinaryPrefixAdapter is like UnmarshalBinaryAdapter, but it doesn't
+// require the whole input to be consumed.
+//
+// It's meant for peeking at the leading fields of a larger structure (e.g. an event
+// header inside a full event payload) without paying for decoding the rest of it.
+// The result must not be treated as a proof that the whole input is canonical.
+func UnmarshalBinaryPrefixAdapter(raw []byte, unmarshalCser func(reader *Reader) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = ErrMalformedEncoding
+		}
+	}()
+
+	bbits, bbytes, err := binaryToCSER(raw)
+	if err != nil {
+		return err
+	}
+
+	bodyReader := &Reader{
+		BitsR:  bits.NewReader(bbits),
+		BytesR: fast.NewReader(bbytes),
+	}
+
+	return unmarshalCser(bodyReader)
+}
+
 // tail returns the last `cap` bytes of slice `b`.
 // If `b` is smaller than `cap`, it returns the whole slice.
 func tail(b []byte, cap int) []byte {
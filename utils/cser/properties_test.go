@@ -0,0 +1,211 @@
+package cser
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// The properties below are checked for every integer encoding over random values:
+//   - round trip: a decoded value equals the encoded one;
+//   - canonical minimality: if arbitrary bytes decode successfully, re-encoding the decoded
+//     value yields exactly the same bytes, so every value has a single accepted encoding;
+//   - monotone size: the encoding of a larger magnitude is never shorter, and takes exactly
+//     the minimal number of bytes.
+
+// anyBits is a random value of a random bit length, so that every encoding size is covered,
+// unlike with the uniform values which are almost always 8 bytes long.
+type anyBits uint64
+
+func (anyBits) Generate(r *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(anyBits(r.Uint64() >> uint(r.Intn(65))))
+}
+
+// intCodec is an integer encoding over the uint64 representation of its values.
+type intCodec struct {
+	name    string
+	minSize int    // bytes of the smallest value
+	max     uint64 // the largest encoded value
+	write   func(w *Writer, v uint64)
+	read    func(r *Reader) uint64
+	// magnitude orders the values by the expected encoding size
+	magnitude func(v uint64) uint64
+}
+
+func identity(v uint64) uint64 { return v }
+
+var intCodecs = []intCodec{
+	{"U16", 1, math.MaxUint16, func(w *Writer, v uint64) { w.U16(uint16(v)) }, func(r *Reader) uint64 { return uint64(r.U16()) }, identity},
+	{"U32", 1, math.MaxUint32, func(w *Writer, v uint64) { w.U32(uint32(v)) }, func(r *Reader) uint64 { return uint64(r.U32()) }, identity},
+	{"U64", 1, math.MaxUint64, func(w *Writer, v uint64) { w.U64(v) }, func(r *Reader) uint64 { return r.U64() }, identity},
+	{"VarUint", 1, math.MaxUint64, func(w *Writer, v uint64) { w.VarUint(v) }, func(r *Reader) uint64 { return r.VarUint() }, identity},
+	{"U56", 0, 1<<56 - 1, func(w *Writer, v uint64) { w.U56(v) }, func(r *Reader) uint64 { return r.U56() }, identity},
+	{"I64", 1, math.MaxUint64, func(w *Writer, v uint64) { w.I64(int64(v)) }, func(r *Reader) uint64 { return uint64(r.I64()) }, func(v uint64) uint64 {
+		if int64(v) < 0 {
+			return -v
+		}
+		return v
+	}},
+}
+
+func (c intCodec) encode(t *testing.T, v uint64) []byte {
+	raw, err := MarshalBinaryAdapter(func(w *Writer) error {
+		c.write(w, v)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func (c intCodec) decode(raw []byte) (v uint64, err error) {
+	err = UnmarshalBinaryAdapter(raw, func(r *Reader) error {
+		v = c.read(r)
+		return nil
+	})
+	return v, err
+}
+
+// clamp maps the random value into the range of the codec, keeping its bit length.
+func (c intCodec) clamp(v anyBits) uint64 {
+	if uint64(v) > c.max {
+		return uint64(v) & c.max
+	}
+	return uint64(v)
+}
+
+// minimalSize returns the number of bytes the value must be encoded with.
+func (c intCodec) minimalSize(v uint64) int {
+	size := 0
+	for m := c.magnitude(v); m != 0; m >>= 8 {
+		size++
+	}
+	if size < c.minSize {
+		return c.minSize
+	}
+	return size
+}
+
+func quickConfig() *quick.Config {
+	return &quick.Config{MaxCount: 5000, Rand: rand.New(rand.NewSource(1))}
+}
+
+func TestIntegersRoundTripProperty(t *testing.T) {
+	for _, c := range intCodecs {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			prop := func(x anyBits) bool {
+				v := c.clamp(x)
+				got, err := c.decode(c.encode(t, v))
+				return err == nil && got == v
+			}
+			if err := quick.Check(prop, quickConfig()); err != nil {
+				t.Fatal(err)
+			}
+			for _, v := range []uint64{0, 1, c.max, c.max >> 1, c.max>>1 + 1} {
+				if !prop(anyBits(v)) {
+					t.Fatalf("value %#x doesn't round trip", v)
+				}
+			}
+		})
+	}
+}
+
+func TestIntegersCanonicalProperty(t *testing.T) {
+	for _, c := range intCodecs {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			// encodings of the random values are re-encoded identically
+			prop := func(x anyBits) bool {
+				raw := c.encode(t, c.clamp(x))
+				v, err := c.decode(raw)
+				return err == nil && bytes.Equal(c.encode(t, v), raw)
+			}
+			if err := quick.Check(prop, quickConfig()); err != nil {
+				t.Fatal(err)
+			}
+
+			// arbitrary bytes are either rejected or the only encoding of their value
+			accepted := 0
+			arbitrary := func(raw []byte) bool {
+				v, err := c.decode(raw)
+				if err != nil {
+					return true
+				}
+				accepted++
+				return bytes.Equal(c.encode(t, v), raw)
+			}
+			cfg := quickConfig()
+			cfg.MaxCount = 50000
+			cfg.Values = func(args []reflect.Value, r *rand.Rand) {
+				raw := make([]byte, 1+r.Intn(11))
+				r.Read(raw)
+				// mostly a valid suffix of a single byte bits stream, to get past the framing
+				if r.Intn(4) != 0 {
+					raw[len(raw)-1] = 0x81
+				}
+				args[0] = reflect.ValueOf(raw)
+			}
+			if err := quick.Check(arbitrary, cfg); err != nil {
+				t.Fatal(err)
+			}
+			if accepted == 0 {
+				t.Fatal("no arbitrary input is accepted, the property is vacuous")
+			}
+		})
+	}
+}
+
+func TestIntegersSizeProperty(t *testing.T) {
+	for _, c := range intCodecs {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			// the framing overhead of a single value is constant, the bits stream takes a byte
+			overhead := len(c.encode(t, 0)) - c.minimalSize(0)
+			prop := func(x, y anyBits) bool {
+				a, b := c.clamp(x), c.clamp(y)
+				if c.magnitude(a) > c.magnitude(b) {
+					a, b = b, a
+				}
+				sa, sb := len(c.encode(t, a)), len(c.encode(t, b))
+				return sa <= sb && sa-overhead == c.minimalSize(a) && sb-overhead == c.minimalSize(b)
+			}
+			if err := quick.Check(prop, quickConfig()); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// TestI64Wraparound checks that a magnitude above int64 isn't accepted as another value.
+func TestI64Wraparound(t *testing.T) {
+	u64 := intCodecs[2]
+	i64 := intCodecs[5]
+	for _, tc := range []struct {
+		neg bool
+		abs uint64
+		ok  bool
+	}{
+		{false, math.MaxInt64, true},
+		{true, 1 << 63, true},
+		{false, 1 << 63, false},
+		{true, 1<<63 + 1, false},
+		{false, math.MaxUint64, false},
+	} {
+		raw, err := MarshalBinaryAdapter(func(w *Writer) error {
+			w.Bool(tc.neg)
+			u64.write(w, tc.abs)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := i64.decode(raw); (err == nil) != tc.ok {
+			t.Fatalf("neg=%v abs=%#x: unexpected error %v", tc.neg, tc.abs, err)
+		}
+	}
+}
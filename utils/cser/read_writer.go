@@ -11,6 +11,7 @@ package cser
 
 import (
 	"errors"
+	"math"
 	"math/big"
 
 	"github.com/rony4d/go-opera-asset/utils/bits"
@@ -222,6 +223,11 @@ func (r *Reader) I64() int64 {
 	if neg && abs == 0 {
 		panic(ErrNonCanonicalEncoding)
 	}
+	// Canonical Check: the magnitude must fit int64, otherwise it wraps around
+	// into a value which has another encoding. Only MinInt64 has the magnitude 1<<63.
+	if abs > math.MaxInt64 && !(neg && abs == 1<<63) {
+		panic(ErrNonCanonicalEncoding)
+	}
 	if neg {
 		return -int64(abs)
 	}
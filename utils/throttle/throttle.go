@@ -0,0 +1,180 @@
+// Package throttle lowers the priority of the background work of the node
+// (historical sync, index building, DB compaction) relative to the live block processing.
+//
+// A Throttler limits a class of background tasks in three ways:
+//   - the live processing holds the shared Gate while it handles a block or an event,
+//     and the background tasks don't start new units of work until the gate is free;
+//   - at most MaxConcurrency units of work run simultaneously;
+//   - the units are spent at no more than Rate per second, with bursts up to Burst.
+//
+// So a syncing validator doesn't miss its emission deadlines because of its own catch-up.
+package throttle
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+// ErrCostAboveBurst is returned if a single unit of work is larger than the burst,
+// so it would never be allowed by the rate limit.
+var ErrCostAboveBurst = errors.New("throttle: cost exceeds the burst")
+
+// Config limits a class of background tasks. Zero values mean no limit.
+type Config struct {
+	MaxConcurrency int     // number of units of work which may run simultaneously
+	Rate           float64 // units of work per second (e.g. blocks or MB, depending on the task)
+	Burst          int     // units which may be spent at once, defaults to max(1, Rate)
+}
+
+// Gate is held by the live processing. It's safe for concurrent use.
+type Gate struct {
+	mu   sync.Mutex
+	busy int
+	idle chan struct{} // closed while nobody holds the gate
+}
+
+// NewGate creates a free gate.
+func NewGate() *Gate {
+	idle := make(chan struct{})
+	close(idle)
+	return &Gate{idle: idle}
+}
+
+// Enter marks the live processing as busy until the returned function is called.
+// Enter may be called concurrently, the gate is free once all the holders exit.
+func (g *Gate) Enter() (exit func()) {
+	g.mu.Lock()
+	if g.busy == 0 {
+		g.idle = make(chan struct{})
+	}
+	g.busy++
+	g.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			g.busy--
+			if g.busy == 0 {
+				close(g.idle)
+			}
+		})
+	}
+}
+
+// Wait blocks until the gate is free or the context is cancelled.
+func (g *Gate) Wait(ctx context.Context) error {
+	g.mu.Lock()
+	idle := g.idle
+	g.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Throttler limits a class of background tasks. It's safe for concurrent use.
+type Throttler struct {
+	cfg   Config
+	gate  *Gate
+	clock clock.Clock
+	slots chan struct{}
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// New creates the throttler. gate may be nil if the tasks don't yield to the live processing.
+func New(cfg Config, gate *Gate, c clock.Clock) *Throttler {
+	if cfg.Burst <= 0 {
+		cfg.Burst = int(math.Max(1, math.Ceil(cfg.Rate)))
+	}
+	t := &Throttler{
+		cfg:    cfg,
+		gate:   gate,
+		clock:  c,
+		tokens: float64(cfg.Burst),
+		last:   c.Now(),
+	}
+	if cfg.MaxConcurrency > 0 {
+		t.slots = make(chan struct{}, cfg.MaxConcurrency)
+	}
+	return t
+}
+
+// Unlimited returns the throttler which never delays the work.
+func Unlimited() *Throttler {
+	return New(Config{}, nil, clock.Real{})
+}
+
+// Config returns the limits of the throttler.
+func (t *Throttler) Config() Config {
+	return t.cfg
+}
+
+// Acquire waits until a unit of work of the cost may start. The returned function must be
+// called once the work is done, to free the concurrency slot.
+func (t *Throttler) Acquire(ctx context.Context, cost int) (release func(), err error) {
+	if t.cfg.Rate > 0 && cost > t.cfg.Burst {
+		return nil, ErrCostAboveBurst
+	}
+	if t.gate != nil {
+		if err := t.gate.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	release = func() {}
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		var once sync.Once
+		release = func() {
+			once.Do(func() { <-t.slots })
+		}
+	}
+	if delay := t.reserve(cost); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
+
+// reserve takes the cost from the token bucket and returns how long to wait
+// until the tokens are refilled. The tokens may go negative, so the waiters are served in order.
+func (t *Throttler) reserve(cost int) time.Duration {
+	if t.cfg.Rate <= 0 {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	if elapsed := now.Sub(t.last); elapsed > 0 {
+		t.tokens = math.Min(float64(t.cfg.Burst), t.tokens+elapsed.Seconds()*t.cfg.Rate)
+	}
+	t.last = now
+	t.tokens -= float64(cost)
+	if t.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-t.tokens / t.cfg.Rate * float64(time.Second))
+}
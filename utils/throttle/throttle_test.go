@@ -0,0 +1,80 @@
+package throttle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rony4d/go-opera-asset/utils/clock"
+)
+
+func TestReserve(t *testing.T) {
+	c := clock.NewManual(time.Unix(1600000000, 0))
+	th := New(Config{Rate: 10, Burst: 5}, nil, c)
+
+	if d := th.reserve(5); d != 0 {
+		t.Fatalf("burst must pass without delay, got %v", d)
+	}
+	if d := th.reserve(2); d != 200*time.Millisecond {
+		t.Fatalf("expected 200ms delay, got %v", d)
+	}
+	// the next waiter is queued after the previous one
+	if d := th.reserve(1); d != 300*time.Millisecond {
+		t.Fatalf("expected 300ms delay, got %v", d)
+	}
+	c.Advance(time.Hour)
+	if d := th.reserve(5); d != 0 {
+		t.Fatalf("refilled bucket must not exceed the burst, got %v", d)
+	}
+	if d := th.reserve(1); d != 100*time.Millisecond {
+		t.Fatalf("expected 100ms delay, got %v", d)
+	}
+
+	if _, err := th.Acquire(context.Background(), 6); err != ErrCostAboveBurst {
+		t.Fatalf("expected %v, got %v", ErrCostAboveBurst, err)
+	}
+	if d := Unlimited().reserve(1000); d != 0 {
+		t.Fatalf("unlimited throttler delayed the work by %v", d)
+	}
+}
+
+func TestAcquire(t *testing.T) {
+	gate := NewGate()
+	th := New(Config{MaxConcurrency: 1}, gate, clock.Real{})
+	ctx := context.Background()
+
+	release, err := th.Acquire(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// no free slots
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := th.Acquire(short, 1); err != context.DeadlineExceeded {
+		t.Fatalf("expected the concurrency cap to block, got %v", err)
+	}
+	release()
+	release() // repeated release is a no-op
+
+	// the live processing holds the gate
+	exit := gate.Enter()
+	acquired := make(chan struct{})
+	go func() {
+		release, err := th.Acquire(ctx, 1)
+		if err == nil {
+			release()
+		}
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("background work started while the gate is held")
+	case <-time.After(10 * time.Millisecond):
+	}
+	exit()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("background work didn't start after the gate is freed")
+	}
+}
@@ -0,0 +1,132 @@
+// Package valkeystore keeps the validator keys encrypted by a password.
+//
+// A key is stored in <keystore>/validator/<pubkey in hex, without 0x> as JSON with the key type,
+// the public key and the private key encrypted the same way as the accounts keystore does it
+// (scrypt and AES-128-CTR, see keystore.EncryptDataV3).
+package valkeystore
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/rony4d/go-opera-asset/inter/validatorpk"
+)
+
+var (
+	// ErrNotFound is returned if the keystore has no key of the public key.
+	ErrNotFound = errors.New("validator key not found")
+	// ErrAlreadyExists is returned if the keystore already has a key of the public key.
+	ErrAlreadyExists = errors.New("validator key already exists")
+	// ErrUnsupportedType is returned for the keys of the types other than secp256k1.
+	ErrUnsupportedType = errors.New("unsupported validator key type")
+)
+
+// EncryptedKeyJSON is the stored validator key.
+type EncryptedKeyJSON struct {
+	Type      uint8               `json:"type"`
+	PublicKey string              `json:"pubkey"`
+	Crypto    keystore.CryptoJSON `json:"crypto"`
+}
+
+// FileKeystore is the keystore of the validator keys in a directory.
+type FileKeystore struct {
+	dir     string
+	scryptN int
+	scryptP int
+}
+
+// NewFileKeystore opens the validator keys of the keystore directory.
+// scryptN and scryptP are the parameters of the key derivation for the added keys,
+// e.g. keystore.StandardScryptN and keystore.StandardScryptP.
+func NewFileKeystore(keystoreDir string, scryptN, scryptP int) *FileKeystore {
+	return &FileKeystore{
+		dir:     filepath.Join(keystoreDir, "validator"),
+		scryptN: scryptN,
+		scryptP: scryptP,
+	}
+}
+
+// PathOf returns the file of the key.
+func (ks *FileKeystore) PathOf(pubkey validatorpk.PubKey) string {
+	return filepath.Join(ks.dir, strings.TrimPrefix(pubkey.String(), "0x"))
+}
+
+// Has returns true if the keystore has the key.
+func (ks *FileKeystore) Has(pubkey validatorpk.PubKey) bool {
+	_, err := os.Stat(ks.PathOf(pubkey))
+	return err == nil
+}
+
+// Add encrypts the private key by the password and stores it.
+func (ks *FileKeystore) Add(pubkey validatorpk.PubKey, key *ecdsa.PrivateKey, password string) error {
+	if pubkey.Type != validatorpk.Types.Secp256k1 {
+		return ErrUnsupportedType
+	}
+	if !bytes.Equal(pubkey.Raw, crypto.FromECDSAPub(&key.PublicKey)) {
+		return fmt.Errorf("private key doesn't match the public key %s", pubkey)
+	}
+	if ks.Has(pubkey) {
+		return ErrAlreadyExists
+	}
+	encrypted, err := keystore.EncryptDataV3(crypto.FromECDSA(key), []byte(password), ks.scryptN, ks.scryptP)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(EncryptedKeyJSON{
+		Type:      pubkey.Type,
+		PublicKey: pubkey.String(),
+		Crypto:    encrypted,
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(ks.dir, 0700); err != nil {
+		return err
+	}
+	// write to a temporary file first, so a failure never leaves a partial key behind
+	path := ks.PathOf(pubkey)
+	if err := ioutil.WriteFile(path+".tmp", b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Get decrypts the key by the password. Returns keystore.ErrDecrypt if the password is wrong.
+func (ks *FileKeystore) Get(pubkey validatorpk.PubKey, password string) (*ecdsa.PrivateKey, error) {
+	b, err := ioutil.ReadFile(ks.PathOf(pubkey))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, pubkey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stored EncryptedKeyJSON
+	if err := json.Unmarshal(b, &stored); err != nil {
+		return nil, err
+	}
+	if stored.Type != validatorpk.Types.Secp256k1 {
+		return nil, ErrUnsupportedType
+	}
+	raw, err := keystore.DecryptDataV3(stored.Crypto, password)
+	if err != nil {
+		return nil, err
+	}
+	key, err := crypto.ToECDSA(raw)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(pubkey.Raw, crypto.FromECDSAPub(&key.PublicKey)) {
+		return nil, fmt.Errorf("stored key doesn't match the public key %s", pubkey)
+	}
+	return key, nil
+}
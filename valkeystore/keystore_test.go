@@ -0,0 +1,64 @@
+package valkeystore
+
+import (
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/rony4d/go-opera-asset/inter/validatorpk"
+)
+
+func TestFileKeystore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "valkeystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ks := NewFileKeystore(dir, keystore.LightScryptN, keystore.LightScryptP)
+
+	key, _ := crypto.GenerateKey()
+	pubkey := validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: crypto.FromECDSAPub(&key.PublicKey)}
+	if ks.Has(pubkey) {
+		t.Fatal("empty keystore has the key")
+	}
+	if _, err := ks.Get(pubkey, "pass"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected %v, got %v", ErrNotFound, err)
+	}
+
+	if err := ks.Add(pubkey, key, "pass"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Add(pubkey, key, "pass"); err != ErrAlreadyExists {
+		t.Fatalf("expected %v, got %v", ErrAlreadyExists, err)
+	}
+	other, _ := crypto.GenerateKey()
+	if err := ks.Add(validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: crypto.FromECDSAPub(&key.PublicKey)[:10]}, other, "pass"); err == nil {
+		t.Fatal("added a key which doesn't match the public key")
+	}
+
+	// the private key isn't stored in plain
+	raw, err := ioutil.ReadFile(ks.PathOf(pubkey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), hex.EncodeToString(crypto.FromECDSA(key))) {
+		t.Fatal("private key is stored unencrypted")
+	}
+
+	if _, err := ks.Get(pubkey, "wrong"); err != keystore.ErrDecrypt {
+		t.Fatalf("expected %v, got %v", keystore.ErrDecrypt, err)
+	}
+	got, err := ks.Get(pubkey, "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(key) {
+		t.Fatal("decrypted key doesn't match")
+	}
+}